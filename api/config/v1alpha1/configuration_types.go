@@ -17,8 +17,11 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	configv1alpha1 "k8s.io/component-base/config/v1alpha1"
+	tracingv1 "k8s.io/component-base/tracing/api/v1"
 )
 
 // +k8s:defaulter-gen=true
@@ -36,6 +39,199 @@ type Configuration struct {
 
 	// ClientConnection is configuration of the client while connecting to API Server
 	ClientConnection *ClientConnection `json:"clientConnection,omitempty"`
+
+	// TopologyFile is configuration for rendering the group topology into a file
+	// shared with the containers of the group pods.
+	// +optional
+	TopologyFile *TopologyFile `json:"topologyFile,omitempty"`
+
+	// RollingUpdateDefaults overrides the rolling update parameters the webhook
+	// sets on LeaderWorkerSets omitting spec.rolloutStrategy.rollingUpdateConfiguration.
+	// +optional
+	RollingUpdateDefaults *RollingUpdateDefaults `json:"rollingUpdateDefaults,omitempty"`
+
+	// Cache is configuration for the informers cache of the controller manager.
+	// +optional
+	Cache *Cache `json:"cache,omitempty"`
+
+	// WatchNamespaces restricts the informers cache of the controller manager, and so the
+	// LeaderWorkerSets reconciled, to these namespaces, e.g. when the controller can't be
+	// granted the cluster-wide list and watch of the pods and statefulsets. The webhooks
+	// still admit the objects of all the namespaces unless their namespaceSelector is set.
+	// If empty, all the namespaces are watched.
+	// +optional
+	WatchNamespaces []string `json:"watchNamespaces,omitempty"`
+
+	// MaxTotalManagedPods caps the number of pods managed by the controller across
+	// all the LeaderWorkerSets. The groups which would exceed the cap aren't created,
+	// the affected LeaderWorkerSets report a Pending condition until enough pods are
+	// deleted. Zero means unlimited.
+	// +optional
+	MaxTotalManagedPods int32 `json:"maxTotalManagedPods,omitempty"`
+
+	// MaxPodsPerLeaderWorkerSet caps the number of pods of a single LeaderWorkerSet, the
+	// product of its replicas and size, to prevent accidentally huge deployments. The
+	// LeaderWorkerSets exceeding it are rejected on admission, the updates only when they
+	// increase the number of pods, so that the existing LeaderWorkerSets can still be updated
+	// and scaled down once the cap is lowered. Zero means unlimited.
+	// +optional
+	MaxPodsPerLeaderWorkerSet int32 `json:"maxPodsPerLeaderWorkerSet,omitempty"`
+
+	// RecommendedLabels configures the app.kubernetes.io recommended labels stamped on the
+	// statefulsets, pods and services created for the LeaderWorkerSets.
+	// If not set, the recommended labels are not stamped.
+	// +optional
+	RecommendedLabels *RecommendedLabels `json:"recommendedLabels,omitempty"`
+
+	// CrashLoopDetection configures the detection of crash-looping groups during rolling
+	// updates, the rollout is paused while groups of the new revision are crash-looping.
+	// If not set, the rollouts are never paused.
+	// +optional
+	CrashLoopDetection *CrashLoopDetection `json:"crashLoopDetection,omitempty"`
+
+	// RecreationBackoff configures the exponential backoff of the recreations of the groups
+	// by the RecreateGroupOnPodRestart restart policy, so that a crash-looping group isn't
+	// recreated in a tight loop.
+	// If not set, the groups are recreated as soon as one of their pods restarts.
+	// +optional
+	RecreationBackoff *RecreationBackoff `json:"recreationBackoff,omitempty"`
+
+	// ReconcileTimeout bounds the duration of each reconciliation of the controllers, a
+	// reconciliation exceeding it is cancelled and requeued with an error, so that a slow
+	// object doesn't hold a worker.
+	// If not set, the reconciliations are not bounded.
+	// +optional
+	ReconcileTimeout *metav1.Duration `json:"reconcileTimeout,omitempty"`
+
+	// WatchPersistentVolumeClaims reconciles the LeaderWorkerSets on the events of the
+	// PersistentVolumeClaims labeled with their name, so that the status reflects the groups
+	// pending on volumes as soon as the claims are bound or lost. The claims are neither
+	// watched nor cached when disabled, and the controller doesn't need any access to them.
+	// Defaults to false.
+	// +optional
+	WatchPersistentVolumeClaims bool `json:"watchPersistentVolumeClaims,omitempty"`
+
+	// AllowedImageRegistries restricts the images of the containers of the leader and worker
+	// templates to the ones starting with one of the prefixes, e.g. "registry.example.com/".
+	// The LeaderWorkerSets using other images are rejected on admission, the updates
+	// only for the images they change, so that the existing LeaderWorkerSets can still
+	// be updated once the registries are restricted. If empty, all the images are allowed.
+	// +optional
+	AllowedImageRegistries []string `json:"allowedImageRegistries,omitempty"`
+
+	// FeatureGates is a map of feature names to bools that enable or disable alpha or beta
+	// features of the controller, e.g. AllLeadersReadyStartupPolicy.
+	// +optional
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+
+	// RolloutWaveLabel is the key of the label stamped on the leader and worker pods with
+	// the rollout wave of their group, the 0-based index of the batch in which the group is
+	// updated by a rolling update given the maxUnavailable and maxSurge of the LeaderWorkerSet,
+	// so that progressive delivery tools can target the waves. The labels are updated when
+	// the replicas or the rollout strategy change.
+	// If empty, the pods are not labeled.
+	// +optional
+	RolloutWaveLabel string `json:"rolloutWaveLabel,omitempty"`
+
+	// StatusUpdateDebounce coalesces the status writes of a LeaderWorkerSet, the replica
+	// counts and group summaries changing within this interval after a status write are
+	// written once the interval elapses, so that high-churn clusters don't patch the status
+	// on every pod event. The changes of the conditions or of the generation of the
+	// LeaderWorkerSet are always written immediately.
+	// If not set, every change of the status is written immediately.
+	// +optional
+	StatusUpdateDebounce *metav1.Duration `json:"statusUpdateDebounce,omitempty"`
+
+	// RequiredLabels are the keys of the labels every LeaderWorkerSet must carry, e.g. for
+	// the governance of the teams and cost centers. The LeaderWorkerSets missing any of them
+	// are rejected on creation, and the updates removing one of them are rejected too.
+	// If empty, the labels are not checked.
+	// +optional
+	RequiredLabels []string `json:"requiredLabels,omitempty"`
+
+	// Tracing enables the OpenTelemetry tracing of the reconciliations, the spans are
+	// exported with OTLP over gRPC to the endpoint, localhost:4317 by default. The
+	// reconciliations have no parent span, so samplingRatePerMillion must be set for
+	// them to be sampled.
+	// If not set, the tracing is disabled.
+	// +optional
+	Tracing *tracingv1.TracingConfiguration `json:"tracing,omitempty"`
+
+	// SecurityContextDefaults are merged by the pod webhook into the security contexts of
+	// the leader and worker pods and of their containers, to enforce security baselines.
+	// The fields set on the pods or containers are never overridden.
+	// If not set, the security contexts are left as they are.
+	// +optional
+	SecurityContextDefaults *SecurityContextDefaults `json:"securityContextDefaults,omitempty"`
+
+	// DefaultAnnotations are stamped on the leader and worker pods and the headless services
+	// created for the LeaderWorkerSets, e.g. to control the injection of a service mesh. The
+	// annotations set by the pod templates are never overridden. The keys must not use the
+	// leaderworkerset.sigs.k8s.io/ prefix of the annotations of the controller.
+	// If empty, no annotation is stamped.
+	// +optional
+	DefaultAnnotations map[string]string `json:"defaultAnnotations,omitempty"`
+
+	// LeaderReadyPollInterval is the interval at which the groups waiting for their leader pod, or
+	// for all the leader pods, to be ready with the LeaderReady and AllLeadersReady startup policies
+	// are reconciled again, so that their startup latency is predictable even if a readiness change
+	// of a leader pod is missed.
+	// If not set, the groups only wait for the readiness changes of the leader pods.
+	// +optional
+	LeaderReadyPollInterval *metav1.Duration `json:"leaderReadyPollInterval,omitempty"`
+
+	// MaxConcurrentPodDeletes bounds the pods of a LeaderWorkerSet being deleted at once when
+	// its groups are removed, on scale-down or by the Recreate rollout strategy, to limit the
+	// load on the apiserver. The unit of a batch is a whole group: the groups are removed from
+	// the last one, as many at once as their pods fit in the limit, and at least one, so the
+	// pods of a group larger than the limit are still deleted at once.
+	// If not set, the removed groups are deleted at once.
+	// +optional
+	MaxConcurrentPodDeletes *int32 `json:"maxConcurrentPodDeletes,omitempty"`
+
+	// DefaultStartupPolicy is the startup policy set on the LeaderWorkerSets omitting one,
+	// it can be LeaderCreated, LeaderReady or AllLeadersReady. AllLeadersReady requires the
+	// AllLeadersReadyStartupPolicy feature gate.
+	// Defaults to LeaderCreated.
+	DefaultStartupPolicy string `json:"defaultStartupPolicy,omitempty"`
+
+	// DrainQueueOnShutdown makes the controllers reconcile the queued requests to completion
+	// when the manager stops, within its graceful shutdown timeout, instead of failing them on
+	// the cancelled context.
+	// Defaults to false.
+	DrainQueueOnShutdown bool `json:"drainQueueOnShutdown,omitempty"`
+
+	// ValidatePodTemplates makes the webhook validate the names of the containers, ports,
+	// env vars and volumes of the leader, worker and coordinator templates, the port numbers,
+	// the missing images and the mounts of undeclared volumes, so that these invalid templates
+	// are rejected on the LeaderWorkerSets instead of failing their statefulsets or pods. It
+	// is a subset of the validation of the API server, which the other fields are left to.
+	// Defaults to false.
+	ValidatePodTemplates bool `json:"validatePodTemplates,omitempty"`
+
+	// StuckTerminatingPodTimeout is how long the pods of the LeaderWorkerSets may remain
+	// Terminating past their deletion grace period, e.g. on an unreachable node, before they
+	// are force deleted so that their group can be recreated. It requires the
+	// ForceDeleteStuckTerminatingPods feature gate, or the DryRunReconcile one to only count
+	// the pods which would be force deleted.
+	// If not set, the pods are never force deleted.
+	// +optional
+	StuckTerminatingPodTimeout *metav1.Duration `json:"stuckTerminatingPodTimeout,omitempty"`
+
+	// LastAppliedConfigFile is the absolute path of the file persisting the hash and the fields
+	// of the last applied config file, e.g. on a persistent volume. When set, the fields set in
+	// the last applied config file but omitted from the loaded one, silently reverting to their
+	// defaults, are reported with a warning at startup and when the config file is reloaded.
+	// The errors reading or writing this file are logged, the config file is still applied.
+	// If empty, the config file is not compared with the last applied one.
+	// +optional
+	LastAppliedConfigFile string `json:"lastAppliedConfigFile,omitempty"`
+
+	// LogAppliedManagerOptions makes the configuration logged at startup report the webhook,
+	// leaderElection, metrics and health sections as applied to the manager, e.g. once
+	// overridden by the command line flags, so that the effective configuration can be audited.
+	// Defaults to false, the sections are logged as set in the config file.
+	LogAppliedManagerOptions bool `json:"logAppliedManagerOptions,omitempty"`
 }
 
 type ControllerManager struct {
@@ -75,15 +271,47 @@ type ControllerWebhook struct {
 	// must be named tls.key and tls.crt, respectively.
 	// +optional
 	CertDir string `json:"certDir,omitempty"`
+
+	// MaxConcurrentRequests is the maximum number of admission requests served concurrently,
+	// the requests over the limit wait for one to complete.
+	// Defaults to no limit.
+	// +optional
+	MaxConcurrentRequests *int32 `json:"maxConcurrentRequests,omitempty"`
+
+	// ValidateCert makes the controller load the server certificate and key from CertDir at
+	// startup, and fail if they can't be parsed or the certificate is expired, rather than
+	// when the webhook server serves. It is ignored with internal cert management enabled, as
+	// the certificate is generated after the startup.
+	// Defaults to false.
+	// +optional
+	ValidateCert bool `json:"validateCert,omitempty"`
 }
 
 // ControllerMetrics defines the metrics configs.
 type ControllerMetrics struct {
 	// BindAddress is the TCP address that the controller should bind to
 	// for serving prometheus metrics.
-	// It can be set to "0" to disable the metrics serving.
+	// It can be set to "0" to disable the metrics serving, the certificate files are
+	// then ignored.
 	// +optional
 	BindAddress string `json:"bindAddress,omitempty"`
+
+	// CertFile is the path to the certificate presented by the metrics server.
+	// It must be set together with KeyFile. If not set, a self-signed certificate
+	// is generated.
+	// +optional
+	CertFile string `json:"certFile,omitempty"`
+
+	// KeyFile is the path to the key of the certificate presented by the metrics server.
+	// It must be set together with CertFile.
+	// +optional
+	KeyFile string `json:"keyFile,omitempty"`
+
+	// ClientCAFile is the path to the CA bundle used to verify the certificates of the
+	// clients scraping the metrics. When set, the clients must present a certificate
+	// signed by one of these CAs.
+	// +optional
+	ClientCAFile string `json:"clientCAFile,omitempty"`
 }
 
 // ControllerHealth defines the health configs.
@@ -101,6 +329,14 @@ type ControllerHealth struct {
 	// LivenessEndpointName, defaults to "healthz"
 	// +optional
 	LivenessEndpointName string `json:"livenessEndpointName,omitempty"`
+
+	// ReadinessChecks are the named subchecks registered under the readiness endpoint, in
+	// addition to the ping check, so that <readinessEndpointName>?verbose shows the status
+	// of each subsystem. The supported subchecks are cache-sync, webhook-ready and
+	// leader-status. The leader-status subcheck reports the replicas not holding the leader
+	// election lease as not ready.
+	// +optional
+	ReadinessChecks []string `json:"readinessChecks,omitempty"`
 }
 
 // InternalCertManagement defines internal certificate management configs
@@ -127,4 +363,132 @@ type ClientConnection struct {
 
 	// Burst allows extra queries to accumulate when a client is exceeding its rate.
 	Burst *int32 `json:"burst,omitempty"`
+	// UserAgent is the user agent of the requests to the API server, e.g. to attribute
+	// them in the audit logs.
+	// Defaults to lws/<version> (<os>/<arch>) <commit>.
+	UserAgent *string `json:"userAgent,omitempty"`
+	// DisableCompression disables the compression of the responses of the API server,
+	// trading bandwidth for CPU on clusters with a fast network to the API server.
+	// Defaults to false.
+	DisableCompression bool `json:"disableCompression,omitempty"`
+}
+
+// TopologyFile defines the configs for the topology file, an alternative to the
+// injected environment variables for frameworks expecting a rendezvous file.
+// When enabled, an init container is injected into every group pod, it reads the
+// group labels and annotations through the downward API and writes them into a
+// file on an emptyDir volume mounted into all the containers of the pod.
+type TopologyFile struct {
+	// Enable controls whether to inject the topology file init container or not.
+	// Defaults to false.
+	Enable *bool `json:"enable,omitempty"`
+
+	// Image is the image of the init container rendering the topology file,
+	// it must provide a POSIX shell.
+	// Defaults to busybox:1.36.
+	Image *string `json:"image,omitempty"`
+
+	// MountPath is the path the shared volume is mounted at in every container,
+	// the topology file is named "topology" under it.
+	// Defaults to /etc/lws.
+	MountPath *string `json:"mountPath,omitempty"`
+
+	// Resources are the compute resources of the init container, so that it isn't
+	// the first evicted under node pressure. LeaderWorkerSets can override them
+	// with the leaderworkerset.sigs.k8s.io/injected-container-resources annotation.
+	// Defaults to requests of 10m CPU and 16Mi of memory.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// RollingUpdateDefaults defines the rolling update parameters applied to
+// LeaderWorkerSets which don't configure them explicitly.
+type RollingUpdateDefaults struct {
+	// MaxUnavailable is the default maximum number of replicas that can be
+	// unavailable during the update, as an absolute number or a percentage.
+	// Defaults to 1.
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// MaxSurge is the default maximum number of replicas that can be scheduled
+	// above the original number of replicas, as an absolute number or a percentage.
+	// Defaults to 0.
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+}
+
+// SecurityContextDefaults defines the security context fields applied to the group pods which
+// don't set them. The defaults are merged field by field, a field set on a pod or container,
+// e.g. capabilities, is kept as a whole.
+type SecurityContextDefaults struct {
+	// Pod is merged into the security context of the pods.
+	Pod *corev1.PodSecurityContext `json:"pod,omitempty"`
+
+	// Container is merged into the security context of the containers and init containers
+	// of the pods, including the ones injected by the webhook.
+	Container *corev1.SecurityContext `json:"container,omitempty"`
+}
+
+// Cache defines the configs restricting the objects held in the informers cache.
+type Cache struct {
+	// PodLabelSelector is a label selector, in the kubectl format, restricting the
+	// pods held in the cache, e.g. "leaderworkerset.sigs.k8s.io/name" to only cache
+	// the pods managed by LeaderWorkerSets. Pods not matching it are invisible to the
+	// controllers, so it must match all the leader and worker pods.
+	// Defaults to caching all the pods.
+	PodLabelSelector *string `json:"podLabelSelector,omitempty"`
+
+	// ResyncPeriod is the minimum interval at which the informers replay all the
+	// objects held in the cache, reconciling every LeaderWorkerSet and pod again to
+	// catch up with missed events. A short period keeps the state converged at the
+	// cost of reconciling the unchanged objects and the API writes they may cause.
+	// Defaults to the controller-runtime period of 10 hours.
+	ResyncPeriod *metav1.Duration `json:"resyncPeriod,omitempty"`
+}
+
+// RecommendedLabels defines the values of the recommended labels stamped on the objects
+// created for the LeaderWorkerSets.
+type RecommendedLabels struct {
+	// ManagedBy is the value of the app.kubernetes.io/managed-by label.
+	// Defaults to lws.
+	ManagedBy *string `json:"managedBy,omitempty"`
+
+	// PartOf is the value of the app.kubernetes.io/part-of label.
+	// Defaults to lws.
+	PartOf *string `json:"partOf,omitempty"`
+}
+
+// RecreationBackoff defines the delays between the successive recreations of a group by the
+// RecreateGroupOnPodRestart restart policy. The first recreation isn't delayed, the next
+// ones are delayed by the base doubled on each recreation, up to the cap.
+type RecreationBackoff struct {
+	// Base is the delay before the second recreation of a group.
+	// Defaults to 10s.
+	Base *metav1.Duration `json:"base,omitempty"`
+
+	// Cap is the maximum delay between two recreations of a group, jitter included.
+	// Defaults to 5m.
+	Cap *metav1.Duration `json:"cap,omitempty"`
+
+	// JitterPercent is the maximum percentage of the delay randomly added to it, so that the
+	// groups crash-looping together aren't recreated at once.
+	// Defaults to 10.
+	JitterPercent *int32 `json:"jitterPercent,omitempty"`
+
+	// ResetAfter is how long a group must run without being recreated for its backoff to
+	// be reset, its next recreation then isn't delayed.
+	// Defaults to 10m.
+	ResetAfter *metav1.Duration `json:"resetAfter,omitempty"`
+}
+
+// CrashLoopDetection defines when a group of the new revision is considered crash-looping.
+type CrashLoopDetection struct {
+	// RestartThreshold is the number of restarts of a container, or of recreations of the
+	// group by the RecreateGroupOnPodRestart restart policy, from which the group is
+	// considered crash-looping.
+	// Defaults to 3.
+	RestartThreshold *int32 `json:"restartThreshold,omitempty"`
+
+	// Window is the duration within which the last restart must have happened for the
+	// group to be considered crash-looping, so that groups which recovered don't block
+	// the rollout.
+	// Defaults to 10m.
+	Window *metav1.Duration `json:"window,omitempty"`
 }
@@ -17,8 +17,16 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	configv1alpha1 "k8s.io/component-base/config/v1alpha1"
 	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/lws/pkg/utils/useragent"
 )
 
 const (
@@ -34,6 +42,35 @@ const (
 	DefaultResourceLock                   = "leases"
 	DefaultClientConnectionQPS    float32 = 500
 	DefaultClientConnectionBurst  int32   = 500
+	DefaultTopologyFileImage              = "busybox:1.36"
+	DefaultTopologyFileMountPath          = "/etc/lws"
+)
+
+const (
+	DefaultTopologyFileCPURequest    = "10m"
+	DefaultTopologyFileMemoryRequest = "16Mi"
+)
+
+const (
+	DefaultRollingUpdateMaxUnavailable int32 = 1
+	DefaultRollingUpdateMaxSurge       int32 = 0
+)
+
+const (
+	DefaultRecommendedLabelsManagedBy = "lws"
+	DefaultRecommendedLabelsPartOf    = "lws"
+)
+
+const (
+	DefaultCrashLoopRestartThreshold int32 = 3
+	DefaultCrashLoopWindow                 = 10 * time.Minute
+)
+
+const (
+	DefaultRecreationBackoffBase                = 10 * time.Second
+	DefaultRecreationBackoffCap                 = 5 * time.Minute
+	DefaultRecreationBackoffJitterPercent int32 = 10
+	DefaultRecreationBackoffResetAfter          = 10 * time.Minute
 )
 
 // SetDefaults_Configuration sets default values for ComponentConfig.
@@ -94,4 +131,61 @@ func SetDefaults_Configuration(cfg *Configuration) {
 	if cfg.ClientConnection.Burst == nil {
 		cfg.ClientConnection.Burst = ptr.To(DefaultClientConnectionBurst)
 	}
+	if cfg.ClientConnection.UserAgent == nil {
+		cfg.ClientConnection.UserAgent = ptr.To(useragent.Default())
+	}
+	if cfg.TopologyFile != nil && ptr.Deref(cfg.TopologyFile.Enable, false) {
+		if cfg.TopologyFile.Image == nil {
+			cfg.TopologyFile.Image = ptr.To(DefaultTopologyFileImage)
+		}
+		if cfg.TopologyFile.MountPath == nil {
+			cfg.TopologyFile.MountPath = ptr.To(DefaultTopologyFileMountPath)
+		}
+		if cfg.TopologyFile.Resources == nil {
+			cfg.TopologyFile.Resources = &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(DefaultTopologyFileCPURequest),
+					corev1.ResourceMemory: resource.MustParse(DefaultTopologyFileMemoryRequest),
+				},
+			}
+		}
+	}
+	if cfg.RollingUpdateDefaults != nil {
+		if cfg.RollingUpdateDefaults.MaxUnavailable == nil {
+			cfg.RollingUpdateDefaults.MaxUnavailable = ptr.To(intstr.FromInt32(DefaultRollingUpdateMaxUnavailable))
+		}
+		if cfg.RollingUpdateDefaults.MaxSurge == nil {
+			cfg.RollingUpdateDefaults.MaxSurge = ptr.To(intstr.FromInt32(DefaultRollingUpdateMaxSurge))
+		}
+	}
+	if cfg.RecommendedLabels != nil {
+		if cfg.RecommendedLabels.ManagedBy == nil {
+			cfg.RecommendedLabels.ManagedBy = ptr.To(DefaultRecommendedLabelsManagedBy)
+		}
+		if cfg.RecommendedLabels.PartOf == nil {
+			cfg.RecommendedLabels.PartOf = ptr.To(DefaultRecommendedLabelsPartOf)
+		}
+	}
+	if cfg.CrashLoopDetection != nil {
+		if cfg.CrashLoopDetection.RestartThreshold == nil {
+			cfg.CrashLoopDetection.RestartThreshold = ptr.To(DefaultCrashLoopRestartThreshold)
+		}
+		if cfg.CrashLoopDetection.Window == nil {
+			cfg.CrashLoopDetection.Window = &metav1.Duration{Duration: DefaultCrashLoopWindow}
+		}
+	}
+	if cfg.RecreationBackoff != nil {
+		if cfg.RecreationBackoff.Base == nil {
+			cfg.RecreationBackoff.Base = &metav1.Duration{Duration: DefaultRecreationBackoffBase}
+		}
+		if cfg.RecreationBackoff.Cap == nil {
+			cfg.RecreationBackoff.Cap = &metav1.Duration{Duration: DefaultRecreationBackoffCap}
+		}
+		if cfg.RecreationBackoff.JitterPercent == nil {
+			cfg.RecreationBackoff.JitterPercent = ptr.To(DefaultRecreationBackoffJitterPercent)
+		}
+		if cfg.RecreationBackoff.ResetAfter == nil {
+			cfg.RecreationBackoff.ResetAfter = &metav1.Duration{Duration: DefaultRecreationBackoffResetAfter}
+		}
+	}
 }
@@ -21,9 +21,14 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	configv1alpha1 "k8s.io/component-base/config/v1alpha1"
 	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/lws/pkg/utils/useragent"
 )
 
 const (
@@ -61,8 +66,9 @@ func TestSetDefaults_Configuration(t *testing.T) {
 		},
 	}
 	defaultClientConnection := &ClientConnection{
-		QPS:   ptr.To(DefaultClientConnectionQPS),
-		Burst: ptr.To(DefaultClientConnectionBurst),
+		QPS:       ptr.To(DefaultClientConnectionQPS),
+		Burst:     ptr.To(DefaultClientConnectionBurst),
+		UserAgent: ptr.To(useragent.Default()),
 	}
 
 	testCases := map[string]struct {
@@ -249,8 +255,9 @@ func TestSetDefaults_Configuration(t *testing.T) {
 					Enable: ptr.To(false),
 				},
 				ClientConnection: &ClientConnection{
-					QPS:   ptr.To[float32](123.0),
-					Burst: ptr.To[int32](456),
+					QPS:       ptr.To[float32](123.0),
+					Burst:     ptr.To[int32](456),
+					UserAgent: ptr.To("audit-attribution"),
 				},
 			},
 			want: &Configuration{
@@ -259,8 +266,9 @@ func TestSetDefaults_Configuration(t *testing.T) {
 					Enable: ptr.To(false),
 				},
 				ClientConnection: &ClientConnection{
-					QPS:   ptr.To[float32](123.0),
-					Burst: ptr.To[int32](456),
+					QPS:       ptr.To[float32](123.0),
+					Burst:     ptr.To[int32](456),
+					UserAgent: ptr.To("audit-attribution"),
 				},
 			},
 		},
@@ -279,6 +287,75 @@ func TestSetDefaults_Configuration(t *testing.T) {
 				ClientConnection: defaultClientConnection,
 			},
 		},
+		"defaulting enabled TopologyFile": {
+			original: &Configuration{
+				InternalCertManagement: &InternalCertManagement{
+					Enable: ptr.To(false),
+				},
+				TopologyFile: &TopologyFile{
+					Enable: ptr.To(true),
+				},
+			},
+			want: &Configuration{
+				ControllerManager: defaultCtrlManagerConfigurationSpec,
+				InternalCertManagement: &InternalCertManagement{
+					Enable: ptr.To(false),
+				},
+				ClientConnection: defaultClientConnection,
+				TopologyFile: &TopologyFile{
+					Enable:    ptr.To(true),
+					Image:     ptr.To(DefaultTopologyFileImage),
+					MountPath: ptr.To(DefaultTopologyFileMountPath),
+					Resources: &corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse(DefaultTopologyFileCPURequest),
+							corev1.ResourceMemory: resource.MustParse(DefaultTopologyFileMemoryRequest),
+						},
+					},
+				},
+			},
+		},
+		"should not default disabled TopologyFile": {
+			original: &Configuration{
+				InternalCertManagement: &InternalCertManagement{
+					Enable: ptr.To(false),
+				},
+				TopologyFile: &TopologyFile{
+					Enable: ptr.To(false),
+				},
+			},
+			want: &Configuration{
+				ControllerManager: defaultCtrlManagerConfigurationSpec,
+				InternalCertManagement: &InternalCertManagement{
+					Enable: ptr.To(false),
+				},
+				ClientConnection: defaultClientConnection,
+				TopologyFile: &TopologyFile{
+					Enable: ptr.To(false),
+				},
+			},
+		},
+		"defaulting partial RollingUpdateDefaults": {
+			original: &Configuration{
+				InternalCertManagement: &InternalCertManagement{
+					Enable: ptr.To(false),
+				},
+				RollingUpdateDefaults: &RollingUpdateDefaults{
+					MaxSurge: ptr.To(intstr.FromString("25%")),
+				},
+			},
+			want: &Configuration{
+				ControllerManager: defaultCtrlManagerConfigurationSpec,
+				InternalCertManagement: &InternalCertManagement{
+					Enable: ptr.To(false),
+				},
+				ClientConnection: defaultClientConnection,
+				RollingUpdateDefaults: &RollingUpdateDefaults{
+					MaxUnavailable: ptr.To(intstr.FromInt32(DefaultRollingUpdateMaxUnavailable)),
+					MaxSurge:       ptr.To(intstr.FromString("25%")),
+				},
+			},
+		},
 	}
 
 	for name, tc := range testCases {
@@ -21,10 +21,39 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	configv1alpha1 "k8s.io/component-base/config/v1alpha1"
+	apiv1 "k8s.io/component-base/tracing/api/v1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Cache) DeepCopyInto(out *Cache) {
+	*out = *in
+	if in.PodLabelSelector != nil {
+		in, out := &in.PodLabelSelector, &out.PodLabelSelector
+		*out = new(string)
+		**out = **in
+	}
+	if in.ResyncPeriod != nil {
+		in, out := &in.ResyncPeriod, &out.ResyncPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Cache.
+func (in *Cache) DeepCopy() *Cache {
+	if in == nil {
+		return nil
+	}
+	out := new(Cache)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClientConnection) DeepCopyInto(out *ClientConnection) {
 	*out = *in
@@ -38,6 +67,11 @@ func (in *ClientConnection) DeepCopyInto(out *ClientConnection) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.UserAgent != nil {
+		in, out := &in.UserAgent, &out.UserAgent
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientConnection.
@@ -65,6 +99,100 @@ func (in *Configuration) DeepCopyInto(out *Configuration) {
 		*out = new(ClientConnection)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TopologyFile != nil {
+		in, out := &in.TopologyFile, &out.TopologyFile
+		*out = new(TopologyFile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RollingUpdateDefaults != nil {
+		in, out := &in.RollingUpdateDefaults, &out.RollingUpdateDefaults
+		*out = new(RollingUpdateDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(Cache)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WatchNamespaces != nil {
+		in, out := &in.WatchNamespaces, &out.WatchNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RecommendedLabels != nil {
+		in, out := &in.RecommendedLabels, &out.RecommendedLabels
+		*out = new(RecommendedLabels)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CrashLoopDetection != nil {
+		in, out := &in.CrashLoopDetection, &out.CrashLoopDetection
+		*out = new(CrashLoopDetection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RecreationBackoff != nil {
+		in, out := &in.RecreationBackoff, &out.RecreationBackoff
+		*out = new(RecreationBackoff)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReconcileTimeout != nil {
+		in, out := &in.ReconcileTimeout, &out.ReconcileTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.AllowedImageRegistries != nil {
+		in, out := &in.AllowedImageRegistries, &out.AllowedImageRegistries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.StatusUpdateDebounce != nil {
+		in, out := &in.StatusUpdateDebounce, &out.StatusUpdateDebounce
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RequiredLabels != nil {
+		in, out := &in.RequiredLabels, &out.RequiredLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tracing != nil {
+		in, out := &in.Tracing, &out.Tracing
+		*out = new(apiv1.TracingConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContextDefaults != nil {
+		in, out := &in.SecurityContextDefaults, &out.SecurityContextDefaults
+		*out = new(SecurityContextDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DefaultAnnotations != nil {
+		in, out := &in.DefaultAnnotations, &out.DefaultAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LeaderReadyPollInterval != nil {
+		in, out := &in.LeaderReadyPollInterval, &out.LeaderReadyPollInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxConcurrentPodDeletes != nil {
+		in, out := &in.MaxConcurrentPodDeletes, &out.MaxConcurrentPodDeletes
+		*out = new(int32)
+		**out = **in
+	}
+	if in.StuckTerminatingPodTimeout != nil {
+		in, out := &in.StuckTerminatingPodTimeout, &out.StuckTerminatingPodTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Configuration.
@@ -88,6 +216,11 @@ func (in *Configuration) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControllerHealth) DeepCopyInto(out *ControllerHealth) {
 	*out = *in
+	if in.ReadinessChecks != nil {
+		in, out := &in.ReadinessChecks, &out.ReadinessChecks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerHealth.
@@ -110,7 +243,7 @@ func (in *ControllerManager) DeepCopyInto(out *ControllerManager) {
 		(*in).DeepCopyInto(*out)
 	}
 	out.Metrics = in.Metrics
-	out.Health = in.Health
+	in.Health.DeepCopyInto(&out.Health)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerManager.
@@ -146,6 +279,11 @@ func (in *ControllerWebhook) DeepCopyInto(out *ControllerWebhook) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.MaxConcurrentRequests != nil {
+		in, out := &in.MaxConcurrentRequests, &out.MaxConcurrentRequests
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerWebhook.
@@ -158,6 +296,31 @@ func (in *ControllerWebhook) DeepCopy() *ControllerWebhook {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrashLoopDetection) DeepCopyInto(out *CrashLoopDetection) {
+	*out = *in
+	if in.RestartThreshold != nil {
+		in, out := &in.RestartThreshold, &out.RestartThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrashLoopDetection.
+func (in *CrashLoopDetection) DeepCopy() *CrashLoopDetection {
+	if in == nil {
+		return nil
+	}
+	out := new(CrashLoopDetection)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InternalCertManagement) DeepCopyInto(out *InternalCertManagement) {
 	*out = *in
@@ -187,3 +350,148 @@ func (in *InternalCertManagement) DeepCopy() *InternalCertManagement {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecreationBackoff) DeepCopyInto(out *RecreationBackoff) {
+	*out = *in
+	if in.Base != nil {
+		in, out := &in.Base, &out.Base
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Cap != nil {
+		in, out := &in.Cap, &out.Cap
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.JitterPercent != nil {
+		in, out := &in.JitterPercent, &out.JitterPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ResetAfter != nil {
+		in, out := &in.ResetAfter, &out.ResetAfter
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecreationBackoff.
+func (in *RecreationBackoff) DeepCopy() *RecreationBackoff {
+	if in == nil {
+		return nil
+	}
+	out := new(RecreationBackoff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecommendedLabels) DeepCopyInto(out *RecommendedLabels) {
+	*out = *in
+	if in.ManagedBy != nil {
+		in, out := &in.ManagedBy, &out.ManagedBy
+		*out = new(string)
+		**out = **in
+	}
+	if in.PartOf != nil {
+		in, out := &in.PartOf, &out.PartOf
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecommendedLabels.
+func (in *RecommendedLabels) DeepCopy() *RecommendedLabels {
+	if in == nil {
+		return nil
+	}
+	out := new(RecommendedLabels)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollingUpdateDefaults) DeepCopyInto(out *RollingUpdateDefaults) {
+	*out = *in
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollingUpdateDefaults.
+func (in *RollingUpdateDefaults) DeepCopy() *RollingUpdateDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(RollingUpdateDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityContextDefaults) DeepCopyInto(out *SecurityContextDefaults) {
+	*out = *in
+	if in.Pod != nil {
+		in, out := &in.Pod, &out.Pod
+		*out = new(corev1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Container != nil {
+		in, out := &in.Container, &out.Container
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityContextDefaults.
+func (in *SecurityContextDefaults) DeepCopy() *SecurityContextDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityContextDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologyFile) DeepCopyInto(out *TopologyFile) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(string)
+		**out = **in
+	}
+	if in.MountPath != nil {
+		in, out := &in.MountPath, &out.MountPath
+		*out = new(string)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyFile.
+func (in *TopologyFile) DeepCopy() *TopologyFile {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologyFile)
+	in.DeepCopyInto(out)
+	return out
+}
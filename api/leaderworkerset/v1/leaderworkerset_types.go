@@ -91,8 +91,134 @@ const (
 	// Leader pods will have an annotation that determines what type of domain
 	// will be injected. Corresponds to LeaderWorkerSet.Spec.NetworkConfig.SubdomainPolicy
 	SubdomainPolicyAnnotationKey string = "leaderworkerset.sigs.k8s.io/subdomainPolicy"
+
+	// Groups per minute annotation is used to stagger the scale-up of a
+	// LeaderWorkerSet, no more than the specified number of new groups will
+	// be created within any one minute window. Scale-down is not affected.
+	GroupsPerMinuteAnnotationKey string = "leaderworkerset.sigs.k8s.io/groups-per-minute"
+
+	// Disable pod injection annotation opts a LeaderWorkerSet out of the injection of
+	// the environment variables, the exclusive placement affinities and the topology
+	// file into its pods by the pod webhook, for tenants managing the mutation of their
+	// pods themselves. It can only be set when the LeaderWorkerSet is created, and not
+	// in its pod templates.
+	DisablePodInjectionAnnotationKey string = "leaderworkerset.sigs.k8s.io/disable-pod-injection"
+
+	// Group resource overrides annotation carries the GroupResourceOverrides of the
+	// LeaderWorkerSet, serialized in JSON, to the leader pods. The leader pods share
+	// a single template, so the pod webhook applies the overrides targeting the group
+	// of the leader pod when it is created.
+	GroupResourceOverridesAnnotationKey string = "leaderworkerset.sigs.k8s.io/group-resource-overrides"
+
+	// Injected container resources annotation overrides the resources of the containers
+	// injected into the pods of a LeaderWorkerSet, i.e. the topology file init container,
+	// with resource requirements serialized in JSON. The controller configuration sets
+	// them otherwise. It can only be set when the LeaderWorkerSet is created: the resources
+	// are only applied when the pods are created, and a change would recreate all the groups
+	// at once instead of rolling them out.
+	InjectedContainerResourcesAnnotationKey string = "leaderworkerset.sigs.k8s.io/injected-container-resources"
+
+	// Queue name annotation names the queue the groups of a LeaderWorkerSet are submitted
+	// to, it is recorded in the gang annotation of its pods. It can only be set when the
+	// LeaderWorkerSet is created.
+	QueueNameAnnotationKey string = "leaderworkerset.sigs.k8s.io/queue-name"
+
+	// Active deadline seconds annotation carries the ActiveDeadlineSeconds of the
+	// LeaderWorkerSet to the pods. The StatefulSets reject pod templates setting
+	// activeDeadlineSeconds, so the pod webhook sets it on each pod when it is created.
+	ActiveDeadlineSecondsAnnotationKey string = "leaderworkerset.sigs.k8s.io/active-deadline-seconds"
+
+	// Gang annotation is set by the pod webhook to record the gang of the group of a pod,
+	// serialized in JSON, with the number of pods of the group and the queue of the
+	// LeaderWorkerSet if any, e.g. {"size":4,"queue":"team-a"}. The groups aren't gang
+	// scheduled by LWS, the annotation lets the external schedulers and dashboards reason
+	// about them.
+	GangAnnotationKey string = "leaderworkerset.sigs.k8s.io/gang"
+
+	// Drain timeout annotation enables the draining of the groups removed on scale-down.
+	// The leader pods of these groups are annotated with the drain started annotation
+	// first, and the groups are only deleted once their leader pod is no longer ready
+	// or the specified number of seconds elapsed.
+	DrainTimeoutAnnotationKey string = "leaderworkerset.sigs.k8s.io/drain-timeout-seconds"
+
+	// Drain started annotation is set on the leader pod of a group being drained before
+	// its deletion, with the RFC 3339 time the drain started at. Serving stacks can watch
+	// it, e.g. through the downward API, to stop accepting new requests.
+	DrainStartedAnnotationKey string = "leaderworkerset.sigs.k8s.io/drain-started"
+
+	// Sticky groups annotation lists the comma-separated indices of the groups held at
+	// their revision during a rolling update, e.g. "0,3". The groups are held by the
+	// partition of the leader statefulset, which updates the groups from the highest index
+	// down, so that the outdated groups of lower indices are held as well. They keep running,
+	// and are recreated at their revision, until the annotation no longer lists them.
+	StickyGroupsAnnotationKey string = "leaderworkerset.sigs.k8s.io/sticky-groups"
+
+	// Group readiness gate annotation adds a readiness gate to the leader pods of a
+	// LeaderWorkerSet, the leader pods are then only Ready, and only ready endpoints of
+	// the Services selecting them, once the workers of their group are Ready as well.
+	// It requires the LeaderCreated startup policy, since the workers of the other ones
+	// wait for the leader to be Ready, and it can only be set when the LeaderWorkerSet
+	// is created.
+	GroupReadinessGateAnnotationKey string = "leaderworkerset.sigs.k8s.io/group-readiness-gate"
+
+	// Coordinator group label is added with the "true" value to the leader and worker
+	// pods of the group 0 when LeaderWorkerSet.Spec.LeaderWorkerTemplate.CoordinatorTemplate
+	// is set.
+	CoordinatorGroupLabelKey string = "leaderworkerset.sigs.k8s.io/coordinator-group"
+
+	// Coordinator template annotation carries the CoordinatorTemplate of the
+	// LeaderWorkerSet, serialized in JSON, to the leader pods. The leader pods share
+	// a single template, so the pod webhook applies it to the leader pod of the group 0
+	// when it is created.
+	CoordinatorTemplateAnnotationKey string = "leaderworkerset.sigs.k8s.io/coordinator-template"
+
+	// Environment variable added with the "true" value to all containers of the
+	// pods of the coordinator group.
+	LwsCoordinatorGroup string = "LWS_COORDINATOR_GROUP"
+
+	// Inject workload identity annotation adds the LWS_UID, LWS_NAME and LWS_NAMESPACE
+	// environment variables to all containers of the pods of a LeaderWorkerSet, for the
+	// applications building idempotency keys from a stable workload identity. It can
+	// only be set when the LeaderWorkerSet is created.
+	InjectWorkloadIdentityAnnotationKey string = "leaderworkerset.sigs.k8s.io/inject-workload-identity"
+
+	// Leaderless annotation makes the pods of each group identical leader candidates for
+	// the frameworks electing their own leader: the leader pods are created from the worker
+	// template, no pod gets the LWS_LEADER_ADDRESS, and the group is Ready once a majority of
+	// its pods are Ready. Losing the first candidate doesn't delete the others, and the
+	// RecreateGroupOnPodRestart restart policy only recreates the group once a majority of its
+	// pods aren't Ready. The pods are labeled with the candidate label, the group is exposed
+	// through its own headless service, so it requires the UniquePerReplica subdomain policy
+	// and the LeaderCreated startup policy. It can only be set when the LeaderWorkerSet is
+	// created.
+	LeaderlessAnnotationKey string = "leaderworkerset.sigs.k8s.io/leaderless"
+
+	// Candidate label is added with the "true" value to all the pods of the groups of a
+	// LeaderWorkerSet with the leaderless annotation.
+	CandidateLabelKey string = "leaderworkerset.sigs.k8s.io/candidate"
+
+	// Environment variable added to all containers of the pods of a leaderless LeaderWorkerSet,
+	// instead of LWS_LEADER_ADDRESS, with the address of the headless service of the group,
+	// resolving to the addresses of all the candidates.
+	LwsGroupAddress string = "LWS_GROUP_ADDRESS"
+
+	// Workload UID annotation carries the UID of the LeaderWorkerSet to its pods when
+	// the inject workload identity annotation is set.
+	WorkloadUIDAnnotationKey string = "leaderworkerset.sigs.k8s.io/uid"
+
+	// Environment variables added to all containers of the pods of a LeaderWorkerSet
+	// with the inject workload identity annotation, with the UID, the name and the
+	// namespace of the LeaderWorkerSet respectively.
+	LwsUID       string = "LWS_UID"
+	LwsName      string = "LWS_NAME"
+	LwsNamespace string = "LWS_NAMESPACE"
 )
 
+// GroupReadyPodConditionType is the condition of the readiness gate of the leader pods of the
+// LeaderWorkerSets with the group readiness gate annotation, it is set by the controller to
+// whether the workers of the group are Ready.
+const GroupReadyPodConditionType corev1.PodConditionType = "leaderworkerset.sigs.k8s.io/group-ready"
+
 // One group consists of a single leader and M workers, and the total number of pods in a group is M+1.
 // LeaderWorkerSet will create N replicas of leader-worker pod groups (hereinafter referred to as group).
 //
@@ -110,10 +236,12 @@ type LeaderWorkerSetSpec struct {
 	// the rest of the group and expose them as a summary custom metric representing the whole
 	// group.
 	// On scale down, the leader pod as well as the workers statefulset will be deleted.
+	// Scaling to zero deletes all the groups and keeps the LeaderWorkerSet.
 	// Default to 1.
 	//
 	// +optional
 	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
 	Replicas *int32 `json:"replicas,omitempty"`
 
 	// LeaderWorkerTemplate defines the template for leader/worker pods
@@ -125,14 +253,20 @@ type LeaderWorkerSetSpec struct {
 	RolloutStrategy RolloutStrategy `json:"rolloutStrategy,omitempty"`
 
 	// StartupPolicy determines the startup policy for the worker statefulset.
-	// +kubebuilder:default=LeaderCreated
-	// +kubebuilder:validation:Enum={LeaderCreated,LeaderReady}
+	// Defaults to the defaultStartupPolicy of the controller configuration, LeaderCreated
+	// if not set.
+	// +kubebuilder:validation:Enum={LeaderCreated,LeaderReady,AllLeadersReady}
 	// +optional
 	StartupPolicy StartupPolicyType `json:"startupPolicy"`
 
 	// NetworkConfig defines the network configuration of the group
 	// +optional
 	NetworkConfig *NetworkConfig `json:"networkConfig,omitempty"`
+
+	// GroupReadinessPolicy defines when a group is considered Ready.
+	// When not set, a group is Ready once the leader and all the workers are Ready.
+	// +optional
+	GroupReadinessPolicy *GroupReadinessPolicy `json:"groupReadinessPolicy,omitempty"`
 }
 
 // Template of the leader/worker pods, the group will include at least one leader pod.
@@ -169,27 +303,87 @@ type LeaderWorkerTemplate struct {
 	// in each replica.
 	// +optional
 	SubGroupPolicy *SubGroupPolicy `json:"subGroupPolicy,omitempty"`
+
+	// ActiveDeadlineSeconds is the duration in seconds that each pod of a group
+	// may be active before the kubelet terminates it, it is set on both the leader
+	// and worker pods by the pod webhook when they are created. Once a pod exceeds
+	// the deadline, the RestartPolicy decides whether the whole group is recreated.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+
+	// GroupResourceOverrides override the resources of the containers of the pods
+	// of specific groups, e.g. to give the group 0 larger containers than the other
+	// groups. A group index must not be targeted by more than one override.
+	// +listType=atomic
+	// +optional
+	GroupResourceOverrides []GroupResourceOverride `json:"groupResourceOverrides,omitempty"`
+
+	// CoordinatorTemplate defines the pod template of both the leader and worker pods
+	// of the group 0, the coordinator group, for the architectures where one group runs
+	// with a different configuration than the others. The other groups keep using the
+	// leader and worker templates.
+	// +optional
+	CoordinatorTemplate *corev1.PodTemplateSpec `json:"coordinatorTemplate,omitempty"`
+}
+
+// GroupResourceOverride overrides the resources of containers of the leader and
+// worker pods of the groups with the given indices.
+type GroupResourceOverride struct {
+	// GroupIndices are the indices of the groups whose pods are overridden.
+	// +listType=set
+	// +kubebuilder:validation:MinItems=1
+	GroupIndices []int32 `json:"groupIndices"`
+
+	// Containers are the resources of the containers to override, matched by name
+	// in both the leader and worker templates.
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MinItems=1
+	Containers []ContainerResourceOverride `json:"containers"`
+}
+
+// ContainerResourceOverride overrides the resources of a container. The requests
+// and limits are merged into the ones of the template, a resource present in both
+// takes the value of the override.
+type ContainerResourceOverride struct {
+	// Name of the container, it must match a container of the leader or worker template.
+	Name string `json:"name"`
+
+	// Resources are the requests and limits overriding the ones of the container.
+	Resources corev1.ResourceRequirements `json:"resources"`
 }
 
 // RolloutStrategy defines the strategy that the leaderWorkerSet controller
 // will use to perform replica updates.
 type RolloutStrategy struct {
-	// Type defines the rollout strategy, it can only be “RollingUpdate” for now.
+	// Type defines the rollout strategy, it can be “RollingUpdate” or “Recreate”.
 	//
-	// +kubebuilder:validation:Enum={RollingUpdate}
+	// +kubebuilder:validation:Enum={RollingUpdate,Recreate}
 	// +kubebuilder:default=RollingUpdate
 	Type RolloutStrategyType `json:"type"`
 
 	// RollingUpdateConfiguration defines the parameters to be used when type is RollingUpdateStrategyType.
+	// It must not be set when type is RecreateStrategyType.
 	// +optional
 	RollingUpdateConfiguration *RollingUpdateConfiguration `json:"rollingUpdateConfiguration,omitempty"`
+
+	// MetadataUpdatePolicy defines how the groups are updated when an update of the
+	// leaderWorkerTemplate only changes the labels and annotations of the leader and worker
+	// templates, it can be “Recreate” or “InPlace”. With InPlace, the labels and annotations
+	// of the pods of the existing groups are updated without recreating them.
+	// Defaults to Recreate.
+	//
+	// +kubebuilder:validation:Enum={Recreate,InPlace}
+	// +optional
+	MetadataUpdatePolicy MetadataUpdatePolicyType `json:"metadataUpdatePolicy,omitempty"`
 }
 
 // SubGroupPolicy describes the policy that will be applied when creating subgroups.
 type SubGroupPolicy struct {
 
 	// Defines what type of Subgroups to create. Defaults to
-	// LeaderWorker
+	// LeaderWorker. This value is immutable.
 	//
 	// +kubebuilder:validation:Enum={LeaderWorker,LeaderExcluded}
 	// +kubebuilder:default=LeaderWorker
@@ -246,6 +440,26 @@ const (
 	SubdomainUniquePerReplica SubdomainPolicy = "UniquePerReplica"
 )
 
+// GroupReadinessPolicy defines when a group is considered Ready.
+type GroupReadinessPolicy struct {
+	// MinReadyWorkers is the minimum number of Ready workers for the group to be
+	// considered Ready, in addition to the leader. It allows serving stacks usable
+	// with a quorum of workers to count the group as Ready while some workers are
+	// missing. It must not be greater than LeaderWorkerSet.Spec.LeaderWorkerTemplate.Size - 1.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinReadyWorkers *int32 `json:"minReadyWorkers,omitempty"`
+
+	// CoordinationOnlyLeader indicates the leader pods only coordinate the workers and
+	// don't serve. The group is then considered Ready once its leader pod is Running and
+	// its workers are Ready, whether or not the leader pod is Ready, so that the leader
+	// can be kept out of the endpoints of the Services routing the traffic. It requires
+	// the groups to have workers, and minReadyWorkers to be positive if set. The LeaderReady
+	// and AllLeadersReady startup policies also only wait for the leader pods to be Running.
+	// +optional
+	CoordinationOnlyLeader bool `json:"coordinationOnlyLeader,omitempty"`
+}
+
 // RollingUpdateConfiguration defines the parameters to be used for RollingUpdateStrategyType.
 type RollingUpdateConfiguration struct {
 	// The maximum number of replicas that can be unavailable during the update.
@@ -287,6 +501,24 @@ const (
 	// by RollingUpdateConfiguration), the latter one will not start the update until the
 	// former one(leader+workers) is ready.
 	RollingUpdateStrategyType RolloutStrategyType = "RollingUpdate"
+
+	// RecreateStrategyType indicates that all the replicas will be deleted before
+	// being recreated with the updated template, replicas never run mixed revisions
+	// at the cost of a downtime window.
+	RecreateStrategyType RolloutStrategyType = "Recreate"
+)
+
+type MetadataUpdatePolicyType string
+
+const (
+	// RecreateMetadataUpdatePolicy rolls out the updates only changing the labels and
+	// annotations of the templates like any other update, recreating the groups.
+	RecreateMetadataUpdatePolicy MetadataUpdatePolicyType = "Recreate"
+
+	// InPlaceMetadataUpdatePolicy updates the labels and annotations of the pods of the
+	// existing groups in place, the groups are only recreated by the updates changing
+	// anything else.
+	InPlaceMetadataUpdatePolicy MetadataUpdatePolicyType = "InPlace"
 )
 
 type RestartPolicyType string
@@ -317,6 +549,13 @@ const (
 
 	// LeaderCreated creates the workers statefulset immediately after the leader pod is created.
 	LeaderCreatedStartupPolicy StartupPolicyType = "LeaderCreated"
+
+	// AllLeadersReady creates the workers statefulsets of all the groups once the leader pods
+	// of all the groups are ready, for the topologies spanning several groups. It requires the
+	// AllLeadersReadyStartupPolicy feature gate, without which the LeaderWorkerSets using it are
+	// rejected on admission, and the existing ones behave like LeaderReady and must move to
+	// another policy to be updated.
+	AllLeadersReadyStartupPolicy StartupPolicyType = "AllLeadersReady"
 )
 
 // LeaderWorkerSetStatus defines the observed state of LeaderWorkerSet
@@ -325,18 +564,104 @@ type LeaderWorkerSetStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
 	// ReadyReplicas track the number of groups that are in ready state (updated or not).
-	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+	// The replica counts are always reported, even when 0, since they back the printer columns.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas"`
 
 	// UpdatedReplicas track the number of groups that have been updated (ready or not).
-	UpdatedReplicas int32 `json:"updatedReplicas,omitempty"`
+	// +optional
+	UpdatedReplicas int32 `json:"updatedReplicas"`
+
+	// HeldReplicas track the number of groups held at an outdated revision by the sticky-groups
+	// annotation, i.e. the listed groups and the outdated groups of lower indices.
+	// +optional
+	HeldReplicas int32 `json:"heldReplicas,omitempty"`
 
 	// Replicas track the total number of groups that have been created (updated or not, ready or not)
-	Replicas int32 `json:"replicas,omitempty"`
+	// +optional
+	Replicas int32 `json:"replicas"`
 
 	// HPAPodSelector for pods that belong to the LeaderWorkerSet object, this is
 	// needed for HPA to know what pods belong to the LeaderWorkerSet object. Here
 	// we only select the leader pods.
 	HPAPodSelector string `json:"hpaPodSelector,omitempty"`
+
+	// Groups summarizes the pod conditions of each group, sorted by group index,
+	// so that users can see why a group isn't ready without listing its pods.
+	// Only the first 100 groups are reported.
+	// +kubebuilder:validation:MaxItems=100
+	// +listType=map
+	// +listMapKey=index
+	// +optional
+	Groups []GroupStatus `json:"groups,omitempty"`
+
+	// CurrentLeaderPods lists the names of the leader pods of the groups, sorted by group index,
+	// so that users can find the leader of a group without listing its pods. The terminating
+	// leader pods aren't listed. Only the leaders of the first 100 groups are reported.
+	// +kubebuilder:validation:MaxItems=100
+	// +optional
+	CurrentLeaderPods []string `json:"currentLeaderPods,omitempty"`
+
+	// UpdateRevision is the revision key of the latest revision of the templates, as
+	// set in the leaderworkerset.sigs.k8s.io/template-revision-hash label of the pods.
+	// +optional
+	UpdateRevision string `json:"updateRevision,omitempty"`
+
+	// CurrentRevision is the revision key of the last revision rolled out to all the
+	// groups. It is set to the UpdateRevision once all the groups are updated and ready.
+	// +optional
+	CurrentRevision string `json:"currentRevision,omitempty"`
+
+	// SubGroups is the number of subgroups across the created groups, only reported
+	// when a subGroupPolicy is set.
+	// +optional
+	SubGroups int32 `json:"subGroups,omitempty"`
+
+	// ReadySubGroups is the number of subgroups, across the created groups, whose pods
+	// all exist and are ready, so that partial subgroup failures can be told apart.
+	// +optional
+	ReadySubGroups int32 `json:"readySubGroups,omitempty"`
+
+	// ObservedGeneration is the generation of the LeaderWorkerSet last reconciled by the
+	// controller. A generation bump which doesn't change the templates, e.g. re-applying the
+	// same spec, only updates it, without recreating any pod.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// GroupStatus summarizes the pod conditions of a group.
+type GroupStatus struct {
+	// Index is the index of the group.
+	Index int32 `json:"index"`
+
+	// Pods is the number of pods of the group observed by the controller.
+	Pods int32 `json:"pods"`
+
+	// Conditions summarizes the pod conditions of the group, with one entry
+	// per pod condition type.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []GroupPodCondition `json:"conditions,omitempty"`
+}
+
+// GroupPodCondition summarizes a pod condition type across the pods of a group.
+type GroupPodCondition struct {
+	// Type is the type of the pod condition.
+	Type corev1.PodConditionType `json:"type"`
+
+	// TruePods is the number of pods of the group with the condition set to True.
+	TruePods int32 `json:"truePods"`
+
+	// Reason is the reason of the condition of the first pod of the group, by
+	// worker index, whose condition isn't True.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is the message of the condition of the first pod of the group, by
+	// worker index, whose condition isn't True, prefixed with the pod name.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 type LeaderWorkerSetConditionType string
@@ -358,6 +683,22 @@ const (
 	// is true when the lws is in upgrade process after the (leader/worker) template is updated. If only replicas is modified, it will
 	// not be considered as UpdateInProgress.
 	LeaderWorkerSetUpdateInProgress LeaderWorkerSetConditionType = "UpdateInProgress"
+
+	// LeaderWorkerSetPending means some groups of the lws are not created because the
+	// pods managed by the controller reached the configured maxTotalManagedPods. The
+	// groups are created once enough pods are deleted.
+	LeaderWorkerSetPending LeaderWorkerSetConditionType = "Pending"
+
+	// LeaderWorkerSetWaitingForLeader means some groups of a lws using the LeaderReady or
+	// AllLeadersReady startup policy are waiting for their leader pod, or the leader pods of
+	// all the groups, to be ready before creating their workers. The message reports the
+	// number of affected groups.
+	LeaderWorkerSetWaitingForLeader LeaderWorkerSetConditionType = "WaitingForLeader"
+
+	// LeaderWorkerSetRolloutStalled means the rolling update is paused because groups of the
+	// new revision are crash-looping, see the crashLoopDetection controller configuration.
+	// The rollout resumes once the crash-looping groups recover or the lws is updated again.
+	LeaderWorkerSetRolloutStalled LeaderWorkerSetConditionType = "RolloutStalled"
 )
 
 // +genclient
@@ -365,6 +706,10 @@ const (
 //+kubebuilder:subresource:status
 //+kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.hpaPodSelector
 //+kubebuilder:resource:shortName={lws}
+//+kubebuilder:printcolumn:name="Desired",type=integer,JSONPath=`.spec.replicas`,description="The desired number of groups"
+//+kubebuilder:printcolumn:name="Ready",type=integer,JSONPath=`.status.readyReplicas`,description="The number of ready groups"
+//+kubebuilder:printcolumn:name="Updated",type=integer,JSONPath=`.status.updatedReplicas`,description="The number of groups updated to the latest revision"
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // LeaderWorkerSet is the Schema for the leaderworkersets API
 type LeaderWorkerSet struct {
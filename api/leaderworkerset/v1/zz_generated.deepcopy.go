@@ -26,6 +26,104 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerResourceOverride) DeepCopyInto(out *ContainerResourceOverride) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerResourceOverride.
+func (in *ContainerResourceOverride) DeepCopy() *ContainerResourceOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerResourceOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupPodCondition) DeepCopyInto(out *GroupPodCondition) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupPodCondition.
+func (in *GroupPodCondition) DeepCopy() *GroupPodCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupPodCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupReadinessPolicy) DeepCopyInto(out *GroupReadinessPolicy) {
+	*out = *in
+	if in.MinReadyWorkers != nil {
+		in, out := &in.MinReadyWorkers, &out.MinReadyWorkers
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupReadinessPolicy.
+func (in *GroupReadinessPolicy) DeepCopy() *GroupReadinessPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupReadinessPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupResourceOverride) DeepCopyInto(out *GroupResourceOverride) {
+	*out = *in
+	if in.GroupIndices != nil {
+		in, out := &in.GroupIndices, &out.GroupIndices
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]ContainerResourceOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupResourceOverride.
+func (in *GroupResourceOverride) DeepCopy() *GroupResourceOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupResourceOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupStatus) DeepCopyInto(out *GroupStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]GroupPodCondition, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupStatus.
+func (in *GroupStatus) DeepCopy() *GroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LeaderWorkerSet) DeepCopyInto(out *LeaderWorkerSet) {
 	*out = *in
@@ -100,6 +198,11 @@ func (in *LeaderWorkerSetSpec) DeepCopyInto(out *LeaderWorkerSetSpec) {
 		*out = new(NetworkConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GroupReadinessPolicy != nil {
+		in, out := &in.GroupReadinessPolicy, &out.GroupReadinessPolicy
+		*out = new(GroupReadinessPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LeaderWorkerSetSpec.
@@ -122,6 +225,18 @@ func (in *LeaderWorkerSetStatus) DeepCopyInto(out *LeaderWorkerSetStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]GroupStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CurrentLeaderPods != nil {
+		in, out := &in.CurrentLeaderPods, &out.CurrentLeaderPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LeaderWorkerSetStatus.
@@ -153,6 +268,23 @@ func (in *LeaderWorkerTemplate) DeepCopyInto(out *LeaderWorkerTemplate) {
 		*out = new(SubGroupPolicy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.GroupResourceOverrides != nil {
+		in, out := &in.GroupResourceOverrides, &out.GroupResourceOverrides
+		*out = make([]GroupResourceOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CoordinatorTemplate != nil {
+		in, out := &in.CoordinatorTemplate, &out.CoordinatorTemplate
+		*out = new(corev1.PodTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LeaderWorkerTemplate.
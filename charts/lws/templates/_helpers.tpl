@@ -74,3 +74,123 @@ Create the name of the service account to use
 {{- default "default" .Values.serviceAccount.name }}
 {{- end }}
 {{- end }}
+
+{{/*
+Rules of the manager role on the namespaced resources, granted cluster-wide, or in each of the
+watchNamespaces when set.
+*/}}
+{{- define "lws.managerNamespacedRules" -}}
+- apiGroups:
+    - ""
+  resources:
+    - events
+  verbs:
+    - create
+    - get
+    - list
+    - patch
+    - update
+    - watch
+{{- if .Values.watchPersistentVolumeClaims }}
+- apiGroups:
+    - ""
+  resources:
+    - persistentvolumeclaims
+  verbs:
+    - get
+    - list
+    - watch
+{{- end }}
+- apiGroups:
+    - ""
+  resources:
+    - services
+  verbs:
+    - create
+    - delete
+    - get
+    - list
+    - patch
+    - update
+    - watch
+- apiGroups:
+    - ""
+  resources:
+    - pods
+  verbs:
+    - delete
+    - get
+    - list
+    - patch
+    - update
+    - watch
+- apiGroups:
+    - ""
+  resources:
+    - pods/finalizers
+  verbs:
+    - update
+- apiGroups:
+    - ""
+  resources:
+    - pods/status
+  verbs:
+    - get
+    - patch
+    - update
+- apiGroups:
+    - apps
+  resources:
+    - controllerrevisions
+    - statefulsets
+  verbs:
+    - create
+    - delete
+    - get
+    - list
+    - patch
+    - update
+    - watch
+- apiGroups:
+    - apps
+  resources:
+    - controllerrevisions/finalizers
+    - statefulsets/finalizers
+  verbs:
+    - update
+- apiGroups:
+    - apps
+  resources:
+    - controllerrevisions/status
+    - statefulsets/status
+  verbs:
+    - get
+    - patch
+    - update
+- apiGroups:
+    - leaderworkerset.x-k8s.io
+  resources:
+    - leaderworkersets
+  verbs:
+    - create
+    - delete
+    - get
+    - list
+    - patch
+    - update
+    - watch
+- apiGroups:
+    - leaderworkerset.x-k8s.io
+  resources:
+    - leaderworkersets/finalizers
+  verbs:
+    - update
+- apiGroups:
+    - leaderworkerset.x-k8s.io
+  resources:
+    - leaderworkersets/status
+  verbs:
+    - get
+    - patch
+    - update
+{{- end }}
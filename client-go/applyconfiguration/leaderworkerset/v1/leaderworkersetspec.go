@@ -29,6 +29,7 @@ type LeaderWorkerSetSpecApplyConfiguration struct {
 	RolloutStrategy      *RolloutStrategyApplyConfiguration      `json:"rolloutStrategy,omitempty"`
 	StartupPolicy        *leaderworkersetv1.StartupPolicyType    `json:"startupPolicy,omitempty"`
 	NetworkConfig        *NetworkConfigApplyConfiguration        `json:"networkConfig,omitempty"`
+	GroupReadinessPolicy *GroupReadinessPolicyApplyConfiguration `json:"groupReadinessPolicy,omitempty"`
 }
 
 // LeaderWorkerSetSpecApplyConfiguration constructs a declarative configuration of the LeaderWorkerSetSpec type for use with
@@ -76,3 +77,11 @@ func (b *LeaderWorkerSetSpecApplyConfiguration) WithNetworkConfig(value *Network
 	b.NetworkConfig = value
 	return b
 }
+
+// WithGroupReadinessPolicy sets the GroupReadinessPolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the GroupReadinessPolicy field is set to the value of the last call.
+func (b *LeaderWorkerSetSpecApplyConfiguration) WithGroupReadinessPolicy(value *GroupReadinessPolicyApplyConfiguration) *LeaderWorkerSetSpecApplyConfiguration {
+	b.GroupReadinessPolicy = value
+	return b
+}
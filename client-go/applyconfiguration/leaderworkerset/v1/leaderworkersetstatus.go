@@ -24,11 +24,19 @@ import (
 // LeaderWorkerSetStatusApplyConfiguration represents a declarative configuration of the LeaderWorkerSetStatus type for use
 // with apply.
 type LeaderWorkerSetStatusApplyConfiguration struct {
-	Conditions      []metav1.ConditionApplyConfiguration `json:"conditions,omitempty"`
-	ReadyReplicas   *int32                               `json:"readyReplicas,omitempty"`
-	UpdatedReplicas *int32                               `json:"updatedReplicas,omitempty"`
-	Replicas        *int32                               `json:"replicas,omitempty"`
-	HPAPodSelector  *string                              `json:"hpaPodSelector,omitempty"`
+	Conditions         []metav1.ConditionApplyConfiguration `json:"conditions,omitempty"`
+	ReadyReplicas      *int32                               `json:"readyReplicas,omitempty"`
+	UpdatedReplicas    *int32                               `json:"updatedReplicas,omitempty"`
+	HeldReplicas       *int32                               `json:"heldReplicas,omitempty"`
+	Replicas           *int32                               `json:"replicas,omitempty"`
+	HPAPodSelector     *string                              `json:"hpaPodSelector,omitempty"`
+	Groups             []GroupStatusApplyConfiguration      `json:"groups,omitempty"`
+	CurrentLeaderPods  []string                             `json:"currentLeaderPods,omitempty"`
+	UpdateRevision     *string                              `json:"updateRevision,omitempty"`
+	CurrentRevision    *string                              `json:"currentRevision,omitempty"`
+	SubGroups          *int32                               `json:"subGroups,omitempty"`
+	ReadySubGroups     *int32                               `json:"readySubGroups,omitempty"`
+	ObservedGeneration *int64                               `json:"observedGeneration,omitempty"`
 }
 
 // LeaderWorkerSetStatusApplyConfiguration constructs a declarative configuration of the LeaderWorkerSetStatus type for use with
@@ -66,6 +74,14 @@ func (b *LeaderWorkerSetStatusApplyConfiguration) WithUpdatedReplicas(value int3
 	return b
 }
 
+// WithHeldReplicas sets the HeldReplicas field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the HeldReplicas field is set to the value of the last call.
+func (b *LeaderWorkerSetStatusApplyConfiguration) WithHeldReplicas(value int32) *LeaderWorkerSetStatusApplyConfiguration {
+	b.HeldReplicas = &value
+	return b
+}
+
 // WithReplicas sets the Replicas field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Replicas field is set to the value of the last call.
@@ -81,3 +97,66 @@ func (b *LeaderWorkerSetStatusApplyConfiguration) WithHPAPodSelector(value strin
 	b.HPAPodSelector = &value
 	return b
 }
+
+// WithGroups adds the given value to the Groups field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Groups field.
+func (b *LeaderWorkerSetStatusApplyConfiguration) WithGroups(values ...*GroupStatusApplyConfiguration) *LeaderWorkerSetStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithGroups")
+		}
+		b.Groups = append(b.Groups, *values[i])
+	}
+	return b
+}
+
+// WithCurrentLeaderPods adds the given value to the CurrentLeaderPods field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the CurrentLeaderPods field.
+func (b *LeaderWorkerSetStatusApplyConfiguration) WithCurrentLeaderPods(values ...string) *LeaderWorkerSetStatusApplyConfiguration {
+	for i := range values {
+		b.CurrentLeaderPods = append(b.CurrentLeaderPods, values[i])
+	}
+	return b
+}
+
+// WithUpdateRevision sets the UpdateRevision field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the UpdateRevision field is set to the value of the last call.
+func (b *LeaderWorkerSetStatusApplyConfiguration) WithUpdateRevision(value string) *LeaderWorkerSetStatusApplyConfiguration {
+	b.UpdateRevision = &value
+	return b
+}
+
+// WithCurrentRevision sets the CurrentRevision field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the CurrentRevision field is set to the value of the last call.
+func (b *LeaderWorkerSetStatusApplyConfiguration) WithCurrentRevision(value string) *LeaderWorkerSetStatusApplyConfiguration {
+	b.CurrentRevision = &value
+	return b
+}
+
+// WithSubGroups sets the SubGroups field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SubGroups field is set to the value of the last call.
+func (b *LeaderWorkerSetStatusApplyConfiguration) WithSubGroups(value int32) *LeaderWorkerSetStatusApplyConfiguration {
+	b.SubGroups = &value
+	return b
+}
+
+// WithReadySubGroups sets the ReadySubGroups field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ReadySubGroups field is set to the value of the last call.
+func (b *LeaderWorkerSetStatusApplyConfiguration) WithReadySubGroups(value int32) *LeaderWorkerSetStatusApplyConfiguration {
+	b.ReadySubGroups = &value
+	return b
+}
+
+// WithObservedGeneration sets the ObservedGeneration field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ObservedGeneration field is set to the value of the last call.
+func (b *LeaderWorkerSetStatusApplyConfiguration) WithObservedGeneration(value int64) *LeaderWorkerSetStatusApplyConfiguration {
+	b.ObservedGeneration = &value
+	return b
+}
@@ -25,11 +25,14 @@ import (
 // LeaderWorkerTemplateApplyConfiguration represents a declarative configuration of the LeaderWorkerTemplate type for use
 // with apply.
 type LeaderWorkerTemplateApplyConfiguration struct {
-	LeaderTemplate *corev1.PodTemplateSpecApplyConfiguration `json:"leaderTemplate,omitempty"`
-	WorkerTemplate *corev1.PodTemplateSpecApplyConfiguration `json:"workerTemplate,omitempty"`
-	Size           *int32                                    `json:"size,omitempty"`
-	RestartPolicy  *leaderworkersetv1.RestartPolicyType      `json:"restartPolicy,omitempty"`
-	SubGroupPolicy *SubGroupPolicyApplyConfiguration         `json:"subGroupPolicy,omitempty"`
+	LeaderTemplate         *corev1.PodTemplateSpecApplyConfiguration `json:"leaderTemplate,omitempty"`
+	WorkerTemplate         *corev1.PodTemplateSpecApplyConfiguration `json:"workerTemplate,omitempty"`
+	Size                   *int32                                    `json:"size,omitempty"`
+	RestartPolicy          *leaderworkersetv1.RestartPolicyType      `json:"restartPolicy,omitempty"`
+	SubGroupPolicy         *SubGroupPolicyApplyConfiguration         `json:"subGroupPolicy,omitempty"`
+	ActiveDeadlineSeconds  *int64                                    `json:"activeDeadlineSeconds,omitempty"`
+	GroupResourceOverrides []GroupResourceOverrideApplyConfiguration `json:"groupResourceOverrides,omitempty"`
+	CoordinatorTemplate    *corev1.PodTemplateSpecApplyConfiguration `json:"coordinatorTemplate,omitempty"`
 }
 
 // LeaderWorkerTemplateApplyConfiguration constructs a declarative configuration of the LeaderWorkerTemplate type for use with
@@ -77,3 +80,32 @@ func (b *LeaderWorkerTemplateApplyConfiguration) WithSubGroupPolicy(value *SubGr
 	b.SubGroupPolicy = value
 	return b
 }
+
+// WithActiveDeadlineSeconds sets the ActiveDeadlineSeconds field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ActiveDeadlineSeconds field is set to the value of the last call.
+func (b *LeaderWorkerTemplateApplyConfiguration) WithActiveDeadlineSeconds(value int64) *LeaderWorkerTemplateApplyConfiguration {
+	b.ActiveDeadlineSeconds = &value
+	return b
+}
+
+// WithGroupResourceOverrides adds the given value to the GroupResourceOverrides field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the GroupResourceOverrides field.
+func (b *LeaderWorkerTemplateApplyConfiguration) WithGroupResourceOverrides(values ...*GroupResourceOverrideApplyConfiguration) *LeaderWorkerTemplateApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithGroupResourceOverrides")
+		}
+		b.GroupResourceOverrides = append(b.GroupResourceOverrides, *values[i])
+	}
+	return b
+}
+
+// WithCoordinatorTemplate sets the CoordinatorTemplate field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the CoordinatorTemplate field is set to the value of the last call.
+func (b *LeaderWorkerTemplateApplyConfiguration) WithCoordinatorTemplate(value *corev1.PodTemplateSpecApplyConfiguration) *LeaderWorkerTemplateApplyConfiguration {
+	b.CoordinatorTemplate = value
+	return b
+}
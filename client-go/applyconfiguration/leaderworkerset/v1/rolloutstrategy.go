@@ -26,6 +26,7 @@ import (
 type RolloutStrategyApplyConfiguration struct {
 	Type                       *leaderworkersetv1.RolloutStrategyType        `json:"type,omitempty"`
 	RollingUpdateConfiguration *RollingUpdateConfigurationApplyConfiguration `json:"rollingUpdateConfiguration,omitempty"`
+	MetadataUpdatePolicy       *leaderworkersetv1.MetadataUpdatePolicyType   `json:"metadataUpdatePolicy,omitempty"`
 }
 
 // RolloutStrategyApplyConfiguration constructs a declarative configuration of the RolloutStrategy type for use with
@@ -49,3 +50,11 @@ func (b *RolloutStrategyApplyConfiguration) WithRollingUpdateConfiguration(value
 	b.RollingUpdateConfiguration = value
 	return b
 }
+
+// WithMetadataUpdatePolicy sets the MetadataUpdatePolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MetadataUpdatePolicy field is set to the value of the last call.
+func (b *RolloutStrategyApplyConfiguration) WithMetadataUpdatePolicy(value leaderworkersetv1.MetadataUpdatePolicyType) *RolloutStrategyApplyConfiguration {
+	b.MetadataUpdatePolicy = &value
+	return b
+}
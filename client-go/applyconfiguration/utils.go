@@ -31,6 +31,16 @@ import (
 func ForKind(kind schema.GroupVersionKind) interface{} {
 	switch kind {
 	// Group=leaderworkerset.x-k8s.io, Version=v1
+	case v1.SchemeGroupVersion.WithKind("ContainerResourceOverride"):
+		return &leaderworkersetv1.ContainerResourceOverrideApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("GroupPodCondition"):
+		return &leaderworkersetv1.GroupPodConditionApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("GroupReadinessPolicy"):
+		return &leaderworkersetv1.GroupReadinessPolicyApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("GroupResourceOverride"):
+		return &leaderworkersetv1.GroupResourceOverrideApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("GroupStatus"):
+		return &leaderworkersetv1.GroupStatusApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("LeaderWorkerSet"):
 		return &leaderworkersetv1.LeaderWorkerSetApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("LeaderWorkerSetSpec"):
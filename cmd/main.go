@@ -17,6 +17,7 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
 	"os"
@@ -26,21 +27,28 @@ import (
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/component-base/tracing"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
-	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
 	leaderworkersetv1 "sigs.k8s.io/lws/api/leaderworkerset/v1"
 	"sigs.k8s.io/lws/pkg/cert"
 	"sigs.k8s.io/lws/pkg/config"
 	"sigs.k8s.io/lws/pkg/controllers"
+	"sigs.k8s.io/lws/pkg/features"
+	"sigs.k8s.io/lws/pkg/metrics"
 	"sigs.k8s.io/lws/pkg/utils"
-	"sigs.k8s.io/lws/pkg/utils/useragent"
+	"sigs.k8s.io/lws/pkg/utils/health"
 	"sigs.k8s.io/lws/pkg/version"
 	"sigs.k8s.io/lws/pkg/webhooks"
 	//+kubebuilder:scaffold:imports
@@ -75,6 +83,9 @@ func main() {
 		leaderElectResourceLock  string
 		leaderElectionID         string
 		configFile               string
+		configStrictDecoding     bool
+		configReloadInterval     time.Duration
+		validateConfigFile       string
 	)
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8443", "DEPRECATED(please pass configuration file via --config flag): The address the metric endpoint binds to.")
@@ -103,6 +114,15 @@ func main() {
 		"The controller will load its initial configuration from this file. "+
 			"Command-line flags will override any configurations set in this file. "+
 			"Omit this flag to use the default configuration values.")
+	flag.BoolVar(&configStrictDecoding, "config-strict-decoding", true,
+		"Fail on the unknown and duplicate fields of the configuration file. "+
+			"When disabled, these fields are ignored with a warning, e.g. to tolerate the fields of a newer version.")
+	flag.DurationVar(&configReloadInterval, "config-reload-interval", 10*time.Second,
+		"How often the configuration file is checked for changes, the fields which can be changed without "+
+			"a restart are then applied to the running controller. Zero disables the reload.")
+	flag.StringVar(&validateConfigFile, "validate-config", "",
+		"Validate the configuration file at this path and exit without starting the manager, "+
+			"with a non-zero status if it is invalid.")
 
 	opts := zap.Options{
 		Development: true,
@@ -116,26 +136,59 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
-	options, cfg, err := apply(configFile, probeAddr, enableLeaderElection, leaderElectLeaseDuration, leaderElectRenewDeadline, leaderElectRetryPeriod, leaderElectResourceLock, leaderElectionID, metricsAddr)
+	if validateConfigFile != "" {
+		if err := config.ValidateFile(scheme, validateConfigFile); err != nil {
+			setupLog.Error(err, "invalid configuration", "file", validateConfigFile)
+			os.Exit(1)
+		}
+		setupLog.Info("valid configuration", "file", validateConfigFile)
+		os.Exit(0)
+	}
+
+	result, err := apply(configFile, configStrictDecoding, probeAddr, enableLeaderElection, leaderElectLeaseDuration, leaderElectRenewDeadline, leaderElectRetryPeriod, leaderElectResourceLock, leaderElectionID, metricsAddr)
+	options, cfg := result.Options, result.Configuration
 	if err != nil {
 		setupLog.Error(err, "unable to load the configuration")
 		os.Exit(1)
 	}
 
+	if err := features.DefaultMutableFeatureGate.SetFromMap(cfg.FeatureGates); err != nil {
+		setupLog.Error(err, "unable to set the feature gates")
+		os.Exit(1)
+	}
+
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err := config.ValidateWebhookCertDir(&cfg); err != nil {
+			setupLog.Error(err, "invalid webhook configuration")
+			os.Exit(1)
+		}
+		if err := config.ValidateWebhookCert(&cfg, time.Now()); err != nil {
+			setupLog.Error(err, "invalid webhook certificate")
+			os.Exit(1)
+		}
+	}
+
+	// The provider is a noop unless the tracing is configured.
+	tracerProvider, err := tracing.NewProvider(context.Background(), cfg.Tracing, nil, []resource.Option{
+		resource.WithAttributes(semconv.ServiceNameKey.String("lws-controller-manager")),
+	})
+	if err != nil {
+		setupLog.Error(err, "unable to set up the tracing")
+		os.Exit(1)
+	}
+	otel.SetTracerProvider(tracerProvider)
+
 	kubeConfig := ctrl.GetConfigOrDie()
 
-	kubeConfig.QPS = *cfg.ClientConnection.QPS
+	var clientConnectionFlags configapi.ClientConnection
 	if flagsSet["kube-api-qps"] {
-		kubeConfig.QPS = float32(qps)
+		clientConnectionFlags.QPS = ptr.To(float32(qps))
 	}
-	kubeConfig.Burst = int(*cfg.ClientConnection.Burst)
 	if flagsSet["kube-api-burst"] {
-		kubeConfig.Burst = burst
-	}
-	if kubeConfig.UserAgent == "" {
-		kubeConfig.UserAgent = useragent.Default()
+		clientConnectionFlags.Burst = ptr.To(int32(burst))
 	}
-	setupLog.Info("Initializing", "gitVersion", version.GitVersion, "gitCommit", version.GitCommit, "userAgent", kubeConfig.UserAgent)
+	config.ApplyClientConnection(kubeConfig, result.ClientConnection(clientConnectionFlags))
+	setupLog.Info("Initializing", "gitVersion", version.GitVersion, "gitCommit", version.GitCommit, "userAgent", kubeConfig.UserAgent, "qps", kubeConfig.QPS, "burst", kubeConfig.Burst)
 
 	mgr, err := ctrl.NewManager(kubeConfig, options)
 	if err != nil {
@@ -145,7 +198,7 @@ func main() {
 
 	certsReady := make(chan struct{})
 	if cfg.InternalCertManagement != nil && *cfg.InternalCertManagement.Enable {
-		if err = cert.CertsManager(mgr, options.LeaderElectionNamespace, *cfg.InternalCertManagement.WebhookServiceName, *cfg.InternalCertManagement.WebhookSecretName, cfg.Webhook.CertDir, certsReady); err != nil {
+		if err = cert.CertsManager(mgr, utils.GetOperatorNamespace(), *cfg.InternalCertManagement.WebhookServiceName, *cfg.InternalCertManagement.WebhookSecretName, cfg.Webhook.CertDir, certsReady); err != nil {
 			setupLog.Error(err, "unable to setup cert rotation")
 			os.Exit(1)
 		}
@@ -153,59 +206,99 @@ func main() {
 		close(certsReady)
 	}
 
+	metrics.Register()
+	if err := mgr.Add(metrics.NewLeaderElectionTracker()); err != nil {
+		setupLog.Error(err, "unable to setup leader election metrics")
+		os.Exit(1)
+	}
+	// The managed objects gauges are only refreshed while they are served.
+	if options.Metrics.BindAddress != config.DisabledMetricsBindAddress {
+		if err := mgr.Add(metrics.NewManagedObjectsCollector(mgr.GetClient())); err != nil {
+			setupLog.Error(err, "unable to setup managed objects metrics")
+			os.Exit(1)
+		}
+	}
+
 	if err := controllers.SetupIndexes(mgr.GetFieldIndexer()); err != nil {
 		setupLog.Error(err, "unable to setup indexes")
 	}
 
+	var configWatcher *config.Watcher
+	if configFile != "" && configReloadInterval > 0 {
+		configWatcher = config.NewWatcher(scheme, configFile, configStrictDecoding, cfg, configReloadInterval)
+		if err := mgr.Add(configWatcher); err != nil {
+			setupLog.Error(err, "unable to setup the configuration reload")
+			os.Exit(1)
+		}
+	}
+
 	// Cert won't be ready until manager starts, so start a goroutine here which
 	// will block until the cert is ready before setting up the controllers.
 	// Controllers who register after manager starts will start directly.
-	go setupControllers(mgr, certsReady)
+	go setupControllers(mgr, &cfg, configWatcher, certsReady)
 
-	setupHealthzAndReadyzCheck(mgr)
+	setupHealthzAndReadyzCheck(mgr, &cfg, certsReady)
 	setupLog.Info("starting manager")
 
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+	// Export the spans still batched.
+	if err := tracerProvider.Shutdown(context.Background()); err != nil {
+		setupLog.Error(err, "unable to flush the traces")
+	}
 
 }
-func setupControllers(mgr ctrl.Manager, certsReady chan struct{}) {
+func setupControllers(mgr ctrl.Manager, cfg *configapi.Configuration, configWatcher *config.Watcher, certsReady chan struct{}) {
 	// The controllers won't work until the webhooks are operating,
 	// and the webhook won't work until the certs are all in places.
 	setupLog.Info("waiting for the cert generation to complete")
 	<-certsReady
 	setupLog.Info("certs ready")
 
-	if err := controllers.NewLeaderWorkerSetReconciler(
+	// The reloaded configurations are applied to the controllers and webhooks, if enabled.
+	onReload := func(func(*configapi.Configuration)) {}
+	if configWatcher != nil {
+		onReload = configWatcher.OnReload
+	}
+
+	lwsController := controllers.NewLeaderWorkerSetReconciler(
 		mgr.GetClient(),
 		mgr.GetScheme(),
 		mgr.GetEventRecorderFor("leaderworkerset"),
-	).SetupWithManager(mgr); err != nil {
+		cfg,
+	)
+	if err := lwsController.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "LeaderWorkerSet")
 		os.Exit(1)
 	}
+	onReload(lwsController.ApplyConfiguration)
 	// Set up pod reconciler.
-	podController := controllers.NewPodReconciler(mgr.GetClient(), mgr.GetScheme(), mgr.GetEventRecorderFor("leaderworkerset"))
+	podController := controllers.NewPodReconciler(mgr.GetClient(), mgr.GetScheme(), mgr.GetEventRecorderFor("leaderworkerset"), cfg)
 	if err := podController.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Pod")
 		os.Exit(1)
 	}
+	onReload(podController.ApplyConfiguration)
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
-		if err := webhooks.SetupLeaderWorkerSetWebhook(mgr); err != nil {
+		lwsWebhook, err := webhooks.SetupLeaderWorkerSetWebhook(mgr, cfg)
+		if err != nil {
 			setupLog.Error(err, "unable to create leaderworkerset webhook", "webhook", "LeaderWorkerSet")
 			os.Exit(1)
 		}
-		if err := webhooks.SetupPodWebhook(mgr); err != nil {
+		onReload(lwsWebhook.ApplyConfiguration)
+		podWebhook, err := webhooks.SetupPodWebhook(mgr, cfg)
+		if err != nil {
 			setupLog.Error(err, "unable to create pod webhook", "webhook", "LeaderWorkerSet")
 			os.Exit(1)
 		}
+		onReload(podWebhook.ApplyConfiguration)
 	}
 	//+kubebuilder:scaffold:builder
 }
 
-func setupHealthzAndReadyzCheck(mgr ctrl.Manager) {
+func setupHealthzAndReadyzCheck(mgr ctrl.Manager, cfg *configapi.Configuration, certsReady chan struct{}) {
 	defer setupLog.Info("both healthz and readyz check are finished and configured")
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
@@ -215,9 +308,18 @@ func setupHealthzAndReadyzCheck(mgr ctrl.Manager) {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	var webhookServer webhook.Server
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		webhookServer = mgr.GetWebhookServer()
+	}
+	if err := health.AddReadinessChecks(mgr, cfg.Health.ReadinessChecks, webhookServer, certsReady); err != nil {
+		setupLog.Error(err, "unable to set up the readiness subchecks")
+		os.Exit(1)
+	}
 }
 
 func apply(configFile string,
+	configStrictDecoding bool,
 	probeAddr string,
 	enableLeaderElection bool,
 	leaderElectLeaseDuration time.Duration,
@@ -225,17 +327,18 @@ func apply(configFile string,
 	leaderElectRetryPeriod time.Duration,
 	leaderElectResourceLock,
 	leaderElectionID string,
-	metricsAddr string) (ctrl.Options, configapi.Configuration, error) {
+	metricsAddr string) (config.LoadResult, error) {
 	namespace := utils.GetOperatorNamespace()
 
-	options, cfg, err := config.Load(scheme, configFile)
+	result, err := config.LoadWithResult(scheme, configFile, configStrictDecoding)
+	options, cfg := result.Options, result.Configuration
 	if err != nil {
-		return options, cfg, err
+		return result, err
 	}
-	cfgStr, err := config.Encode(scheme, &cfg)
-	if err != nil {
-		return options, cfg, err
+	for _, warning := range result.Warnings {
+		setupLog.Info("Ignoring a field of the configuration file", "warning", warning)
 	}
+	config.LogLastApplied(ctrl.LoggerInto(context.Background(), setupLog), configFile, &cfg)
 
 	if flagsSet["health-probe-bind-address"] {
 		options.HealthProbeBindAddress = probeAddr
@@ -276,16 +379,29 @@ func apply(configFile string,
 	// More info:
 	// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.19.1/pkg/metrics/server
 	// - https://book.kubebuilder.io/reference/metrics.html
-	metricsServerOptions := metricsserver.Options{
-		BindAddress:    metricsAddr,
-		SecureServing:  true,
-		FilterProvider: filters.WithAuthenticationAndAuthorization,
-		TLSOpts:        []func(*tls.Config){disableHTTP2},
+	// The certificates options set by the configuration are preserved.
+	options.Metrics.BindAddress = metricsAddr
+	if metricsAddr != config.DisabledMetricsBindAddress {
+		options.Metrics.SecureServing = true
+		options.Metrics.FilterProvider = filters.WithAuthenticationAndAuthorization
+		options.Metrics.TLSOpts = append(options.Metrics.TLSOpts, disableHTTP2)
+	}
+	// The lease lives in the namespace of the controller, unless the configuration sets another one.
+	// The leader election Role must then be created in that namespace, see the leaderElectionNamespace
+	// chart value and config/leader-election-namespace.
+	if options.LeaderElectionNamespace == "" {
+		options.LeaderElectionNamespace = namespace
 	}
 
-	options.Metrics = metricsServerOptions
-	options.LeaderElectionNamespace = namespace
-
+	var encodeOpts []config.EncodeOption
+	if cfg.LogAppliedManagerOptions {
+		encodeOpts = append(encodeOpts, config.AppliedManagerOptions(options))
+	}
+	cfgStr, err := config.Encode(scheme, &cfg, encodeOpts...)
+	if err != nil {
+		return result, err
+	}
 	setupLog.Info("Successfully loaded configuration", "config", cfgStr)
-	return options, cfg, nil
+	result.Options = options
+	return result, nil
 }
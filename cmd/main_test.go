@@ -29,6 +29,8 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	ctrlcache "sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"sigs.k8s.io/lws/pkg/utils"
 )
 
 func TestApply(t *testing.T) {
@@ -164,7 +166,8 @@ internalCertManagement:
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			flagsSet = tc.flagtrack
-			opts, _, err := apply(tc.configFile,
+			result, err := apply(tc.configFile,
+				true,
 				tc.probeAddr,
 				tc.enableLeaderElection,
 				tc.leaderElectLeaseDuration,
@@ -176,9 +179,61 @@ internalCertManagement:
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
-			if diff := cmp.Diff(tc.expectedOpts, opts, ctrlOptsCmpOpts...); diff != "" {
+			if diff := cmp.Diff(tc.expectedOpts, result.Options, ctrlOptsCmpOpts...); diff != "" {
 				t.Errorf("Unexpected options (-want +got):\n%s", diff)
 			}
 		})
 	}
 }
+
+func TestApplyLeaderElectionNamespace(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	defaultConfig := filepath.Join(tmpDir, "default.yaml")
+	if err := os.WriteFile(defaultConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	resourceNamespaceConfig := filepath.Join(tmpDir, "resource-namespace.yaml")
+	if err := os.WriteFile(resourceNamespaceConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+leaderElection:
+  resourceNamespace: lws-leases
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name          string
+		configFile    string
+		wantNamespace string
+	}{
+		{
+			name:          "namespace of the controller",
+			configFile:    defaultConfig,
+			wantNamespace: utils.GetOperatorNamespace(),
+		},
+		{
+			name:          "configured resource namespace",
+			configFile:    resourceNamespaceConfig,
+			wantNamespace: "lws-leases",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			flagsSet = map[string]bool{}
+			result, err := apply(tc.configFile, true, "", false, 0, 0, 0, "", "", "metrics-addr")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := result.Options.LeaderElectionNamespace; got != tc.wantNamespace {
+				t.Errorf("Expected the leader election namespace %q, got %q", tc.wantNamespace, got)
+			}
+		})
+	}
+}
@@ -18,30 +18,100 @@ package config
 
 import (
 	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/rest"
+	configv1alpha1 "k8s.io/component-base/config/v1alpha1"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlcache "sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/yaml"
 
 	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
 )
 
-func fromFile(path string, scheme *runtime.Scheme, cfg *configapi.Configuration) error {
+// DisabledMetricsBindAddress is the metrics bind address disabling the metrics server, e.g. when
+// the metrics are scraped through a sidecar.
+const DisabledMetricsBindAddress = "0"
+
+// The names of the webhook server certificate and key in the webhook cert directory.
+const (
+	webhookCertName = "tls.crt"
+	webhookKeyName  = "tls.key"
+)
+
+var (
+	// ErrFileRead is the kind of the errors returned by Load when the config file can't be read.
+	ErrFileRead = errors.New("config file read error")
+	// ErrStrictDecoding is the kind of the errors returned by Load when the config file has unknown or duplicate fields.
+	ErrStrictDecoding = errors.New("config strict decoding error")
+	// ErrDecoding is the kind of the errors returned by Load when the config file can't be decoded.
+	ErrDecoding = errors.New("config decoding error")
+	// ErrValidation is the kind of the errors returned by Load when the configuration is invalid.
+	ErrValidation = errors.New("config validation error")
+)
+
+// Error is the error returned by Load, so that callers can tell the failures apart with
+// errors.Is on its Kind, while the message of the underlying error is preserved.
+type Error struct {
+	// Kind is one of ErrFileRead, ErrStrictDecoding, ErrDecoding or ErrValidation.
+	Kind error
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// fromFile decodes the config file into cfg. Unless strict, the unknown and duplicate fields are
+// ignored and returned as warnings.
+func fromFile(path string, scheme *runtime.Scheme, cfg *configapi.Configuration, strict bool) ([]string, error) {
 	content, err := os.ReadFile(path)
 	if err != nil {
-		return err
+		return nil, &Error{Kind: ErrFileRead, Err: err}
 	}
 
 	codecs := serializer.NewCodecFactory(scheme, serializer.EnableStrict)
 
 	// Regardless of if the bytes are of any external version,
 	// it will be read successfully and converted into the internal version
-	return runtime.DecodeInto(codecs.UniversalDecoder(), content, cfg)
+	if err := runtime.DecodeInto(codecs.UniversalDecoder(), content, cfg); err != nil {
+		strictErr, ok := runtime.AsStrictDecodingError(err)
+		if !ok {
+			return nil, &Error{Kind: ErrDecoding, Err: err}
+		}
+		if strict {
+			return nil, &Error{Kind: ErrStrictDecoding, Err: err}
+		}
+		// The strict decoding errors are returned once the object is fully decoded and defaulted.
+		var warnings []string
+		for _, e := range strictErr.Errors() {
+			warnings = append(warnings, e.Error())
+		}
+		return warnings, nil
+	}
+	return nil, nil
 }
 
 // addTo applies the configuration from cfg to the controller-runtime Options o.
@@ -65,7 +135,7 @@ func addTo(o *ctrl.Options, cfg *configapi.Configuration) {
 		o.LivenessEndpointName = cfg.Health.LivenessEndpointName
 	}
 
-	if o.WebhookServer == nil && cfg.Webhook.Port != nil {
+	if o.WebhookServer == nil && (cfg.Webhook.Port != nil || cfg.Webhook.CertDir != "") {
 		wo := webhook.Options{}
 		if cfg.Webhook.Port != nil {
 			wo.Port = *cfg.Webhook.Port
@@ -77,7 +147,39 @@ func addTo(o *ctrl.Options, cfg *configapi.Configuration) {
 			wo.CertDir = cfg.Webhook.CertDir
 		}
 		o.WebhookServer = webhook.NewServer(wo)
+		if cfg.Webhook.MaxConcurrentRequests != nil {
+			o.WebhookServer = newLimitedServer(o.WebhookServer, int(*cfg.Webhook.MaxConcurrentRequests))
+		}
 	}
+
+	addCacheTo(o, cfg)
+}
+
+func addCacheTo(o *ctrl.Options, cfg *configapi.Configuration) {
+	if len(cfg.WatchNamespaces) > 0 && o.Cache.DefaultNamespaces == nil {
+		o.Cache.DefaultNamespaces = make(map[string]ctrlcache.Config, len(cfg.WatchNamespaces))
+		for _, namespace := range cfg.WatchNamespaces {
+			o.Cache.DefaultNamespaces[namespace] = ctrlcache.Config{}
+		}
+	}
+	if cfg.Cache == nil {
+		return
+	}
+	if cfg.Cache.ResyncPeriod != nil {
+		o.Cache.SyncPeriod = &cfg.Cache.ResyncPeriod.Duration
+	}
+	if cfg.Cache.PodLabelSelector == nil {
+		return
+	}
+	// The selector was checked by validate.
+	selector, err := labels.Parse(*cfg.Cache.PodLabelSelector)
+	if err != nil {
+		return
+	}
+	if o.Cache.ByObject == nil {
+		o.Cache.ByObject = map[client.Object]ctrlcache.ByObject{}
+	}
+	o.Cache.ByObject[&corev1.Pod{}] = ctrlcache.ByObject{Label: selector}
 }
 
 func addLeaderElectionTo(o *ctrl.Options, cfg *configapi.Configuration) {
@@ -115,7 +217,81 @@ func addLeaderElectionTo(o *ctrl.Options, cfg *configapi.Configuration) {
 	}
 }
 
-func Encode(scheme *runtime.Scheme, cfg *configapi.Configuration) (string, error) {
+// ValidateWebhookCertDir checks that the webhook cert directory is readable when the certs are
+// provisioned externally, e.g. by cert-manager. With internal cert management enabled the
+// directory is populated by the cert rotator, so it may not exist yet at startup.
+func ValidateWebhookCertDir(cfg *configapi.Configuration) error {
+	if cfg.InternalCertManagement != nil && ptr.Deref(cfg.InternalCertManagement.Enable, false) {
+		return nil
+	}
+	if _, err := os.ReadDir(cfg.Webhook.CertDir); err != nil {
+		return fmt.Errorf("webhook cert directory %q is not readable: %w", cfg.Webhook.CertDir, err)
+	}
+	return nil
+}
+
+// ValidateWebhookCert loads the externally provisioned webhook certificate and key when
+// webhook.validateCert is set, and checks that the certificate is valid at now.
+func ValidateWebhookCert(cfg *configapi.Configuration, now time.Time) error {
+	if !cfg.Webhook.ValidateCert || (cfg.InternalCertManagement != nil && ptr.Deref(cfg.InternalCertManagement.Enable, false)) {
+		return nil
+	}
+	certFile := filepath.Join(cfg.Webhook.CertDir, webhookCertName)
+	keyFile := filepath.Join(cfg.Webhook.CertDir, webhookKeyName)
+	pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return fmt.Errorf("loading the webhook certificate %q and key %q: %w", certFile, keyFile, err)
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("parsing the webhook certificate %q: %w", certFile, err)
+	}
+	if now.After(cert.NotAfter) {
+		return fmt.Errorf("the webhook certificate %q expired at %s", certFile, cert.NotAfter.UTC().Format(time.RFC3339))
+	}
+	if now.Before(cert.NotBefore) {
+		return fmt.Errorf("the webhook certificate %q is not valid before %s", certFile, cert.NotBefore.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// EncodeOption configures Encode.
+type EncodeOption func(*encodeOptions)
+
+type encodeOptions struct {
+	omitDefaults   bool
+	managerOptions *ctrl.Options
+}
+
+// OmitDefaults makes Encode omit the top-level sections equal to their defaulted value,
+// producing a terse configuration.
+func OmitDefaults() EncodeOption {
+	return func(o *encodeOptions) {
+		o.omitDefaults = true
+	}
+}
+
+// AppliedManagerOptions makes Encode report the webhook, metrics, health and leaderElection
+// sections as applied by the given manager options, e.g. once overridden by the command line
+// flags, instead of as set in the configuration.
+func AppliedManagerOptions(options ctrl.Options) EncodeOption {
+	return func(o *encodeOptions) {
+		o.managerOptions = &options
+	}
+}
+
+// Encode returns the YAML representation of the configuration, all the sections are
+// included unless OmitDefaults is given.
+func Encode(scheme *runtime.Scheme, cfg *configapi.Configuration, opts ...EncodeOption) (string, error) {
+	var o encodeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.managerOptions != nil {
+		cfg = cfg.DeepCopy()
+		applyManagerOptions(cfg, o.managerOptions)
+	}
+
 	codecs := serializer.NewCodecFactory(scheme)
 	const mediaType = runtime.ContentTypeYAML
 	info, ok := runtime.SerializerInfoForMediaType(codecs.SupportedMediaTypes(), mediaType)
@@ -128,13 +304,193 @@ func Encode(scheme *runtime.Scheme, cfg *configapi.Configuration) (string, error
 	if err := encoder.Encode(cfg, buf); err != nil {
 		return "", err
 	}
-	return buf.String(), nil
+	if !o.omitDefaults {
+		return buf.String(), nil
+	}
+
+	defaults := &configapi.Configuration{}
+	scheme.Default(defaults)
+	defaultsBuf := new(bytes.Buffer)
+	if err := encoder.Encode(defaults, defaultsBuf); err != nil {
+		return "", err
+	}
+	var sections, defaultSections map[string]any
+	if err := yaml.Unmarshal(buf.Bytes(), &sections); err != nil {
+		return "", err
+	}
+	if err := yaml.Unmarshal(defaultsBuf.Bytes(), &defaultSections); err != nil {
+		return "", err
+	}
+	for name, section := range sections {
+		if name == "apiVersion" || name == "kind" {
+			continue
+		}
+		if defaultSection, found := defaultSections[name]; found && equality.Semantic.DeepEqual(section, defaultSection) {
+			delete(sections, name)
+		}
+	}
+	terse, err := yaml.Marshal(sections)
+	if err != nil {
+		return "", err
+	}
+	return string(terse), nil
+}
+
+// applyManagerOptions sets the controller manager sections of cfg to the values of the manager
+// options, the fields left unset by the options keep their configured value.
+func applyManagerOptions(cfg *configapi.Configuration, o *ctrl.Options) {
+	if o.HealthProbeBindAddress != "" {
+		cfg.Health.HealthProbeBindAddress = o.HealthProbeBindAddress
+	}
+	if o.ReadinessEndpointName != "" {
+		cfg.Health.ReadinessEndpointName = o.ReadinessEndpointName
+	}
+	if o.LivenessEndpointName != "" {
+		cfg.Health.LivenessEndpointName = o.LivenessEndpointName
+	}
+	if o.Metrics.BindAddress != "" {
+		cfg.Metrics.BindAddress = o.Metrics.BindAddress
+	}
+
+	server := o.WebhookServer
+	if limited, ok := server.(*limitedServer); ok {
+		server = limited.Server
+	}
+	if defaultServer, ok := server.(*webhook.DefaultServer); ok {
+		if defaultServer.Options.Port != 0 {
+			cfg.Webhook.Port = ptr.To(defaultServer.Options.Port)
+		}
+		if defaultServer.Options.Host != "" {
+			cfg.Webhook.Host = defaultServer.Options.Host
+		}
+		if defaultServer.Options.CertDir != "" {
+			cfg.Webhook.CertDir = defaultServer.Options.CertDir
+		}
+	}
+
+	if cfg.LeaderElection == nil {
+		cfg.LeaderElection = &configv1alpha1.LeaderElectionConfiguration{}
+	}
+	cfg.LeaderElection.LeaderElect = ptr.To(o.LeaderElection)
+	if o.LeaderElectionResourceLock != "" {
+		cfg.LeaderElection.ResourceLock = o.LeaderElectionResourceLock
+	}
+	if o.LeaderElectionID != "" {
+		cfg.LeaderElection.ResourceName = o.LeaderElectionID
+	}
+	if o.LeaderElectionNamespace != "" {
+		cfg.LeaderElection.ResourceNamespace = o.LeaderElectionNamespace
+	}
+	if o.LeaseDuration != nil {
+		cfg.LeaderElection.LeaseDuration = metav1.Duration{Duration: *o.LeaseDuration}
+	}
+	if o.RenewDeadline != nil {
+		cfg.LeaderElection.RenewDeadline = metav1.Duration{Duration: *o.RenewDeadline}
+	}
+	if o.RetryPeriod != nil {
+		cfg.LeaderElection.RetryPeriod = metav1.Duration{Duration: *o.RetryPeriod}
+	}
+}
+
+// EncodeObject returns the configuration as an unstructured object with its apiVersion and kind,
+// e.g. for GitOps tooling to commit and apply it. It holds the same fields as the YAML returned
+// by Encode with the same options.
+func EncodeObject(scheme *runtime.Scheme, cfg *configapi.Configuration, opts ...EncodeOption) (*unstructured.Unstructured, error) {
+	encoded, err := Encode(scheme, cfg, opts...)
+	if err != nil {
+		return nil, err
+	}
+	obj := &unstructured.Unstructured{}
+	if err := utilyaml.Unmarshal([]byte(encoded), &obj.Object); err != nil {
+		return nil, err
+	}
+	return obj, nil
 }
 
 // Load returns a set of controller options and configuration from the given file, if the config file path is empty
 // it used the default configapi values.
 func Load(scheme *runtime.Scheme, configFile string) (ctrl.Options, configapi.Configuration, error) {
-	var err error
+	result, err := LoadWithResult(scheme, configFile, true)
+	return result.Options, result.Configuration, err
+}
+
+// LoadLenient is like Load, except that the unknown and duplicate fields of the config file are
+// ignored instead of failing, e.g. to tolerate the fields of a newer version. The ignored fields
+// are returned as warnings for the caller to report.
+func LoadLenient(scheme *runtime.Scheme, configFile string) (ctrl.Options, configapi.Configuration, []string, error) {
+	result, err := LoadWithResult(scheme, configFile, false)
+	return result.Options, result.Configuration, result.Warnings, err
+}
+
+// LoadResult is what LoadWithResult loaded from the config file.
+type LoadResult struct {
+	// Options are the controller options built from the configuration.
+	Options ctrl.Options
+	// Configuration is the defaulted configuration.
+	Configuration configapi.Configuration
+	// Warnings are the unknown and duplicate fields ignored by a lenient load.
+	Warnings []string
+}
+
+// LoadWithResult is like Load, or like LoadLenient unless strict, returning what was loaded as a
+// single result, e.g. for embedders inspecting the resolved settings.
+func LoadWithResult(scheme *runtime.Scheme, configFile string, strict bool) (LoadResult, error) {
+	options, cfg, warnings, err := load(scheme, configFile, strict)
+	return LoadResult{Options: options, Configuration: cfg, Warnings: warnings}, err
+}
+
+// ClientConnection returns the settings of the client of the manager resolved from the configuration,
+// with the defaults applied. The settings of overrides, e.g. set by the --kube-api-qps and
+// --kube-api-burst flags, take precedence over the configuration.
+func (r *LoadResult) ClientConnection(overrides configapi.ClientConnection) configapi.ClientConnection {
+	cc := configapi.ClientConnection{}
+	if r.Configuration.ClientConnection != nil {
+		r.Configuration.ClientConnection.DeepCopyInto(&cc)
+	}
+	if overrides.QPS != nil {
+		cc.QPS = ptr.To(*overrides.QPS)
+	}
+	if overrides.Burst != nil {
+		cc.Burst = ptr.To(*overrides.Burst)
+	}
+	if overrides.UserAgent != nil {
+		cc.UserAgent = ptr.To(*overrides.UserAgent)
+	}
+	return cc
+}
+
+// ApplyClientConnection sets the client connection settings on the rest config of the manager,
+// the unset ones are left as they are.
+func ApplyClientConnection(kubeConfig *rest.Config, cc configapi.ClientConnection) {
+	if cc.QPS != nil {
+		kubeConfig.QPS = *cc.QPS
+	}
+	if cc.Burst != nil {
+		kubeConfig.Burst = int(*cc.Burst)
+	}
+	if cc.UserAgent != nil {
+		kubeConfig.UserAgent = *cc.UserAgent
+	}
+	kubeConfig.DisableCompression = cc.DisableCompression
+}
+
+// ValidateFile strictly decodes and validates the config file like Load, without building the
+// controller options, e.g. to check a configuration in CI. The files referenced by the configuration,
+// e.g. the metrics certificates, aren't checked since they only exist where the controller runs.
+// The returned error is an *Error, the validation errors are aggregated.
+func ValidateFile(scheme *runtime.Scheme, configFile string) error {
+	cfg := configapi.Configuration{}
+	if _, err := fromFile(configFile, scheme, &cfg, true); err != nil {
+		return err
+	}
+	if err := validate(&cfg).ToAggregate(); err != nil {
+		return &Error{Kind: ErrValidation, Err: err}
+	}
+	return nil
+}
+
+func load(scheme *runtime.Scheme, configFile string, strict bool) (ctrl.Options, configapi.Configuration, []string, error) {
+	var warnings []string
 	options := ctrl.Options{
 		Scheme: scheme,
 	}
@@ -143,14 +499,59 @@ func Load(scheme *runtime.Scheme, configFile string) (ctrl.Options, configapi.Co
 	if configFile == "" {
 		scheme.Default(&cfg)
 	} else {
-		err := fromFile(configFile, scheme, &cfg)
+		var err error
+		warnings, err = fromFile(configFile, scheme, &cfg, strict)
 		if err != nil {
-			return options, cfg, err
+			return options, cfg, nil, err
 		}
 	}
 	if err := validate(&cfg).ToAggregate(); err != nil {
-		return options, cfg, err
+		return options, cfg, warnings, &Error{Kind: ErrValidation, Err: err}
 	}
 	addTo(&options, &cfg)
-	return options, cfg, err
+	if err := addMetricsCertsTo(&options, &cfg); err != nil {
+		return options, cfg, warnings, &Error{Kind: ErrFileRead, Err: err}
+	}
+	return options, cfg, warnings, nil
+}
+
+// addMetricsCertsTo configures the metrics server certificate and the verification of the client
+// certificates, the files are checked so that a misconfiguration fails at startup rather than when
+// the metrics server starts. They are ignored when the metrics server is disabled.
+func addMetricsCertsTo(o *ctrl.Options, cfg *configapi.Configuration) error {
+	if cfg.Metrics.BindAddress == DisabledMetricsBindAddress {
+		return nil
+	}
+	if cfg.Metrics.CertFile != "" {
+		for _, file := range []string{cfg.Metrics.CertFile, cfg.Metrics.KeyFile} {
+			if _, err := os.Stat(file); err != nil {
+				return fmt.Errorf("metrics certificate file %q is not readable: %w", file, err)
+			}
+		}
+		// The metrics server expects the key path relative to the certificate directory.
+		certDir := filepath.Dir(cfg.Metrics.CertFile)
+		keyName, err := filepath.Rel(certDir, cfg.Metrics.KeyFile)
+		if err != nil {
+			return fmt.Errorf("metrics key file %q: %w", cfg.Metrics.KeyFile, err)
+		}
+		o.Metrics.CertDir = certDir
+		o.Metrics.CertName = filepath.Base(cfg.Metrics.CertFile)
+		o.Metrics.KeyName = keyName
+	}
+
+	if cfg.Metrics.ClientCAFile != "" {
+		caBundle, err := os.ReadFile(cfg.Metrics.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("metrics client CA file %q is not readable: %w", cfg.Metrics.ClientCAFile, err)
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(caBundle) {
+			return fmt.Errorf("metrics client CA file %q contains no certificate", cfg.Metrics.ClientCAFile)
+		}
+		o.Metrics.TLSOpts = append(o.Metrics.TLSOpts, func(c *tls.Config) {
+			c.ClientCAs = clientCAs
+			c.ClientAuth = tls.RequireAndVerifyClientCert
+		})
+	}
+	return nil
 }
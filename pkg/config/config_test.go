@@ -17,27 +17,49 @@ limitations under the License.
 package config
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"errors"
+	"fmt"
 	"io/fs"
+	"math/big"
 	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	certutil "k8s.io/client-go/util/cert"
+	tracingv1 "k8s.io/component-base/tracing/api/v1"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	ctrlcache "sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/utils/health"
+	"sigs.k8s.io/lws/pkg/utils/useragent"
 )
 
 const (
@@ -113,6 +135,26 @@ internalCertManagement:
 		t.Fatal(err)
 	}
 
+	customCertDirConfig := filepath.Join(tmpDir, "custom-cert-dir.yaml")
+	if err := os.WriteFile(customCertDirConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+health:
+  healthProbeBindAddress: :8081
+metrics:
+  bindAddress: :8443
+leaderElection:
+  leaderElect: true
+  resourceName: b8b2488c.x-k8s.io
+webhook:
+  port: 9443
+  certDir: /etc/lws/certs
+internalCertManagement:
+  enable: false
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
 	leaderElectionDisabledConfig := filepath.Join(tmpDir, "leaderElection-disabled.yaml")
 	if err := os.WriteFile(leaderElectionDisabledConfig, []byte(`
 apiVersion: config.lws.x-k8s.io/v1alpha1
@@ -145,6 +187,17 @@ webhook:
 clientConnection:
   qps: 50
   burst: 100
+  userAgent: lws-audit
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	disableCompressionConfig := filepath.Join(tmpDir, "disableCompression.yaml")
+	if err := os.WriteFile(disableCompressionConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+clientConnection:
+  disableCompression: true
 `), os.FileMode(0600)); err != nil {
 		t.Fatal(err)
 	}
@@ -167,235 +220,1972 @@ webhook:
 		t.Fatal(err)
 	}
 
-	defaultControlOptions := ctrl.Options{
-		HealthProbeBindAddress: configapi.DefaultHealthProbeBindAddress,
-		ReadinessEndpointName:  configapi.DefaultReadinessEndpoint,
-		LivenessEndpointName:   configapi.DefaultLivenessEndpoint,
-		Metrics: metricsserver.Options{
-			BindAddress: configapi.DefaultMetricsBindAddress,
-		},
-		LeaderElection:             true,
-		LeaderElectionID:           configapi.DefaultLeaderElectionID,
-		LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
-		LeaseDuration:              ptr.To(defaultLeaderElectionLeaseDuration),
-		RenewDeadline:              ptr.To(defaultLeaderElectionRenewDeadline),
-		RetryPeriod:                ptr.To(defaultLeaderElectionRetryPeriod),
-		WebhookServer: &webhook.DefaultServer{
-			Options: webhook.Options{
-				Port:    configapi.DefaultWebhookPort,
-				CertDir: configapi.DefaultWebhookCertDir,
-			},
-		},
+	undecodableConfig := filepath.Join(tmpDir, "undecodable-config.yaml")
+	if err := os.WriteFile(undecodableConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+webhook:
+  port: notANumber
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
 	}
 
-	enableDefaultInternalCertManagement := &configapi.InternalCertManagement{
-		Enable:             ptr.To(true),
-		WebhookServiceName: ptr.To(configapi.DefaultWebhookServiceName),
-		WebhookSecretName:  ptr.To(configapi.DefaultWebhookSecretName),
+	rollingUpdateDefaultsConfig := filepath.Join(tmpDir, "rolling-update-defaults.yaml")
+	if err := os.WriteFile(rollingUpdateDefaultsConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+rollingUpdateDefaults:
+  maxUnavailable: 25%
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
 	}
 
-	ctrlOptsCmpOpts := []cmp.Option{
-		cmpopts.IgnoreUnexported(ctrl.Options{}),
-		cmpopts.IgnoreUnexported(webhook.DefaultServer{}),
-		cmpopts.IgnoreUnexported(ctrlcache.Options{}),
-		cmpopts.IgnoreUnexported(net.ListenConfig{}),
-		cmpopts.IgnoreFields(ctrl.Options{}, "Scheme", "Logger"),
-		cmpopts.IgnoreFields(ctrl.Options{}, "Controller", "Logger"),
+	invalidRollingUpdateDefaultsConfig := filepath.Join(tmpDir, "invalid-rolling-update-defaults.yaml")
+	if err := os.WriteFile(invalidRollingUpdateDefaultsConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+rollingUpdateDefaults:
+  maxUnavailable: 0
+  maxSurge: 0
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
 	}
 
-	// Ignore the controller manager section since it's side effect is checked against
-	// the content of  the resulting options
-	configCmpOpts := []cmp.Option{
-		cmpopts.IgnoreFields(configapi.Configuration{}, "ControllerManager"),
+	recommendedLabelsConfig := filepath.Join(tmpDir, "recommended-labels.yaml")
+	if err := os.WriteFile(recommendedLabelsConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+recommendedLabels:
+  partOf: inference
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
 	}
 
-	defaultClientConnection := &configapi.ClientConnection{
-		QPS:   ptr.To[float32](configapi.DefaultClientConnectionQPS),
-		Burst: ptr.To[int32](configapi.DefaultClientConnectionBurst),
+	invalidRecommendedLabelsConfig := filepath.Join(tmpDir, "invalid-recommended-labels.yaml")
+	if err := os.WriteFile(invalidRecommendedLabelsConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+recommendedLabels:
+  managedBy: "platform operator"
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
 	}
 
-	testcases := []struct {
-		name              string
-		configFile        string
-		wantConfiguration configapi.Configuration
-		wantOptions       ctrl.Options
-		wantError         error
-	}{
-		{
-			name:       "default config",
-			configFile: "",
-			wantConfiguration: configapi.Configuration{
-				InternalCertManagement: enableDefaultInternalCertManagement,
-				ClientConnection:       defaultClientConnection,
-			},
-			wantOptions: ctrl.Options{
-				HealthProbeBindAddress: configapi.DefaultHealthProbeBindAddress,
-				ReadinessEndpointName:  configapi.DefaultReadinessEndpoint,
-				LivenessEndpointName:   configapi.DefaultLivenessEndpoint,
-				Metrics: metricsserver.Options{
-					BindAddress: configapi.DefaultMetricsBindAddress,
-				},
-				LeaderElection:             true,
-				LeaderElectionID:           configapi.DefaultLeaderElectionID,
-				LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
-				LeaseDuration:              ptr.To(defaultLeaderElectionLeaseDuration),
-				RenewDeadline:              ptr.To(defaultLeaderElectionRenewDeadline),
-				RetryPeriod:                ptr.To(defaultLeaderElectionRetryPeriod),
-				WebhookServer: &webhook.DefaultServer{
-					Options: webhook.Options{
-						Port:    configapi.DefaultWebhookPort,
-						CertDir: configapi.DefaultWebhookCertDir,
-					},
-				},
-			},
-		},
-		{
-			name:       "bad path",
-			configFile: ".",
-			wantError: &fs.PathError{
-				Op:   "read",
-				Path: ".",
-				Err:  errors.New("is a directory"),
-			},
-		},
-		{
-			name:       "ControllerManagerConfigurationSpec overwrite config",
-			configFile: ctrlManagerConfigSpecOverWriteConfig,
-			wantConfiguration: configapi.Configuration{
-				TypeMeta: metav1.TypeMeta{
-					APIVersion: configapi.GroupVersion.String(),
-					Kind:       "Configuration",
-				},
-				InternalCertManagement: enableDefaultInternalCertManagement,
-				ClientConnection:       defaultClientConnection,
-			},
-			wantOptions: ctrl.Options{
-				HealthProbeBindAddress: ":38081",
-				ReadinessEndpointName:  "test",
-				LivenessEndpointName:   configapi.DefaultLivenessEndpoint,
-				Metrics: metricsserver.Options{
-					BindAddress: ":38080",
-				},
-				LeaderElection:             true,
-				LeaderElectionID:           "test-id",
-				LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
-				LeaseDuration:              ptr.To(defaultLeaderElectionLeaseDuration),
-				RenewDeadline:              ptr.To(defaultLeaderElectionRenewDeadline),
-				RetryPeriod:                ptr.To(defaultLeaderElectionRetryPeriod),
-				WebhookServer: &webhook.DefaultServer{
-					Options: webhook.Options{
-						Port:    9444,
-						CertDir: configapi.DefaultWebhookCertDir,
-					},
-				},
-			},
-		},
-		{
-			name:       "cert options overwrite config",
-			configFile: certOverWriteConfig,
-			wantConfiguration: configapi.Configuration{
-				TypeMeta: metav1.TypeMeta{
-					APIVersion: configapi.GroupVersion.String(),
-					Kind:       "Configuration",
-				},
-				InternalCertManagement: &configapi.InternalCertManagement{
-					Enable:             ptr.To(true),
-					WebhookServiceName: ptr.To("lws-tenant-a-webhook-service"),
-					WebhookSecretName:  ptr.To("lws-tenant-a-webhook-server-cert"),
-				},
-				ClientConnection: defaultClientConnection,
-			},
-			wantOptions: defaultControlOptions,
-		},
+	crashLoopDetectionConfig := filepath.Join(tmpDir, "crash-loop-detection.yaml")
+	if err := os.WriteFile(crashLoopDetectionConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+crashLoopDetection:
+  restartThreshold: 5
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	recreationBackoffConfig := filepath.Join(tmpDir, "recreation-backoff.yaml")
+	if err := os.WriteFile(recreationBackoffConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+recreationBackoff:
+  base: 30s
+  jitterPercent: 0
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	reconcileTimeoutConfig := filepath.Join(tmpDir, "reconcile-timeout.yaml")
+	if err := os.WriteFile(reconcileTimeoutConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+reconcileTimeout: 30s
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	invalidReconcileTimeoutConfig := filepath.Join(tmpDir, "invalid-reconcile-timeout.yaml")
+	if err := os.WriteFile(invalidReconcileTimeoutConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+reconcileTimeout: 0s
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	leaderReadyPollIntervalConfig := filepath.Join(tmpDir, "leader-ready-poll-interval.yaml")
+	if err := os.WriteFile(leaderReadyPollIntervalConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+leaderReadyPollInterval: 5s
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	invalidLeaderReadyPollIntervalConfig := filepath.Join(tmpDir, "invalid-leader-ready-poll-interval.yaml")
+	if err := os.WriteFile(invalidLeaderReadyPollIntervalConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+leaderReadyPollInterval: -5s
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	maxConcurrentPodDeletesConfig := filepath.Join(tmpDir, "max-concurrent-pod-deletes.yaml")
+	if err := os.WriteFile(maxConcurrentPodDeletesConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+maxConcurrentPodDeletes: 50
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	invalidMaxConcurrentPodDeletesConfig := filepath.Join(tmpDir, "invalid-max-concurrent-pod-deletes.yaml")
+	if err := os.WriteFile(invalidMaxConcurrentPodDeletesConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+maxConcurrentPodDeletes: 0
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	webhookMaxConcurrentRequestsConfig := filepath.Join(tmpDir, "webhook-max-concurrent-requests.yaml")
+	if err := os.WriteFile(webhookMaxConcurrentRequestsConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+webhook:
+  maxConcurrentRequests: 20
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	invalidWebhookMaxConcurrentRequestsConfig := filepath.Join(tmpDir, "invalid-webhook-max-concurrent-requests.yaml")
+	if err := os.WriteFile(invalidWebhookMaxConcurrentRequestsConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+webhook:
+  maxConcurrentRequests: -1
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	webhookValidateCertConfig := filepath.Join(tmpDir, "webhook-validate-cert.yaml")
+	if err := os.WriteFile(webhookValidateCertConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+webhook:
+  validateCert: true
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	readinessChecksConfig := filepath.Join(tmpDir, "readiness-checks.yaml")
+	if err := os.WriteFile(readinessChecksConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+health:
+  readinessChecks:
+  - cache-sync
+  - webhook-ready
+  - leader-status
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	invalidReadinessChecksConfig := filepath.Join(tmpDir, "invalid-readiness-checks.yaml")
+	if err := os.WriteFile(invalidReadinessChecksConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+health:
+  readinessChecks:
+  - cache-sync
+  - etcd
+  - cache-sync
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	leaderElectionNamespaceConfig := filepath.Join(tmpDir, "leader-election-namespace.yaml")
+	if err := os.WriteFile(leaderElectionNamespaceConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+leaderElection:
+  resourceNamespace: lws-leases
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	invalidLeaderElectionNamespaceConfig := filepath.Join(tmpDir, "invalid-leader-election-namespace.yaml")
+	if err := os.WriteFile(invalidLeaderElectionNamespaceConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+leaderElection:
+  resourceNamespace: LWS_Leases
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	drainQueueOnShutdownConfig := filepath.Join(tmpDir, "drain-queue-on-shutdown.yaml")
+	if err := os.WriteFile(drainQueueOnShutdownConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+drainQueueOnShutdown: true
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	validatePodTemplatesConfig := filepath.Join(tmpDir, "validate-pod-templates.yaml")
+	if err := os.WriteFile(validatePodTemplatesConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+validatePodTemplates: true
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	logAppliedManagerOptionsConfig := filepath.Join(tmpDir, "log-applied-manager-options.yaml")
+	if err := os.WriteFile(logAppliedManagerOptionsConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+logAppliedManagerOptions: true
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	stuckTerminatingPodTimeoutConfig := filepath.Join(tmpDir, "stuck-terminating-pod-timeout.yaml")
+	if err := os.WriteFile(stuckTerminatingPodTimeoutConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+featureGates:
+  ForceDeleteStuckTerminatingPods: true
+stuckTerminatingPodTimeout: 10m
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	gatedStuckTerminatingPodTimeoutConfig := filepath.Join(tmpDir, "gated-stuck-terminating-pod-timeout.yaml")
+	if err := os.WriteFile(gatedStuckTerminatingPodTimeoutConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+stuckTerminatingPodTimeout: 10m
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	invalidStuckTerminatingPodTimeoutConfig := filepath.Join(tmpDir, "invalid-stuck-terminating-pod-timeout.yaml")
+	if err := os.WriteFile(invalidStuckTerminatingPodTimeoutConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+featureGates:
+  ForceDeleteStuckTerminatingPods: true
+stuckTerminatingPodTimeout: 0s
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	lastAppliedConfig := filepath.Join(tmpDir, "last-applied.yaml")
+	if err := os.WriteFile(lastAppliedConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+lastAppliedConfigFile: /var/lib/lws/last-applied-config.json
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	invalidLastAppliedConfig := filepath.Join(tmpDir, "invalid-last-applied.yaml")
+	if err := os.WriteFile(invalidLastAppliedConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+lastAppliedConfigFile: last-applied-config.json
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	defaultStartupPolicyConfig := filepath.Join(tmpDir, "default-startup-policy.yaml")
+	if err := os.WriteFile(defaultStartupPolicyConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+defaultStartupPolicy: LeaderReady
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	invalidDefaultStartupPolicyConfig := filepath.Join(tmpDir, "invalid-default-startup-policy.yaml")
+	if err := os.WriteFile(invalidDefaultStartupPolicyConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+defaultStartupPolicy: WorkersReady
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	gatedDefaultStartupPolicyConfig := filepath.Join(tmpDir, "gated-default-startup-policy.yaml")
+	if err := os.WriteFile(gatedDefaultStartupPolicyConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+defaultStartupPolicy: AllLeadersReady
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	topologyFileResourcesConfig := filepath.Join(tmpDir, "topology-file-resources.yaml")
+	if err := os.WriteFile(topologyFileResourcesConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+topologyFile:
+  enable: true
+  resources:
+    requests:
+      cpu: 20m
+    limits:
+      memory: 32Mi
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	invalidTopologyFileResourcesConfig := filepath.Join(tmpDir, "invalid-topology-file-resources.yaml")
+	if err := os.WriteFile(invalidTopologyFileResourcesConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+topologyFile:
+  enable: true
+  resources:
+    requests:
+      memory: 64Mi
+    limits:
+      memory: 32Mi
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	statusUpdateDebounceConfig := filepath.Join(tmpDir, "status-update-debounce.yaml")
+	if err := os.WriteFile(statusUpdateDebounceConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+statusUpdateDebounce: 5s
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	invalidStatusUpdateDebounceConfig := filepath.Join(tmpDir, "invalid-status-update-debounce.yaml")
+	if err := os.WriteFile(invalidStatusUpdateDebounceConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+statusUpdateDebounce: -1s
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	requiredLabelsConfig := filepath.Join(tmpDir, "required-labels.yaml")
+	if err := os.WriteFile(requiredLabelsConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+requiredLabels:
+- example.com/team
+- example.com/cost-center
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	tracingConfig := filepath.Join(tmpDir, "tracing.yaml")
+	if err := os.WriteFile(tracingConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+tracing:
+  endpoint: otel-collector.observability:4317
+  samplingRatePerMillion: 10000
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	securityContextDefaultsConfig := filepath.Join(tmpDir, "security-context-defaults.yaml")
+	if err := os.WriteFile(securityContextDefaultsConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+securityContextDefaults:
+  pod:
+    runAsNonRoot: true
+    seccompProfile:
+      type: RuntimeDefault
+  container:
+    allowPrivilegeEscalation: false
+    capabilities:
+      drop:
+      - ALL
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	defaultAnnotationsConfig := filepath.Join(tmpDir, "default-annotations.yaml")
+	if err := os.WriteFile(defaultAnnotationsConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+defaultAnnotations:
+  sidecar.istio.io/inject: "false"
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	invalidDefaultAnnotationsConfig := filepath.Join(tmpDir, "invalid-default-annotations.yaml")
+	if err := os.WriteFile(invalidDefaultAnnotationsConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+defaultAnnotations:
+  "invalid key": "false"
+  leaderworkerset.sigs.k8s.io/disable-pod-injection: "true"
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	invalidTracingEndpointConfig := filepath.Join(tmpDir, "invalid-tracing-endpoint.yaml")
+	if err := os.WriteFile(invalidTracingEndpointConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+tracing:
+  endpoint: https://otel-collector.observability:4317
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	allowedImageRegistriesConfig := filepath.Join(tmpDir, "allowed-image-registries.yaml")
+	if err := os.WriteFile(allowedImageRegistriesConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+allowedImageRegistries:
+- registry.example.com/
+- us-docker.pkg.dev/example/
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	watchPersistentVolumeClaimsConfig := filepath.Join(tmpDir, "watch-persistent-volume-claims.yaml")
+	if err := os.WriteFile(watchPersistentVolumeClaimsConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+watchPersistentVolumeClaims: true
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	podCacheConfig := filepath.Join(tmpDir, "pod-cache.yaml")
+	if err := os.WriteFile(podCacheConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+cache:
+  podLabelSelector: leaderworkerset.sigs.k8s.io/name
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	cacheResyncPeriodConfig := filepath.Join(tmpDir, "cache-resync-period.yaml")
+	if err := os.WriteFile(cacheResyncPeriodConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+cache:
+  resyncPeriod: 30m
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	invalidCacheResyncPeriodConfig := filepath.Join(tmpDir, "invalid-cache-resync-period.yaml")
+	if err := os.WriteFile(invalidCacheResyncPeriodConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+cache:
+  resyncPeriod: 0s
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	watchNamespacesConfig := filepath.Join(tmpDir, "watch-namespaces.yaml")
+	if err := os.WriteFile(watchNamespacesConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+watchNamespaces:
+- team-a
+- team-b
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	invalidWatchNamespacesConfig := filepath.Join(tmpDir, "invalid-watch-namespaces.yaml")
+	if err := os.WriteFile(invalidWatchNamespacesConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+watchNamespaces:
+- Team_A
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	invalidPodCacheConfig := filepath.Join(tmpDir, "invalid-pod-cache.yaml")
+	if err := os.WriteFile(invalidPodCacheConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+cache:
+  podLabelSelector: "app in (a"
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	maxTotalManagedPodsConfig := filepath.Join(tmpDir, "max-total-managed-pods.yaml")
+	if err := os.WriteFile(maxTotalManagedPodsConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+maxTotalManagedPods: 100
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	maxPodsPerLWSConfig := filepath.Join(tmpDir, "max-pods-per-lws.yaml")
+	if err := os.WriteFile(maxPodsPerLWSConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+maxPodsPerLeaderWorkerSet: 64
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	invalidMaxTotalManagedPodsConfig := filepath.Join(tmpDir, "invalid-max-total-managed-pods.yaml")
+	if err := os.WriteFile(invalidMaxTotalManagedPodsConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+maxTotalManagedPods: -1
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	metricsCert, metricsKey, err := certutil.GenerateSelfSignedCertKey("lws-controller-manager-metrics-service", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	metricsCertFile := filepath.Join(tmpDir, "metrics-certs", "tls.crt")
+	metricsKeyFile := filepath.Join(tmpDir, "metrics-keys", "tls.key")
+	metricsClientCAFile := filepath.Join(tmpDir, "metrics-certs", "ca.crt")
+	for file, content := range map[string][]byte{
+		metricsCertFile:     metricsCert,
+		metricsKeyFile:      metricsKey,
+		metricsClientCAFile: metricsCert,
+	} {
+		if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, content, os.FileMode(0600)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	metricsCertsConfig := filepath.Join(tmpDir, "metrics-certs.yaml")
+	if err := os.WriteFile(metricsCertsConfig, []byte(fmt.Sprintf(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+metrics:
+  bindAddress: :8443
+  certFile: %s
+  keyFile: %s
+  clientCAFile: %s
+`, metricsCertFile, metricsKeyFile, metricsClientCAFile)), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	missingMetricsCertConfig := filepath.Join(tmpDir, "missing-metrics-cert.yaml")
+	if err := os.WriteFile(missingMetricsCertConfig, []byte(fmt.Sprintf(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+metrics:
+  certFile: %s
+  keyFile: %s
+`, filepath.Join(tmpDir, "missing", "tls.crt"), metricsKeyFile)), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The certificates are ignored while the metrics server is disabled.
+	disabledMetricsConfig := filepath.Join(tmpDir, "disabled-metrics.yaml")
+	if err := os.WriteFile(disabledMetricsConfig, []byte(fmt.Sprintf(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+metrics:
+  bindAddress: "0"
+  certFile: %s
+  keyFile: %s
+`, filepath.Join(tmpDir, "missing", "tls.crt"), metricsKeyFile)), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	invalidMetricsClientCAConfig := filepath.Join(tmpDir, "invalid-metrics-client-ca.yaml")
+	if err := os.WriteFile(invalidMetricsClientCAConfig, []byte(fmt.Sprintf(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+metrics:
+  clientCAFile: %s
+`, invalidMetricsClientCAConfig)), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	metricsKeyWithoutCertConfig := filepath.Join(tmpDir, "metrics-key-without-cert.yaml")
+	if err := os.WriteFile(metricsKeyWithoutCertConfig, []byte(fmt.Sprintf(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+metrics:
+  keyFile: %s
+`, metricsKeyFile)), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	defaultControlOptions := ctrl.Options{
+		HealthProbeBindAddress: configapi.DefaultHealthProbeBindAddress,
+		ReadinessEndpointName:  configapi.DefaultReadinessEndpoint,
+		LivenessEndpointName:   configapi.DefaultLivenessEndpoint,
+		Metrics: metricsserver.Options{
+			BindAddress: configapi.DefaultMetricsBindAddress,
+		},
+		LeaderElection:             true,
+		LeaderElectionID:           configapi.DefaultLeaderElectionID,
+		LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
+		LeaseDuration:              ptr.To(defaultLeaderElectionLeaseDuration),
+		RenewDeadline:              ptr.To(defaultLeaderElectionRenewDeadline),
+		RetryPeriod:                ptr.To(defaultLeaderElectionRetryPeriod),
+		WebhookServer: &webhook.DefaultServer{
+			Options: webhook.Options{
+				Port:    configapi.DefaultWebhookPort,
+				CertDir: configapi.DefaultWebhookCertDir,
+			},
+		},
+	}
+
+	managedPodsSelector, err := labels.Parse(leaderworkerset.SetNameLabelKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	podCacheControlOptions := defaultControlOptions
+	podCacheControlOptions.Cache = ctrlcache.Options{
+		ByObject: map[client.Object]ctrlcache.ByObject{
+			&corev1.Pod{}: {Label: managedPodsSelector},
+		},
+	}
+
+	cacheResyncPeriodControlOptions := defaultControlOptions
+	cacheResyncPeriodControlOptions.Cache = ctrlcache.Options{
+		SyncPeriod: ptr.To(30 * time.Minute),
+	}
+
+	watchNamespacesControlOptions := defaultControlOptions
+	watchNamespacesControlOptions.Cache = ctrlcache.Options{
+		DefaultNamespaces: map[string]ctrlcache.Config{
+			"team-a": {},
+			"team-b": {},
+		},
+	}
+
+	metricsCertsControlOptions := defaultControlOptions
+	metricsCertsControlOptions.Metrics = metricsserver.Options{
+		BindAddress: configapi.DefaultMetricsBindAddress,
+		CertDir:     filepath.Dir(metricsCertFile),
+		CertName:    "tls.crt",
+		KeyName:     filepath.Join("..", "metrics-keys", "tls.key"),
+	}
+
+	disabledMetricsControlOptions := defaultControlOptions
+	disabledMetricsControlOptions.Metrics = metricsserver.Options{
+		BindAddress: DisabledMetricsBindAddress,
+	}
+
+	enableDefaultInternalCertManagement := &configapi.InternalCertManagement{
+		Enable:             ptr.To(true),
+		WebhookServiceName: ptr.To(configapi.DefaultWebhookServiceName),
+		WebhookSecretName:  ptr.To(configapi.DefaultWebhookSecretName),
+	}
+
+	ctrlOptsCmpOpts := []cmp.Option{
+		cmpopts.IgnoreUnexported(ctrl.Options{}),
+		cmpopts.IgnoreUnexported(webhook.DefaultServer{}),
+		cmpopts.IgnoreUnexported(limitedServer{}),
+		cmpopts.IgnoreUnexported(ctrlcache.Options{}),
+		cmpopts.IgnoreUnexported(net.ListenConfig{}),
+		cmpopts.IgnoreFields(ctrl.Options{}, "Scheme", "Logger"),
+		cmpopts.IgnoreFields(ctrl.Options{}, "Controller", "Logger"),
+		// The TLS options are checked by applying them, see wantMetricsClientAuth.
+		cmpopts.IgnoreFields(metricsserver.Options{}, "TLSOpts"),
+		// The cache selectors are keyed by object pointers, compare them by type instead.
+		cmp.Transformer("ByObject", func(byObject map[client.Object]ctrlcache.ByObject) map[string]string {
+			out := make(map[string]string, len(byObject))
+			for obj, opts := range byObject {
+				out[fmt.Sprintf("%T", obj)] = opts.Label.String()
+			}
+			return out
+		}),
+	}
+
+	// Ignore the controller manager section since it's side effect is checked against
+	// the content of  the resulting options
+	configCmpOpts := []cmp.Option{
+		cmpopts.IgnoreFields(configapi.Configuration{}, "ControllerManager"),
+	}
+
+	defaultClientConnection := &configapi.ClientConnection{
+		QPS:       ptr.To[float32](configapi.DefaultClientConnectionQPS),
+		Burst:     ptr.To[int32](configapi.DefaultClientConnectionBurst),
+		UserAgent: ptr.To(useragent.Default()),
+	}
+
+	testcases := []struct {
+		name              string
+		configFile        string
+		wantConfiguration configapi.Configuration
+		wantOptions       ctrl.Options
+		wantError         error
+		wantErrorKind     error
+		// wantMetricsClientAuth is the client authentication of the metrics server.
+		wantMetricsClientAuth tls.ClientAuthType
+		// wantMetricsServerDisabled checks that no metrics server, hence no listener, is created.
+		wantMetricsServerDisabled bool
+	}{
+		{
+			name:       "default config",
+			configFile: "",
+			wantConfiguration: configapi.Configuration{
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+			},
+			wantOptions: ctrl.Options{
+				HealthProbeBindAddress: configapi.DefaultHealthProbeBindAddress,
+				ReadinessEndpointName:  configapi.DefaultReadinessEndpoint,
+				LivenessEndpointName:   configapi.DefaultLivenessEndpoint,
+				Metrics: metricsserver.Options{
+					BindAddress: configapi.DefaultMetricsBindAddress,
+				},
+				LeaderElection:             true,
+				LeaderElectionID:           configapi.DefaultLeaderElectionID,
+				LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
+				LeaseDuration:              ptr.To(defaultLeaderElectionLeaseDuration),
+				RenewDeadline:              ptr.To(defaultLeaderElectionRenewDeadline),
+				RetryPeriod:                ptr.To(defaultLeaderElectionRetryPeriod),
+				WebhookServer: &webhook.DefaultServer{
+					Options: webhook.Options{
+						Port:    configapi.DefaultWebhookPort,
+						CertDir: configapi.DefaultWebhookCertDir,
+					},
+				},
+			},
+		},
+		{
+			name:       "bad path",
+			configFile: ".",
+			wantError: &fs.PathError{
+				Op:   "read",
+				Path: ".",
+				Err:  errors.New("is a directory"),
+			},
+			wantErrorKind: ErrFileRead,
+		},
+		{
+			name:       "ControllerManagerConfigurationSpec overwrite config",
+			configFile: ctrlManagerConfigSpecOverWriteConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+			},
+			wantOptions: ctrl.Options{
+				HealthProbeBindAddress: ":38081",
+				ReadinessEndpointName:  "test",
+				LivenessEndpointName:   configapi.DefaultLivenessEndpoint,
+				Metrics: metricsserver.Options{
+					BindAddress: ":38080",
+				},
+				LeaderElection:             true,
+				LeaderElectionID:           "test-id",
+				LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
+				LeaseDuration:              ptr.To(defaultLeaderElectionLeaseDuration),
+				RenewDeadline:              ptr.To(defaultLeaderElectionRenewDeadline),
+				RetryPeriod:                ptr.To(defaultLeaderElectionRetryPeriod),
+				WebhookServer: &webhook.DefaultServer{
+					Options: webhook.Options{
+						Port:    9444,
+						CertDir: configapi.DefaultWebhookCertDir,
+					},
+				},
+			},
+		},
+		{
+			name:       "cert options overwrite config",
+			configFile: certOverWriteConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: &configapi.InternalCertManagement{
+					Enable:             ptr.To(true),
+					WebhookServiceName: ptr.To("lws-tenant-a-webhook-service"),
+					WebhookSecretName:  ptr.To("lws-tenant-a-webhook-server-cert"),
+				},
+				ClientConnection: defaultClientConnection,
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:       "disable cert overwrite config",
+			configFile: disableCertOverWriteConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: &configapi.InternalCertManagement{
+					Enable: ptr.To(false),
+				},
+				ClientConnection: defaultClientConnection,
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:       "custom cert dir with internal cert management disabled",
+			configFile: customCertDirConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: &configapi.InternalCertManagement{
+					Enable: ptr.To(false),
+				},
+				ClientConnection: defaultClientConnection,
+			},
+			wantOptions: ctrl.Options{
+				HealthProbeBindAddress: configapi.DefaultHealthProbeBindAddress,
+				ReadinessEndpointName:  configapi.DefaultReadinessEndpoint,
+				LivenessEndpointName:   configapi.DefaultLivenessEndpoint,
+				Metrics: metricsserver.Options{
+					BindAddress: configapi.DefaultMetricsBindAddress,
+				},
+				LeaderElection:             true,
+				LeaderElectionID:           configapi.DefaultLeaderElectionID,
+				LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
+				LeaseDuration:              ptr.To(defaultLeaderElectionLeaseDuration),
+				RenewDeadline:              ptr.To(defaultLeaderElectionRenewDeadline),
+				RetryPeriod:                ptr.To(defaultLeaderElectionRetryPeriod),
+				WebhookServer: &webhook.DefaultServer{
+					Options: webhook.Options{
+						Port:    configapi.DefaultWebhookPort,
+						CertDir: "/etc/lws/certs",
+					},
+				},
+			},
+		},
+		{
+			name:       "leaderElection disabled config",
+			configFile: leaderElectionDisabledConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+			},
+			wantOptions: ctrl.Options{
+				HealthProbeBindAddress: configapi.DefaultHealthProbeBindAddress,
+				ReadinessEndpointName:  configapi.DefaultReadinessEndpoint,
+				LivenessEndpointName:   configapi.DefaultLivenessEndpoint,
+				Metrics: metricsserver.Options{
+					BindAddress: configapi.DefaultMetricsBindAddress,
+				},
+				LeaderElectionID:           configapi.DefaultLeaderElectionID,
+				LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
+				LeaseDuration:              ptr.To(defaultLeaderElectionLeaseDuration),
+				RenewDeadline:              ptr.To(defaultLeaderElectionRenewDeadline),
+				RetryPeriod:                ptr.To(defaultLeaderElectionRetryPeriod),
+				LeaderElection:             false,
+				WebhookServer: &webhook.DefaultServer{
+					Options: webhook.Options{
+						Port:    configapi.DefaultWebhookPort,
+						CertDir: configapi.DefaultWebhookCertDir,
+					},
+				},
+			},
+		},
+		{
+			name:       "clientConnection config",
+			configFile: clientConnectionConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection: &configapi.ClientConnection{
+					QPS:       ptr.To[float32](50),
+					Burst:     ptr.To[int32](100),
+					UserAgent: ptr.To("lws-audit"),
+				},
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:       "disable compression config",
+			configFile: disableCompressionConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection: &configapi.ClientConnection{
+					QPS:                ptr.To[float32](configapi.DefaultClientConnectionQPS),
+					Burst:              ptr.To[int32](configapi.DefaultClientConnectionBurst),
+					UserAgent:          ptr.To(useragent.Default()),
+					DisableCompression: true,
+				},
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:       "pod cache label selector config",
+			configFile: podCacheConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+				Cache: &configapi.Cache{
+					PodLabelSelector: ptr.To(leaderworkerset.SetNameLabelKey),
+				},
+			},
+			wantOptions: podCacheControlOptions,
+		},
+		{
+			name:          "invalid pod cache label selector config",
+			configFile:    invalidPodCacheConfig,
+			wantErrorKind: ErrValidation,
+		},
+		{
+			name:       "cache resync period config",
+			configFile: cacheResyncPeriodConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+				Cache: &configapi.Cache{
+					ResyncPeriod: &metav1.Duration{Duration: 30 * time.Minute},
+				},
+			},
+			wantOptions: cacheResyncPeriodControlOptions,
+		},
+		{
+			name:          "non-positive cache resync period config",
+			configFile:    invalidCacheResyncPeriodConfig,
+			wantErrorKind: ErrValidation,
+		},
+		{
+			name:       "watch namespaces config",
+			configFile: watchNamespacesConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+				WatchNamespaces:        []string{"team-a", "team-b"},
+			},
+			wantOptions: watchNamespacesControlOptions,
+		},
+		{
+			name:          "invalid watch namespaces config",
+			configFile:    invalidWatchNamespacesConfig,
+			wantErrorKind: ErrValidation,
+		},
+		{
+			name:       "max total managed pods config",
+			configFile: maxTotalManagedPodsConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+				MaxTotalManagedPods:    100,
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:       "max pods per leaderworkerset config",
+			configFile: maxPodsPerLWSConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement:    enableDefaultInternalCertManagement,
+				ClientConnection:          defaultClientConnection,
+				MaxPodsPerLeaderWorkerSet: 64,
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:       "negative max total managed pods config",
+			configFile: invalidMaxTotalManagedPodsConfig,
+			wantError: field.ErrorList{
+				field.Invalid(field.NewPath("maxTotalManagedPods"), int32(-1), "must be greater than or equal to 0"),
+			}.ToAggregate(),
+			wantErrorKind: ErrValidation,
+		},
+		{
+			name:       "recommended labels config",
+			configFile: recommendedLabelsConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+				RecommendedLabels: &configapi.RecommendedLabels{
+					ManagedBy: ptr.To(configapi.DefaultRecommendedLabelsManagedBy),
+					PartOf:    ptr.To("inference"),
+				},
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:          "invalid recommended labels config",
+			configFile:    invalidRecommendedLabelsConfig,
+			wantErrorKind: ErrValidation,
+		},
+		{
+			name:       "crash loop detection config",
+			configFile: crashLoopDetectionConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+				CrashLoopDetection: &configapi.CrashLoopDetection{
+					RestartThreshold: ptr.To[int32](5),
+					Window:           &metav1.Duration{Duration: configapi.DefaultCrashLoopWindow},
+				},
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:       "recreation backoff config",
+			configFile: recreationBackoffConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+				RecreationBackoff: &configapi.RecreationBackoff{
+					Base:          &metav1.Duration{Duration: 30 * time.Second},
+					Cap:           &metav1.Duration{Duration: configapi.DefaultRecreationBackoffCap},
+					JitterPercent: ptr.To[int32](0),
+					ResetAfter:    &metav1.Duration{Duration: configapi.DefaultRecreationBackoffResetAfter},
+				},
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:       "reconcile timeout config",
+			configFile: reconcileTimeoutConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+				ReconcileTimeout:       &metav1.Duration{Duration: 30 * time.Second},
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:       "zero reconcile timeout config",
+			configFile: invalidReconcileTimeoutConfig,
+			wantError: field.ErrorList{
+				field.Invalid(field.NewPath("reconcileTimeout"), "0s", "must be greater than 0"),
+			}.ToAggregate(),
+			wantErrorKind: ErrValidation,
+		},
+		{
+			name:       "leader ready poll interval config",
+			configFile: leaderReadyPollIntervalConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement:  enableDefaultInternalCertManagement,
+				ClientConnection:        defaultClientConnection,
+				LeaderReadyPollInterval: &metav1.Duration{Duration: 5 * time.Second},
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:       "negative leader ready poll interval config",
+			configFile: invalidLeaderReadyPollIntervalConfig,
+			wantError: field.ErrorList{
+				field.Invalid(field.NewPath("leaderReadyPollInterval"), "-5s", "must be greater than 0"),
+			}.ToAggregate(),
+			wantErrorKind: ErrValidation,
+		},
+		{
+			name:       "max concurrent pod deletes config",
+			configFile: maxConcurrentPodDeletesConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement:  enableDefaultInternalCertManagement,
+				ClientConnection:        defaultClientConnection,
+				MaxConcurrentPodDeletes: ptr.To[int32](50),
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:       "zero max concurrent pod deletes config",
+			configFile: invalidMaxConcurrentPodDeletesConfig,
+			wantError: field.ErrorList{
+				field.Invalid(field.NewPath("maxConcurrentPodDeletes"), int32(0), "must be greater than 0"),
+			}.ToAggregate(),
+			wantErrorKind: ErrValidation,
+		},
+		{
+			name:       "webhook max concurrent requests config",
+			configFile: webhookMaxConcurrentRequestsConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+			},
+			wantOptions: func() ctrl.Options {
+				o := defaultControlOptions
+				o.WebhookServer = &limitedServer{Server: defaultControlOptions.WebhookServer}
+				return o
+			}(),
+		},
+		{
+			name:       "negative webhook max concurrent requests config",
+			configFile: invalidWebhookMaxConcurrentRequestsConfig,
+			wantError: field.ErrorList{
+				field.Invalid(field.NewPath("webhook", "maxConcurrentRequests"), int32(-1), "must be greater than 0"),
+			}.ToAggregate(),
+			wantErrorKind: ErrValidation,
+		},
+		{
+			name:       "webhook validate cert config",
+			configFile: webhookValidateCertConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:       "readiness checks config",
+			configFile: readinessChecksConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:       "invalid readiness checks config",
+			configFile: invalidReadinessChecksConfig,
+			wantError: field.ErrorList{
+				field.NotSupported(field.NewPath("health", "readinessChecks").Index(1), "etcd", health.Checks()),
+				field.Duplicate(field.NewPath("health", "readinessChecks").Index(2), "cache-sync"),
+			}.ToAggregate(),
+			wantErrorKind: ErrValidation,
+		},
+		{
+			name:       "leader election namespace config",
+			configFile: leaderElectionNamespaceConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+			},
+			wantOptions: func() ctrl.Options {
+				o := defaultControlOptions
+				o.LeaderElectionNamespace = "lws-leases"
+				return o
+			}(),
+		},
+		{
+			name:       "invalid leader election namespace config",
+			configFile: invalidLeaderElectionNamespaceConfig,
+			wantError: field.ErrorList{
+				field.Invalid(field.NewPath("leaderElection", "resourceNamespace"), "LWS_Leases", "a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')"),
+			}.ToAggregate(),
+			wantErrorKind: ErrValidation,
+		},
+		{
+			name:       "drain queue on shutdown config",
+			configFile: drainQueueOnShutdownConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+				DrainQueueOnShutdown:   true,
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:       "validate pod templates config",
+			configFile: validatePodTemplatesConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+				ValidatePodTemplates:   true,
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:       "log applied manager options config",
+			configFile: logAppliedManagerOptionsConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement:   enableDefaultInternalCertManagement,
+				ClientConnection:         defaultClientConnection,
+				LogAppliedManagerOptions: true,
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:       "stuck terminating pod timeout config",
+			configFile: stuckTerminatingPodTimeoutConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement:     enableDefaultInternalCertManagement,
+				ClientConnection:           defaultClientConnection,
+				FeatureGates:               map[string]bool{"ForceDeleteStuckTerminatingPods": true},
+				StuckTerminatingPodTimeout: &metav1.Duration{Duration: 10 * time.Minute},
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:       "feature gated stuck terminating pod timeout config",
+			configFile: gatedStuckTerminatingPodTimeoutConfig,
+			wantError: field.ErrorList{
+				field.Invalid(field.NewPath("stuckTerminatingPodTimeout"), "10m0s", "requires the ForceDeleteStuckTerminatingPods or the DryRunReconcile feature gate"),
+			}.ToAggregate(),
+			wantErrorKind: ErrValidation,
+		},
+		{
+			name:       "zero stuck terminating pod timeout config",
+			configFile: invalidStuckTerminatingPodTimeoutConfig,
+			wantError: field.ErrorList{
+				field.Invalid(field.NewPath("stuckTerminatingPodTimeout"), "0s", "must be greater than 0"),
+			}.ToAggregate(),
+			wantErrorKind: ErrValidation,
+		},
+		{
+			name:       "last applied config file config",
+			configFile: lastAppliedConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+				LastAppliedConfigFile:  "/var/lib/lws/last-applied-config.json",
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:       "relative last applied config file config",
+			configFile: invalidLastAppliedConfig,
+			wantError: field.ErrorList{
+				field.Invalid(field.NewPath("lastAppliedConfigFile"), "last-applied-config.json", "must be an absolute path"),
+			}.ToAggregate(),
+			wantErrorKind: ErrValidation,
+		},
+		{
+			name:       "default startup policy config",
+			configFile: defaultStartupPolicyConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+				DefaultStartupPolicy:   "LeaderReady",
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:       "unsupported default startup policy config",
+			configFile: invalidDefaultStartupPolicyConfig,
+			wantError: field.ErrorList{
+				field.NotSupported(field.NewPath("defaultStartupPolicy"), "WorkersReady", []leaderworkerset.StartupPolicyType{
+					leaderworkerset.LeaderCreatedStartupPolicy, leaderworkerset.LeaderReadyStartupPolicy, leaderworkerset.AllLeadersReadyStartupPolicy,
+				}),
+			}.ToAggregate(),
+			wantErrorKind: ErrValidation,
+		},
+		{
+			name:       "feature gated default startup policy config",
+			configFile: gatedDefaultStartupPolicyConfig,
+			wantError: field.ErrorList{
+				field.Invalid(field.NewPath("defaultStartupPolicy"), "AllLeadersReady", "requires the AllLeadersReadyStartupPolicy feature gate"),
+			}.ToAggregate(),
+			wantErrorKind: ErrValidation,
+		},
+		{
+			name:       "topology file resources config",
+			configFile: topologyFileResourcesConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+				TopologyFile: &configapi.TopologyFile{
+					Enable:    ptr.To(true),
+					Image:     ptr.To(configapi.DefaultTopologyFileImage),
+					MountPath: ptr.To(configapi.DefaultTopologyFileMountPath),
+					Resources: &corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("20m")},
+						Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("32Mi")},
+					},
+				},
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:       "topology file requests exceeding the limits config",
+			configFile: invalidTopologyFileResourcesConfig,
+			wantError: field.ErrorList{
+				field.Invalid(field.NewPath("topologyFile", "resources", "requests").Key("memory"), "64Mi", "must be less than or equal to memory limit of 32Mi"),
+			}.ToAggregate(),
+			wantErrorKind: ErrValidation,
+		},
+		{
+			name:       "status update debounce config",
+			configFile: statusUpdateDebounceConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+				StatusUpdateDebounce:   &metav1.Duration{Duration: 5 * time.Second},
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:       "negative status update debounce config",
+			configFile: invalidStatusUpdateDebounceConfig,
+			wantError: field.ErrorList{
+				field.Invalid(field.NewPath("statusUpdateDebounce"), "-1s", "must be greater than or equal to 0"),
+			}.ToAggregate(),
+			wantErrorKind: ErrValidation,
+		},
+		{
+			name:       "watch persistent volume claims config",
+			configFile: watchPersistentVolumeClaimsConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement:      enableDefaultInternalCertManagement,
+				ClientConnection:            defaultClientConnection,
+				WatchPersistentVolumeClaims: true,
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:       "allowed image registries config",
+			configFile: allowedImageRegistriesConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+				AllowedImageRegistries: []string{"registry.example.com/", "us-docker.pkg.dev/example/"},
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:       "required labels config",
+			configFile: requiredLabelsConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+				RequiredLabels:         []string{"example.com/team", "example.com/cost-center"},
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:       "tracing config",
+			configFile: tracingConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+				Tracing: &tracingv1.TracingConfiguration{
+					Endpoint:               ptr.To("otel-collector.observability:4317"),
+					SamplingRatePerMillion: ptr.To[int32](10000),
+				},
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:       "security context defaults config",
+			configFile: securityContextDefaultsConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+				SecurityContextDefaults: &configapi.SecurityContextDefaults{
+					Pod: &corev1.PodSecurityContext{
+						RunAsNonRoot:   ptr.To(true),
+						SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+					},
+					Container: &corev1.SecurityContext{
+						AllowPrivilegeEscalation: ptr.To(false),
+						Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+					},
+				},
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:       "default annotations config",
+			configFile: defaultAnnotationsConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+				DefaultAnnotations:     map[string]string{"sidecar.istio.io/inject": "false"},
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:          "invalid default annotations config",
+			configFile:    invalidDefaultAnnotationsConfig,
+			wantErrorKind: ErrValidation,
+		},
+		{
+			name:          "unsupported tracing endpoint scheme config",
+			configFile:    invalidTracingEndpointConfig,
+			wantErrorKind: ErrValidation,
+		},
+		{
+			name:       "metrics certificates config",
+			configFile: metricsCertsConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				ControllerManager: configapi.ControllerManager{
+					Metrics: configapi.ControllerMetrics{
+						BindAddress:  configapi.DefaultMetricsBindAddress,
+						CertFile:     metricsCertFile,
+						KeyFile:      metricsKeyFile,
+						ClientCAFile: metricsClientCAFile,
+					},
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+			},
+			wantOptions:           metricsCertsControlOptions,
+			wantMetricsClientAuth: tls.RequireAndVerifyClientCert,
+		},
+		{
+			name:       "disabled metrics config",
+			configFile: disabledMetricsConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				ControllerManager: configapi.ControllerManager{
+					Metrics: configapi.ControllerMetrics{
+						BindAddress: DisabledMetricsBindAddress,
+						CertFile:    filepath.Join(tmpDir, "missing", "tls.crt"),
+						KeyFile:     metricsKeyFile,
+					},
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+			},
+			wantOptions:               disabledMetricsControlOptions,
+			wantMetricsServerDisabled: true,
+		},
+		{
+			name:          "missing metrics certificate config",
+			configFile:    missingMetricsCertConfig,
+			wantErrorKind: ErrFileRead,
+		},
+		{
+			name:          "metrics client CA without certificate config",
+			configFile:    invalidMetricsClientCAConfig,
+			wantErrorKind: ErrFileRead,
+		},
+		{
+			name:       "metrics key without certificate config",
+			configFile: metricsKeyWithoutCertConfig,
+			wantError: field.ErrorList{
+				field.Required(field.NewPath("metrics", "certFile"), "must be set when keyFile is set"),
+			}.ToAggregate(),
+			wantErrorKind: ErrValidation,
+		},
+		{
+			name:       "invalid config",
+			configFile: invalidConfig,
+			wantError: runtime.NewStrictDecodingError([]error{
+				errors.New("unknown field \"invalidField\""),
+			}),
+			wantErrorKind: ErrStrictDecoding,
+		},
+		{
+			name:          "undecodable config",
+			configFile:    undecodableConfig,
+			wantErrorKind: ErrDecoding,
+		},
+		{
+			name:       "rollingUpdateDefaults config",
+			configFile: rollingUpdateDefaultsConfig,
+			wantConfiguration: configapi.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: configapi.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				InternalCertManagement: enableDefaultInternalCertManagement,
+				ClientConnection:       defaultClientConnection,
+				RollingUpdateDefaults: &configapi.RollingUpdateDefaults{
+					MaxUnavailable: ptr.To(intstr.FromString("25%")),
+					MaxSurge:       ptr.To(intstr.FromInt32(configapi.DefaultRollingUpdateMaxSurge)),
+				},
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:       "invalid rollingUpdateDefaults config",
+			configFile: invalidRollingUpdateDefaultsConfig,
+			wantError: field.ErrorList{
+				field.Invalid(field.NewPath("rollingUpdateDefaults", "maxUnavailable"), "0", "must not be 0 when maxSurge is 0"),
+			}.ToAggregate(),
+			wantErrorKind: ErrValidation,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			options, cfg, err := Load(testScheme, tc.configFile)
+			if tc.wantErrorKind == nil {
+				if err != nil {
+					t.Errorf("Unexpected error:%s", err)
+				}
+				if diff := cmp.Diff(tc.wantConfiguration, cfg, configCmpOpts...); diff != "" {
+					t.Errorf("Unexpected config (-want +got):\n%s", diff)
+				}
+				if diff := cmp.Diff(tc.wantOptions, options, ctrlOptsCmpOpts...); diff != "" {
+					t.Errorf("Unexpected options (-want +got):\n%s", diff)
+				}
+				tlsConfig := &tls.Config{}
+				for _, opt := range options.Metrics.TLSOpts {
+					opt(tlsConfig)
+				}
+				if tlsConfig.ClientAuth != tc.wantMetricsClientAuth {
+					t.Errorf("Unexpected metrics client authentication, want %v, got %v", tc.wantMetricsClientAuth, tlsConfig.ClientAuth)
+				}
+				if tc.wantMetricsServerDisabled {
+					server, err := metricsserver.NewServer(options.Metrics, &rest.Config{}, nil)
+					if err != nil {
+						t.Errorf("Unexpected metrics server error: %s", err)
+					}
+					if server != nil {
+						t.Errorf("Expected no metrics server, got %T", server)
+					}
+				}
+			} else {
+				if !errors.Is(err, tc.wantErrorKind) {
+					t.Errorf("Unexpected error kind, want %v, got: %v", tc.wantErrorKind, err)
+				}
+				var cfgErr *Error
+				if !errors.As(err, &cfgErr) {
+					t.Errorf("Expected a *Error, got: %T", err)
+				}
+				if tc.wantError != nil {
+					if diff := cmp.Diff(tc.wantError.Error(), err.Error()); diff != "" {
+						t.Errorf("Unexpected error (-want +got):\n%s", diff)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestValidateWebhookCertDir(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	certDir := filepath.Join(tmpDir, "certs")
+	if err := os.Mkdir(certDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	unreadableCertDir := filepath.Join(tmpDir, "unreadable-certs")
+	if err := os.Mkdir(unreadableCertDir, 0000); err != nil {
+		t.Fatal(err)
+	}
+	notADir := filepath.Join(tmpDir, "not-a-dir")
+	if err := os.WriteFile(notADir, nil, os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	testcases := []struct {
+		name               string
+		certDir            string
+		internalCertEnable bool
+		skipAsRoot         bool
+		wantError          bool
+	}{
+		{
+			name:    "readable cert dir",
+			certDir: certDir,
+		},
+		{
+			name:      "missing cert dir",
+			certDir:   filepath.Join(tmpDir, "missing"),
+			wantError: true,
+		},
+		{
+			name:      "cert dir is a file",
+			certDir:   notADir,
+			wantError: true,
+		},
+		{
+			name:       "unreadable cert dir",
+			certDir:    unreadableCertDir,
+			skipAsRoot: true,
+			wantError:  true,
+		},
+		{
+			name:               "missing cert dir with internal cert management enabled",
+			certDir:            filepath.Join(tmpDir, "missing"),
+			internalCertEnable: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skipAsRoot && os.Geteuid() == 0 {
+				t.Skip("directory permissions are not enforced for root")
+			}
+			cfg := &configapi.Configuration{
+				InternalCertManagement: &configapi.InternalCertManagement{
+					Enable: ptr.To(tc.internalCertEnable),
+				},
+			}
+			cfg.Webhook.CertDir = tc.certDir
+			err := ValidateWebhookCertDir(cfg)
+			if gotError := err != nil; gotError != tc.wantError {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateWebhookCert(t *testing.T) {
+	now := time.Now()
+	writeCert := func(t *testing.T, notBefore, notAfter time.Time) string {
+		t.Helper()
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: "lws-webhook-service.lws-system.svc"},
+			NotBefore:    notBefore,
+			NotAfter:     notAfter,
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		certDir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(certDir, "tls.crt"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), os.FileMode(0600)); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(certDir, "tls.key"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), os.FileMode(0600)); err != nil {
+			t.Fatal(err)
+		}
+		return certDir
+	}
+
+	testcases := []struct {
+		name               string
+		certDir            func(t *testing.T) string
+		validateCert       bool
+		internalCertEnable bool
+		wantError          string
+	}{
+		{
+			name:         "valid cert",
+			certDir:      func(t *testing.T) string { return writeCert(t, now.Add(-time.Hour), now.Add(time.Hour)) },
+			validateCert: true,
+		},
+		{
+			name:         "expired cert",
+			certDir:      func(t *testing.T) string { return writeCert(t, now.Add(-2*time.Hour), now.Add(-time.Hour)) },
+			validateCert: true,
+			wantError:    "expired at",
+		},
+		{
+			name:         "cert not valid yet",
+			certDir:      func(t *testing.T) string { return writeCert(t, now.Add(time.Hour), now.Add(2*time.Hour)) },
+			validateCert: true,
+			wantError:    "is not valid before",
+		},
+		{
+			name: "unparseable cert",
+			certDir: func(t *testing.T) string {
+				certDir := writeCert(t, now.Add(-time.Hour), now.Add(time.Hour))
+				if err := os.WriteFile(filepath.Join(certDir, "tls.crt"), []byte("not a certificate"), os.FileMode(0600)); err != nil {
+					t.Fatal(err)
+				}
+				return certDir
+			},
+			validateCert: true,
+			wantError:    "loading the webhook certificate",
+		},
+		{
+			name:         "missing cert",
+			certDir:      func(t *testing.T) string { return t.TempDir() },
+			validateCert: true,
+			wantError:    "loading the webhook certificate",
+		},
+		{
+			name:    "expired cert not validated",
+			certDir: func(t *testing.T) string { return writeCert(t, now.Add(-2*time.Hour), now.Add(-time.Hour)) },
+		},
+		{
+			name:               "missing cert with internal cert management enabled",
+			certDir:            func(t *testing.T) string { return t.TempDir() },
+			validateCert:       true,
+			internalCertEnable: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &configapi.Configuration{
+				InternalCertManagement: &configapi.InternalCertManagement{
+					Enable: ptr.To(tc.internalCertEnable),
+				},
+			}
+			cfg.Webhook.CertDir = tc.certDir(t)
+			cfg.Webhook.ValidateCert = tc.validateCert
+			err := ValidateWebhookCert(cfg, now)
+			if tc.wantError == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tc.wantError) {
+				t.Errorf("Expected an error containing %q, got: %v", tc.wantError, err)
+			}
+		})
+	}
+}
+
+func TestLoadLenient(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	if err := configapi.AddToScheme(testScheme); err != nil {
+		t.Fatal(err)
+	}
+
+	invalidConfig := filepath.Join(t.TempDir(), "invalid-config.yaml")
+	if err := os.WriteFile(invalidConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+invalidField: invalidValue
+health:
+  healthProbeBindAddress: :8081
+webhook:
+  port: 9443
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := Load(testScheme, invalidConfig); !errors.Is(err, ErrStrictDecoding) {
+		t.Errorf("Expected the strict decoding to fail, got: %v", err)
+	}
+
+	_, cfg, warnings, err := LoadLenient(testScheme, invalidConfig)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if diff := cmp.Diff([]string{`unknown field "invalidField"`}, warnings); diff != "" {
+		t.Errorf("Unexpected warnings (-want +got):\n%s", diff)
+	}
+	if cfg.Health.HealthProbeBindAddress != ":8081" || ptr.Deref(cfg.Webhook.Port, 0) != 9443 {
+		t.Errorf("Expected the known fields to be decoded, got health %+v, webhook %+v", cfg.Health, cfg.Webhook)
+	}
+	if cfg.InternalCertManagement == nil || !ptr.Deref(cfg.InternalCertManagement.Enable, false) {
+		t.Errorf("Expected the configuration to be defaulted, got internalCertManagement %+v", cfg.InternalCertManagement)
+	}
+}
+
+func TestLoadWithResultClientConnection(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	if err := configapi.AddToScheme(testScheme); err != nil {
+		t.Fatal(err)
+	}
+
+	clientConnectionConfig := filepath.Join(t.TempDir(), "client-connection.yaml")
+	if err := os.WriteFile(clientConnectionConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+clientConnection:
+  qps: 50
+  disableCompression: true
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name       string
+		configFile string
+		overrides  configapi.ClientConnection
+		want       configapi.ClientConnection
+		wantRest   rest.Config
+	}{
 		{
-			name:       "disable cert overwrite config",
-			configFile: disableCertOverWriteConfig,
-			wantConfiguration: configapi.Configuration{
-				TypeMeta: metav1.TypeMeta{
-					APIVersion: configapi.GroupVersion.String(),
-					Kind:       "Configuration",
-				},
-				InternalCertManagement: &configapi.InternalCertManagement{
-					Enable: ptr.To(false),
-				},
-				ClientConnection: defaultClientConnection,
+			name: "defaults",
+			want: configapi.ClientConnection{
+				QPS:       ptr.To(configapi.DefaultClientConnectionQPS),
+				Burst:     ptr.To(configapi.DefaultClientConnectionBurst),
+				UserAgent: ptr.To(useragent.Default()),
+			},
+			wantRest: rest.Config{
+				QPS:       configapi.DefaultClientConnectionQPS,
+				Burst:     int(configapi.DefaultClientConnectionBurst),
+				UserAgent: useragent.Default(),
 			},
-			wantOptions: defaultControlOptions,
 		},
 		{
-			name:       "leaderElection disabled config",
-			configFile: leaderElectionDisabledConfig,
-			wantConfiguration: configapi.Configuration{
-				TypeMeta: metav1.TypeMeta{
-					APIVersion: configapi.GroupVersion.String(),
-					Kind:       "Configuration",
-				},
-				InternalCertManagement: enableDefaultInternalCertManagement,
-				ClientConnection:       defaultClientConnection,
+			name:       "overrides",
+			configFile: clientConnectionConfig,
+			want: configapi.ClientConnection{
+				QPS:                ptr.To[float32](50),
+				Burst:              ptr.To(configapi.DefaultClientConnectionBurst),
+				UserAgent:          ptr.To(useragent.Default()),
+				DisableCompression: true,
 			},
-			wantOptions: ctrl.Options{
-				HealthProbeBindAddress: configapi.DefaultHealthProbeBindAddress,
-				ReadinessEndpointName:  configapi.DefaultReadinessEndpoint,
-				LivenessEndpointName:   configapi.DefaultLivenessEndpoint,
-				Metrics: metricsserver.Options{
-					BindAddress: configapi.DefaultMetricsBindAddress,
-				},
-				LeaderElectionID:           configapi.DefaultLeaderElectionID,
-				LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
-				LeaseDuration:              ptr.To(defaultLeaderElectionLeaseDuration),
-				RenewDeadline:              ptr.To(defaultLeaderElectionRenewDeadline),
-				RetryPeriod:                ptr.To(defaultLeaderElectionRetryPeriod),
-				LeaderElection:             false,
-				WebhookServer: &webhook.DefaultServer{
-					Options: webhook.Options{
-						Port:    configapi.DefaultWebhookPort,
-						CertDir: configapi.DefaultWebhookCertDir,
-					},
-				},
+			wantRest: rest.Config{
+				QPS:                50,
+				Burst:              int(configapi.DefaultClientConnectionBurst),
+				UserAgent:          useragent.Default(),
+				DisableCompression: true,
 			},
 		},
 		{
-			name:       "clientConnection config",
+			name:       "command-line flags",
 			configFile: clientConnectionConfig,
-			wantConfiguration: configapi.Configuration{
-				TypeMeta: metav1.TypeMeta{
-					APIVersion: configapi.GroupVersion.String(),
-					Kind:       "Configuration",
-				},
-				InternalCertManagement: enableDefaultInternalCertManagement,
-				ClientConnection: &configapi.ClientConnection{
-					QPS:   ptr.To[float32](50),
-					Burst: ptr.To[int32](100),
-				},
+			overrides: configapi.ClientConnection{
+				QPS:   ptr.To[float32](10),
+				Burst: ptr.To[int32](20),
+			},
+			want: configapi.ClientConnection{
+				QPS:                ptr.To[float32](10),
+				Burst:              ptr.To[int32](20),
+				UserAgent:          ptr.To(useragent.Default()),
+				DisableCompression: true,
+			},
+			wantRest: rest.Config{
+				QPS:                10,
+				Burst:              20,
+				UserAgent:          useragent.Default(),
+				DisableCompression: true,
 			},
-			wantOptions: defaultControlOptions,
-		},
-		{
-			name:       "invalid config",
-			configFile: invalidConfig,
-			wantError: runtime.NewStrictDecodingError([]error{
-				errors.New("unknown field \"invalidField\""),
-			}),
 		},
 	}
-
-	for _, tc := range testcases {
+	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			options, cfg, err := Load(testScheme, tc.configFile)
-			if tc.wantError == nil {
-				if err != nil {
-					t.Errorf("Unexpected error:%s", err)
-				}
-				if diff := cmp.Diff(tc.wantConfiguration, cfg, configCmpOpts...); diff != "" {
-					t.Errorf("Unexpected config (-want +got):\n%s", diff)
-				}
-				if diff := cmp.Diff(tc.wantOptions, options, ctrlOptsCmpOpts...); diff != "" {
-					t.Errorf("Unexpected options (-want +got):\n%s", diff)
-				}
-			} else {
-				if diff := cmp.Diff(tc.wantError.Error(), err.Error()); diff != "" {
-					t.Errorf("Unexpected error (-want +got):\n%s", diff)
-				}
+			result, err := LoadWithResult(testScheme, tc.configFile, true)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			got := result.ClientConnection(tc.overrides)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected client connection (-want +got):\n%s", diff)
+			}
+			// The returned settings are a copy, e.g. for the command-line flags to override.
+			*got.QPS = 1
+			if *result.Configuration.ClientConnection.QPS == 1 {
+				t.Error("Expected the client connection of the configuration to be left unchanged")
+			}
+			var kubeConfig rest.Config
+			ApplyClientConnection(&kubeConfig, result.ClientConnection(tc.overrides))
+			if diff := cmp.Diff(tc.wantRest, kubeConfig, cmpopts.IgnoreUnexported(rest.Config{})); diff != "" {
+				t.Errorf("Unexpected rest config (-want +got):\n%s", diff)
 			}
 		})
 	}
@@ -411,10 +2201,33 @@ func TestEncode(t *testing.T) {
 	defaultConfig := &configapi.Configuration{}
 	testScheme.Default(defaultConfig)
 
+	customClientConnectionConfig := defaultConfig.DeepCopy()
+	customClientConnectionConfig.ClientConnection.QPS = ptr.To[float32](50)
+
+	customUserAgentConfig := defaultConfig.DeepCopy()
+	customUserAgentConfig.ClientConnection.UserAgent = ptr.To("lws-audit")
+	disableCompressionConfig := defaultConfig.DeepCopy()
+	disableCompressionConfig.ClientConnection.DisableCompression = true
+
+	cacheResyncPeriodConfig := defaultConfig.DeepCopy()
+	cacheResyncPeriodConfig.Cache = &configapi.Cache{ResyncPeriod: &metav1.Duration{Duration: 30 * time.Minute}}
+
+	// The manager options once overridden by the command line flags.
+	appliedManagerOptions := ctrl.Options{
+		HealthProbeBindAddress:  ":8082",
+		Metrics:                 metricsserver.Options{BindAddress: ":8444"},
+		LeaderElection:          false,
+		LeaderElectionID:        configapi.DefaultLeaderElectionID,
+		LeaderElectionNamespace: "lws-system",
+		LeaseDuration:           ptr.To(30 * time.Second),
+		WebhookServer:           newLimitedServer(webhook.NewServer(webhook.Options{Port: 9444, CertDir: "/certs"}), 10),
+	}
+
 	testcases := []struct {
 		name       string
 		scheme     *runtime.Scheme
 		cfg        *configapi.Configuration
+		opts       []EncodeOption
 		wantResult map[string]any
 	}{
 
@@ -464,15 +2277,142 @@ func TestEncode(t *testing.T) {
 					"webhookSecretName":  configapi.DefaultWebhookSecretName,
 				},
 				"clientConnection": map[string]any{
-					"burst": int64(configapi.DefaultClientConnectionBurst),
-					"qps":   int64(configapi.DefaultClientConnectionQPS),
+					"burst":     int64(configapi.DefaultClientConnectionBurst),
+					"qps":       int64(configapi.DefaultClientConnectionQPS),
+					"userAgent": useragent.Default(),
+				},
+			},
+		},
+		{
+			name:   "default with omitted defaults",
+			scheme: testScheme,
+			cfg:    defaultConfig,
+			opts:   []EncodeOption{OmitDefaults()},
+			wantResult: map[string]any{
+				"apiVersion": "config.lws.x-k8s.io/v1alpha1",
+				"kind":       "Configuration",
+			},
+		},
+		{
+			name:   "custom section with omitted defaults",
+			scheme: testScheme,
+			cfg:    customClientConnectionConfig,
+			opts:   []EncodeOption{OmitDefaults()},
+			wantResult: map[string]any{
+				"apiVersion": "config.lws.x-k8s.io/v1alpha1",
+				"kind":       "Configuration",
+				"clientConnection": map[string]any{
+					"burst":     int64(configapi.DefaultClientConnectionBurst),
+					"qps":       int64(50),
+					"userAgent": useragent.Default(),
+				},
+			},
+		},
+		{
+			name:   "custom user agent with omitted defaults",
+			scheme: testScheme,
+			cfg:    customUserAgentConfig,
+			opts:   []EncodeOption{OmitDefaults()},
+			wantResult: map[string]any{
+				"apiVersion": "config.lws.x-k8s.io/v1alpha1",
+				"kind":       "Configuration",
+				"clientConnection": map[string]any{
+					"burst":     int64(configapi.DefaultClientConnectionBurst),
+					"qps":       int64(configapi.DefaultClientConnectionQPS),
+					"userAgent": "lws-audit",
+				},
+			},
+		},
+		{
+			name:   "disable compression with omitted defaults",
+			scheme: testScheme,
+			cfg:    disableCompressionConfig,
+			opts:   []EncodeOption{OmitDefaults()},
+			wantResult: map[string]any{
+				"apiVersion": "config.lws.x-k8s.io/v1alpha1",
+				"kind":       "Configuration",
+				"clientConnection": map[string]any{
+					"burst":              int64(configapi.DefaultClientConnectionBurst),
+					"qps":                int64(configapi.DefaultClientConnectionQPS),
+					"userAgent":          useragent.Default(),
+					"disableCompression": true,
+				},
+			},
+		},
+		{
+			name:   "default with the applied manager options",
+			scheme: testScheme,
+			cfg:    defaultConfig,
+			opts:   []EncodeOption{AppliedManagerOptions(appliedManagerOptions)},
+			wantResult: map[string]any{
+				"apiVersion": "config.lws.x-k8s.io/v1alpha1",
+				"kind":       "Configuration",
+				"webhook": map[string]any{
+					"port":    int64(9444),
+					"certDir": "/certs",
+				},
+				"metrics": map[string]any{
+					"bindAddress": ":8444",
+				},
+				"health": map[string]any{
+					"healthProbeBindAddress": ":8082",
+					"readinessEndpointName":  configapi.DefaultReadinessEndpoint,
+					"livenessEndpointName":   configapi.DefaultLivenessEndpoint,
+				},
+				"leaderElection": map[string]any{
+					"leaderElect":       false,
+					"leaseDuration":     "30s",
+					"renewDeadline":     defaultLeaderElectionRenewDeadline.String(),
+					"retryPeriod":       defaultLeaderElectionRetryPeriod.String(),
+					"resourceLock":      resourcelock.LeasesResourceLock,
+					"resourceName":      configapi.DefaultLeaderElectionID,
+					"resourceNamespace": "lws-system",
+				},
+				"internalCertManagement": map[string]any{
+					"enable":             true,
+					"webhookServiceName": configapi.DefaultWebhookServiceName,
+					"webhookSecretName":  configapi.DefaultWebhookSecretName,
+				},
+				"clientConnection": map[string]any{
+					"burst":     int64(configapi.DefaultClientConnectionBurst),
+					"qps":       int64(configapi.DefaultClientConnectionQPS),
+					"userAgent": useragent.Default(),
+				},
+			},
+		},
+		{
+			name:   "applied manager options with omitted defaults",
+			scheme: testScheme,
+			cfg:    defaultConfig,
+			opts: []EncodeOption{
+				AppliedManagerOptions(ctrl.Options{LeaderElection: true, Metrics: metricsserver.Options{BindAddress: DisabledMetricsBindAddress}}),
+				OmitDefaults(),
+			},
+			wantResult: map[string]any{
+				"apiVersion": "config.lws.x-k8s.io/v1alpha1",
+				"kind":       "Configuration",
+				"metrics": map[string]any{
+					"bindAddress": DisabledMetricsBindAddress,
+				},
+			},
+		},
+		{
+			name:   "cache resync period with omitted defaults",
+			scheme: testScheme,
+			cfg:    cacheResyncPeriodConfig,
+			opts:   []EncodeOption{OmitDefaults()},
+			wantResult: map[string]any{
+				"apiVersion": "config.lws.x-k8s.io/v1alpha1",
+				"kind":       "Configuration",
+				"cache": map[string]any{
+					"resyncPeriod": "30m0s",
 				},
 			},
 		},
 	}
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
-			got, err := Encode(tc.scheme, tc.cfg)
+			got, err := Encode(tc.scheme, tc.cfg, tc.opts...)
 			if err != nil {
 				t.Errorf("Unexpected error:%s", err)
 			}
@@ -487,3 +2427,169 @@ func TestEncode(t *testing.T) {
 		})
 	}
 }
+
+func TestEncodeObject(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	if err := configapi.AddToScheme(testScheme); err != nil {
+		t.Fatal(err)
+	}
+
+	defaultConfig := &configapi.Configuration{}
+	testScheme.Default(defaultConfig)
+
+	customConfig := defaultConfig.DeepCopy()
+	customConfig.ClientConnection.QPS = ptr.To[float32](50)
+	customConfig.Cache = &configapi.Cache{ResyncPeriod: &metav1.Duration{Duration: 30 * time.Minute}}
+	customConfig.MaxTotalManagedPods = 1000
+
+	testcases := []struct {
+		name string
+		cfg  *configapi.Configuration
+		opts []EncodeOption
+	}{
+		{
+			name: "empty",
+			cfg:  &configapi.Configuration{},
+		},
+		{
+			name: "default",
+			cfg:  defaultConfig,
+		},
+		{
+			name: "default with omitted defaults",
+			cfg:  defaultConfig,
+			opts: []EncodeOption{OmitDefaults()},
+		},
+		{
+			name: "custom",
+			cfg:  customConfig,
+		},
+		{
+			name: "custom with omitted defaults",
+			cfg:  customConfig,
+			opts: []EncodeOption{OmitDefaults()},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			obj, err := EncodeObject(testScheme, tc.cfg, tc.opts...)
+			if err != nil {
+				t.Fatalf("Unexpected error:%s", err)
+			}
+			if diff := cmp.Diff(configapi.GroupVersion.WithKind("Configuration"), obj.GroupVersionKind()); diff != "" {
+				t.Errorf("Unexpected group version kind (-want +got):\n%s", diff)
+			}
+
+			encoded, err := Encode(testScheme, tc.cfg, tc.opts...)
+			if err != nil {
+				t.Fatalf("Unexpected error:%s", err)
+			}
+			want := map[string]any{}
+			if err := yaml.Unmarshal([]byte(encoded), &want); err != nil {
+				t.Fatalf("Unable to unmarshal result:%s", err)
+			}
+			if diff := cmp.Diff(want, obj.Object); diff != "" {
+				t.Errorf("Unexpected object (-want +got):\n%s", diff)
+			}
+
+			// The object decodes back into the encoded configuration.
+			got := &configapi.Configuration{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, got); err != nil {
+				t.Fatalf("Unable to convert the object:%s", err)
+			}
+			wantCfg := tc.cfg.DeepCopy()
+			wantCfg.TypeMeta = metav1.TypeMeta{APIVersion: configapi.GroupVersion.String(), Kind: "Configuration"}
+			if len(tc.opts) != 0 {
+				testScheme.Default(wantCfg)
+				testScheme.Default(got)
+			}
+			if diff := cmp.Diff(wantCfg, got); diff != "" {
+				t.Errorf("Unexpected configuration (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateFile(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	if err := configapi.AddToScheme(testScheme); err != nil {
+		t.Fatal(err)
+	}
+	tmpDir := t.TempDir()
+	writeConfig := func(name, content string) string {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte(content), os.FileMode(0600)); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	testcases := []struct {
+		name          string
+		configFile    string
+		wantErrorKind error
+		wantError     string
+	}{
+		{
+			name: "valid config",
+			configFile: writeConfig("valid.yaml", `
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+maxTotalManagedPods: 1000
+metrics:
+  bindAddress: :8443
+  certFile: /etc/lws/metrics/tls.crt
+  keyFile: /etc/lws/metrics/tls.key
+`),
+		},
+		{
+			name: "invalid config",
+			configFile: writeConfig("invalid.yaml", `
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+maxTotalManagedPods: -1
+maxPodsPerLeaderWorkerSet: -1
+`),
+			wantErrorKind: ErrValidation,
+			wantError:     "[maxTotalManagedPods: Invalid value: -1: must be greater than or equal to 0, maxPodsPerLeaderWorkerSet: Invalid value: -1: must be greater than or equal to 0]",
+		},
+		{
+			name: "unknown field",
+			configFile: writeConfig("unknown-field.yaml", `
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+unknownField: true
+`),
+			wantErrorKind: ErrStrictDecoding,
+		},
+		{
+			name:          "malformed config",
+			configFile:    writeConfig("malformed.yaml", "apiVersion: ["),
+			wantErrorKind: ErrDecoding,
+		},
+		{
+			name:          "missing file",
+			configFile:    filepath.Join(tmpDir, "missing.yaml"),
+			wantErrorKind: ErrFileRead,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateFile(testScheme, tc.configFile)
+			if tc.wantErrorKind == nil {
+				if err != nil {
+					t.Errorf("Unexpected error:%s", err)
+				}
+				return
+			}
+			if !errors.Is(err, tc.wantErrorKind) {
+				t.Errorf("Unexpected error kind, want %v, got: %v", tc.wantErrorKind, err)
+			}
+			if tc.wantError != "" {
+				if diff := cmp.Diff(tc.wantError, err.Error()); diff != "" {
+					t.Errorf("Unexpected error (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
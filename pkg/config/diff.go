@@ -0,0 +1,107 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
+)
+
+// hotApplicableFields are the top-level fields only consumed when admitting or reconciling
+// objects. All the other fields are used to build the manager and require a restart.
+var hotApplicableFields = sets.New(
+	"topologyFile",
+	"rollingUpdateDefaults",
+	"maxTotalManagedPods",
+	"maxPodsPerLeaderWorkerSet",
+	"crashLoopDetection",
+	"allowedImageRegistries",
+	"rolloutWaveLabel",
+	"requiredLabels",
+	"securityContextDefaults",
+	"defaultAnnotations",
+	"leaderReadyPollInterval",
+	"maxConcurrentPodDeletes",
+	"defaultStartupPolicy",
+	"validatePodTemplates",
+	"stuckTerminatingPodTimeout",
+	"recreationBackoff",
+)
+
+var configAPIPkgPath = reflect.TypeFor[configapi.Configuration]().PkgPath()
+
+// DiffConfiguration returns the paths of the fields which differ between the old and new
+// configurations, e.g. "metrics.bindAddress", and whether any of them requires a restart
+// of the controller to be applied. The configurations are compared as they are, callers
+// comparing a decoded configuration with a loaded one should default both first.
+func DiffConfiguration(old, new *configapi.Configuration) (changed []string, restartRequired bool) {
+	changed = diffFields(reflect.ValueOf(old), reflect.ValueOf(new), nil)
+	for _, path := range changed {
+		section, _, _ := strings.Cut(path, ".")
+		if !hotApplicableFields.Has(section) {
+			restartRequired = true
+		}
+	}
+	return changed, restartRequired
+}
+
+// diffFields walks the types of the configuration API down to their leaf fields, the fields
+// of other types, e.g. the leader election configuration, are compared as a whole.
+func diffFields(oldValue, newValue reflect.Value, path *field.Path) []string {
+	if oldValue.Kind() == reflect.Pointer {
+		if oldValue.IsNil() || newValue.IsNil() {
+			if oldValue.IsNil() != newValue.IsNil() {
+				return []string{path.String()}
+			}
+			return nil
+		}
+		return diffFields(oldValue.Elem(), newValue.Elem(), path)
+	}
+
+	if oldValue.Kind() != reflect.Struct || oldValue.Type().PkgPath() != configAPIPkgPath {
+		if equality.Semantic.DeepEqual(oldValue.Interface(), newValue.Interface()) {
+			return nil
+		}
+		return []string{path.String()}
+	}
+
+	var changed []string
+	for i := range oldValue.NumField() {
+		structField := oldValue.Type().Field(i)
+		if structField.Type == reflect.TypeFor[metav1.TypeMeta]() {
+			continue
+		}
+		name, _, _ := strings.Cut(structField.Tag.Get("json"), ",")
+		fieldPath := path
+		if name != "" {
+			if path == nil {
+				fieldPath = field.NewPath(name)
+			} else {
+				fieldPath = path.Child(name)
+			}
+		}
+		changed = append(changed, diffFields(oldValue.Field(i), newValue.Field(i), fieldPath)...)
+	}
+	return changed
+}
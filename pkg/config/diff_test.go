@@ -0,0 +1,88 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	configv1alpha1 "k8s.io/component-base/config/v1alpha1"
+	"k8s.io/utils/ptr"
+
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
+)
+
+func TestDiffConfiguration(t *testing.T) {
+	newConfig := func() *configapi.Configuration {
+		cfg := &configapi.Configuration{}
+		configapi.SetDefaults_Configuration(cfg)
+		return cfg
+	}
+
+	testCases := map[string]struct {
+		update              func(*configapi.Configuration)
+		wantChanged         []string
+		wantRestartRequired bool
+	}{
+		"no changes": {
+			update: func(*configapi.Configuration) {},
+		},
+		"hot-applicable changes only": {
+			update: func(cfg *configapi.Configuration) {
+				cfg.RollingUpdateDefaults = &configapi.RollingUpdateDefaults{
+					MaxUnavailable: ptr.To(intstr.FromString("10%")),
+				}
+				cfg.TopologyFile = &configapi.TopologyFile{Enable: ptr.To(true)}
+				cfg.MaxTotalManagedPods = 100
+			},
+			wantChanged: []string{
+				"topologyFile",
+				"rollingUpdateDefaults",
+				"maxTotalManagedPods",
+			},
+		},
+		"restart-required changes": {
+			update: func(cfg *configapi.Configuration) {
+				cfg.Metrics.BindAddress = ":9443"
+				cfg.LeaderElection = &configv1alpha1.LeaderElectionConfiguration{LeaderElect: ptr.To(false)}
+				cfg.ClientConnection.QPS = ptr.To[float32](100)
+				cfg.MaxTotalManagedPods = 100
+			},
+			wantChanged: []string{
+				"leaderElection",
+				"metrics.bindAddress",
+				"clientConnection.qps",
+				"maxTotalManagedPods",
+			},
+			wantRestartRequired: true,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			old, new := newConfig(), newConfig()
+			tc.update(new)
+			changed, restartRequired := DiffConfiguration(old, new)
+			if diff := cmp.Diff(tc.wantChanged, changed); diff != "" {
+				t.Errorf("Unexpected changed fields (-want +got):\n%s", diff)
+			}
+			if restartRequired != tc.wantRestartRequired {
+				t.Errorf("Unexpected restartRequired, want %v, got %v", tc.wantRestartRequired, restartRequired)
+			}
+		})
+	}
+}
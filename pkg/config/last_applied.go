@@ -0,0 +1,139 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/yaml"
+
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
+)
+
+// lastApplied is the snapshot of the last applied config file persisted in the
+// lastAppliedConfigFile.
+type lastApplied struct {
+	// Hash is the SHA-256 of the content of the config file.
+	Hash string `json:"hash"`
+	// Fields are the paths of the fields set in the config file, e.g. "metrics.bindAddress".
+	Fields []string `json:"fields"`
+}
+
+// LogLastApplied runs CheckLastApplied and logs the omitted fields with the logger of ctx. As logr has
+// no warning level, they are logged as errors without an error. The errors reading or writing the
+// snapshot are logged as well, they don't prevent the config file from being applied.
+func LogLastApplied(ctx context.Context, configFile string, cfg *configapi.Configuration) {
+	log := ctrl.LoggerFrom(ctx)
+	reverted, err := CheckLastApplied(configFile, cfg)
+	if err != nil {
+		log.Error(err, "Unable to check the config file against the last applied one", "lastAppliedConfigFile", cfg.LastAppliedConfigFile)
+	}
+	for _, warning := range reverted {
+		log.Error(nil, "A field of the last applied configuration file is omitted", "warning", warning)
+	}
+}
+
+// CheckLastApplied compares the loaded config file with the snapshot of the last applied one
+// when lastAppliedConfigFile is set, and returns a warning for each field set in the last
+// applied config file but omitted from the loaded one, which silently reverts to its default.
+// The snapshot is then replaced with the one of the loaded config file. A missing snapshot,
+// e.g. on the first startup, or an empty config file path only record the snapshot.
+func CheckLastApplied(configFile string, cfg *configapi.Configuration) ([]string, error) {
+	if cfg.LastAppliedConfigFile == "" {
+		return nil, nil
+	}
+	current := lastApplied{Fields: []string{}}
+	if configFile != "" {
+		content, err := os.ReadFile(configFile)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(content)
+		current.Hash = hex.EncodeToString(sum[:])
+		var fields map[string]any
+		if err := yaml.Unmarshal(content, &fields); err != nil {
+			return nil, err
+		}
+		current.Fields = setFields(fields, nil)
+	}
+
+	var previous *lastApplied
+	content, err := os.ReadFile(cfg.LastAppliedConfigFile)
+	switch {
+	case err == nil:
+		previous = &lastApplied{}
+		if err := json.Unmarshal(content, previous); err != nil {
+			return nil, fmt.Errorf("decoding the last applied config file %q: %w", cfg.LastAppliedConfigFile, err)
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, err
+	}
+	if previous != nil && previous.Hash == current.Hash {
+		return nil, nil
+	}
+
+	var warnings []string
+	if previous != nil {
+		currentFields := sets.New(current.Fields...)
+		for _, path := range previous.Fields {
+			if !currentFields.Has(path) {
+				warnings = append(warnings, fmt.Sprintf("%s is set in the last applied config file but omitted, it reverts to its default", path))
+			}
+		}
+	}
+	snapshot, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(cfg.LastAppliedConfigFile, snapshot, 0o600); err != nil {
+		return warnings, fmt.Errorf("writing the last applied config file %q: %w", cfg.LastAppliedConfigFile, err)
+	}
+	return warnings, nil
+}
+
+// setFields returns the sorted paths of the leaf fields of a decoded config file, the lists and
+// the empty sections are leaves.
+func setFields(fields map[string]any, path *field.Path) []string {
+	var paths []string
+	for name, value := range fields {
+		if path == nil && (name == "apiVersion" || name == "kind") {
+			continue
+		}
+		fieldPath := field.NewPath(name)
+		if path != nil {
+			fieldPath = path.Child(name)
+		}
+		if section, ok := value.(map[string]any); ok && len(section) > 0 {
+			paths = append(paths, setFields(section, fieldPath)...)
+			continue
+		}
+		paths = append(paths, fieldPath.String())
+	}
+	slices.Sort(paths)
+	return paths
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
+)
+
+func TestCheckLastApplied(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "config.yaml")
+	cfg := &configapi.Configuration{LastAppliedConfigFile: filepath.Join(tmpDir, "last-applied-config.json")}
+	checkLastApplied := func(content string) []string {
+		t.Helper()
+		if err := os.WriteFile(configFile, []byte(content), os.FileMode(0600)); err != nil {
+			t.Fatal(err)
+		}
+		warnings, err := CheckLastApplied(configFile, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return warnings
+	}
+
+	// The first startup only records the snapshot.
+	if warnings := checkLastApplied(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+metrics:
+  bindAddress: :8443
+maxTotalManagedPods: 100
+`); len(warnings) != 0 {
+		t.Errorf("Unexpected warnings without a snapshot: %v", warnings)
+	}
+
+	// Omitting a previously set field triggers a warning.
+	warnings := checkLastApplied(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+metrics:
+  bindAddress: :8443
+`)
+	wantWarnings := []string{"maxTotalManagedPods is set in the last applied config file but omitted, it reverts to its default"}
+	if diff := cmp.Diff(wantWarnings, warnings); diff != "" {
+		t.Errorf("Unexpected warnings (-want +got):\n%s", diff)
+	}
+
+	// The snapshot was replaced, the same config file is not reported again.
+	if warnings := checkLastApplied(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+metrics:
+  bindAddress: :8443
+`); len(warnings) != 0 {
+		t.Errorf("Unexpected warnings for the last applied config file: %v", warnings)
+	}
+
+	// Changing a field isn't reported, omitting a nested one is.
+	warnings = checkLastApplied(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+metrics: {}
+maxTotalManagedPods: 10
+`)
+	wantWarnings = []string{"metrics.bindAddress is set in the last applied config file but omitted, it reverts to its default"}
+	if diff := cmp.Diff(wantWarnings, warnings); diff != "" {
+		t.Errorf("Unexpected warnings (-want +got):\n%s", diff)
+	}
+}
+
+func TestCheckLastAppliedDisabled(t *testing.T) {
+	warnings, err := CheckLastApplied(filepath.Join(t.TempDir(), "missing.yaml"), &configapi.Configuration{})
+	if err != nil || len(warnings) != 0 {
+		t.Errorf("Unexpected result (%v, %v) without a last applied config file", warnings, err)
+	}
+}
+
+func TestCheckLastAppliedWriteError(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("The permissions of the snapshot don't apply to root")
+	}
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "config.yaml")
+	if err := os.WriteFile(configFile, []byte("maxTotalManagedPods: 10\n"), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+	// The snapshot is read-only, the omitted fields are still returned along with the error.
+	snapshot := filepath.Join(tmpDir, "last-applied-config.json")
+	if err := os.WriteFile(snapshot, []byte(`{"hash":"previous","fields":["metrics.bindAddress"]}`), os.FileMode(0400)); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &configapi.Configuration{LastAppliedConfigFile: snapshot}
+	warnings, err := CheckLastApplied(configFile, cfg)
+	if err == nil {
+		t.Error("Expected an error writing the snapshot")
+	}
+	wantWarnings := []string{"metrics.bindAddress is set in the last applied config file but omitted, it reverts to its default"}
+	if diff := cmp.Diff(wantWarnings, warnings); diff != "" {
+		t.Errorf("Unexpected warnings (-want +got):\n%s", diff)
+	}
+}
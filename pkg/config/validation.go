@@ -17,22 +17,162 @@ limitations under the License.
 package config
 
 import (
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
 	"strings"
 
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	apimachineryvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	tracingv1 "k8s.io/component-base/tracing/api/v1"
 	"k8s.io/utils/ptr"
 
 	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/features"
+	"sigs.k8s.io/lws/pkg/utils"
+	"sigs.k8s.io/lws/pkg/utils/health"
 )
 
 var (
 	internalCertManagementPath = field.NewPath("internalCertManagement")
+	topologyFilePath           = field.NewPath("topologyFile")
+	rollingUpdateDefaultsPath  = field.NewPath("rollingUpdateDefaults")
+	cachePath                  = field.NewPath("cache")
+	watchNamespacesPath        = field.NewPath("watchNamespaces")
+	metricsPath                = field.NewPath("metrics")
+	maxTotalManagedPodsPath    = field.NewPath("maxTotalManagedPods")
+	maxPodsPerLWSPath          = field.NewPath("maxPodsPerLeaderWorkerSet")
+	recommendedLabelsPath      = field.NewPath("recommendedLabels")
+	crashLoopDetectionPath     = field.NewPath("crashLoopDetection")
+	recreationBackoffPath      = field.NewPath("recreationBackoff")
+	reconcileTimeoutPath       = field.NewPath("reconcileTimeout")
+	allowedImageRegistriesPath = field.NewPath("allowedImageRegistries")
+	featureGatesPath           = field.NewPath("featureGates")
+	rolloutWaveLabelPath       = field.NewPath("rolloutWaveLabel")
+	statusUpdateDebouncePath   = field.NewPath("statusUpdateDebounce")
+	requiredLabelsPath         = field.NewPath("requiredLabels")
+	tracingPath                = field.NewPath("tracing")
+	defaultAnnotationsPath     = field.NewPath("defaultAnnotations")
+	leaderReadyIntervalPath    = field.NewPath("leaderReadyPollInterval")
+	maxPodDeletesPath          = field.NewPath("maxConcurrentPodDeletes")
+	defaultStartupPolicyPath   = field.NewPath("defaultStartupPolicy")
+	webhookPath                = field.NewPath("webhook")
+	leaderElectionPath         = field.NewPath("leaderElection")
+	stuckTerminatingPodPath    = field.NewPath("stuckTerminatingPodTimeout")
+	readinessChecksPath        = field.NewPath("health", "readinessChecks")
+	lastAppliedConfigFilePath  = field.NewPath("lastAppliedConfigFile")
 )
 
 func validate(c *configapi.Configuration) field.ErrorList {
 	var allErrs field.ErrorList
 	allErrs = append(allErrs, validateInternalCertManagement(c)...)
+	allErrs = append(allErrs, validateTopologyFile(c)...)
+	allErrs = append(allErrs, validateRollingUpdateDefaults(c)...)
+	allErrs = append(allErrs, validateCache(c)...)
+	allErrs = append(allErrs, validateMetrics(c)...)
+	allErrs = append(allErrs, validateRecommendedLabels(c)...)
+	allErrs = append(allErrs, validateCrashLoopDetection(c)...)
+	allErrs = append(allErrs, validateRecreationBackoff(c)...)
+	if c.LeaderElection != nil && c.LeaderElection.ResourceNamespace != "" {
+		for _, msg := range apimachineryvalidation.IsDNS1123Label(c.LeaderElection.ResourceNamespace) {
+			allErrs = append(allErrs, field.Invalid(leaderElectionPath.Child("resourceNamespace"), c.LeaderElection.ResourceNamespace, msg))
+		}
+	}
+	if c.MaxTotalManagedPods < 0 {
+		allErrs = append(allErrs, field.Invalid(maxTotalManagedPodsPath, c.MaxTotalManagedPods, "must be greater than or equal to 0"))
+	}
+	if c.MaxPodsPerLeaderWorkerSet < 0 {
+		allErrs = append(allErrs, field.Invalid(maxPodsPerLWSPath, c.MaxPodsPerLeaderWorkerSet, "must be greater than or equal to 0"))
+	}
+	if c.ReconcileTimeout != nil && c.ReconcileTimeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(reconcileTimeoutPath, c.ReconcileTimeout.Duration.String(), "must be greater than 0"))
+	}
+	if c.LeaderReadyPollInterval != nil && c.LeaderReadyPollInterval.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(leaderReadyIntervalPath, c.LeaderReadyPollInterval.Duration.String(), "must be greater than 0"))
+	}
+	if c.MaxConcurrentPodDeletes != nil && *c.MaxConcurrentPodDeletes <= 0 {
+		allErrs = append(allErrs, field.Invalid(maxPodDeletesPath, *c.MaxConcurrentPodDeletes, "must be greater than 0"))
+	}
+	if c.Webhook.MaxConcurrentRequests != nil && *c.Webhook.MaxConcurrentRequests <= 0 {
+		allErrs = append(allErrs, field.Invalid(webhookPath.Child("maxConcurrentRequests"), *c.Webhook.MaxConcurrentRequests, "must be greater than 0"))
+	}
+	seenReadinessChecks := sets.New[string]()
+	for i, check := range c.Health.ReadinessChecks {
+		if !slices.Contains(health.Checks(), check) {
+			allErrs = append(allErrs, field.NotSupported(readinessChecksPath.Index(i), check, health.Checks()))
+		} else if seenReadinessChecks.Has(check) {
+			allErrs = append(allErrs, field.Duplicate(readinessChecksPath.Index(i), check))
+		}
+		seenReadinessChecks.Insert(check)
+	}
+	seenNamespaces := sets.New[string]()
+	for i, namespace := range c.WatchNamespaces {
+		for _, msg := range apimachineryvalidation.IsDNS1123Label(namespace) {
+			allErrs = append(allErrs, field.Invalid(watchNamespacesPath.Index(i), namespace, msg))
+		}
+		if seenNamespaces.Has(namespace) {
+			allErrs = append(allErrs, field.Duplicate(watchNamespacesPath.Index(i), namespace))
+		}
+		seenNamespaces.Insert(namespace)
+	}
+	for i, registry := range c.AllowedImageRegistries {
+		// An empty prefix would silently allow all the images.
+		if registry == "" {
+			allErrs = append(allErrs, field.Required(allowedImageRegistriesPath.Index(i), "must not be empty"))
+		}
+	}
+	// The feature gates are set on a copy, they are applied to the controller once loaded.
+	featureGates := features.DefaultFeatureGate.DeepCopy()
+	if err := featureGates.SetFromMap(c.FeatureGates); err != nil {
+		allErrs = append(allErrs, field.Invalid(featureGatesPath, c.FeatureGates, err.Error()))
+	}
+	switch leaderworkerset.StartupPolicyType(c.DefaultStartupPolicy) {
+	case "", leaderworkerset.LeaderCreatedStartupPolicy, leaderworkerset.LeaderReadyStartupPolicy:
+	case leaderworkerset.AllLeadersReadyStartupPolicy:
+		if !featureGates.Enabled(features.AllLeadersReadyStartupPolicy) {
+			allErrs = append(allErrs, field.Invalid(defaultStartupPolicyPath, c.DefaultStartupPolicy, fmt.Sprintf("requires the %s feature gate", features.AllLeadersReadyStartupPolicy)))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(defaultStartupPolicyPath, c.DefaultStartupPolicy, []leaderworkerset.StartupPolicyType{
+			leaderworkerset.LeaderCreatedStartupPolicy, leaderworkerset.LeaderReadyStartupPolicy, leaderworkerset.AllLeadersReadyStartupPolicy}))
+	}
+	if c.StuckTerminatingPodTimeout != nil {
+		if c.StuckTerminatingPodTimeout.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(stuckTerminatingPodPath, c.StuckTerminatingPodTimeout.Duration.String(), "must be greater than 0"))
+		} else if !featureGates.Enabled(features.ForceDeleteStuckTerminatingPods) && !featureGates.Enabled(features.DryRunReconcile) {
+			allErrs = append(allErrs, field.Invalid(stuckTerminatingPodPath, c.StuckTerminatingPodTimeout.Duration.String(), fmt.Sprintf("requires the %s or the %s feature gate", features.ForceDeleteStuckTerminatingPods, features.DryRunReconcile)))
+		}
+	}
+	if c.RolloutWaveLabel != "" {
+		if errs := apimachineryvalidation.IsQualifiedName(c.RolloutWaveLabel); len(errs) > 0 {
+			allErrs = append(allErrs, field.Invalid(rolloutWaveLabelPath, c.RolloutWaveLabel, strings.Join(errs, ",")))
+		}
+	}
+	if c.StatusUpdateDebounce != nil && c.StatusUpdateDebounce.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(statusUpdateDebouncePath, c.StatusUpdateDebounce.Duration.String(), "must be greater than or equal to 0"))
+	}
+	for i, key := range c.RequiredLabels {
+		if errs := apimachineryvalidation.IsQualifiedName(key); len(errs) > 0 {
+			allErrs = append(allErrs, field.Invalid(requiredLabelsPath.Index(i), key, strings.Join(errs, ",")))
+		}
+	}
+	if c.LastAppliedConfigFile != "" && !path.IsAbs(c.LastAppliedConfigFile) {
+		allErrs = append(allErrs, field.Invalid(lastAppliedConfigFilePath, c.LastAppliedConfigFile, "must be an absolute path"))
+	}
+	// The tracing isn't gated by a feature of the controller.
+	allErrs = append(allErrs, tracingv1.ValidateTracingConfiguration(c.Tracing, nil, tracingPath)...)
+	allErrs = append(allErrs, apivalidation.ValidateAnnotations(c.DefaultAnnotations, defaultAnnotationsPath)...)
+	for key := range c.DefaultAnnotations {
+		if strings.HasPrefix(key, "leaderworkerset.sigs.k8s.io/") {
+			allErrs = append(allErrs, field.Invalid(defaultAnnotationsPath.Key(key), key, "must not use the prefix of the annotations of the controller"))
+		}
+	}
 	return allErrs
 }
 
@@ -53,3 +193,146 @@ func validateInternalCertManagement(c *configapi.Configuration) field.ErrorList
 	}
 	return allErrs
 }
+
+func validateTopologyFile(c *configapi.Configuration) field.ErrorList {
+	var allErrs field.ErrorList
+	if c.TopologyFile == nil || !ptr.Deref(c.TopologyFile.Enable, false) {
+		return allErrs
+	}
+	if image := c.TopologyFile.Image; image != nil && len(*image) == 0 {
+		allErrs = append(allErrs, field.Required(topologyFilePath.Child("image"), "must not be empty when the topology file is enabled"))
+	}
+	if mountPath := c.TopologyFile.MountPath; mountPath != nil && !path.IsAbs(*mountPath) {
+		allErrs = append(allErrs, field.Invalid(topologyFilePath.Child("mountPath"), *mountPath, "must be an absolute path"))
+	}
+	if resources := c.TopologyFile.Resources; resources != nil {
+		allErrs = append(allErrs, utils.ValidateResourceRequirements(topologyFilePath.Child("resources"), *resources)...)
+	}
+	return allErrs
+}
+
+func validateRollingUpdateDefaults(c *configapi.Configuration) field.ErrorList {
+	var allErrs field.ErrorList
+	if c.RollingUpdateDefaults == nil {
+		return allErrs
+	}
+	maxUnavailable := ptr.Deref(c.RollingUpdateDefaults.MaxUnavailable, intstr.FromInt32(configapi.DefaultRollingUpdateMaxUnavailable))
+	maxSurge := ptr.Deref(c.RollingUpdateDefaults.MaxSurge, intstr.FromInt32(configapi.DefaultRollingUpdateMaxSurge))
+	allErrs = append(allErrs, validateIntOrPercent(maxUnavailable, rollingUpdateDefaultsPath.Child("maxUnavailable"))...)
+	allErrs = append(allErrs, validateIntOrPercent(maxSurge, rollingUpdateDefaultsPath.Child("maxSurge"))...)
+	if len(allErrs) == 0 && isZeroIntOrPercent(maxUnavailable) && isZeroIntOrPercent(maxSurge) {
+		allErrs = append(allErrs, field.Invalid(rollingUpdateDefaultsPath.Child("maxUnavailable"), maxUnavailable.String(), "must not be 0 when maxSurge is 0"))
+	}
+	return allErrs
+}
+
+// validateIntOrPercent validates that the value is a non-negative integer or a
+// percentage not greater than 100%.
+func validateIntOrPercent(value intstr.IntOrString, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if value.Type == intstr.String {
+		if errs := apimachineryvalidation.IsValidPercent(value.StrVal); len(errs) != 0 {
+			return append(allErrs, field.Invalid(fldPath, value.StrVal, strings.Join(errs, ",")))
+		}
+		if percent, _ := strconv.Atoi(strings.TrimSuffix(value.StrVal, "%")); percent > 100 {
+			allErrs = append(allErrs, field.Invalid(fldPath, value.StrVal, "must not be greater than 100%"))
+		}
+		return allErrs
+	}
+	if value.IntVal < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath, value.IntVal, "must be greater than or equal to 0"))
+	}
+	return allErrs
+}
+
+func isZeroIntOrPercent(value intstr.IntOrString) bool {
+	if value.Type == intstr.String {
+		percent, _ := strconv.Atoi(strings.TrimSuffix(value.StrVal, "%"))
+		return percent == 0
+	}
+	return value.IntVal == 0
+}
+
+func validateCache(c *configapi.Configuration) field.ErrorList {
+	var allErrs field.ErrorList
+	if c.Cache == nil {
+		return allErrs
+	}
+	if c.Cache.ResyncPeriod != nil && c.Cache.ResyncPeriod.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(cachePath.Child("resyncPeriod"), c.Cache.ResyncPeriod.Duration.String(), "must be greater than 0"))
+	}
+	if c.Cache.PodLabelSelector == nil {
+		return allErrs
+	}
+	if _, err := labels.Parse(*c.Cache.PodLabelSelector); err != nil {
+		allErrs = append(allErrs, field.Invalid(cachePath.Child("podLabelSelector"), *c.Cache.PodLabelSelector, err.Error()))
+	}
+	return allErrs
+}
+
+func validateMetrics(c *configapi.Configuration) field.ErrorList {
+	var allErrs field.ErrorList
+	if c.Metrics.CertFile != "" && c.Metrics.KeyFile == "" {
+		allErrs = append(allErrs, field.Required(metricsPath.Child("keyFile"), "must be set when certFile is set"))
+	}
+	if c.Metrics.KeyFile != "" && c.Metrics.CertFile == "" {
+		allErrs = append(allErrs, field.Required(metricsPath.Child("certFile"), "must be set when keyFile is set"))
+	}
+	return allErrs
+}
+
+func validateRecommendedLabels(c *configapi.Configuration) field.ErrorList {
+	var allErrs field.ErrorList
+	if c.RecommendedLabels == nil {
+		return allErrs
+	}
+	allErrs = append(allErrs, validateLabelValue(c.RecommendedLabels.ManagedBy, recommendedLabelsPath.Child("managedBy"))...)
+	allErrs = append(allErrs, validateLabelValue(c.RecommendedLabels.PartOf, recommendedLabelsPath.Child("partOf"))...)
+	return allErrs
+}
+
+func validateLabelValue(value *string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if value == nil {
+		return allErrs
+	}
+	for _, msg := range apimachineryvalidation.IsValidLabelValue(*value) {
+		allErrs = append(allErrs, field.Invalid(fldPath, *value, msg))
+	}
+	return allErrs
+}
+
+func validateCrashLoopDetection(c *configapi.Configuration) field.ErrorList {
+	var allErrs field.ErrorList
+	if c.CrashLoopDetection == nil {
+		return allErrs
+	}
+	if threshold := c.CrashLoopDetection.RestartThreshold; threshold != nil && *threshold < 1 {
+		allErrs = append(allErrs, field.Invalid(crashLoopDetectionPath.Child("restartThreshold"), *threshold, "must be greater than or equal to 1"))
+	}
+	if window := c.CrashLoopDetection.Window; window != nil && window.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(crashLoopDetectionPath.Child("window"), window.Duration.String(), "must be greater than 0"))
+	}
+	return allErrs
+}
+
+func validateRecreationBackoff(c *configapi.Configuration) field.ErrorList {
+	var allErrs field.ErrorList
+	if c.RecreationBackoff == nil {
+		return allErrs
+	}
+	backoff := c.RecreationBackoff
+	if backoff.Base != nil && backoff.Base.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(recreationBackoffPath.Child("base"), backoff.Base.Duration.String(), "must be greater than 0"))
+	}
+	if backoff.Cap != nil && backoff.Base != nil && backoff.Cap.Duration < backoff.Base.Duration {
+		allErrs = append(allErrs, field.Invalid(recreationBackoffPath.Child("cap"), backoff.Cap.Duration.String(), "must be greater than or equal to the base"))
+	}
+	if jitter := backoff.JitterPercent; jitter != nil && (*jitter < 0 || *jitter > 100) {
+		allErrs = append(allErrs, field.Invalid(recreationBackoffPath.Child("jitterPercent"), *jitter, "must be between 0 and 100"))
+	}
+	if backoff.ResetAfter != nil && backoff.ResetAfter.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(recreationBackoffPath.Child("resetAfter"), backoff.ResetAfter.Duration.String(), "must be greater than 0"))
+	}
+	return allErrs
+}
@@ -18,9 +18,12 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/ptr"
 
@@ -77,6 +80,301 @@ func TestValidate(t *testing.T) {
 				},
 			},
 		},
+		"invalid .topologyFile.mountPath": {
+			cfg: &configapi.Configuration{
+				TopologyFile: &configapi.TopologyFile{
+					Enable:    ptr.To(true),
+					Image:     ptr.To("busybox"),
+					MountPath: ptr.To("etc/lws"),
+				},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "topologyFile.mountPath",
+				},
+			},
+		},
+		"empty .topologyFile.image": {
+			cfg: &configapi.Configuration{
+				TopologyFile: &configapi.TopologyFile{
+					Enable:    ptr.To(true),
+					Image:     ptr.To(""),
+					MountPath: ptr.To("/etc/lws"),
+				},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "topologyFile.image",
+				},
+			},
+		},
+		"disabled .topologyFile with invalid .topologyFile.mountPath": {
+			cfg: &configapi.Configuration{
+				TopologyFile: &configapi.TopologyFile{
+					Enable:    ptr.To(false),
+					MountPath: ptr.To("etc/lws"),
+				},
+			},
+		},
+		"valid .rollingUpdateDefaults": {
+			cfg: &configapi.Configuration{
+				RollingUpdateDefaults: &configapi.RollingUpdateDefaults{
+					MaxUnavailable: ptr.To(intstr.FromString("25%")),
+					MaxSurge:       ptr.To(intstr.FromInt32(0)),
+				},
+			},
+		},
+		"invalid .rollingUpdateDefaults.maxUnavailable": {
+			cfg: &configapi.Configuration{
+				RollingUpdateDefaults: &configapi.RollingUpdateDefaults{
+					MaxUnavailable: ptr.To(intstr.FromString("200%")),
+					MaxSurge:       ptr.To(intstr.FromInt32(-1)),
+				},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "rollingUpdateDefaults.maxUnavailable",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "rollingUpdateDefaults.maxSurge",
+				},
+			},
+		},
+		"zero .rollingUpdateDefaults.maxUnavailable and .rollingUpdateDefaults.maxSurge": {
+			cfg: &configapi.Configuration{
+				RollingUpdateDefaults: &configapi.RollingUpdateDefaults{
+					MaxUnavailable: ptr.To(intstr.FromString("0%")),
+					MaxSurge:       ptr.To(intstr.FromInt32(0)),
+				},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "rollingUpdateDefaults.maxUnavailable",
+				},
+			},
+		},
+		"valid .cache.podLabelSelector": {
+			cfg: &configapi.Configuration{
+				Cache: &configapi.Cache{
+					PodLabelSelector: ptr.To("leaderworkerset.sigs.k8s.io/name,app!=test"),
+				},
+			},
+		},
+		"invalid .recommendedLabels.partOf": {
+			cfg: &configapi.Configuration{
+				RecommendedLabels: &configapi.RecommendedLabels{
+					ManagedBy: ptr.To("lws"),
+					PartOf:    ptr.To("-inference"),
+				},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "recommendedLabels.partOf",
+				},
+			},
+		},
+		"invalid .crashLoopDetection": {
+			cfg: &configapi.Configuration{
+				CrashLoopDetection: &configapi.CrashLoopDetection{
+					RestartThreshold: ptr.To[int32](0),
+					Window:           &metav1.Duration{},
+				},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "crashLoopDetection.restartThreshold",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "crashLoopDetection.window",
+				},
+			},
+		},
+		"invalid .recreationBackoff": {
+			cfg: &configapi.Configuration{
+				RecreationBackoff: &configapi.RecreationBackoff{
+					Base:          &metav1.Duration{Duration: time.Minute},
+					Cap:           &metav1.Duration{Duration: time.Second},
+					JitterPercent: ptr.To[int32](101),
+					ResetAfter:    &metav1.Duration{},
+				},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "recreationBackoff.cap",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "recreationBackoff.jitterPercent",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "recreationBackoff.resetAfter",
+				},
+			},
+		},
+		"negative reconcileTimeout": {
+			cfg: &configapi.Configuration{
+				ReconcileTimeout: &metav1.Duration{Duration: -time.Second},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "reconcileTimeout",
+				},
+			},
+		},
+		"invalid and duplicate watchNamespaces": {
+			cfg: &configapi.Configuration{
+				WatchNamespaces: []string{"team-a", "Team_B", "team-a"},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "watchNamespaces[1]",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeDuplicate,
+					Field: "watchNamespaces[2]",
+				},
+			},
+		},
+		"empty allowedImageRegistries entry": {
+			cfg: &configapi.Configuration{
+				AllowedImageRegistries: []string{"registry.example.com/", ""},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "allowedImageRegistries[1]",
+				},
+			},
+		},
+		"unknown feature gate": {
+			cfg: &configapi.Configuration{
+				FeatureGates: map[string]bool{"UnknownFeature": true},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "featureGates",
+				},
+			},
+		},
+		"invalid rolloutWaveLabel": {
+			cfg: &configapi.Configuration{
+				RolloutWaveLabel: "example.com/rollout wave",
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "rolloutWaveLabel",
+				},
+			},
+		},
+		"invalid requiredLabels key": {
+			cfg: &configapi.Configuration{
+				RequiredLabels: []string{"example.com/team", "cost center"},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "requiredLabels[1]",
+				},
+			},
+		},
+		"invalid defaultAnnotations keys": {
+			cfg: &configapi.Configuration{
+				DefaultAnnotations: map[string]string{
+					"invalid key": "false",
+				},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "defaultAnnotations",
+				},
+			},
+		},
+		"controller prefix in defaultAnnotations keys": {
+			cfg: &configapi.Configuration{
+				DefaultAnnotations: map[string]string{
+					"leaderworkerset.sigs.k8s.io/disable-pod-injection": "true",
+				},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "defaultAnnotations[leaderworkerset.sigs.k8s.io/disable-pod-injection]",
+				},
+			},
+		},
+		"negative maxPodsPerLeaderWorkerSet": {
+			cfg: &configapi.Configuration{
+				MaxPodsPerLeaderWorkerSet: -1,
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "maxPodsPerLeaderWorkerSet",
+				},
+			},
+		},
+		"negative maxTotalManagedPods": {
+			cfg: &configapi.Configuration{
+				MaxTotalManagedPods: -1,
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "maxTotalManagedPods",
+				},
+			},
+		},
+		"metrics certFile without keyFile": {
+			cfg: &configapi.Configuration{
+				ControllerManager: configapi.ControllerManager{
+					Metrics: configapi.ControllerMetrics{CertFile: "/etc/lws/metrics/tls.crt"},
+				},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "metrics.keyFile",
+				},
+			},
+		},
+		"metrics certFile and keyFile": {
+			cfg: &configapi.Configuration{
+				ControllerManager: configapi.ControllerManager{
+					Metrics: configapi.ControllerMetrics{
+						CertFile: "/etc/lws/metrics/tls.crt",
+						KeyFile:  "/etc/lws/metrics/tls.key",
+					},
+				},
+			},
+		},
+		"invalid .cache.podLabelSelector": {
+			cfg: &configapi.Configuration{
+				Cache: &configapi.Cache{
+					PodLabelSelector: ptr.To("app in (a"),
+				},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "cache.podLabelSelector",
+				},
+			},
+		},
 	}
 
 	for name, tc := range testCases {
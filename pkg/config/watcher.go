@@ -0,0 +1,149 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
+)
+
+// Watcher reloads the config file when its content changes, and hands the configuration
+// with the changes of the hot-applicable fields to its handlers. The changes of the other
+// fields are logged, they are only applied on the next restart of the controller.
+type Watcher struct {
+	scheme     *runtime.Scheme
+	configFile string
+	strict     bool
+	interval   time.Duration
+
+	mu sync.Mutex
+	// applied is the configuration handed to the handlers, the one of the startup with the
+	// changes of the hot-applicable fields.
+	applied configapi.Configuration
+	// hash is the SHA-256 of the content of the config file last loaded.
+	hash     []byte
+	handlers []func(*configapi.Configuration)
+}
+
+var _ manager.LeaderElectionRunnable = &Watcher{}
+
+// NewWatcher returns a watcher checking the config file every interval, cfg is the
+// configuration the controller was started with. The file is loaded like LoadWithResult.
+func NewWatcher(scheme *runtime.Scheme, configFile string, strict bool, cfg configapi.Configuration, interval time.Duration) *Watcher {
+	return &Watcher{
+		scheme:     scheme,
+		configFile: configFile,
+		strict:     strict,
+		interval:   interval,
+		applied:    *cfg.DeepCopy(),
+	}
+}
+
+// OnReload registers a handler called with the configuration each time hot-applicable fields
+// change. It is called once when registered, so that it catches up with the reloads done
+// before, e.g. while the controllers were set up.
+func (w *Watcher) OnReload(handler func(*configapi.Configuration)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.handlers = append(w.handlers, handler)
+	handler(w.applied.DeepCopy())
+}
+
+// NeedLeaderElection is false, the webhooks of all the replicas apply the configuration.
+func (w *Watcher) NeedLeaderElection() bool {
+	return false
+}
+
+func (w *Watcher) Start(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx).WithName("config-watcher")
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		w.reload(ctrl.LoggerInto(ctx, log))
+	}, w.interval)
+	return nil
+}
+
+// reload loads the config file if its content changed, applies the changes of the
+// hot-applicable fields and reports the ones requiring a restart, as well as the fields
+// omitted since the last applied config file.
+func (w *Watcher) reload(ctx context.Context) {
+	log := ctrl.LoggerFrom(ctx)
+	content, err := os.ReadFile(w.configFile)
+	if err != nil {
+		log.Error(err, "Unable to read the config file", "file", w.configFile)
+		return
+	}
+	sum := sha256.Sum256(content)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if bytes.Equal(w.hash, sum[:]) {
+		return
+	}
+	result, err := LoadWithResult(w.scheme, w.configFile, w.strict)
+	if err != nil {
+		log.Error(err, "Unable to reload the config file, keeping the current configuration", "file", w.configFile)
+		return
+	}
+	w.hash = sum[:]
+	// The path of the snapshot requires a restart to change, the one of the startup is kept.
+	LogLastApplied(ctx, w.configFile, &w.applied)
+
+	changed, _ := DiffConfiguration(&w.applied, &result.Configuration)
+	var hotFields, restartFields []string
+	for _, path := range changed {
+		if section, _, _ := strings.Cut(path, "."); hotApplicableFields.Has(section) {
+			hotFields = append(hotFields, path)
+		} else {
+			restartFields = append(restartFields, path)
+		}
+	}
+	if len(restartFields) > 0 {
+		log.Info("The changed fields of the config file require a restart of the controller to be applied", "fields", restartFields)
+	}
+	if len(hotFields) == 0 {
+		return
+	}
+	copyHotApplicableFields(&w.applied, &result.Configuration)
+	log.Info("Applying the reloaded configuration", "fields", hotFields)
+	for _, handler := range w.handlers {
+		handler(w.applied.DeepCopy())
+	}
+}
+
+// copyHotApplicableFields sets the hot-applicable fields of dst to their value in src.
+func copyHotApplicableFields(dst, src *configapi.Configuration) {
+	dstValue, srcValue := reflect.ValueOf(dst).Elem(), reflect.ValueOf(src).Elem()
+	for i := range dstValue.NumField() {
+		name, _, _ := strings.Cut(dstValue.Type().Field(i).Tag.Get("json"), ",")
+		if hotApplicableFields.Has(name) {
+			dstValue.Field(i).Set(srcValue.Field(i))
+		}
+	}
+}
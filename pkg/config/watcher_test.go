@@ -0,0 +1,185 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
+)
+
+func TestWatcherReload(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := configapi.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(configFile, []byte(content), os.FileMode(0600)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeConfig(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+metrics:
+  bindAddress: :8443
+maxPodsPerLeaderWorkerSet: 64
+`)
+	_, cfg, err := Load(scheme, configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := NewWatcher(scheme, configFile, true, cfg, time.Second)
+
+	var reloads []*configapi.Configuration
+	w.OnReload(func(cfg *configapi.Configuration) {
+		reloads = append(reloads, cfg)
+	})
+	if len(reloads) != 1 || reloads[0].MaxPodsPerLeaderWorkerSet != 64 {
+		t.Fatalf("Expected the handler to be called with the current configuration when registered, got %v", reloads)
+	}
+
+	// The unchanged config file isn't applied again.
+	w.reload(context.TODO())
+	if len(reloads) != 1 {
+		t.Errorf("Unexpected reload of the unchanged config file, got %d reloads", len(reloads))
+	}
+
+	// The hot-applicable fields are applied, the others wait for the restart.
+	writeConfig(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+metrics:
+  bindAddress: :9443
+maxPodsPerLeaderWorkerSet: 128
+`)
+	w.reload(context.TODO())
+	if len(reloads) != 2 {
+		t.Fatalf("Expected the changed config file to be applied, got %d reloads", len(reloads))
+	}
+	if got := reloads[1].MaxPodsPerLeaderWorkerSet; got != 128 {
+		t.Errorf("Expected the reloaded maxPodsPerLeaderWorkerSet 128, got %d", got)
+	}
+	if got := reloads[1].Metrics.BindAddress; got != ":8443" {
+		t.Errorf("Expected the metrics bind address of the startup :8443 until the restart, got %s", got)
+	}
+
+	// Only changing fields requiring a restart applies nothing.
+	writeConfig(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+metrics:
+  bindAddress: :10443
+maxPodsPerLeaderWorkerSet: 128
+`)
+	w.reload(context.TODO())
+	if len(reloads) != 2 {
+		t.Errorf("Unexpected reload when only the fields requiring a restart changed, got %d reloads", len(reloads))
+	}
+
+	// An invalid config file keeps the current configuration, and is loaded once fixed.
+	writeConfig(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+maxPodsPerLeaderWorkerSet: -1
+`)
+	w.reload(context.TODO())
+	if len(reloads) != 2 {
+		t.Errorf("Unexpected reload of the invalid config file, got %d reloads", len(reloads))
+	}
+	writeConfig(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+maxPodsPerLeaderWorkerSet: 32
+`)
+	w.reload(context.TODO())
+	if len(reloads) != 3 || reloads[2].MaxPodsPerLeaderWorkerSet != 32 {
+		t.Errorf("Expected the fixed config file to be applied, got %v", reloads)
+	}
+
+	// A handler registered late catches up with the reloads.
+	var late *configapi.Configuration
+	w.OnReload(func(cfg *configapi.Configuration) {
+		late = cfg
+	})
+	if late == nil || late.MaxPodsPerLeaderWorkerSet != 32 {
+		t.Errorf("Expected the late handler to be called with the reloaded configuration, got %v", late)
+	}
+}
+
+func TestWatcherReloadLastApplied(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := configapi.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "config.yaml")
+	lastAppliedFile := filepath.Join(tmpDir, "last-applied-config.json")
+	writeConfig := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(configFile, []byte(content), os.FileMode(0600)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	snapshotFields := func() []string {
+		t.Helper()
+		content, err := os.ReadFile(lastAppliedFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var snapshot lastApplied
+		if err := json.Unmarshal(content, &snapshot); err != nil {
+			t.Fatal(err)
+		}
+		return snapshot.Fields
+	}
+
+	writeConfig(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+lastAppliedConfigFile: ` + lastAppliedFile + `
+maxPodsPerLeaderWorkerSet: 64
+`)
+	_, cfg, err := Load(scheme, configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	LogLastApplied(context.TODO(), configFile, &cfg)
+	w := NewWatcher(scheme, configFile, true, cfg, time.Second)
+
+	// The snapshot follows the reloaded config file.
+	writeConfig(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+lastAppliedConfigFile: ` + lastAppliedFile + `
+`)
+	w.reload(context.TODO())
+	if diff := cmp.Diff([]string{"lastAppliedConfigFile"}, snapshotFields()); diff != "" {
+		t.Errorf("Unexpected fields of the snapshot (-want +got):\n%s", diff)
+	}
+}
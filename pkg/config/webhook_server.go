@@ -0,0 +1,55 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"net/http"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// limitedServer is a webhook server serving at most cap(slots) admission requests concurrently,
+// across all its webhooks. The requests over the limit wait for a slot until they are cancelled,
+// the API server then applies the failure policy of the webhook.
+type limitedServer struct {
+	webhook.Server
+	slots chan struct{}
+}
+
+func newLimitedServer(server webhook.Server, maxConcurrentRequests int) *limitedServer {
+	return &limitedServer{
+		Server: server,
+		slots:  make(chan struct{}, maxConcurrentRequests),
+	}
+}
+
+// Register registers the webhook behind the concurrency limit of the server.
+func (s *limitedServer) Register(path string, hook http.Handler) {
+	s.Server.Register(path, s.limit(hook))
+}
+
+func (s *limitedServer) limit(hook http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case s.slots <- struct{}{}:
+			defer func() { <-s.slots }()
+			hook.ServeHTTP(w, r)
+		case <-r.Context().Done():
+			http.Error(w, "too many concurrent admission requests", http.StatusServiceUnavailable)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+func TestLimitedServer(t *testing.T) {
+	server := newLimitedServer(webhook.NewServer(webhook.Options{}), 1)
+	started, release := make(chan struct{}), make(chan struct{})
+	server.Register("/validate", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+	}))
+	serve := func(ctx context.Context) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		server.WebhookMux().ServeHTTP(rec, httptest.NewRequestWithContext(ctx, http.MethodPost, "/validate", nil))
+		return rec
+	}
+
+	// The first request holds the only slot until it is released.
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- serve(context.Background()) }()
+	<-started
+
+	// The requests over the limit wait for a slot until they are cancelled.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if rec := serve(ctx); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected the request over the limit to be rejected, got status %d", rec.Code)
+	}
+
+	// A waiting request is served once the slot is released.
+	waiting := make(chan *httptest.ResponseRecorder)
+	go func() { waiting <- serve(context.Background()) }()
+	close(release)
+	if rec := <-done; rec.Code != http.StatusOK {
+		t.Errorf("Expected the first request to be served, got status %d", rec.Code)
+	}
+	<-started
+	if rec := <-waiting; rec.Code != http.StatusOK {
+		t.Errorf("Expected the waiting request to be served, got status %d", rec.Code)
+	}
+}
@@ -18,17 +18,28 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	appsapplyv1 "k8s.io/client-go/applyconfigurations/apps/v1"
 	coreapplyv1 "k8s.io/client-go/applyconfigurations/core/v1"
 	metaapplyv1 "k8s.io/client-go/applyconfigurations/meta/v1"
@@ -36,13 +47,20 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/features"
+	"sigs.k8s.io/lws/pkg/metrics"
 	"sigs.k8s.io/lws/pkg/utils"
 	controllerutils "sigs.k8s.io/lws/pkg/utils/controller"
+	"sigs.k8s.io/lws/pkg/utils/naming"
 	podutils "sigs.k8s.io/lws/pkg/utils/pod"
 	revisionutils "sigs.k8s.io/lws/pkg/utils/revision"
 	statefulsetutils "sigs.k8s.io/lws/pkg/utils/statefulset"
@@ -53,6 +71,83 @@ type LeaderWorkerSetReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
 	Record record.EventRecorder
+
+	// config holds the fields applied again when the configuration is reloaded, each
+	// reconciliation uses the configuration loaded when it starts.
+	config atomic.Pointer[leaderWorkerSetReconcilerConfig]
+	// recommendedLabels are stamped on the leader statefulsets and the headless services.
+	recommendedLabels map[string]string
+	// reconcileTimeout bounds the duration of each reconciliation, 0 means unbounded.
+	reconcileTimeout time.Duration
+	// drainQueueOnShutdown reconciles the queued requests to completion when the manager stops.
+	drainQueueOnShutdown bool
+	// watchPersistentVolumeClaims reconciles the lws on the events of the claims labeled with its name.
+	watchPersistentVolumeClaims bool
+	// namer names the headless services.
+	namer naming.Namer
+	// statusUpdateDebounce coalesces the status writes of each lws within the interval after
+	// its last one, 0 means every change of the status is written immediately.
+	statusUpdateDebounce time.Duration
+	// statusWrites tracks the last status write of each lws for the debounce.
+	statusWrites statusWrites
+}
+
+// leaderWorkerSetReconcilerConfig are the fields of the LeaderWorkerSetReconciler which can be
+// changed without a restart.
+type leaderWorkerSetReconcilerConfig struct {
+	// maxTotalManagedPods caps the pods managed across all the lws, zero means unlimited.
+	maxTotalManagedPods int32
+	// maxConcurrentPodDeletes caps the pods of the groups removed at once, rounded to whole groups, zero means unlimited.
+	maxConcurrentPodDeletes int32
+	// defaultAnnotations are stamped on the headless services.
+	defaultAnnotations map[string]string
+	// crashLoopDetection pauses the rolling updates while groups of the new revision are
+	// crash-looping, nil means disabled.
+	crashLoopDetection *configapi.CrashLoopDetection
+	// rolloutWaveLabel is the key of the label stamped on the pods with the rollout wave of
+	// their group, empty means the pods are not labeled.
+	rolloutWaveLabel string
+}
+
+// statusWrites tracks when the status of each lws was last written, and for which generation.
+type statusWrites struct {
+	sync.Mutex
+	last map[types.NamespacedName]statusWrite
+}
+
+type statusWrite struct {
+	time       time.Time
+	generation int64
+}
+
+// delay returns how long the status write of the lws is deferred, 0 if it is written
+// immediately. The status of a new generation of the lws is always written immediately.
+func (w *statusWrites) delay(lws *leaderworkerset.LeaderWorkerSet, debounce time.Duration, now time.Time) time.Duration {
+	if debounce <= 0 {
+		return 0
+	}
+	w.Lock()
+	defer w.Unlock()
+	last, found := w.last[client.ObjectKeyFromObject(lws)]
+	if !found || last.generation != lws.Generation {
+		return 0
+	}
+	return max(last.time.Add(debounce).Sub(now), 0)
+}
+
+func (w *statusWrites) record(lws *leaderworkerset.LeaderWorkerSet, now time.Time) {
+	w.Lock()
+	defer w.Unlock()
+	if w.last == nil {
+		w.last = make(map[types.NamespacedName]statusWrite)
+	}
+	w.last[client.ObjectKeyFromObject(lws)] = statusWrite{time: now, generation: lws.Generation}
+}
+
+func (w *statusWrites) forget(key types.NamespacedName) {
+	w.Lock()
+	defer w.Unlock()
+	delete(w.last, key)
 }
 
 var (
@@ -62,28 +157,96 @@ var (
 const (
 	lwsOwnerKey  = ".metadata.controller"
 	fieldManager = "lws"
+
+	// maxGroupStatuses bounds the number of groups reported in the status.
+	maxGroupStatuses = 100
+
+	// podQuotaRequeuePeriod is how often a lws held back by maxTotalManagedPods
+	// checks whether enough pods were deleted to create its pending groups.
+	podQuotaRequeuePeriod = 15 * time.Second
+
+	// podDeleteRequeuePeriod is how often a lws removing its groups in batches checks
+	// whether the pods of the previous batch are gone.
+	podDeleteRequeuePeriod = 5 * time.Second
+
+	// idleRequeuePeriod is how often an idle lws is reconciled, bounding the time since its last
+	// reconcile reported by lws_seconds_since_last_reconcile while the controller services it.
+	idleRequeuePeriod = 5 * time.Minute
 )
 
+// summarizedPodConditions are the pod conditions summarized per group in the status, in order.
+var summarizedPodConditions = []corev1.PodConditionType{
+	corev1.PodScheduled,
+	corev1.PodInitialized,
+	corev1.ContainersReady,
+	corev1.PodReady,
+}
+
 const (
 	// FailedCreate Event reason used when a resource creation fails.
 	// The event uses the error(s) as the reason.
 	FailedCreate      = "FailedCreate"
 	GroupsProgressing = "GroupsProgressing"
 	GroupsUpdating    = "GroupsUpdating"
+	GroupsRecreating  = "GroupsRecreating"
 	CreatingRevision  = "CreatingRevision"
+	PodQuotaExceeded  = "PodQuotaExceeded"
+	LeaderNotReady    = "LeaderNotReady"
+	CrashLooping      = "CrashLooping"
+	DuplicatePod      = "DuplicatePod"
+	ScaledToZero      = "ScaledToZero"
+	// ForceDeletedStuckPod Event reason used when a pod stuck terminating is force deleted.
+	ForceDeletedStuckPod = "ForceDeletedStuckPod"
 )
 
-func NewLeaderWorkerSetReconciler(client client.Client, scheme *runtime.Scheme, record record.EventRecorder) *LeaderWorkerSetReconciler {
-	return &LeaderWorkerSetReconciler{
-		Client: client,
-		Scheme: scheme,
-		Record: record,
+func NewLeaderWorkerSetReconciler(client client.Client, scheme *runtime.Scheme, record record.EventRecorder, cfg *configapi.Configuration) *LeaderWorkerSetReconciler {
+	r := &LeaderWorkerSetReconciler{
+		Client:                      client,
+		Scheme:                      scheme,
+		Record:                      record,
+		recommendedLabels:           utils.RecommendedLabels(cfg.RecommendedLabels),
+		reconcileTimeout:            reconcileTimeout(cfg),
+		drainQueueOnShutdown:        cfg.DrainQueueOnShutdown,
+		watchPersistentVolumeClaims: cfg.WatchPersistentVolumeClaims,
+		namer:                       naming.Default(),
+		statusUpdateDebounce:        statusUpdateDebounce(cfg),
+	}
+	r.ApplyConfiguration(cfg)
+	return r
+}
+
+// ApplyConfiguration sets the fields of the reconciler which can be changed without a restart,
+// the in-flight reconciliations complete with the previous ones.
+func (r *LeaderWorkerSetReconciler) ApplyConfiguration(cfg *configapi.Configuration) {
+	r.config.Store(&leaderWorkerSetReconcilerConfig{
+		maxTotalManagedPods:     cfg.MaxTotalManagedPods,
+		maxConcurrentPodDeletes: ptr.Deref(cfg.MaxConcurrentPodDeletes, 0),
+		defaultAnnotations:      cfg.DefaultAnnotations,
+		crashLoopDetection:      cfg.CrashLoopDetection,
+		rolloutWaveLabel:        cfg.RolloutWaveLabel,
+	})
+}
+
+// statusUpdateDebounce returns the configured status update debounce, 0 if not set.
+func statusUpdateDebounce(cfg *configapi.Configuration) time.Duration {
+	if cfg.StatusUpdateDebounce == nil {
+		return 0
+	}
+	return cfg.StatusUpdateDebounce.Duration
+}
+
+// reconcileTimeout returns the configured reconcile timeout of the controllers, 0 if not set.
+func reconcileTimeout(cfg *configapi.Configuration) time.Duration {
+	if cfg.ReconcileTimeout == nil {
+		return 0
 	}
+	return cfg.ReconcileTimeout.Duration
 }
 
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;watch;update;patch
 //+kubebuilder:rbac:groups=leaderworkerset.x-k8s.io,resources=leaderworkersets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=leaderworkerset.x-k8s.io,resources=leaderworkersets/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch
 //+kubebuilder:rbac:groups=leaderworkerset.x-k8s.io,resources=leaderworkersets/finalizers,verbs=update
 //+kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=apps,resources=statefulsets/status,verbs=get;update;patch
@@ -95,13 +258,25 @@ func NewLeaderWorkerSetReconciler(client client.Client, scheme *runtime.Scheme,
 // +kubebuilder:rbac:groups=apps,resources=controllerrevisions/finalizers,verbs=update
 
 func (r *LeaderWorkerSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	cfg := r.config.Load()
+
 	// Get leaderworkerset object
 	lws := &leaderworkerset.LeaderWorkerSet{}
 	if err := r.Get(ctx, types.NamespacedName{Name: req.Name, Namespace: req.Namespace}, lws); err != nil {
+		if apierrors.IsNotFound(err) {
+			metrics.GroupsWaitingForLeader.DeleteLabelValues(req.Namespace, req.Name)
+			metrics.ReadyGroupsWithUnreadyLeader.DeleteLabelValues(req.Namespace, req.Name)
+			r.statusWrites.forget(req.NamespacedName)
+			metrics.LastReconciles.Forget(req.NamespacedName)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
 	if lws.DeletionTimestamp != nil {
+		metrics.GroupsWaitingForLeader.DeleteLabelValues(req.Namespace, req.Name)
+		metrics.ReadyGroupsWithUnreadyLeader.DeleteLabelValues(req.Namespace, req.Name)
+		r.statusWrites.forget(req.NamespacedName)
+		metrics.LastReconciles.Forget(req.NamespacedName)
 		return ctrl.Result{}, nil
 	}
 
@@ -130,63 +305,163 @@ func (r *LeaderWorkerSetReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	}
 	lwsUpdated := updatedRevision != nil
 	if lwsUpdated {
+		previousRevision := revision
 		revision, err = revisionutils.CreateRevision(ctx, r.Client, updatedRevision, lws)
 		if err != nil {
 			log.Error(err, "Creating revision for updated LWS")
 			return ctrl.Result{}, err
 		}
-		r.Record.Eventf(lws, corev1.EventTypeNormal, CreatingRevision, fmt.Sprintf("Creating revision with key %s for updated LWS", revisionutils.GetRevisionKey(revision)))
+		r.Record.Eventf(lws, corev1.EventTypeNormal, CreatingRevision, "Creating revision with key %s for updated LWS", revisionutils.GetRevisionKey(revision))
+		if lws.Spec.RolloutStrategy.MetadataUpdatePolicy == leaderworkerset.InPlaceMetadataUpdatePolicy {
+			updatedInPlace, err := r.updateMetadataInPlace(ctx, lws, previousRevision, revision)
+			if err != nil {
+				log.Error(err, "Updating the labels and annotations of the groups in place")
+				return ctrl.Result{}, err
+			}
+			// The groups already run the updated revision, there is nothing to roll out.
+			lwsUpdated = !updatedInPlace
+		}
 	}
 
-	partition, replicas, err := r.rollingUpdateParameters(ctx, lws, leaderSts, revisionutils.GetRevisionKey(revision), lwsUpdated)
+	var partition, replicas int32
+	if lws.Spec.RolloutStrategy.Type == leaderworkerset.RecreateStrategyType {
+		partition, replicas, err = r.recreateParameters(ctx, lws, leaderSts, revisionutils.GetRevisionKey(revision))
+	} else {
+		partition, replicas, err = r.rollingUpdateParameters(ctx, lws, leaderSts, revisionutils.GetRevisionKey(revision), lwsUpdated)
+	}
 	if err != nil {
 		log.Error(err, "Rolling partition error")
 		return ctrl.Result{}, err
 	}
 
+	partition, rolloutStalled, err := r.crashLoopPartition(ctx, cfg, lws, leaderSts, revisionutils.GetRevisionKey(revision), partition)
+	if err != nil {
+		log.Error(err, "Detecting crash-looping groups")
+		return ctrl.Result{}, err
+	}
+
+	partition, err = r.inPlaceUpdatePartition(ctx, lws, leaderSts, revisionutils.GetRevisionKey(revision), partition)
+	if err != nil {
+		log.Error(err, "Handing over the groups updated in place to the leader statefulset")
+		return ctrl.Result{}, err
+	}
+
+	partition, err = r.stickyGroupsPartition(ctx, lws, leaderSts, revisionutils.GetRevisionKey(revision), partition)
+	if err != nil {
+		log.Error(err, "Holding the sticky groups at their revision")
+		return ctrl.Result{}, err
+	}
+
+	replicas, requeueAfter, err := r.rateLimitedReplicas(ctx, lws, leaderSts, replicas)
+	if err != nil {
+		log.Error(err, "Rate limiting the scale-up")
+		return ctrl.Result{}, err
+	}
+	wantReplicas := replicas
+	replicas, err = r.podQuotaReplicas(ctx, cfg, lws, leaderSts, replicas)
+	if err != nil {
+		log.Error(err, "Capping the replicas to the managed pods quota")
+		return ctrl.Result{}, err
+	}
+	podQuotaExceeded := replicas < wantReplicas
+	if podQuotaExceeded && (requeueAfter == 0 || requeueAfter > podQuotaRequeuePeriod) {
+		requeueAfter = podQuotaRequeuePeriod
+	}
+	replicas, drainRequeueAfter, err := r.drainReplicas(ctx, lws, leaderSts, replicas)
+	if err != nil {
+		log.Error(err, "Draining the groups removed on scale-down")
+		return ctrl.Result{}, err
+	}
+	if drainRequeueAfter > 0 && (requeueAfter == 0 || requeueAfter > drainRequeueAfter) {
+		requeueAfter = drainRequeueAfter
+	}
+	replicas, deleteRequeueAfter, err := r.batchedDeleteReplicas(ctx, cfg, lws, leaderSts, replicas)
+	if err != nil {
+		log.Error(err, "Removing the groups in batches")
+		return ctrl.Result{}, err
+	}
+	if deleteRequeueAfter > 0 && (requeueAfter == 0 || requeueAfter > deleteRequeueAfter) {
+		requeueAfter = deleteRequeueAfter
+	}
+	// The restarts leave the window without any pod update, recheck them once they did.
+	if rolloutStalled != "" && (requeueAfter == 0 || requeueAfter > cfg.crashLoopDetection.Window.Duration) {
+		requeueAfter = cfg.crashLoopDetection.Window.Duration
+	}
+	partition = min(partition, replicas)
+
 	if err := r.SSAWithStatefulset(ctx, lws, partition, replicas, revisionutils.GetRevisionKey(revision)); err != nil {
 		if leaderSts == nil {
-			r.Record.Eventf(lws, corev1.EventTypeWarning, FailedCreate, fmt.Sprintf("Failed to create leader statefulset %s", lws.Name))
+			r.Record.Eventf(lws, corev1.EventTypeWarning, FailedCreate, "Failed to create leader statefulset %s", lws.Name)
 		}
 		return ctrl.Result{}, err
 	}
 
 	if leaderSts == nil {
 		// An event is logged to track sts creation.
-		r.Record.Eventf(lws, corev1.EventTypeNormal, GroupsProgressing, fmt.Sprintf("Created leader statefulset %s", lws.Name))
+		r.Record.Eventf(lws, corev1.EventTypeNormal, GroupsProgressing, "Created leader statefulset %s", lws.Name)
 	} else if !lwsUpdated && partition != *leaderSts.Spec.UpdateStrategy.RollingUpdate.Partition {
 		// An event is logged to track update progress.
-		r.Record.Eventf(lws, corev1.EventTypeNormal, GroupsUpdating, fmt.Sprintf("Updating replicas %d to %d", *leaderSts.Spec.UpdateStrategy.RollingUpdate.Partition, partition))
+		r.Record.Eventf(lws, corev1.EventTypeNormal, GroupsUpdating, "Updating replicas %d to %d", *leaderSts.Spec.UpdateStrategy.RollingUpdate.Partition, partition)
+	}
+
+	if err := r.deleteRemovedWorkerStatefulSets(ctx, lws, replicas); err != nil {
+		log.Error(err, "Deleting the worker statefulsets of the removed groups")
+		return ctrl.Result{}, err
 	}
 
 	// Create headless service if it does not exist.
-	if err := r.reconcileHeadlessServices(ctx, lws); err != nil {
+	if err := r.reconcileHeadlessServices(ctx, cfg, lws); err != nil {
 		log.Error(err, "Creating headless service.")
 		r.Record.Eventf(lws, corev1.EventTypeWarning, FailedCreate,
-			fmt.Sprintf("Failed to create headless service for error: %v", err))
+			"Failed to create headless service for error: %v", err)
+		return ctrl.Result{}, err
+	}
+
+	if err := r.adoptOrphanPods(ctx, lws); err != nil {
+		log.Error(err, "Adopting the orphan pods")
+		return ctrl.Result{}, err
+	}
+
+	if err := r.deleteDuplicatePods(ctx, lws); err != nil {
+		log.Error(err, "Deleting the pods with duplicate group indices")
 		return ctrl.Result{}, err
 	}
 
-	updateDone, err := r.updateStatus(ctx, lws, revisionutils.GetRevisionKey(revision))
+	if cfg.rolloutWaveLabel != "" {
+		if err := r.labelRolloutWaves(ctx, cfg, lws); err != nil {
+			log.Error(err, "Labeling the rollout waves of the pods")
+			return ctrl.Result{}, err
+		}
+	}
+
+	updateDone, statusDelay, err := r.updateStatus(ctx, lws, revisionutils.GetRevisionKey(revision), podQuotaExceeded, rolloutStalled)
 	if err != nil {
 		if apierrors.IsConflict(err) {
 			return ctrl.Result{Requeue: true}, nil
 		}
 		return ctrl.Result{}, err
 	}
+	// The deferred status write is done once the debounce interval elapsed.
+	if statusDelay > 0 && (requeueAfter == 0 || requeueAfter > statusDelay) {
+		requeueAfter = statusDelay
+	}
 
 	if updateDone {
 		if err := revisionutils.TruncateRevisions(ctx, r.Client, lws, revisionutils.GetRevisionKey(revision)); err != nil {
 			return ctrl.Result{}, err
 		}
 	}
+	metrics.LastReconciles.Record(req.NamespacedName, time.Now())
+	if requeueAfter == 0 || requeueAfter > idleRequeuePeriod {
+		requeueAfter = idleRequeuePeriod
+	}
 	log.V(2).Info("Leader Reconcile completed.")
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
-func (r *LeaderWorkerSetReconciler) reconcileHeadlessServices(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) error {
+func (r *LeaderWorkerSetReconciler) reconcileHeadlessServices(ctx context.Context, cfg *leaderWorkerSetReconcilerConfig, lws *leaderworkerset.LeaderWorkerSet) error {
 	if lws.Spec.NetworkConfig == nil || *lws.Spec.NetworkConfig.SubdomainPolicy == leaderworkerset.SubdomainShared {
-		if err := controllerutils.CreateHeadlessServiceIfNotExists(ctx, r.Client, r.Scheme, lws, lws.Name, map[string]string{leaderworkerset.SetNameLabelKey: lws.Name}, lws); err != nil {
+		if err := controllerutils.ReconcileHeadlessService(ctx, r.Client, r.Scheme, lws, r.namer.ServiceName(lws.Name), map[string]string{leaderworkerset.SetNameLabelKey: lws.Name}, lws, r.recommendedLabels, cfg.defaultAnnotations); err != nil {
 			return err
 		}
 		return nil
@@ -194,9 +469,246 @@ func (r *LeaderWorkerSetReconciler) reconcileHeadlessServices(ctx context.Contex
 	return nil
 }
 
+// deleteRemovedWorkerStatefulSets deletes the worker statefulsets of the leaderless groups removed on
+// scale-down, which aren't garbage collected with their candidate 0 since the lws owns them as well.
+func (r *LeaderWorkerSetReconciler) deleteRemovedWorkerStatefulSets(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, replicas int32) error {
+	if leaderless, _ := utils.ParseLeaderless(lws.Annotations); !leaderless {
+		return nil
+	}
+	var stsList appsv1.StatefulSetList
+	if err := r.List(ctx, &stsList, client.InNamespace(lws.Namespace), client.MatchingLabels{leaderworkerset.SetNameLabelKey: lws.Name}); err != nil {
+		return err
+	}
+	for i := range stsList.Items {
+		sts := &stsList.Items[i]
+		groupIndex, err := strconv.Atoi(sts.Labels[leaderworkerset.GroupIndexLabelKey])
+		if err != nil || int32(groupIndex) < replicas || metav1.IsControlledBy(sts, lws) || sts.DeletionTimestamp != nil {
+			continue
+		}
+		if err := deleteWorkerStatefulSet(ctx, r.Client, sts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// adoptOrphanPods sets the statefulsets of the lws as the controllers of its pods left without one,
+// e.g. the pods of a hand-rolled deployment being migrated, so that they are converged instead of
+// recreated. The worker pods are adopted once the worker statefulset of their group is created.
+func (r *LeaderWorkerSetReconciler) adoptOrphanPods(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) error {
+	var podList corev1.PodList
+	if err := r.List(ctx, &podList, client.InNamespace(lws.Namespace), client.MatchingLabels{leaderworkerset.SetNameLabelKey: lws.Name}); err != nil {
+		return err
+	}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.DeletionTimestamp != nil || metav1.GetControllerOf(pod) != nil {
+			continue
+		}
+		stsName, ordinal := statefulsetutils.GetParentNameAndOrdinal(pod.Name)
+		if ordinal == -1 {
+			continue
+		}
+		var sts appsv1.StatefulSet
+		if err := r.Get(ctx, types.NamespacedName{Namespace: lws.Namespace, Name: stsName}, &sts); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		adoptable, err := podAdoptable(lws, &sts, pod, ordinal)
+		if err != nil {
+			return err
+		}
+		if !adoptable {
+			continue
+		}
+		patch := client.MergeFromWithOptions(pod.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		pod.OwnerReferences = append(pod.OwnerReferences, *metav1.NewControllerRef(&sts, appsv1.SchemeGroupVersion.WithKind("StatefulSet")))
+		if err := r.Patch(ctx, pod, patch); err != nil {
+			return err
+		}
+		ctrl.LoggerFrom(ctx).V(2).Info("Adopted the orphan pod", "pod", klog.KObj(pod), "statefulset", klog.KObj(&sts))
+	}
+	return nil
+}
+
+// podAdoptable returns whether the pod can be adopted by the statefulset, the statefulset must be
+// one of the lws and the pod must be labeled like the pods it creates, for one of its ordinals.
+func podAdoptable(lws *leaderworkerset.LeaderWorkerSet, sts *appsv1.StatefulSet, pod *corev1.Pod, ordinal int) (bool, error) {
+	if sts.Labels[leaderworkerset.SetNameLabelKey] != lws.Name || sts.DeletionTimestamp != nil {
+		return false, nil
+	}
+	// The leader statefulset is controlled by the lws, the worker ones by the leader pod of their group.
+	owner := metav1.GetControllerOf(sts)
+	if owner == nil {
+		return false, nil
+	}
+	if sts.Name == lws.Name {
+		if owner.UID != lws.UID {
+			return false, nil
+		}
+	} else if owner.Kind != "Pod" || owner.Name != sts.Name {
+		return false, nil
+	}
+	start := 0
+	if sts.Spec.Ordinals != nil {
+		start = int(sts.Spec.Ordinals.Start)
+	}
+	if ordinal < start || ordinal >= start+int(ptr.Deref(sts.Spec.Replicas, 1)) {
+		return false, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(sts.Spec.Selector)
+	if err != nil {
+		return false, err
+	}
+	if !selector.Matches(labels.Set(pod.Labels)) {
+		return false, nil
+	}
+	// The leader pods are the ordinals of the leader statefulset, the workers of the worker ones.
+	workerIndex, groupIndex := strconv.Itoa(ordinal), ""
+	if sts.Name == lws.Name {
+		workerIndex, groupIndex = "0", strconv.Itoa(ordinal)
+	}
+	if pod.Labels[leaderworkerset.WorkerIndexLabelKey] != workerIndex {
+		return false, nil
+	}
+	if index, found := pod.Labels[leaderworkerset.GroupIndexLabelKey]; found && groupIndex != "" && index != groupIndex {
+		return false, nil
+	}
+	return true, nil
+}
+
+// deleteDuplicatePods deletes the pods claiming the group and worker indices of other pods, e.g.
+// after a manual edit of their labels, which break the DNS of the groups. Only the pods owned by
+// the statefulsets of these indices are kept.
+func (r *LeaderWorkerSetReconciler) deleteDuplicatePods(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) error {
+	var podList corev1.PodList
+	if err := r.List(ctx, &podList, client.InNamespace(lws.Namespace), client.MatchingLabels{leaderworkerset.SetNameLabelKey: lws.Name}); err != nil {
+		return err
+	}
+	for _, pod := range duplicatePods(lws, podList.Items) {
+		if err := r.Delete(ctx, pod); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		r.Record.Eventf(lws, corev1.EventTypeWarning, DuplicatePod, "Deleted pod %s duplicating the group index %s and worker index %s",
+			pod.Name, pod.Labels[leaderworkerset.GroupIndexLabelKey], pod.Labels[leaderworkerset.WorkerIndexLabelKey])
+	}
+	return nil
+}
+
+// duplicatePods returns the pods labeled with the same group and worker indices as the pod owned
+// by the statefulset of these indices, e.g. the pods test-sample-1 and test-sample-2 both labeled
+// with the group index 1 resolve to test-sample-1. The indices without an owned pod are left to
+// the adoption and the statefulsets, since there is no pod to keep yet.
+func duplicatePods(lws *leaderworkerset.LeaderWorkerSet, pods []corev1.Pod) []*corev1.Pod {
+	type podIndices struct{ group, worker string }
+	claimants := map[podIndices][]*corev1.Pod{}
+	owned := map[podIndices]bool{}
+	for i := range pods {
+		pod := &pods[i]
+		groupIndex, found := pod.Labels[leaderworkerset.GroupIndexLabelKey]
+		if !found || pod.DeletionTimestamp != nil {
+			continue
+		}
+		indices := podIndices{group: groupIndex, worker: pod.Labels[leaderworkerset.WorkerIndexLabelKey]}
+		claimants[indices] = append(claimants[indices], pod)
+		if podOwnsIndices(lws, pod, indices.group, indices.worker) {
+			owned[indices] = true
+		}
+	}
+
+	var duplicates []*corev1.Pod
+	for indices, claims := range claimants {
+		if len(claims) < 2 || !owned[indices] {
+			continue
+		}
+		for _, pod := range claims {
+			if !podOwnsIndices(lws, pod, indices.group, indices.worker) {
+				duplicates = append(duplicates, pod)
+			}
+		}
+	}
+	slices.SortFunc(duplicates, func(a, b *corev1.Pod) int { return strings.Compare(a.Name, b.Name) })
+	return duplicates
+}
+
+// podOwnsIndices returns whether the pod is the one created by the statefulsets of the lws for the
+// group and worker indices, the leader pods are named after the group index and the worker pods
+// after both indices.
+func podOwnsIndices(lws *leaderworkerset.LeaderWorkerSet, pod *corev1.Pod, groupIndex, workerIndex string) bool {
+	name := fmt.Sprintf("%s-%s", lws.Name, groupIndex)
+	if workerIndex != "0" {
+		name = fmt.Sprintf("%s-%s-%s", lws.Name, groupIndex, workerIndex)
+	}
+	owner := metav1.GetControllerOf(pod)
+	if pod.Name != name || owner == nil || owner.Kind != "StatefulSet" {
+		return false
+	}
+	stsName, _ := statefulsetutils.GetParentNameAndOrdinal(pod.Name)
+	return owner.Name == stsName
+}
+
+// labelRolloutWaves stamps the pods of the lws with the rollout wave of their group, pods
+// created since the last reconciliation or whose wave changed with the spec are patched.
+func (r *LeaderWorkerSetReconciler) labelRolloutWaves(ctx context.Context, cfg *leaderWorkerSetReconcilerConfig, lws *leaderworkerset.LeaderWorkerSet) error {
+	var podList corev1.PodList
+	if err := r.List(ctx, &podList, client.InNamespace(lws.Namespace), client.MatchingLabels{leaderworkerset.SetNameLabelKey: lws.Name}); err != nil {
+		return err
+	}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		// The group index of the leader pods is persisted by the pod controller.
+		groupIndex, err := strconv.Atoi(pod.Labels[leaderworkerset.GroupIndexLabelKey])
+		if err != nil {
+			continue
+		}
+		wave, err := rolloutWave(lws, int32(groupIndex))
+		if err != nil {
+			return err
+		}
+		if pod.Labels[cfg.rolloutWaveLabel] == strconv.Itoa(int(wave)) {
+			continue
+		}
+		patch := client.MergeFrom(pod.DeepCopy())
+		pod.Labels[cfg.rolloutWaveLabel] = strconv.Itoa(int(wave))
+		if err := r.Patch(ctx, pod, patch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rolloutWave returns the 0-based batch in which a rolling update updates the group. The
+// leader statefulset is updated from its last ordinal, including the surge groups, down to
+// 0 in steps of maxUnavailable plus maxSurge groups, e.g. with 4 replicas, maxUnavailable 2
+// and maxSurge 2, the groups 2 to 5 are in the wave 0 and the groups 0 and 1 in the wave 1.
+// All the groups are in the wave 0 with the Recreate strategy.
+func rolloutWave(lws *leaderworkerset.LeaderWorkerSet, groupIndex int32) (int32, error) {
+	if lws.Spec.RolloutStrategy.Type == leaderworkerset.RecreateStrategyType {
+		return 0, nil
+	}
+	lwsReplicas := int(*lws.Spec.Replicas)
+	maxSurge, err := intstr.GetScaledValueFromIntOrPercent(&lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxSurge, lwsReplicas, true)
+	if err != nil {
+		return 0, err
+	}
+	maxSurge = min(maxSurge, lwsReplicas)
+	maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(&lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxUnavailable, lwsReplicas, false)
+	if err != nil {
+		return 0, err
+	}
+	rollingStep := max(int32(maxUnavailable+maxSurge), 1)
+	burstReplicas := int32(lwsReplicas + maxSurge)
+	return max(burstReplicas-1-groupIndex, 0) / rollingStep, nil
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *LeaderWorkerSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&leaderworkerset.LeaderWorkerSet{}).
 		Owns(&appsv1.StatefulSet{}).
 		Owns(&corev1.Service{}).
@@ -209,7 +721,46 @@ func (r *LeaderWorkerSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
 					}},
 				}
 			})).
-		Complete(r)
+		Watches(&corev1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(lwsRequestsForObject),
+			// Only the pod conditions are summarized in the status.
+			builder.WithPredicates(predicate.Funcs{
+				UpdateFunc: func(e event.UpdateEvent) bool {
+					oldPod, newPod := e.ObjectOld.(*corev1.Pod), e.ObjectNew.(*corev1.Pod)
+					return !equality.Semantic.DeepEqual(oldPod.Status.Conditions, newPod.Status.Conditions)
+				},
+			}))
+	if r.watchPersistentVolumeClaims {
+		b = b.Watches(&corev1.PersistentVolumeClaim{},
+			handler.EnqueueRequestsFromMapFunc(lwsRequestsForObject),
+			builder.WithPredicates(persistentVolumeClaimPhaseChanged))
+	}
+	// The reconciles are detached from the cancellation of the controller before being bounded
+	// by the reconcile timeout.
+	reconciler := controllerutils.WithTracing(controllerutils.WithReconcileTimeout(r, r.reconcileTimeout), "LeaderWorkerSet.Reconcile")
+	return b.Complete(controllerutils.WithShutdownDrain(reconciler, r.drainQueueOnShutdown))
+}
+
+// lwsRequestsForObject enqueues the lws named by the set name label of the object.
+func lwsRequestsForObject(ctx context.Context, a client.Object) []reconcile.Request {
+	lwsName, found := a.GetLabels()[leaderworkerset.SetNameLabelKey]
+	if !found {
+		return nil
+	}
+	return []reconcile.Request{
+		{NamespacedName: types.NamespacedName{
+			Name:      lwsName,
+			Namespace: a.GetNamespace(),
+		}},
+	}
+}
+
+// persistentVolumeClaimPhaseChanged filters the claim updates to the binding and unbinding ones.
+var persistentVolumeClaimPhaseChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldClaim, newClaim := e.ObjectOld.(*corev1.PersistentVolumeClaim), e.ObjectNew.(*corev1.PersistentVolumeClaim)
+		return oldClaim.Status.Phase != newClaim.Status.Phase
+	},
 }
 
 func SetupIndexes(indexer client.FieldIndexer) error {
@@ -252,6 +803,8 @@ func SetupIndexes(indexer client.FieldIndexer) error {
 //   - One exception here is when unready replicas of leaderWorkerSet is equal to MaxSurge,
 //     we should reclaim the extra replicas gradually to accommodate for the new replicas.
 func (r *LeaderWorkerSetReconciler) rollingUpdateParameters(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, sts *appsv1.StatefulSet, revisionKey string, leaderWorkerSetUpdated bool) (int32, int32, error) {
+	ctx, span := controllerutils.StartSpan(ctx, "LeaderWorkerSet.rollingUpdateParameters")
+	defer span.End()
 	log := ctrl.LoggerFrom(ctx).WithValues("leaderworkerset", klog.KObj(lws))
 	ctx = ctrl.LoggerInto(ctx, log)
 	lwsReplicas := *lws.Spec.Replicas
@@ -263,85 +816,649 @@ func (r *LeaderWorkerSetReconciler) rollingUpdateParameters(ctx context.Context,
 		return 0, lwsReplicas, nil
 	}
 
-	stsReplicas := *sts.Spec.Replicas
-	maxSurge, err := intstr.GetScaledValueFromIntOrPercent(&lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxSurge, int(lwsReplicas), true)
+	stsReplicas := *sts.Spec.Replicas
+	maxSurge, err := intstr.GetScaledValueFromIntOrPercent(&lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxSurge, int(lwsReplicas), true)
+	if err != nil {
+		return 0, 0, err
+	}
+	// No need to burst more than the replicas.
+	if maxSurge > int(lwsReplicas) {
+		maxSurge = int(lwsReplicas)
+	}
+	burstReplicas := lwsReplicas + int32(maxSurge)
+
+	// wantReplicas calculates the final replicas if needed.
+	wantReplicas := func(unreadyReplicas int32) int32 {
+		if unreadyReplicas <= int32(maxSurge) {
+			// When we have n unready replicas and n bursted replicas, we should
+			// start to release the burst replica gradually for the accommodation of
+			// the unready ones.
+			finalReplicas := lwsReplicas + utils.NonZeroValue(int32(unreadyReplicas)-1)
+			// The partition is also held by the groups updated in place once the rollout completed.
+			if finalReplicas < stsReplicas {
+				r.Record.Eventf(lws, corev1.EventTypeNormal, GroupsProgressing, "deleting surge replica %s-%d", lws.Name, finalReplicas)
+			}
+			return finalReplicas
+		}
+		return burstReplicas
+	}
+
+	// Case 2:
+	// Indicates a new rolling update here.
+	if leaderWorkerSetUpdated {
+		// Processing scaling up/down first prior to rolling update.
+		return min(lwsReplicas, stsReplicas), wantReplicas(lwsReplicas), nil
+	}
+
+	partition := *sts.Spec.UpdateStrategy.RollingUpdate.Partition
+	rollingUpdateCompleted := partition == 0 && stsReplicas == lwsReplicas
+	// Case 3:
+	// In normal cases, return the values directly.
+	if rollingUpdateCompleted {
+		return 0, lwsReplicas, nil
+	}
+
+	states, err := r.getReplicaStates(ctx, lws, stsReplicas, revisionKey)
+	if err != nil {
+		return 0, 0, err
+	}
+	stickyGroups, err := utils.ParseStickyGroups(lws.Annotations)
+	if err != nil {
+		return 0, 0, err
+	}
+	// The outdated sticky groups are held at their revision by the partition, along with the groups of
+	// lower indices, they don't hold back the rollout of the others.
+	var heldPartition int
+	for idx := range states {
+		if stickyGroups.Has(idx) && !states[idx].updated {
+			heldPartition = idx + 1
+		}
+	}
+	for idx := range heldPartition {
+		states[idx].updated = true
+	}
+	lwsUnreadyReplicas := calculateLWSUnreadyReplicas(states, lwsReplicas)
+
+	originalLwsReplicas, err := strconv.Atoi(sts.Annotations[leaderworkerset.ReplicasAnnotationKey])
+	if err != nil {
+		return 0, 0, err
+	}
+	replicasUpdated := originalLwsReplicas != int(*lws.Spec.Replicas)
+	// Case 4:
+	// Replicas changed during rolling update.
+	if replicasUpdated {
+		return min(partition, burstReplicas), wantReplicas(lwsUnreadyReplicas), nil
+	}
+
+	// Case 5:
+	// Calculating the Partition during rolling update, no leaderWorkerSet updates happens.
+
+	rollingStep, err := intstr.GetScaledValueFromIntOrPercent(&lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxUnavailable, int(lwsReplicas), false)
+	if err != nil {
+		return 0, 0, err
+	}
+	// Make sure that we always respect the maxUnavailable, or
+	// we'll violate it when reclaiming bursted replicas.
+	rollingStep += maxSurge - (int(burstReplicas) - int(stsReplicas))
+
+	return rollingUpdatePartition(states, stsReplicas, int32(rollingStep), partition), wantReplicas(lwsUnreadyReplicas), nil
+}
+
+// crashLoopPartition keeps the partition of an in-progress rolling update while groups of the
+// new revision are crash-looping, so that the remaining groups aren't replaced with broken ones.
+// It returns the partition and, if the rollout is paused, the message describing why.
+func (r *LeaderWorkerSetReconciler) crashLoopPartition(ctx context.Context, cfg *leaderWorkerSetReconcilerConfig, lws *leaderworkerset.LeaderWorkerSet, sts *appsv1.StatefulSet, revisionKey string, partition int32) (int32, string, error) {
+	if cfg.crashLoopDetection == nil || sts == nil || sts.Spec.UpdateStrategy.RollingUpdate == nil || lws.Spec.RolloutStrategy.Type != leaderworkerset.RollingUpdateStrategyType {
+		return partition, "", nil
+	}
+	currentPartition := ptr.Deref(sts.Spec.UpdateStrategy.RollingUpdate.Partition, 0)
+	if currentPartition == 0 {
+		// No rollout is in progress.
+		return partition, "", nil
+	}
+
+	var podList corev1.PodList
+	if err := r.List(ctx, &podList, client.InNamespace(lws.Namespace), client.MatchingLabels{
+		leaderworkerset.SetNameLabelKey: lws.Name,
+		leaderworkerset.RevisionKey:     revisionKey,
+	}); err != nil {
+		return 0, "", err
+	}
+	pods := podList.Items
+	slices.SortFunc(pods, func(a, b corev1.Pod) int { return strings.Compare(a.Name, b.Name) })
+
+	threshold, window := *cfg.crashLoopDetection.RestartThreshold, cfg.crashLoopDetection.Window.Duration
+	now := time.Now()
+	for _, pod := range pods {
+		if container, found := podutils.CrashLoopingContainer(pod, threshold, window, now); found {
+			message := fmt.Sprintf("container %s of pod %s restarted at least %d times within %s", container, pod.Name, threshold, window)
+			return max(partition, currentPartition), message, nil
+		}
+		if !podutils.LeaderPod(pod) {
+			continue
+		}
+		if recreations := revisionRecreations.count(client.ObjectKeyFromObject(&pod), revisionKey, window, now); recreations >= int(threshold) {
+			message := fmt.Sprintf("group %s was recreated %d times within %s", pod.Labels[leaderworkerset.GroupIndexLabelKey], recreations, window)
+			return max(partition, currentPartition), message, nil
+		}
+	}
+	return partition, "", nil
+}
+
+// recreateParameters returns the partition and replicas of the leader StatefulSet for the Recreate
+// rollout strategy. While any group still runs an outdated revision, the leader StatefulSet is scaled
+// down to zero so that all the groups are deleted, then it is scaled back up with the updated template.
+func (r *LeaderWorkerSetReconciler) recreateParameters(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, sts *appsv1.StatefulSet, revisionKey string) (int32, int32, error) {
+	lwsReplicas := *lws.Spec.Replicas
+	if sts == nil {
+		return 0, lwsReplicas, nil
+	}
+	outdated, err := r.outdatedGroupsExist(ctx, lws, revisionKey)
+	if err != nil {
+		return 0, 0, err
+	}
+	if outdated {
+		if *sts.Spec.Replicas != 0 {
+			r.Record.Event(lws, corev1.EventTypeNormal, GroupsRecreating, "Deleting all groups to recreate them with the updated revision")
+		}
+		return 0, 0, nil
+	}
+	return 0, lwsReplicas, nil
+}
+
+// outdatedGroupsExist returns whether any leader pod or worker StatefulSet of the lws, including the
+// ones being deleted, doesn't match one of the given revisions. Both of them trigger a reconcile when
+// they are gone, through the status of the leader StatefulSet and the StatefulSet watch respectively.
+func (r *LeaderWorkerSetReconciler) outdatedGroupsExist(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, revisionKeys ...string) (bool, error) {
+	var leaderPods corev1.PodList
+	if err := r.List(ctx, &leaderPods, client.InNamespace(lws.Namespace), client.MatchingLabels{
+		leaderworkerset.SetNameLabelKey:     lws.Name,
+		leaderworkerset.WorkerIndexLabelKey: "0",
+	}); err != nil {
+		return false, err
+	}
+	for i := range leaderPods.Items {
+		if !slices.Contains(revisionKeys, revisionutils.GetRevisionKey(&leaderPods.Items[i])) {
+			return true, nil
+		}
+	}
+
+	var workerStsList appsv1.StatefulSetList
+	if err := r.List(ctx, &workerStsList, client.InNamespace(lws.Namespace), client.MatchingLabels{
+		leaderworkerset.SetNameLabelKey: lws.Name,
+	}); err != nil {
+		return false, err
+	}
+	for i := range workerStsList.Items {
+		// The leader StatefulSet shares the set name label with the worker StatefulSets.
+		if workerStsList.Items[i].Name == lws.Name {
+			continue
+		}
+		if !slices.Contains(revisionKeys, revisionutils.GetRevisionKey(&workerStsList.Items[i])) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// updateMetadataInPlace applies an update of the lws only changing the labels and annotations of its
+// templates to the existing groups, by patching the labels and annotations of their pods and labeling
+// them and their worker statefulsets with the updated revision, instead of recreating them. It returns
+// whether the update was applied, the update is rolled out if it changes anything else or while the
+// groups run other revisions. The templates of the worker statefulsets are left unchanged, since
+// changing them would recreate the workers, so the worker pods later recreated by their statefulset
+// revert to the previous labels and annotations. The leader pods are handled by inPlaceUpdatePartition.
+func (r *LeaderWorkerSetReconciler) updateMetadataInPlace(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, previous, updated *appsv1.ControllerRevision) (bool, error) {
+	if previous == nil {
+		return false, nil
+	}
+	previousLws, err := revisionutils.ApplyRevision(lws, previous)
+	if err != nil {
+		return false, err
+	}
+	if revisionutils.PodImpactingChange(previousLws, lws) {
+		return false, nil
+	}
+	previousKey, updatedKey := revisionutils.GetRevisionKey(previous), revisionutils.GetRevisionKey(updated)
+	// The groups already updated in place by a failed attempt are patched again.
+	outdated, err := r.outdatedGroupsExist(ctx, lws, previousKey, updatedKey)
+	if err != nil || outdated {
+		return false, err
+	}
+
+	var podList corev1.PodList
+	if err := r.List(ctx, &podList, client.InNamespace(lws.Namespace), client.MatchingLabels{leaderworkerset.SetNameLabelKey: lws.Name}); err != nil {
+		return false, err
+	}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.DeletionTimestamp != nil || revisionutils.GetRevisionKey(pod) != previousKey {
+			continue
+		}
+		previousTemplate, updatedTemplate := &previousLws.Spec.LeaderWorkerTemplate.WorkerTemplate, &lws.Spec.LeaderWorkerTemplate.WorkerTemplate
+		if podutils.LeaderPod(*pod) {
+			previousTemplate, updatedTemplate = leaderTemplate(previousLws), leaderTemplate(lws)
+		}
+		patch := client.MergeFrom(pod.DeepCopy())
+		pod.Labels = updateMetadata(pod.Labels, previousTemplate.Labels, updatedTemplate.Labels)
+		pod.Annotations = updateMetadata(pod.Annotations, previousTemplate.Annotations, updatedTemplate.Annotations)
+		pod.Labels[leaderworkerset.RevisionKey] = updatedKey
+		if err := r.Patch(ctx, pod, patch); err != nil {
+			return false, err
+		}
+	}
+
+	var stsList appsv1.StatefulSetList
+	if err := r.List(ctx, &stsList, client.InNamespace(lws.Namespace), client.MatchingLabels{leaderworkerset.SetNameLabelKey: lws.Name}); err != nil {
+		return false, err
+	}
+	for i := range stsList.Items {
+		sts := &stsList.Items[i]
+		// The leader StatefulSet is applied with the updated revision.
+		if sts.Name == lws.Name || revisionutils.GetRevisionKey(sts) == updatedKey {
+			continue
+		}
+		patch := client.MergeFrom(sts.DeepCopy())
+		sts.Labels[leaderworkerset.RevisionKey] = updatedKey
+		if err := r.Patch(ctx, sts, patch); err != nil {
+			return false, err
+		}
+	}
+	r.Record.Eventf(lws, corev1.EventTypeNormal, GroupsUpdating, "Updated the labels and annotations of the groups in place with revision %s", updatedKey)
+	return true, nil
+}
+
+// leaderTemplate returns the template of the leader pods of the lws.
+func leaderTemplate(lws *leaderworkerset.LeaderWorkerSet) *corev1.PodTemplateSpec {
+	if lws.Spec.LeaderWorkerTemplate.LeaderTemplate != nil {
+		return lws.Spec.LeaderWorkerTemplate.LeaderTemplate
+	}
+	return &lws.Spec.LeaderWorkerTemplate.WorkerTemplate
+}
+
+// updateMetadata applies the changes between the previous and updated labels, or annotations, of a
+// template to the ones of a pod, the keys not set by the template are kept.
+func updateMetadata(current, previous, updated map[string]string) map[string]string {
+	for key := range previous {
+		if _, found := updated[key]; !found {
+			delete(current, key)
+		}
+	}
+	if current == nil && len(updated) != 0 {
+		current = make(map[string]string, len(updated))
+	}
+	maps.Copy(current, updated)
+	return current
+}
+
+// inPlaceUpdatePartition keeps the leader pods updated in place above the partition, so that the leader
+// statefulset doesn't recreate them with the template of the revision, which only differs from theirs by
+// its labels and annotations. The leader pods the statefulset recreates below the partition get the
+// previous template, they are updated in place again once the statefulset observed the template of the
+// revision. The pods created above the partition, e.g. on scale up, get the template of the revision.
+func (r *LeaderWorkerSetReconciler) inPlaceUpdatePartition(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, sts *appsv1.StatefulSet, revisionKey string, partition int32) (int32, error) {
+	if sts == nil || lws.Spec.RolloutStrategy.MetadataUpdatePolicy != leaderworkerset.InPlaceMetadataUpdatePolicy {
+		return partition, nil
+	}
+	var leaderPods corev1.PodList
+	if err := r.List(ctx, &leaderPods, client.InNamespace(lws.Namespace), client.MatchingLabels{
+		leaderworkerset.SetNameLabelKey:     lws.Name,
+		leaderworkerset.WorkerIndexLabelKey: "0",
+	}); err != nil {
+		return 0, err
+	}
+	observed := sts.Status.ObservedGeneration == sts.Generation && revisionutils.GetRevisionKey(&sts.Spec.Template) == revisionKey
+	for i := range leaderPods.Items {
+		pod := &leaderPods.Items[i]
+		if pod.DeletionTimestamp != nil || !metav1.IsControlledBy(pod, sts) || (observed && pod.Labels[appsv1.ControllerRevisionHashLabelKey] == sts.Status.UpdateRevision) {
+			continue
+		}
+		if revisionutils.GetRevisionKey(pod) != revisionKey {
+			if !observed {
+				continue
+			}
+			updated, err := r.updateRecreatedLeaderInPlace(ctx, sts, pod)
+			if err != nil {
+				return 0, err
+			}
+			if !updated {
+				continue
+			}
+		}
+		_, ordinal := statefulsetutils.GetParentNameAndOrdinal(pod.Name)
+		partition = max(partition, int32(ordinal)+1)
+	}
+	return partition, nil
+}
+
+// updateRecreatedLeaderInPlace patches the labels and annotations of a leader pod created from a previous
+// template of the leader statefulset with the ones of its current template, if the templates only differ
+// by them. It returns whether the pod was updated in place.
+func (r *LeaderWorkerSetReconciler) updateRecreatedLeaderInPlace(ctx context.Context, sts *appsv1.StatefulSet, pod *corev1.Pod) (bool, error) {
+	var stsRevision appsv1.ControllerRevision
+	if err := r.Get(ctx, types.NamespacedName{Namespace: sts.Namespace, Name: pod.Labels[appsv1.ControllerRevisionHashLabelKey]}, &stsRevision); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	// The revisions of a statefulset hold a patch replacing its template.
+	var previous struct {
+		Spec struct {
+			Template corev1.PodTemplateSpec `json:"template"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(stsRevision.Data.Raw, &previous); err != nil {
+		return false, err
+	}
+	if !equality.Semantic.DeepEqual(previous.Spec.Template.Spec, sts.Spec.Template.Spec) {
+		return false, nil
+	}
+	patch := client.MergeFrom(pod.DeepCopy())
+	pod.Labels = updateMetadata(pod.Labels, previous.Spec.Template.Labels, sts.Spec.Template.Labels)
+	pod.Annotations = updateMetadata(pod.Annotations, previous.Spec.Template.Annotations, sts.Spec.Template.Annotations)
+	if err := r.Patch(ctx, pod, patch); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// stickyGroupsPartition holds the groups listed by the sticky-groups annotation at their revision during
+// a rolling update, by keeping their outdated leader pods below the partition. The groups are released
+// once the annotation no longer lists them.
+func (r *LeaderWorkerSetReconciler) stickyGroupsPartition(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, sts *appsv1.StatefulSet, revisionKey string, partition int32) (int32, error) {
+	if sts == nil || lws.Spec.RolloutStrategy.Type != leaderworkerset.RollingUpdateStrategyType {
+		return partition, nil
+	}
+	stickyGroups, err := utils.ParseStickyGroups(lws.Annotations)
+	if err != nil || stickyGroups.Len() == 0 {
+		return partition, err
+	}
+	var leaderPods corev1.PodList
+	if err := r.List(ctx, &leaderPods, client.InNamespace(lws.Namespace), client.MatchingLabels{
+		leaderworkerset.SetNameLabelKey:     lws.Name,
+		leaderworkerset.WorkerIndexLabelKey: "0",
+	}); err != nil {
+		return 0, err
+	}
+	// The pods owned by another controller aren't updated by the leader statefulset.
+	ownedPods := slices.DeleteFunc(leaderPods.Items, func(pod corev1.Pod) bool { return !metav1.IsControlledBy(&pod, sts) })
+	heldPartition, err := stickyGroupsHeldPartition(lws, stickyGroups, ownedPods, revisionKey)
+	if err != nil {
+		return 0, err
+	}
+	return max(partition, heldPartition), nil
+}
+
+// stickyGroupsHeldPartition returns the partition holding the outdated groups listed by the sticky-groups
+// annotation, one above the highest of them. The statefulset updates its pods from the highest ordinal
+// down, so that the groups of lower indices are held as well. Only a rolling update holds them.
+func stickyGroupsHeldPartition(lws *leaderworkerset.LeaderWorkerSet, stickyGroups sets.Set[int], leaderPods []corev1.Pod, revisionKey string) (int32, error) {
+	var heldPartition int32
+	if lws.Spec.RolloutStrategy.Type != leaderworkerset.RollingUpdateStrategyType {
+		return heldPartition, nil
+	}
+	for i := range leaderPods {
+		pod := &leaderPods[i]
+		if pod.DeletionTimestamp != nil || revisionutils.GetRevisionKey(pod) == revisionKey {
+			continue
+		}
+		index, err := utils.GroupIndex(pod, lws.Name)
+		if err != nil {
+			return 0, err
+		}
+		if stickyGroups.Has(index) {
+			heldPartition = max(heldPartition, int32(index)+1)
+		}
+	}
+	return heldPartition, nil
+}
+
+// rateLimitedReplicas caps the replicas of the leader statefulset when the groups-per-minute annotation
+// is set, so that new groups are created gradually on scale-up. It returns the capped replicas and,
+// if capped, the duration after which the next batch of groups can be created.
+func (r *LeaderWorkerSetReconciler) rateLimitedReplicas(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, sts *appsv1.StatefulSet, replicas int32) (int32, time.Duration, error) {
+	value, found := lws.Annotations[leaderworkerset.GroupsPerMinuteAnnotationKey]
+	if !found {
+		return replicas, 0, nil
+	}
+	groupsPerMinute, err := strconv.Atoi(value)
 	if err != nil {
 		return 0, 0, err
 	}
-	// No need to burst more than the replicas.
-	if maxSurge > int(lwsReplicas) {
-		maxSurge = int(lwsReplicas)
+
+	var stsReplicas int32
+	if sts != nil {
+		stsReplicas = *sts.Spec.Replicas
+	}
+	// Scaling down or keeping the replicas is not rate limited.
+	if replicas <= stsReplicas {
+		return replicas, 0, nil
 	}
-	burstReplicas := lwsReplicas + int32(maxSurge)
 
-	// wantReplicas calculates the final replicas if needed.
-	wantReplicas := func(unreadyReplicas int32) int32 {
-		if unreadyReplicas <= int32(maxSurge) {
-			// When we have n unready replicas and n bursted replicas, we should
-			// start to release the burst replica gradually for the accommodation of
-			// the unready ones.
-			finalReplicas := lwsReplicas + utils.NonZeroValue(int32(unreadyReplicas)-1)
-			r.Record.Eventf(lws, corev1.EventTypeNormal, GroupsProgressing, fmt.Sprintf("deleting surge replica %s-%d", lws.Name, finalReplicas))
-			return finalReplicas
+	var leaderPodList corev1.PodList
+	if err := r.List(ctx, &leaderPodList, client.InNamespace(lws.Namespace), client.MatchingLabels(map[string]string{
+		leaderworkerset.SetNameLabelKey:     lws.Name,
+		leaderworkerset.WorkerIndexLabelKey: "0",
+	})); err != nil {
+		return 0, 0, err
+	}
+	allowed, requeueAfter := scaleUpReplicas(leaderPodList.Items, stsReplicas, replicas, groupsPerMinute, time.Now())
+	return allowed, requeueAfter, nil
+}
+
+// scaleUpReplicas returns the replicas allowed within the one minute sliding window. Groups
+// in the range of the current replicas whose leader pod is created within the window or not
+// created yet are counted against the groupsPerMinute budget.
+func scaleUpReplicas(leaderPods []corev1.Pod, currentReplicas, wantReplicas int32, groupsPerMinute int, now time.Time) (int32, time.Duration) {
+	const window = time.Minute
+
+	sortedPods := utils.SortByIndex(func(pod corev1.Pod) (int, error) {
+		return strconv.Atoi(pod.Labels[leaderworkerset.GroupIndexLabelKey])
+	}, leaderPods, int(currentReplicas))
+
+	var recentGroups int32
+	var oldestRecent time.Time
+	for _, pod := range sortedPods {
+		if pod.Name == "" {
+			// The group is still being created, it will occupy a slot of the window as soon as created.
+			recentGroups++
+			oldestRecent = now
+			continue
+		}
+		if created := pod.CreationTimestamp.Time; now.Sub(created) < window {
+			recentGroups++
+			if oldestRecent.IsZero() || created.Before(oldestRecent) {
+				oldestRecent = created
+			}
 		}
-		return burstReplicas
 	}
 
-	// Case 2:
-	// Indicates a new rolling update here.
-	if leaderWorkerSetUpdated {
-		// Processing scaling up/down first prior to rolling update.
-		return min(lwsReplicas, stsReplicas), wantReplicas(lwsReplicas), nil
+	budget := utils.NonZeroValue(int32(groupsPerMinute) - recentGroups)
+	allowed := min(wantReplicas, currentReplicas+budget)
+	if allowed == wantReplicas {
+		return allowed, 0
 	}
+	if oldestRecent.IsZero() {
+		return allowed, window
+	}
+	// Requeue once the oldest group in the window ages out and frees up a slot.
+	return allowed, oldestRecent.Add(window).Sub(now)
+}
 
-	partition := *sts.Spec.UpdateStrategy.RollingUpdate.Partition
-	rollingUpdateCompleted := partition == 0 && stsReplicas == lwsReplicas
-	// Case 3:
-	// In normal cases, return the values directly.
-	if rollingUpdateCompleted {
-		return 0, lwsReplicas, nil
+// podQuotaReplicas caps the replicas so that the pods managed across all the lws don't
+// exceed maxTotalManagedPods. Only the scale-up is capped, the existing groups are kept.
+func (r *LeaderWorkerSetReconciler) podQuotaReplicas(ctx context.Context, cfg *leaderWorkerSetReconcilerConfig, lws *leaderworkerset.LeaderWorkerSet, sts *appsv1.StatefulSet, replicas int32) (int32, error) {
+	if cfg.maxTotalManagedPods == 0 {
+		return replicas, nil
 	}
 
-	states, err := r.getReplicaStates(ctx, lws, stsReplicas, revisionKey)
+	var stsReplicas int32
+	if sts != nil {
+		stsReplicas = *sts.Spec.Replicas
+	}
+	if replicas <= stsReplicas {
+		return replicas, nil
+	}
+
+	var podList corev1.PodList
+	if err := r.List(ctx, &podList, client.HasLabels{leaderworkerset.SetNameLabelKey}); err != nil {
+		return 0, err
+	}
+	return quotaReplicas(podList.Items, lws, stsReplicas, replicas, cfg.maxTotalManagedPods), nil
+}
+
+// quotaReplicas returns the replicas allowed by the maxPods quota. The pods of the other lws
+// are counted as they are, while the groups of the lws in the range of the current replicas
+// are counted as fully created, whether or not their pods exist yet.
+func quotaReplicas(pods []corev1.Pod, lws *leaderworkerset.LeaderWorkerSet, currentReplicas, wantReplicas, maxPods int32) int32 {
+	size := *lws.Spec.LeaderWorkerTemplate.Size
+
+	var otherPods int32
+	for _, pod := range pods {
+		if pod.Namespace != lws.Namespace || pod.Labels[leaderworkerset.SetNameLabelKey] != lws.Name {
+			otherPods++
+		}
+	}
+
+	budget := utils.NonZeroValue(maxPods - otherPods - currentReplicas*size)
+	return min(wantReplicas, currentReplicas+budget/size)
+}
+
+// drainReplicas delays the scale-down of the leader statefulset when the drain-timeout-seconds
+// annotation is set. The leader pods of the groups being removed are annotated with the time
+// their drain started, and the annotation is removed again if the scale-down is reverted.
+func (r *LeaderWorkerSetReconciler) drainReplicas(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, sts *appsv1.StatefulSet, replicas int32) (int32, time.Duration, error) {
+	value, found := lws.Annotations[leaderworkerset.DrainTimeoutAnnotationKey]
+	if !found {
+		return replicas, 0, nil
+	}
+	drainTimeout, err := strconv.Atoi(value)
 	if err != nil {
 		return 0, 0, err
 	}
-	lwsUnreadyReplicas := calculateLWSUnreadyReplicas(states, lwsReplicas)
 
-	originalLwsReplicas, err := strconv.Atoi(sts.Annotations[leaderworkerset.ReplicasAnnotationKey])
-	if err != nil {
+	var stsReplicas int32
+	if sts != nil {
+		stsReplicas = *sts.Spec.Replicas
+	}
+
+	var leaderPodList corev1.PodList
+	if err := r.List(ctx, &leaderPodList, client.InNamespace(lws.Namespace), client.MatchingLabels(map[string]string{
+		leaderworkerset.SetNameLabelKey:     lws.Name,
+		leaderworkerset.WorkerIndexLabelKey: "0",
+	})); err != nil {
 		return 0, 0, err
 	}
-	replicasUpdated := originalLwsReplicas != int(*lws.Spec.Replicas)
-	// Case 4:
-	// Replicas changed during rolling update.
-	if replicasUpdated {
-		return min(partition, burstReplicas), wantReplicas(lwsUnreadyReplicas), nil
+	now := time.Now()
+	for i := range leaderPodList.Items {
+		pod := &leaderPodList.Items[i]
+		groupIndex, err := strconv.Atoi(pod.Labels[leaderworkerset.GroupIndexLabelKey])
+		if err != nil || pod.DeletionTimestamp != nil {
+			continue
+		}
+		_, draining := pod.Annotations[leaderworkerset.DrainStartedAnnotationKey]
+		drain := int32(groupIndex) >= max(replicas, *lws.Spec.Replicas) && int32(groupIndex) < stsReplicas
+		if drain == draining {
+			continue
+		}
+		patch := client.MergeFrom(pod.DeepCopy())
+		if drain {
+			if pod.Annotations == nil {
+				pod.Annotations = map[string]string{}
+			}
+			pod.Annotations[leaderworkerset.DrainStartedAnnotationKey] = now.Format(time.RFC3339)
+		} else {
+			delete(pod.Annotations, leaderworkerset.DrainStartedAnnotationKey)
+		}
+		if err := r.Patch(ctx, pod, patch); err != nil {
+			return 0, 0, err
+		}
+		ctrl.LoggerFrom(ctx).V(2).Info("Updated the drain of the group", "groupIndex", groupIndex, "draining", drain)
 	}
+	allowed, requeueAfter := drainedReplicas(leaderPodList.Items, *lws.Spec.Replicas, stsReplicas, replicas, time.Duration(drainTimeout)*time.Second, now)
+	return allowed, requeueAfter, nil
+}
 
-	// Case 5:
-	// Calculating the Partition during rolling update, no leaderWorkerSet updates happens.
+// drainedReplicas returns the replicas the leader statefulset can be scaled down to. A group
+// is drained once its leader pod is no longer ready or the timeout elapsed since its drain
+// started. The statefulset deletes the groups from its last ordinal, so it is only scaled
+// down over the drained groups at the top of its range. The groups in the range of the spec
+// replicas are only removed to be recreated by the Recreate strategy and aren't drained.
+func drainedReplicas(leaderPods []corev1.Pod, specReplicas, currentReplicas, wantReplicas int32, timeout time.Duration, now time.Time) (int32, time.Duration) {
+	if wantReplicas >= currentReplicas {
+		return wantReplicas, 0
+	}
+	sortedPods := utils.SortByIndex(func(pod corev1.Pod) (int, error) {
+		return strconv.Atoi(pod.Labels[leaderworkerset.GroupIndexLabelKey])
+	}, leaderPods, int(currentReplicas))
+
+	allowed := currentReplicas
+	for idx := currentReplicas - 1; idx >= wantReplicas; idx-- {
+		pod := sortedPods[idx]
+		if idx >= specReplicas && pod.Name != "" && pod.DeletionTimestamp == nil && podutils.PodRunningAndReady(pod) {
+			// A malformed start time doesn't block the scale-down.
+			started, err := time.Parse(time.RFC3339, pod.Annotations[leaderworkerset.DrainStartedAnnotationKey])
+			if remaining := started.Add(timeout).Sub(now); err == nil && remaining > 0 {
+				return allowed, remaining
+			}
+		}
+		allowed = idx
+	}
+	return allowed, 0
+}
 
-	rollingStep, err := intstr.GetScaledValueFromIntOrPercent(&lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxUnavailable, int(lwsReplicas), false)
-	if err != nil {
+// batchedDeleteReplicas caps the groups removed from the leader statefulset at once so that
+// at most maxConcurrentPodDeletes of their pods are deleted at the same time, the unit being a
+// whole group. Both the scale-down and the groups deleted by the Recreate strategy are batched.
+func (r *LeaderWorkerSetReconciler) batchedDeleteReplicas(ctx context.Context, cfg *leaderWorkerSetReconcilerConfig, lws *leaderworkerset.LeaderWorkerSet, sts *appsv1.StatefulSet, replicas int32) (int32, time.Duration, error) {
+	if cfg.maxConcurrentPodDeletes == 0 || sts == nil || replicas >= *sts.Spec.Replicas {
+		return replicas, 0, nil
+	}
+
+	var podList corev1.PodList
+	if err := r.List(ctx, &podList, client.InNamespace(lws.Namespace), client.MatchingLabels{
+		leaderworkerset.SetNameLabelKey: lws.Name,
+	}); err != nil {
 		return 0, 0, err
 	}
-	// Make sure that we always respect the maxUnavailable, or
-	// we'll violate it when reclaiming bursted replicas.
-	rollingStep += maxSurge - (int(burstReplicas) - int(stsReplicas))
+	allowed, requeueAfter := deleteBatchReplicas(podList.Items, *lws.Spec.LeaderWorkerTemplate.Size, *sts.Spec.Replicas, replicas, cfg.maxConcurrentPodDeletes)
+	if allowed > replicas {
+		ctrl.LoggerFrom(ctx).V(2).Info("Removing the groups in batches", "replicas", allowed, "targetReplicas", replicas)
+	}
+	return allowed, requeueAfter, nil
+}
 
-	return rollingUpdatePartition(states, stsReplicas, int32(rollingStep), partition), wantReplicas(lwsUnreadyReplicas), nil
+// deleteBatchReplicas returns the replicas the leader statefulset can be scaled down to. The
+// next batch of groups is only removed once all the pods of the previous one are gone, and
+// a batch holds at least one group, even if its pods exceed maxDeletes.
+func deleteBatchReplicas(pods []corev1.Pod, size, currentReplicas, wantReplicas, maxDeletes int32) (int32, time.Duration) {
+	if wantReplicas >= currentReplicas {
+		return wantReplicas, 0
+	}
+	for _, pod := range pods {
+		groupIndex, err := strconv.Atoi(pod.Labels[leaderworkerset.GroupIndexLabelKey])
+		if err == nil && int32(groupIndex) >= currentReplicas {
+			return currentReplicas, podDeleteRequeuePeriod
+		}
+	}
+
+	allowed := max(wantReplicas, currentReplicas-max(maxDeletes/size, 1))
+	if allowed == wantReplicas {
+		return allowed, 0
+	}
+	return allowed, podDeleteRequeuePeriod
 }
 
 func (r *LeaderWorkerSetReconciler) SSAWithStatefulset(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, partition, replicas int32, revisionKey string) error {
+	ctx, span := controllerutils.StartSpan(ctx, "LeaderWorkerSet.SSAWithStatefulset")
+	defer span.End()
 	log := ctrl.LoggerFrom(ctx)
 
 	// construct the statefulset apply configuration
-	leaderStatefulSetApplyConfig, err := constructLeaderStatefulSetApplyConfiguration(lws, partition, replicas, revisionKey)
+	leaderStatefulSetApplyConfig, err := constructLeaderStatefulSetApplyConfiguration(lws, partition, replicas, revisionKey, r.namer)
 	if err != nil {
 		log.Error(err, "Constructing StatefulSet apply configuration.")
 		return err
 	}
+	leaderStatefulSetApplyConfig.WithLabels(r.recommendedLabels)
 	if err := setControllerReferenceWithStatefulSet(lws, leaderStatefulSetApplyConfig, r.Scheme); err != nil {
 		log.Error(err, "Setting controller reference.")
 		return err
@@ -383,13 +1500,25 @@ func (r *LeaderWorkerSetReconciler) updateConditions(ctx context.Context, lws *l
 		return false, false, err
 	}
 
+	stickyGroups, err := utils.ParseStickyGroups(lws.Annotations)
+	if err != nil {
+		return false, false, err
+	}
+	heldPartition, err := stickyGroupsHeldPartition(lws, stickyGroups, leaderPodList.Items, revisionKey)
+	if err != nil {
+		return false, false, err
+	}
+
 	updateStatus := false
-	readyCount, updatedCount, updatedNonBurstWorkerCount, currentNonBurstWorkerCount, updatedAndReadyCount := 0, 0, 0, 0, 0
+	readyCount, updatedCount, updatedNonBurstWorkerCount, currentNonBurstWorkerCount, updatedAndReadyCount, heldCount := 0, 0, 0, 0, 0, 0
 	noWorkerSts := *lws.Spec.LeaderWorkerTemplate.Size == 1
+	waitingForLeaderCount, unreadyLeaderCount := 0, 0
+	coordinationOnlyLeader := lws.Spec.GroupReadinessPolicy != nil && lws.Spec.GroupReadinessPolicy.CoordinationOnlyLeader
+	allLeadersReady := leadersReady(lws, leaderPodList.Items)
 
 	// Iterate through all leaderPods.
 	for _, pod := range leaderPodList.Items {
-		index, err := strconv.Atoi(pod.Labels[leaderworkerset.GroupIndexLabelKey])
+		index, err := utils.GroupIndex(&pod, lws.Name)
 		if err != nil {
 			return false, false, err
 		}
@@ -401,6 +1530,11 @@ func (r *LeaderWorkerSetReconciler) updateConditions(ctx context.Context, lws *l
 					log.Error(err, "Fetching worker statefulSet")
 					return false, false, err
 				}
+				// The pod controller defers the creation of the workers until the leader, or all
+				// the leaders, are ready.
+				if pod.DeletionTimestamp == nil && waitingForLeaders(lws, &pod, allLeadersReady) {
+					waitingForLeaderCount++
+				}
 				continue
 			}
 		}
@@ -410,9 +1544,16 @@ func (r *LeaderWorkerSetReconciler) updateConditions(ctx context.Context, lws *l
 		}
 
 		var ready, updated bool
-		if (noWorkerSts || statefulsetutils.StatefulsetReady(sts)) && podutils.PodRunningAndReady(pod) {
+		workerSts := &sts
+		if noWorkerSts {
+			workerSts = nil
+		}
+		if groupReady(lws, pod, workerSts) {
 			ready = true
 			readyCount++
+			if coordinationOnlyLeader && !podutils.IsPodReady(&pod) {
+				unreadyLeaderCount++
+			}
 		}
 		if (noWorkerSts || revisionutils.GetRevisionKey(&sts) == revisionKey) && revisionutils.GetRevisionKey(&pod) == revisionKey {
 			updated = true
@@ -421,6 +1562,8 @@ func (r *LeaderWorkerSetReconciler) updateConditions(ctx context.Context, lws *l
 				// Bursted replicas do not count when determining if rollingUpdate has been completed.
 				updatedNonBurstWorkerCount++
 			}
+		} else if int32(index) < heldPartition && pod.DeletionTimestamp == nil {
+			heldCount++
 		}
 
 		if ready && updated {
@@ -441,13 +1584,37 @@ func (r *LeaderWorkerSetReconciler) updateConditions(ctx context.Context, lws *l
 		updateStatus = true
 	}
 
+	if lws.Status.HeldReplicas != int32(heldCount) {
+		lws.Status.HeldReplicas = int32(heldCount)
+		updateStatus = true
+	}
+
 	var conditions []metav1.Condition
 	updateDone := false
 	if updatedNonBurstWorkerCount < currentNonBurstWorkerCount {
 		// upgradeInProgress is true when the upgrade replicas is smaller than the expected
 		// number of total replicas not including the burst replicas
-		conditions = append(conditions, makeCondition(leaderworkerset.LeaderWorkerSetUpdateInProgress))
+		updateInProgress := makeCondition(leaderworkerset.LeaderWorkerSetUpdateInProgress)
+		if lws.Spec.RolloutStrategy.Type == leaderworkerset.RecreateStrategyType {
+			// The groups are all down until the recreation completes.
+			updateInProgress.Reason = GroupsRecreating
+			updateInProgress.Message = "Recreate is in progress, groups are unavailable until all of them are recreated"
+		}
+		conditions = append(conditions, updateInProgress)
 		conditions = append(conditions, makeCondition(leaderworkerset.LeaderWorkerSetProgressing))
+	} else if *lws.Spec.Replicas == 0 {
+		// Scaled to zero, the lws is only available once all its groups are deleted.
+		if len(leaderPodList.Items) == 0 {
+			available := makeCondition(leaderworkerset.LeaderWorkerSetAvailable)
+			available.Reason = ScaledToZero
+			available.Message = "Scaled to zero, all the groups are deleted"
+			conditions = append(conditions, available)
+			updateDone = true
+		} else {
+			progressing := makeCondition(leaderworkerset.LeaderWorkerSetProgressing)
+			progressing.Message = "Scaling to zero, the groups are being deleted"
+			conditions = append(conditions, progressing)
+		}
 	} else if updatedAndReadyCount == int(*lws.Spec.Replicas) {
 		conditions = append(conditions, makeCondition(leaderworkerset.LeaderWorkerSetAvailable))
 		updateDone = true
@@ -458,21 +1625,33 @@ func (r *LeaderWorkerSetReconciler) updateConditions(ctx context.Context, lws *l
 	updateCondition := setConditions(lws, conditions)
 	// if condition changed, record events
 	if updateCondition {
-		r.Record.Eventf(lws, corev1.EventTypeNormal, conditions[0].Reason, conditions[0].Message+fmt.Sprintf(", with %d groups ready of total %d groups", readyCount, int(*lws.Spec.Replicas)))
+		r.Record.Eventf(lws, corev1.EventTypeNormal, conditions[0].Reason, "%s, with %d groups ready of total %d groups", conditions[0].Message, readyCount, *lws.Spec.Replicas)
+	}
+
+	metrics.GroupsWaitingForLeader.WithLabelValues(lws.Namespace, lws.Name).Set(float64(waitingForLeaderCount))
+	if coordinationOnlyLeader {
+		metrics.ReadyGroupsWithUnreadyLeader.WithLabelValues(lws.Namespace, lws.Name).Set(float64(unreadyLeaderCount))
+	} else {
+		metrics.ReadyGroupsWithUnreadyLeader.DeleteLabelValues(lws.Namespace, lws.Name)
 	}
-	return updateStatus || updateCondition, updateDone, nil
+	updateWaitingForLeader := setWaitingForLeaderCondition(lws, waitingForLeaderCount)
+	return updateStatus || updateCondition || updateWaitingForLeader, updateDone, nil
 }
 
-// Updates status and condition of LeaderWorkerSet and returns whether or not an update actually occurred.
-func (r *LeaderWorkerSetReconciler) updateStatus(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, revisionKey string) (bool, error) {
+// Updates status and condition of LeaderWorkerSet and returns whether or not an update actually occurred,
+// and how long the status write is deferred by the debounce.
+func (r *LeaderWorkerSetReconciler) updateStatus(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, revisionKey string, podQuotaExceeded bool, rolloutStalled string) (bool, time.Duration, error) {
+	ctx, span := controllerutils.StartSpan(ctx, "LeaderWorkerSet.updateStatus")
+	defer span.End()
 	updateStatus := false
 	log := ctrl.LoggerFrom(ctx)
+	oldConditions := slices.Clone(lws.Status.Conditions)
 
 	// Retrieve the leader StatefulSet.
 	sts := &appsv1.StatefulSet{}
 	if err := r.Get(ctx, types.NamespacedName{Name: lws.Name, Namespace: lws.Namespace}, sts); err != nil {
 		log.Error(err, "Error retrieving leader StatefulSet")
-		return false, err
+		return false, 0, err
 	}
 
 	// retrieve the current number of replicas -- the number of leaders
@@ -492,28 +1671,212 @@ func (r *LeaderWorkerSetReconciler) updateStatus(ctx context.Context, lws *leade
 		selector, err := metav1.LabelSelectorAsSelector(labelSelector)
 		if err != nil {
 			log.Error(err, "Converting label selector to selector")
-			return false, err
+			return false, 0, err
 		}
 
 		lws.Status.HPAPodSelector = selector.String()
 		updateStatus = true
 	}
 
+	var podList corev1.PodList
+	if err := r.List(ctx, &podList, client.MatchingLabels{leaderworkerset.SetNameLabelKey: lws.Name}, client.InNamespace(lws.Namespace)); err != nil {
+		log.Error(err, "Listing the pods to summarize the groups")
+		return false, 0, err
+	}
+	groups := summarizeGroupPods(podList.Items)
+	if !equality.Semantic.DeepEqual(lws.Status.Groups, groups) {
+		lws.Status.Groups = groups
+		updateStatus = true
+	}
+	leaderPods := currentLeaderPods(podList.Items, lws.Name)
+	if !slices.Equal(lws.Status.CurrentLeaderPods, leaderPods) {
+		lws.Status.CurrentLeaderPods = leaderPods
+		updateStatus = true
+	}
+	subGroups, readySubGroups := countSubGroups(lws, replicas, podList.Items)
+	if lws.Status.SubGroups != subGroups || lws.Status.ReadySubGroups != readySubGroups {
+		lws.Status.SubGroups = subGroups
+		lws.Status.ReadySubGroups = readySubGroups
+		updateStatus = true
+	}
+
 	// check if an update is needed
 	updateConditions, updateDone, err := r.updateConditions(ctx, lws, revisionKey)
 	if err != nil {
-		return false, err
+		return false, 0, err
+	}
+
+	if lws.Status.UpdateRevision != revisionKey {
+		lws.Status.UpdateRevision = revisionKey
+		updateStatus = true
+	}
+	if updateDone && lws.Status.CurrentRevision != revisionKey {
+		lws.Status.CurrentRevision = revisionKey
+		updateStatus = true
+	}
+	if lws.Status.ObservedGeneration != lws.Generation {
+		lws.Status.ObservedGeneration = lws.Generation
+		updateStatus = true
+	}
+	now := time.Now()
+
+	pending := makeCondition(leaderworkerset.LeaderWorkerSetPending)
+	if !podQuotaExceeded {
+		pending.Status = metav1.ConditionFalse
+	}
+	updatePending := setCondition(lws, pending)
+	if updatePending && podQuotaExceeded {
+		r.Record.Eventf(lws, corev1.EventTypeWarning, PodQuotaExceeded, "%s, new groups are pending until enough pods are deleted", pending.Message)
+	}
+
+	stalled := makeCondition(leaderworkerset.LeaderWorkerSetRolloutStalled)
+	if rolloutStalled != "" {
+		stalled.Message = rolloutStalled
+	}
+	updateStalled := setReportedCondition(lws, stalled, rolloutStalled != "")
+	if updateStalled && rolloutStalled != "" {
+		r.Record.Eventf(lws, corev1.EventTypeWarning, CrashLooping, "Pausing the rolling update, %s", rolloutStalled)
 	}
 
-	if updateStatus || updateConditions {
+	if updateStatus || updateConditions || updatePending || updateStalled {
+		// The changes of the conditions are never deferred, their events are already recorded.
+		if delay := r.statusWrites.delay(lws, r.statusUpdateDebounce, now); delay > 0 && equality.Semantic.DeepEqual(oldConditions, lws.Status.Conditions) {
+			log.V(2).Info("Deferring the status update", "delay", delay)
+			return updateDone, delay, nil
+		}
 		if err := r.Status().Update(ctx, lws); err != nil {
 			if !apierrors.IsConflict(err) {
 				log.Error(err, "Updating LeaderWorkerSet status and/or condition.")
 			}
-			return false, err
+			return false, 0, err
+		}
+		r.statusWrites.record(lws, now)
+	}
+	return updateDone, 0, nil
+}
+
+// summarizeGroupPods summarizes the conditions of the pods per group, sorted by group index and
+// bounded to maxGroupStatuses groups.
+func summarizeGroupPods(pods []corev1.Pod) []leaderworkerset.GroupStatus {
+	podsByGroup := make(map[int][]corev1.Pod)
+	for _, pod := range pods {
+		groupIndex, err := strconv.Atoi(pod.Labels[leaderworkerset.GroupIndexLabelKey])
+		if err != nil {
+			continue
+		}
+		podsByGroup[groupIndex] = append(podsByGroup[groupIndex], pod)
+	}
+	groupIndexes := slices.Sorted(maps.Keys(podsByGroup))
+	if len(groupIndexes) > maxGroupStatuses {
+		groupIndexes = groupIndexes[:maxGroupStatuses]
+	}
+
+	var groups []leaderworkerset.GroupStatus
+	for _, groupIndex := range groupIndexes {
+		groupPods := podsByGroup[groupIndex]
+		slices.SortFunc(groupPods, func(a, b corev1.Pod) int {
+			aIndex, _ := strconv.Atoi(a.Labels[leaderworkerset.WorkerIndexLabelKey])
+			bIndex, _ := strconv.Atoi(b.Labels[leaderworkerset.WorkerIndexLabelKey])
+			return aIndex - bIndex
+		})
+		group := leaderworkerset.GroupStatus{
+			Index: int32(groupIndex),
+			Pods:  int32(len(groupPods)),
+		}
+		for _, conditionType := range summarizedPodConditions {
+			summary := leaderworkerset.GroupPodCondition{Type: conditionType}
+			for i := range groupPods {
+				_, condition := podutils.GetPodCondition(&groupPods[i].Status, conditionType)
+				if condition != nil && condition.Status == corev1.ConditionTrue {
+					summary.TruePods++
+					continue
+				}
+				if summary.Reason == "" && summary.Message == "" && condition != nil {
+					summary.Reason = condition.Reason
+					if condition.Message != "" {
+						summary.Message = fmt.Sprintf("%s: %s", groupPods[i].Name, condition.Message)
+					}
+				}
+			}
+			group.Conditions = append(group.Conditions, summary)
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// currentLeaderPods returns the names of the leader pods which aren't terminating, sorted by group
+// index and bounded to maxGroupStatuses groups like the group statuses.
+func currentLeaderPods(pods []corev1.Pod, lwsName string) []string {
+	leadersByGroup := make(map[int]string)
+	for _, pod := range pods {
+		if !podutils.LeaderPod(pod) || pod.DeletionTimestamp != nil {
+			continue
+		}
+		groupIndex, err := utils.GroupIndex(&pod, lwsName)
+		if err != nil {
+			continue
+		}
+		leadersByGroup[groupIndex] = pod.Name
+	}
+	groupIndexes := slices.Sorted(maps.Keys(leadersByGroup))
+	if len(groupIndexes) > maxGroupStatuses {
+		groupIndexes = groupIndexes[:maxGroupStatuses]
+	}
+
+	var names []string
+	for _, groupIndex := range groupIndexes {
+		names = append(names, leadersByGroup[groupIndex])
+	}
+	return names
+}
+
+// countSubGroups returns the number of subgroups of the created groups, and how many of them have
+// all their pods running and ready, both 0 without a subGroupPolicy. The pods are assigned to
+// their subgroup like the pod webhook does, so that the missing pods count as not ready.
+func countSubGroups(lws *leaderworkerset.LeaderWorkerSet, replicas int, pods []corev1.Pod) (int32, int32) {
+	policy := lws.Spec.LeaderWorkerTemplate.SubGroupPolicy
+	if policy == nil || policy.SubGroupSize == nil {
+		return 0, 0
+	}
+	size, subGroupSize := int(*lws.Spec.LeaderWorkerTemplate.Size), int(*policy.SubGroupSize)
+	leaderExcluded := policy.Type != nil && *policy.Type == leaderworkerset.SubGroupPolicyTypeLeaderExcluded
+
+	type position struct{ group, index int }
+	readyPods := sets.New[position]()
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil || !podutils.PodRunningAndReady(pod) {
+			continue
+		}
+		groupIndex, err := strconv.Atoi(pod.Labels[leaderworkerset.GroupIndexLabelKey])
+		if err != nil {
+			continue
+		}
+		workerIndex, err := strconv.Atoi(pod.Labels[leaderworkerset.WorkerIndexLabelKey])
+		if err != nil {
+			continue
+		}
+		readyPods.Insert(position{groupIndex, workerIndex})
+	}
+
+	subGroupsReady := make(map[position]bool)
+	for groupIndex := range replicas {
+		for workerIndex := range size {
+			if workerIndex == 0 && leaderExcluded {
+				continue
+			}
+			subGroup := position{groupIndex, utils.SubGroupIndex(size, subGroupSize, workerIndex)}
+			ready, found := subGroupsReady[subGroup]
+			subGroupsReady[subGroup] = (ready || !found) && readyPods.Has(position{groupIndex, workerIndex})
 		}
 	}
-	return updateDone, nil
+	var readySubGroups int32
+	for _, ready := range subGroupsReady {
+		if ready {
+			readySubGroups++
+		}
+	}
+	return int32(len(subGroupsReady)), readySubGroups
 }
 
 type replicaState struct {
@@ -538,7 +1901,7 @@ func (r *LeaderWorkerSetReconciler) getReplicaStates(ctx context.Context, lws *l
 	// Get a sorted leader pod list matches with the following sorted statefulsets one by one, which means
 	// the leader pod and the corresponding worker statefulset has the same index.
 	sortedPods := utils.SortByIndex(func(pod corev1.Pod) (int, error) {
-		return strconv.Atoi(pod.Labels[leaderworkerset.GroupIndexLabelKey])
+		return utils.GroupIndex(&pod, lws.Name)
 	}, leaderPodList.Items, int(stsReplicas))
 
 	stsSelector := client.MatchingLabels(map[string]string{
@@ -549,7 +1912,7 @@ func (r *LeaderWorkerSetReconciler) getReplicaStates(ctx context.Context, lws *l
 		return nil, err
 	}
 	sortedSts := utils.SortByIndex(func(sts appsv1.StatefulSet) (int, error) {
-		return strconv.Atoi(sts.Labels[leaderworkerset.GroupIndexLabelKey])
+		return utils.GroupIndex(&sts, lws.Name)
 	}, stsList.Items, int(stsReplicas))
 
 	// Once size==1, no worker statefulSets will be created.
@@ -568,21 +1931,19 @@ func (r *LeaderWorkerSetReconciler) getReplicaStates(ctx context.Context, lws *l
 		}
 
 		leaderUpdated := revisionutils.GetRevisionKey(&sortedPods[idx]) == revisionKey
-		leaderReady := podutils.PodRunningAndReady(sortedPods[idx])
 
 		if noWorkerSts {
 			states[idx] = replicaState{
-				ready:   leaderReady,
+				ready:   groupReady(lws, sortedPods[idx], nil),
 				updated: leaderUpdated,
 			}
 			continue
 		}
 
 		workersUpdated := revisionutils.GetRevisionKey(&sortedSts[idx]) == revisionKey
-		workersReady := statefulsetutils.StatefulsetReady(sortedSts[idx])
 
 		states[idx] = replicaState{
-			ready:   leaderReady && workersReady,
+			ready:   groupReady(lws, sortedPods[idx], &sortedSts[idx]),
 			updated: leaderUpdated && workersUpdated,
 		}
 	}
@@ -622,6 +1983,81 @@ func rollingUpdatePartition(states []replicaState, stsReplicas int32, rollingSte
 	return min(partition, currentPartition)
 }
 
+// waitingForLeaders returns whether the startup policy of the lws defers the creation of the
+// workers of the group of the leader pod, given whether the leaders of all the groups are ready.
+// Without the AllLeadersReadyStartupPolicy feature gate, AllLeadersReady behaves like LeaderReady.
+func waitingForLeaders(lws *leaderworkerset.LeaderWorkerSet, leaderPod *corev1.Pod, allLeadersReady bool) bool {
+	switch lws.Spec.StartupPolicy {
+	case leaderworkerset.LeaderReadyStartupPolicy:
+		return !leaderPodStarted(lws, leaderPod)
+	case leaderworkerset.AllLeadersReadyStartupPolicy:
+		return !leaderPodStarted(lws, leaderPod) || (features.Enabled(features.AllLeadersReadyStartupPolicy) && !allLeadersReady)
+	}
+	return false
+}
+
+// leadersReady returns whether the leader pods of all the groups of the lws are ready, the
+// terminating leader pods and the ones of the bursted groups aren't counted.
+func leadersReady(lws *leaderworkerset.LeaderWorkerSet, leaderPods []corev1.Pod) bool {
+	readyGroups := sets.New[int]()
+	for i := range leaderPods {
+		pod := &leaderPods[i]
+		if pod.DeletionTimestamp != nil || !leaderPodStarted(lws, pod) {
+			continue
+		}
+		groupIndex, err := utils.GroupIndex(pod, lws.Name)
+		if err != nil || groupIndex >= int(*lws.Spec.Replicas) {
+			continue
+		}
+		readyGroups.Insert(groupIndex)
+	}
+	return readyGroups.Len() == int(*lws.Spec.Replicas)
+}
+
+// groupReady returns whether a group is ready given its leader pod and its worker statefulset, nil
+// when the group has no workers. A leaderless group is ready once a majority of its candidates are ready.
+func groupReady(lws *leaderworkerset.LeaderWorkerSet, leaderPod corev1.Pod, workerSts *appsv1.StatefulSet) bool {
+	if leaderless, _ := utils.ParseLeaderless(lws.Annotations); leaderless {
+		var readyCandidates int32
+		if podutils.PodRunningAndReady(leaderPod) {
+			readyCandidates++
+		}
+		if workerSts != nil {
+			readyCandidates += workerSts.Status.ReadyReplicas
+		}
+		return readyCandidates >= *lws.Spec.LeaderWorkerTemplate.Size/2+1
+	}
+	return (workerSts == nil || groupWorkersReady(lws, *workerSts)) && groupLeaderReady(lws, leaderPod)
+}
+
+// leaderPodStarted returns whether the leader pod is ready for the startup policies to create the
+// workers. A coordination-only leader only needs to be running, since it may only become Ready once
+// it reaches its workers.
+func leaderPodStarted(lws *leaderworkerset.LeaderWorkerSet, pod *corev1.Pod) bool {
+	if policy := lws.Spec.GroupReadinessPolicy; policy != nil && policy.CoordinationOnlyLeader {
+		return pod.Status.Phase == corev1.PodRunning
+	}
+	return podutils.IsPodReady(pod)
+}
+
+// groupLeaderReady returns whether the leader pod of a group is ready, a coordination-only leader
+// only needs to be running.
+func groupLeaderReady(lws *leaderworkerset.LeaderWorkerSet, pod corev1.Pod) bool {
+	if policy := lws.Spec.GroupReadinessPolicy; policy != nil && policy.CoordinationOnlyLeader {
+		return pod.Status.Phase == corev1.PodRunning
+	}
+	return podutils.PodRunningAndReady(pod)
+}
+
+// groupWorkersReady returns whether the worker statefulset of a group is ready, a quorum of ready
+// workers is enough when the LeaderWorkerSet sets a group readiness policy.
+func groupWorkersReady(lws *leaderworkerset.LeaderWorkerSet, sts appsv1.StatefulSet) bool {
+	if policy := lws.Spec.GroupReadinessPolicy; policy != nil && policy.MinReadyWorkers != nil {
+		return statefulsetutils.StatefulsetQuorumReady(sts, *policy.MinReadyWorkers)
+	}
+	return statefulsetutils.StatefulsetReady(sts)
+}
+
 func calculateLWSUnreadyReplicas(states []replicaState, lwsReplicas int32) int32 {
 	var unreadyCount int32
 	for idx := int32(0); idx < lwsReplicas; idx++ {
@@ -676,7 +2112,7 @@ func (r *LeaderWorkerSetReconciler) getOrCreateRevisionIfNonExist(ctx context.Co
 		if revisionKey != "" {
 			message = fmt.Sprintf("Creating missing revision with key %s for existing LeaderWorkerSet", revision.Labels[leaderworkerset.RevisionKey])
 		}
-		recorder.Eventf(lws, corev1.EventTypeNormal, CreatingRevision, message)
+		recorder.Event(lws, corev1.EventTypeNormal, CreatingRevision, message)
 	}
 	return newRevision, err
 }
@@ -698,8 +2134,17 @@ func (r *LeaderWorkerSetReconciler) getUpdatedRevision(ctx context.Context, sts
 	return nil, nil
 }
 
+// coordinatorTemplate returns the pod template of the pods of the group 0, nil if the lws doesn't
+// have a coordinator group.
+func coordinatorTemplate(lws *leaderworkerset.LeaderWorkerSet) *corev1.PodTemplateSpec {
+	if lws.Spec.LeaderWorkerTemplate.CoordinatorTemplate == nil {
+		return nil
+	}
+	return lws.Spec.LeaderWorkerTemplate.CoordinatorTemplate.DeepCopy()
+}
+
 // constructLeaderStatefulSetApplyConfiguration constructs the applied configuration for the leader StatefulSet
-func constructLeaderStatefulSetApplyConfiguration(lws *leaderworkerset.LeaderWorkerSet, partition, replicas int32, revisionKey string) (*appsapplyv1.StatefulSetApplyConfiguration, error) {
+func constructLeaderStatefulSetApplyConfiguration(lws *leaderworkerset.LeaderWorkerSet, partition, replicas int32, revisionKey string, namer naming.Namer) (*appsapplyv1.StatefulSetApplyConfiguration, error) {
 	var podTemplateSpec corev1.PodTemplateSpec
 	if lws.Spec.LeaderWorkerTemplate.LeaderTemplate != nil {
 		podTemplateSpec = *lws.Spec.LeaderWorkerTemplate.LeaderTemplate.DeepCopy()
@@ -722,17 +2167,50 @@ func constructLeaderStatefulSetApplyConfiguration(lws *leaderworkerset.LeaderWor
 		leaderworkerset.SetNameLabelKey:     lws.Name,
 		leaderworkerset.RevisionKey:         revisionKey,
 	})
+	if err := setCandidateLabel(lws, &podTemplateApplyConfiguration); err != nil {
+		return nil, err
+	}
 	podAnnotations := make(map[string]string)
 	podAnnotations[leaderworkerset.SizeAnnotationKey] = strconv.Itoa(int(*lws.Spec.LeaderWorkerTemplate.Size))
-	if lws.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey] != "" {
-		podAnnotations[leaderworkerset.ExclusiveKeyAnnotationKey] = lws.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey]
+	if err := setExclusiveTopologyAnnotations(lws, podAnnotations); err != nil {
+		return nil, err
+	}
+	if err := setPodInjectionAnnotation(lws, podAnnotations); err != nil {
+		return nil, err
+	}
+	setInjectedContainerResourcesAnnotation(lws, podAnnotations)
+	setQueueNameAnnotation(lws, podAnnotations)
+	setActiveDeadlineSecondsAnnotation(lws, podAnnotations)
+	if err := setWorkloadIdentityAnnotation(lws, podAnnotations); err != nil {
+		return nil, err
+	}
+	readinessGate, err := utils.ParseGroupReadinessGate(lws.Annotations)
+	if err != nil {
+		return nil, err
+	}
+	if readinessGate {
+		// The pod controller sets the condition once the workers of the group are ready.
+		podTemplateApplyConfiguration.Spec.WithReadinessGates(coreapplyv1.PodReadinessGate().WithConditionType(leaderworkerset.GroupReadyPodConditionType))
+	}
+	// The leader pods share the template, the pod webhook applies the overrides of their group.
+	if overrides := lws.Spec.LeaderWorkerTemplate.GroupResourceOverrides; len(overrides) != 0 {
+		value, err := json.Marshal(overrides)
+		if err != nil {
+			return nil, err
+		}
+		podAnnotations[leaderworkerset.GroupResourceOverridesAnnotationKey] = string(value)
+	}
+	// Likewise, the pod webhook applies the coordinator template to the leader pod of the group 0.
+	if lws.Spec.LeaderWorkerTemplate.CoordinatorTemplate != nil {
+		value, err := json.Marshal(coordinatorTemplate(lws))
+		if err != nil {
+			return nil, err
+		}
+		podAnnotations[leaderworkerset.CoordinatorTemplateAnnotationKey] = string(value)
 	}
 	if lws.Spec.LeaderWorkerTemplate.SubGroupPolicy != nil {
 		podAnnotations[leaderworkerset.SubGroupPolicyTypeAnnotationKey] = (string(*lws.Spec.LeaderWorkerTemplate.SubGroupPolicy.Type))
 		podAnnotations[leaderworkerset.SubGroupSizeAnnotationKey] = strconv.Itoa(int(*lws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize))
-		if lws.Annotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey] != "" {
-			podAnnotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey] = lws.Annotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey]
-		}
 	}
 
 	if lws.Spec.NetworkConfig != nil && *lws.Spec.NetworkConfig.SubdomainPolicy == leaderworkerset.SubdomainUniquePerReplica {
@@ -741,15 +2219,22 @@ func constructLeaderStatefulSetApplyConfiguration(lws *leaderworkerset.LeaderWor
 
 	podTemplateApplyConfiguration.WithAnnotations(podAnnotations)
 
+	// With the Recreate rollout strategy, the groups are deleted before the template is rolled out,
+	// so the leader StatefulSet only needs the partition.
+	rollingUpdateStrategy := appsapplyv1.RollingUpdateStatefulSetStrategy().WithPartition(partition)
+	if lws.Spec.RolloutStrategy.RollingUpdateConfiguration != nil {
+		rollingUpdateStrategy.WithMaxUnavailable(lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxUnavailable)
+	}
+
 	// construct statefulset apply configuration
 	statefulSetConfig := appsapplyv1.StatefulSet(lws.Name, lws.Namespace).
 		WithSpec(appsapplyv1.StatefulSetSpec().
-			WithServiceName(lws.Name).
+			WithServiceName(namer.ServiceName(lws.Name)).
 			WithReplicas(replicas).
 			WithPodManagementPolicy(appsv1.ParallelPodManagement).
 			WithTemplate(&podTemplateApplyConfiguration).
-			WithUpdateStrategy(appsapplyv1.StatefulSetUpdateStrategy().WithType(appsv1.StatefulSetUpdateStrategyType(lws.Spec.RolloutStrategy.Type)).WithRollingUpdate(
-				appsapplyv1.RollingUpdateStatefulSetStrategy().WithMaxUnavailable(lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxUnavailable).WithPartition(partition),
+			WithUpdateStrategy(appsapplyv1.StatefulSetUpdateStrategy().WithType(appsv1.RollingUpdateStatefulSetStrategyType).WithRollingUpdate(
+				rollingUpdateStrategy,
 			)).
 			WithSelector(metaapplyv1.LabelSelector().
 				WithMatchLabels(map[string]string{
@@ -777,6 +2262,18 @@ func makeCondition(conditionType leaderworkerset.LeaderWorkerSetConditionType) m
 		condtype = string(leaderworkerset.LeaderWorkerSetUpdateInProgress)
 		reason = GroupsUpdating
 		message = "Rolling Upgrade is in progress"
+	case leaderworkerset.LeaderWorkerSetWaitingForLeader:
+		condtype = string(leaderworkerset.LeaderWorkerSetWaitingForLeader)
+		reason = LeaderNotReady
+		message = "Groups are waiting for their leader pod to be ready"
+	case leaderworkerset.LeaderWorkerSetRolloutStalled:
+		condtype = string(leaderworkerset.LeaderWorkerSetRolloutStalled)
+		reason = CrashLooping
+		message = "No groups of the new revision are crash-looping"
+	case leaderworkerset.LeaderWorkerSetPending:
+		condtype = string(leaderworkerset.LeaderWorkerSetPending)
+		reason = PodQuotaExceeded
+		message = "The pods managed by the controller reached maxTotalManagedPods"
 	default:
 		condtype = string(leaderworkerset.LeaderWorkerSetProgressing)
 		reason = GroupsProgressing
@@ -793,6 +2290,33 @@ func makeCondition(conditionType leaderworkerset.LeaderWorkerSetConditionType) m
 	return condition
 }
 
+// setWaitingForLeaderCondition reports the number of groups waiting for their leader in the
+// WaitingForLeader condition.
+func setWaitingForLeaderCondition(lws *leaderworkerset.LeaderWorkerSet, waitingGroups int) bool {
+	condition := makeCondition(leaderworkerset.LeaderWorkerSetWaitingForLeader)
+	if waitingGroups == 0 {
+		condition.Message = "No groups are waiting for their leader pod to be ready"
+	} else if lws.Spec.StartupPolicy == leaderworkerset.AllLeadersReadyStartupPolicy && features.Enabled(features.AllLeadersReadyStartupPolicy) {
+		condition.Message = fmt.Sprintf("%d groups are waiting for the leader pods of all the groups to be ready", waitingGroups)
+	} else {
+		condition.Message = fmt.Sprintf("%d groups are waiting for their leader pod to be ready", waitingGroups)
+	}
+	return setReportedCondition(lws, condition, waitingGroups != 0)
+}
+
+// setReportedCondition sets the condition to true while reported, or to false once no longer
+// reported. Unlike setCondition, the message is updated while the condition stays true, and the
+// condition is only added once reported.
+func setReportedCondition(lws *leaderworkerset.LeaderWorkerSet, condition metav1.Condition, reported bool) bool {
+	if !reported {
+		if apimeta.FindStatusCondition(lws.Status.Conditions, condition.Type) == nil {
+			return false
+		}
+		condition.Status = metav1.ConditionFalse
+	}
+	return apimeta.SetStatusCondition(&lws.Status.Conditions, condition)
+}
+
 func setConditions(lws *leaderworkerset.LeaderWorkerSet, conditions []metav1.Condition) bool {
 	shouldUpdate := false
 	for _, condition := range conditions {
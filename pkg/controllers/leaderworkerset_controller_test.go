@@ -18,21 +18,43 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	appsapplyv1 "k8s.io/client-go/applyconfigurations/apps/v1"
 	coreapplyv1 "k8s.io/client-go/applyconfigurations/core/v1"
 	metaapplyv1 "k8s.io/client-go/applyconfigurations/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/utils/ptr"
 
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
 
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/lws/pkg/metrics"
+	"sigs.k8s.io/lws/pkg/utils/naming"
+	podutils "sigs.k8s.io/lws/pkg/utils/pod"
 	revisionutils "sigs.k8s.io/lws/pkg/utils/revision"
 	"sigs.k8s.io/lws/test/wrappers"
 )
@@ -402,10 +424,141 @@ func TestLeaderStatefulSetApplyConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:        "1 replica, size 1, with empty leader template, with active deadline seconds",
+			revisionKey: revisionKey2,
+			lws: wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").
+				Replica(1).
+				RolloutStrategy(leaderworkerset.RolloutStrategy{
+					Type: leaderworkerset.RollingUpdateStrategyType,
+					RollingUpdateConfiguration: &leaderworkerset.RollingUpdateConfiguration{
+						MaxUnavailable: intstr.FromInt32(1),
+					},
+				}).
+				WorkerTemplateSpec(wrappers.MakeWorkerPodSpec()).
+				Size(1).
+				RestartPolicy(leaderworkerset.RecreateGroupOnPodRestart).
+				ActiveDeadlineSeconds(3600).Obj(),
+			wantApplyConfig: &appsapplyv1.StatefulSetApplyConfiguration{
+				TypeMetaApplyConfiguration: metaapplyv1.TypeMetaApplyConfiguration{
+					Kind:       ptr.To[string]("StatefulSet"),
+					APIVersion: ptr.To[string]("apps/v1"),
+				},
+				ObjectMetaApplyConfiguration: &metaapplyv1.ObjectMetaApplyConfiguration{
+					Name:      ptr.To[string]("test-sample"),
+					Namespace: ptr.To[string]("default"),
+					Labels: map[string]string{
+						"leaderworkerset.sigs.k8s.io/name":                   "test-sample",
+						"leaderworkerset.sigs.k8s.io/template-revision-hash": revisionKey2,
+					},
+					Annotations: map[string]string{"leaderworkerset.sigs.k8s.io/replicas": "1"},
+				},
+				Spec: &appsapplyv1.StatefulSetSpecApplyConfiguration{
+					Replicas: ptr.To[int32](1),
+					Selector: &metaapplyv1.LabelSelectorApplyConfiguration{
+						MatchLabels: map[string]string{
+							"leaderworkerset.sigs.k8s.io/name":         "test-sample",
+							"leaderworkerset.sigs.k8s.io/worker-index": "0",
+						},
+					},
+					Template: &coreapplyv1.PodTemplateSpecApplyConfiguration{
+						ObjectMetaApplyConfiguration: &metaapplyv1.ObjectMetaApplyConfiguration{
+							Labels: map[string]string{
+								"leaderworkerset.sigs.k8s.io/name":                   "test-sample",
+								"leaderworkerset.sigs.k8s.io/worker-index":           "0",
+								"leaderworkerset.sigs.k8s.io/template-revision-hash": revisionKey2,
+							},
+							Annotations: map[string]string{
+								"leaderworkerset.sigs.k8s.io/size":                    "1",
+								"leaderworkerset.sigs.k8s.io/active-deadline-seconds": "3600",
+							},
+						},
+						Spec: &coreapplyv1.PodSpecApplyConfiguration{
+							Containers: []coreapplyv1.ContainerApplyConfiguration{
+								{
+									Name:      ptr.To[string]("leader"),
+									Image:     ptr.To[string]("nginxinc/nginx-unprivileged:1.27"),
+									Ports:     []coreapplyv1.ContainerPortApplyConfiguration{{ContainerPort: ptr.To[int32](8080), Protocol: ptr.To[corev1.Protocol](corev1.ProtocolTCP)}},
+									Resources: &coreapplyv1.ResourceRequirementsApplyConfiguration{},
+								},
+							},
+						},
+					},
+					ServiceName:         ptr.To[string]("test-sample"),
+					PodManagementPolicy: ptr.To[appsv1.PodManagementPolicyType](appsv1.ParallelPodManagement),
+					UpdateStrategy: appsapplyv1.StatefulSetUpdateStrategy().
+						WithType(appsv1.RollingUpdateStatefulSetStrategyType).
+						WithRollingUpdate(appsapplyv1.RollingUpdateStatefulSetStrategy().WithPartition(0).WithMaxUnavailable(intstr.FromInt32(1))),
+				},
+			},
+		},
+		{
+			name:        "1 replica, size 1, with empty leader template, with Recreate strategy",
+			revisionKey: revisionKey2,
+			lws: wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").
+				Replica(1).
+				RolloutStrategy(leaderworkerset.RolloutStrategy{
+					Type: leaderworkerset.RecreateStrategyType,
+				}).
+				WorkerTemplateSpec(wrappers.MakeWorkerPodSpec()).
+				Size(1).
+				RestartPolicy(leaderworkerset.RecreateGroupOnPodRestart).Obj(),
+			wantApplyConfig: &appsapplyv1.StatefulSetApplyConfiguration{
+				TypeMetaApplyConfiguration: metaapplyv1.TypeMetaApplyConfiguration{
+					Kind:       ptr.To[string]("StatefulSet"),
+					APIVersion: ptr.To[string]("apps/v1"),
+				},
+				ObjectMetaApplyConfiguration: &metaapplyv1.ObjectMetaApplyConfiguration{
+					Name:      ptr.To[string]("test-sample"),
+					Namespace: ptr.To[string]("default"),
+					Labels: map[string]string{
+						"leaderworkerset.sigs.k8s.io/name":                   "test-sample",
+						"leaderworkerset.sigs.k8s.io/template-revision-hash": revisionKey2,
+					},
+					Annotations: map[string]string{"leaderworkerset.sigs.k8s.io/replicas": "1"},
+				},
+				Spec: &appsapplyv1.StatefulSetSpecApplyConfiguration{
+					Replicas: ptr.To[int32](1),
+					Selector: &metaapplyv1.LabelSelectorApplyConfiguration{
+						MatchLabels: map[string]string{
+							"leaderworkerset.sigs.k8s.io/name":         "test-sample",
+							"leaderworkerset.sigs.k8s.io/worker-index": "0",
+						},
+					},
+					Template: &coreapplyv1.PodTemplateSpecApplyConfiguration{
+						ObjectMetaApplyConfiguration: &metaapplyv1.ObjectMetaApplyConfiguration{
+							Labels: map[string]string{
+								"leaderworkerset.sigs.k8s.io/name":                   "test-sample",
+								"leaderworkerset.sigs.k8s.io/worker-index":           "0",
+								"leaderworkerset.sigs.k8s.io/template-revision-hash": revisionKey2,
+							},
+							Annotations: map[string]string{
+								"leaderworkerset.sigs.k8s.io/size": "1",
+							},
+						},
+						Spec: &coreapplyv1.PodSpecApplyConfiguration{
+							Containers: []coreapplyv1.ContainerApplyConfiguration{
+								{
+									Name:      ptr.To[string]("leader"),
+									Image:     ptr.To[string]("nginxinc/nginx-unprivileged:1.27"),
+									Ports:     []coreapplyv1.ContainerPortApplyConfiguration{{ContainerPort: ptr.To[int32](8080), Protocol: ptr.To[corev1.Protocol](corev1.ProtocolTCP)}},
+									Resources: &coreapplyv1.ResourceRequirementsApplyConfiguration{},
+								},
+							},
+						},
+					},
+					ServiceName:         ptr.To[string]("test-sample"),
+					PodManagementPolicy: ptr.To[appsv1.PodManagementPolicyType](appsv1.ParallelPodManagement),
+					UpdateStrategy: appsapplyv1.StatefulSetUpdateStrategy().
+						WithType(appsv1.RollingUpdateStatefulSetStrategyType).
+						WithRollingUpdate(appsapplyv1.RollingUpdateStatefulSetStrategy().WithPartition(0)),
+				},
+			},
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			stsApplyConfig, err := constructLeaderStatefulSetApplyConfiguration(tc.lws, 0, *tc.lws.Spec.Replicas, tc.revisionKey)
+			stsApplyConfig, err := constructLeaderStatefulSetApplyConfiguration(tc.lws, 0, *tc.lws.Spec.Replicas, tc.revisionKey, naming.Default())
 			if err != nil {
 				t.Errorf("failed with error: %s", err.Error())
 			}
@@ -536,3 +689,3336 @@ func TestSetCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestScaleUpReplicas(t *testing.T) {
+	now := time.Now()
+	leaderPod := func(index int, createdAgo time.Duration) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              fmt.Sprintf("test-sample-%d", index),
+				Labels:            map[string]string{leaderworkerset.GroupIndexLabelKey: strconv.Itoa(index)},
+				CreationTimestamp: metav1.NewTime(now.Add(-createdAgo)),
+			},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		leaderPods       []corev1.Pod
+		currentReplicas  int32
+		wantReplicas     int32
+		groupsPerMinute  int
+		wantAllowed      int32
+		wantRequeueAfter time.Duration
+	}{
+		{
+			name:             "initial creation is capped to the rate",
+			currentReplicas:  0,
+			wantReplicas:     10,
+			groupsPerMinute:  3,
+			wantAllowed:      3,
+			wantRequeueAfter: time.Minute,
+		},
+		{
+			name:            "scale-up within the rate is not capped",
+			leaderPods:      []corev1.Pod{leaderPod(0, time.Hour), leaderPod(1, time.Hour)},
+			currentReplicas: 2,
+			wantReplicas:    4,
+			groupsPerMinute: 3,
+			wantAllowed:     4,
+		},
+		{
+			name:             "groups not created yet count against the rate",
+			leaderPods:       []corev1.Pod{leaderPod(0, time.Hour)},
+			currentReplicas:  3,
+			wantReplicas:     10,
+			groupsPerMinute:  3,
+			wantAllowed:      4,
+			wantRequeueAfter: time.Minute,
+		},
+		{
+			name:             "no budget left, requeue when the oldest recent group ages out",
+			leaderPods:       []corev1.Pod{leaderPod(0, time.Hour), leaderPod(1, 40*time.Second), leaderPod(2, 10*time.Second)},
+			currentReplicas:  3,
+			wantReplicas:     5,
+			groupsPerMinute:  2,
+			wantAllowed:      3,
+			wantRequeueAfter: 20 * time.Second,
+		},
+		{
+			name:            "groups created out of the window free up the budget",
+			leaderPods:      []corev1.Pod{leaderPod(0, 2*time.Minute), leaderPod(1, 90*time.Second)},
+			currentReplicas: 2,
+			wantReplicas:    4,
+			groupsPerMinute: 2,
+			wantAllowed:     4,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			allowed, requeueAfter := scaleUpReplicas(tc.leaderPods, tc.currentReplicas, tc.wantReplicas, tc.groupsPerMinute, now)
+			if allowed != tc.wantAllowed {
+				t.Errorf("Expected %d replicas allowed, got %d", tc.wantAllowed, allowed)
+			}
+			if requeueAfter != tc.wantRequeueAfter {
+				t.Errorf("Expected requeue after %v, got %v", tc.wantRequeueAfter, requeueAfter)
+			}
+		})
+	}
+}
+
+func TestScaleUpReplicasOverTime(t *testing.T) {
+	start := time.Now()
+	const groupsPerMinute = 2
+	var leaderPods []corev1.Pod
+	var current int32
+
+	// Simulate the reconciles happening every 10 seconds, the groups are created right away.
+	for elapsed := time.Duration(0); elapsed <= 3*time.Minute; elapsed += 10 * time.Second {
+		now := start.Add(elapsed)
+		allowed, _ := scaleUpReplicas(leaderPods, current, 8, groupsPerMinute, now)
+		for idx := current; idx < allowed; idx++ {
+			leaderPods = append(leaderPods, corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              fmt.Sprintf("test-sample-%d", idx),
+					Labels:            map[string]string{leaderworkerset.GroupIndexLabelKey: strconv.Itoa(int(idx))},
+					CreationTimestamp: metav1.NewTime(now),
+				},
+			})
+		}
+		current = allowed
+
+		// At any point in time, no more than groupsPerMinute groups are created per started minute.
+		if maxGroups := int32(groupsPerMinute * (int(elapsed/time.Minute) + 1)); current > maxGroups {
+			t.Fatalf("Created %d groups after %v, expected at most %d", current, elapsed, maxGroups)
+		}
+	}
+	if current != 8 {
+		t.Errorf("Expected all the 8 groups to be created after 3 minutes, got %d", current)
+	}
+}
+
+func TestDrainedReplicas(t *testing.T) {
+	// The drain start times are persisted with a precision of one second.
+	now := time.Now().Truncate(time.Second)
+	leaderPod := func(index int, ready bool, drainStartedAgo *time.Duration) corev1.Pod {
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   fmt.Sprintf("test-sample-%d", index),
+				Labels: map[string]string{leaderworkerset.GroupIndexLabelKey: strconv.Itoa(index)},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+		if ready {
+			pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+		}
+		if drainStartedAgo != nil {
+			pod.Annotations = map[string]string{leaderworkerset.DrainStartedAnnotationKey: now.Add(-*drainStartedAgo).Format(time.RFC3339)}
+		}
+		return pod
+	}
+
+	tests := []struct {
+		name             string
+		leaderPods       []corev1.Pod
+		specReplicas     int32
+		currentReplicas  int32
+		wantReplicas     int32
+		wantAllowed      int32
+		wantRequeueAfter time.Duration
+	}{
+		{
+			name:            "scale-up is not delayed",
+			leaderPods:      []corev1.Pod{leaderPod(0, true, nil)},
+			specReplicas:    2,
+			currentReplicas: 1,
+			wantReplicas:    2,
+			wantAllowed:     2,
+		},
+		{
+			name:             "ready groups wait for the timeout",
+			leaderPods:       []corev1.Pod{leaderPod(0, true, nil), leaderPod(1, true, ptr.To(10*time.Second)), leaderPod(2, true, ptr.To(10*time.Second))},
+			specReplicas:     1,
+			currentReplicas:  3,
+			wantReplicas:     1,
+			wantAllowed:      3,
+			wantRequeueAfter: 20 * time.Second,
+		},
+		{
+			name:            "groups are deleted once the timeout elapsed",
+			leaderPods:      []corev1.Pod{leaderPod(0, true, nil), leaderPod(1, true, ptr.To(time.Minute)), leaderPod(2, true, ptr.To(time.Minute))},
+			specReplicas:    1,
+			currentReplicas: 3,
+			wantReplicas:    1,
+			wantAllowed:     1,
+		},
+		{
+			name:            "unready leader pods are drained",
+			leaderPods:      []corev1.Pod{leaderPod(0, true, nil), leaderPod(1, false, ptr.To(10*time.Second)), leaderPod(2, false, ptr.To(10*time.Second))},
+			specReplicas:    1,
+			currentReplicas: 3,
+			wantReplicas:    1,
+			wantAllowed:     1,
+		},
+		{
+			name:             "only the drained groups at the top are deleted",
+			leaderPods:       []corev1.Pod{leaderPod(0, true, nil), leaderPod(1, true, ptr.To(10*time.Second)), leaderPod(2, false, ptr.To(10*time.Second))},
+			specReplicas:     1,
+			currentReplicas:  3,
+			wantReplicas:     1,
+			wantAllowed:      2,
+			wantRequeueAfter: 20 * time.Second,
+		},
+		{
+			name:            "groups not created are not drained",
+			leaderPods:      []corev1.Pod{leaderPod(0, true, nil)},
+			specReplicas:    1,
+			currentReplicas: 3,
+			wantReplicas:    1,
+			wantAllowed:     1,
+		},
+		{
+			name:            "groups recreated by the Recreate strategy are not drained",
+			leaderPods:      []corev1.Pod{leaderPod(0, true, nil), leaderPod(1, true, nil)},
+			specReplicas:    2,
+			currentReplicas: 2,
+			wantReplicas:    0,
+			wantAllowed:     0,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			allowed, requeueAfter := drainedReplicas(tc.leaderPods, tc.specReplicas, tc.currentReplicas, tc.wantReplicas, 30*time.Second, now)
+			if allowed != tc.wantAllowed {
+				t.Errorf("Expected %d replicas allowed, got %d", tc.wantAllowed, allowed)
+			}
+			if requeueAfter != tc.wantRequeueAfter {
+				t.Errorf("Expected requeue after %v, got %v", tc.wantRequeueAfter, requeueAfter)
+			}
+		})
+	}
+}
+
+func TestDrainReplicas(t *testing.T) {
+	lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").Replica(1).Size(2).
+		Annotation(map[string]string{leaderworkerset.DrainTimeoutAnnotationKey: "600"}).Obj()
+	leaderSts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sample", Namespace: "default"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To[int32](3)},
+	}
+	var objects []client.Object
+	for i := range 3 {
+		pod := wrappers.MakePodWithLabels("test-sample", strconv.Itoa(i), "0", "default", 2)
+		pod.Status = corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		}
+		objects = append(objects, pod)
+	}
+	c := fake.NewClientBuilder().WithObjects(objects...).WithStatusSubresource(objects...).Build()
+	r := NewLeaderWorkerSetReconciler(c, nil, record.NewFakeRecorder(10), &configapi.Configuration{})
+	ctx := context.TODO()
+
+	drainStarted := func() []string {
+		var pods corev1.PodList
+		if err := c.List(ctx, &pods); err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, pod := range pods.Items {
+			if _, found := pod.Annotations[leaderworkerset.DrainStartedAnnotationKey]; found {
+				names = append(names, pod.Name)
+			}
+		}
+		return names
+	}
+
+	// The groups removed on scale-down are marked as draining and kept.
+	replicas, requeueAfter, err := r.drainReplicas(ctx, lws, leaderSts, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replicas != 3 || requeueAfter <= 0 || requeueAfter > 10*time.Minute {
+		t.Errorf("Expected the 3 groups to be kept while draining, got %d replicas, requeue after %v", replicas, requeueAfter)
+	}
+	if diff := cmp.Diff([]string{"test-sample-1", "test-sample-2"}, drainStarted()); diff != "" {
+		t.Errorf("Unexpected draining leader pods (-want +got):\n%s", diff)
+	}
+
+	// The last group is deleted once its leader pod is no longer ready.
+	var pod corev1.Pod
+	if err := c.Get(ctx, types.NamespacedName{Name: "test-sample-2", Namespace: "default"}, &pod); err != nil {
+		t.Fatal(err)
+	}
+	pod.Status.Conditions[0].Status = corev1.ConditionFalse
+	if err := c.Status().Update(ctx, &pod); err != nil {
+		t.Fatal(err)
+	}
+	replicas, _, err = r.drainReplicas(ctx, lws, leaderSts, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replicas != 2 {
+		t.Errorf("Expected the drained group to be deleted, got %d replicas", replicas)
+	}
+
+	// Reverting the scale-down stops the drain of the kept groups.
+	replicas, requeueAfter, err = r.drainReplicas(ctx, lws, leaderSts, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replicas != 3 || requeueAfter != 0 {
+		t.Errorf("Expected the scale-up not to be delayed, got %d replicas, requeue after %v", replicas, requeueAfter)
+	}
+	if diff := cmp.Diff([]string(nil), drainStarted()); diff != "" {
+		t.Errorf("Unexpected draining leader pods (-want +got):\n%s", diff)
+	}
+}
+
+func TestBatchedDeleteReplicas(t *testing.T) {
+	lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").Replica(1).Size(2).Obj()
+	leaderSts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sample", Namespace: "default"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To[int32](4)},
+	}
+	var objects []client.Object
+	for i := range 4 {
+		objects = append(objects,
+			wrappers.MakePodWithLabels("test-sample", strconv.Itoa(i), "0", "default", 2),
+			wrappers.MakePodWithLabels("test-sample", strconv.Itoa(i), "1", "default", 2))
+	}
+	c := fake.NewClientBuilder().WithObjects(objects...).Build()
+	r := NewLeaderWorkerSetReconciler(c, nil, record.NewFakeRecorder(10), &configapi.Configuration{
+		MaxConcurrentPodDeletes: ptr.To[int32](2),
+	})
+	ctx := context.TODO()
+
+	// deleteGroupPods simulates the statefulsets deleting the pods of the removed groups.
+	deleteGroupPods := func(replicas int32) {
+		var pods corev1.PodList
+		if err := c.List(ctx, &pods); err != nil {
+			t.Fatal(err)
+		}
+		for i := range pods.Items {
+			groupIndex, err := strconv.Atoi(pods.Items[i].Labels[leaderworkerset.GroupIndexLabelKey])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if int32(groupIndex) >= replicas {
+				if err := c.Delete(ctx, &pods.Items[i]); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+
+	steps := []struct {
+		deletePods       bool
+		wantReplicas     int32
+		wantRequeueAfter time.Duration
+	}{
+		// The 2 pods of the last group are deleted first.
+		{wantReplicas: 3, wantRequeueAfter: podDeleteRequeuePeriod},
+		// The next group waits for the pods of the previous one to be gone.
+		{wantReplicas: 3, wantRequeueAfter: podDeleteRequeuePeriod},
+		{deletePods: true, wantReplicas: 2, wantRequeueAfter: podDeleteRequeuePeriod},
+		// The last batch reaches the target replicas.
+		{deletePods: true, wantReplicas: 1},
+	}
+	for i, step := range steps {
+		if step.deletePods {
+			deleteGroupPods(*leaderSts.Spec.Replicas)
+		}
+		replicas, requeueAfter, err := r.batchedDeleteReplicas(ctx, r.config.Load(), lws, leaderSts, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if replicas != step.wantReplicas || requeueAfter != step.wantRequeueAfter {
+			t.Errorf("Step %d: expected %d replicas, requeue after %v, got %d replicas, requeue after %v",
+				i, step.wantReplicas, step.wantRequeueAfter, replicas, requeueAfter)
+		}
+		leaderSts.Spec.Replicas = ptr.To(replicas)
+	}
+
+	// The scale-up isn't delayed.
+	replicas, requeueAfter, err := r.batchedDeleteReplicas(ctx, r.config.Load(), lws, leaderSts, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replicas != 4 || requeueAfter != 0 {
+		t.Errorf("Expected the scale-up not to be delayed, got %d replicas, requeue after %v", replicas, requeueAfter)
+	}
+}
+
+func TestDeleteBatchReplicas(t *testing.T) {
+	testCases := []struct {
+		name             string
+		maxDeletes       int32
+		wantReplicas     int32
+		wantRequeueAfter time.Duration
+	}{
+		{
+			name:             "several groups per batch",
+			maxDeletes:       4,
+			wantReplicas:     2,
+			wantRequeueAfter: podDeleteRequeuePeriod,
+		},
+		{
+			name:             "a batch holds at least one group",
+			maxDeletes:       1,
+			wantReplicas:     3,
+			wantRequeueAfter: podDeleteRequeuePeriod,
+		},
+		{
+			name:         "the last batch reaches the target",
+			maxDeletes:   10,
+			wantReplicas: 1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			replicas, requeueAfter := deleteBatchReplicas(nil, 2, 4, 1, tc.maxDeletes)
+			if replicas != tc.wantReplicas || requeueAfter != tc.wantRequeueAfter {
+				t.Errorf("Expected %d replicas, requeue after %v, got %d replicas, requeue after %v",
+					tc.wantReplicas, tc.wantRequeueAfter, replicas, requeueAfter)
+			}
+		})
+	}
+}
+
+func TestPodQuotaReplicas(t *testing.T) {
+	lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").Replica(4).Size(2).Obj()
+	leaderSts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sample",
+			Namespace: "default",
+			Labels:    map[string]string{leaderworkerset.SetNameLabelKey: "test-sample"},
+		},
+		Spec: appsv1.StatefulSetSpec{Replicas: ptr.To[int32](1)},
+	}
+	makePod := func(lwsName, name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{leaderworkerset.SetNameLabelKey: lwsName},
+			},
+		}
+	}
+	otherPods := []*corev1.Pod{
+		makePod("other", "other-0"),
+		makePod("other", "other-0-1"),
+		makePod("other", "other-1"),
+		makePod("other", "other-1-1"),
+	}
+	objects := []client.Object{
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "unmanaged", Namespace: "default"}},
+		makePod("test-sample", "test-sample-0"),
+		makePod("test-sample", "test-sample-0-1"),
+	}
+	for _, pod := range otherPods {
+		objects = append(objects, pod)
+	}
+	c := fake.NewClientBuilder().WithObjects(objects...).Build()
+
+	tests := []struct {
+		name                string
+		maxTotalManagedPods int32
+		deletePods          []*corev1.Pod
+		replicas            int32
+		wantReplicas        int32
+	}{
+		{
+			name:         "unlimited",
+			replicas:     4,
+			wantReplicas: 4,
+		},
+		{
+			name:                "scale-up capped by the pods of the other lws",
+			maxTotalManagedPods: 8,
+			replicas:            4,
+			wantReplicas:        2,
+		},
+		{
+			name:                "scale-down is not capped",
+			maxTotalManagedPods: 1,
+			replicas:            1,
+			wantReplicas:        1,
+		},
+		{
+			name:                "existing groups are kept when the quota is already exceeded",
+			maxTotalManagedPods: 4,
+			replicas:            4,
+			wantReplicas:        1,
+		},
+		{
+			name:                "partial group doesn't fit in the quota",
+			maxTotalManagedPods: 8,
+			deletePods:          otherPods[:1],
+			replicas:            4,
+			wantReplicas:        2,
+		},
+		{
+			name:                "quota released as the pods of the other lws are deleted",
+			maxTotalManagedPods: 8,
+			deletePods:          otherPods[1:2],
+			replicas:            4,
+			wantReplicas:        3,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, pod := range tc.deletePods {
+				if err := c.Delete(context.TODO(), pod); err != nil {
+					t.Fatal(err)
+				}
+			}
+			r := NewLeaderWorkerSetReconciler(c, nil, record.NewFakeRecorder(10), &configapi.Configuration{MaxTotalManagedPods: tc.maxTotalManagedPods})
+			replicas, err := r.podQuotaReplicas(context.TODO(), r.config.Load(), lws, leaderSts, tc.replicas)
+			if err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			if replicas != tc.wantReplicas {
+				t.Errorf("Expected replicas %d, got %d", tc.wantReplicas, replicas)
+			}
+		})
+	}
+}
+
+func TestUpdateStatusPodQuotaExceeded(t *testing.T) {
+	lws := wrappers.BuildLeaderWorkerSet("default").Obj()
+	leaderSts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sample",
+			Namespace: "default",
+		},
+		Spec: appsv1.StatefulSetSpec{Replicas: ptr.To[int32](1)},
+	}
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(lws.DeepCopy(), leaderSts).
+		WithStatusSubresource(&leaderworkerset.LeaderWorkerSet{}).
+		Build()
+	recorder := record.NewFakeRecorder(10)
+	r := NewLeaderWorkerSetReconciler(c, nil, recorder, &configapi.Configuration{MaxTotalManagedPods: 1})
+
+	pendingStatus := func() metav1.ConditionStatus {
+		t.Helper()
+		var got leaderworkerset.LeaderWorkerSet
+		if err := c.Get(context.TODO(), client.ObjectKeyFromObject(lws), &got); err != nil {
+			t.Fatal(err)
+		}
+		for _, condition := range got.Status.Conditions {
+			if condition.Type == string(leaderworkerset.LeaderWorkerSetPending) {
+				return condition.Status
+			}
+		}
+		return ""
+	}
+
+	if err := c.Get(context.TODO(), client.ObjectKeyFromObject(lws), lws); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := r.updateStatus(context.TODO(), lws, "", true, ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := pendingStatus(); got != metav1.ConditionTrue {
+		t.Errorf("Expected the Pending condition to be true, got %q", got)
+	}
+	var quotaEvents int
+	for len(recorder.Events) > 0 {
+		if event := <-recorder.Events; strings.Contains(event, PodQuotaExceeded) {
+			quotaEvents++
+		}
+	}
+	if quotaEvents != 1 {
+		t.Errorf("Expected a %s event to be emitted, got %d", PodQuotaExceeded, quotaEvents)
+	}
+
+	if err := c.Get(context.TODO(), client.ObjectKeyFromObject(lws), lws); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := r.updateStatus(context.TODO(), lws, "", false, ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := pendingStatus(); got != metav1.ConditionFalse {
+		t.Errorf("Expected the Pending condition to be false once released, got %q", got)
+	}
+}
+
+func TestUpdateStatusWaitingForLeader(t *testing.T) {
+	lws := wrappers.BuildLeaderWorkerSet("default").Replica(1).StartupPolicy(leaderworkerset.LeaderReadyStartupPolicy).Obj()
+	leaderSts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sample",
+			Namespace: "default",
+		},
+		Spec: appsv1.StatefulSetSpec{Replicas: ptr.To[int32](1)},
+	}
+	leaderPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sample-0",
+			Namespace: "default",
+			Labels: map[string]string{
+				leaderworkerset.SetNameLabelKey:     "test-sample",
+				leaderworkerset.WorkerIndexLabelKey: "0",
+				leaderworkerset.GroupIndexLabelKey:  "0",
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}},
+		},
+	}
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(lws.DeepCopy(), leaderSts, leaderPod).
+		WithStatusSubresource(&leaderworkerset.LeaderWorkerSet{}).
+		Build()
+	r := NewLeaderWorkerSetReconciler(c, nil, record.NewFakeRecorder(10), &configapi.Configuration{})
+
+	updateStatus := func() *metav1.Condition {
+		t.Helper()
+		if err := c.Get(context.TODO(), client.ObjectKeyFromObject(lws), lws); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := r.updateStatus(context.TODO(), lws, "", false, ""); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Get(context.TODO(), client.ObjectKeyFromObject(lws), lws); err != nil {
+			t.Fatal(err)
+		}
+		return apimeta.FindStatusCondition(lws.Status.Conditions, string(leaderworkerset.LeaderWorkerSetWaitingForLeader))
+	}
+
+	// The leader isn't ready, the workers are not created yet.
+	condition := updateStatus()
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		t.Fatalf("Expected the WaitingForLeader condition to be true, got %v", condition)
+	}
+	if want := "1 groups are waiting for their leader pod to be ready"; condition.Message != want {
+		t.Errorf("Expected the condition message %q, got %q", want, condition.Message)
+	}
+	if got := testutil.ToFloat64(metrics.GroupsWaitingForLeader.WithLabelValues("default", "test-sample")); got != 1 {
+		t.Errorf("Expected 1 group waiting for its leader, got %v", got)
+	}
+
+	// The leader becomes ready and the pod controller creates the workers.
+	leaderPod.Status.Conditions[0].Status = corev1.ConditionTrue
+	if err := c.Status().Update(context.TODO(), leaderPod); err != nil {
+		t.Fatal(err)
+	}
+	workerSts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sample-0",
+			Namespace: "default",
+		},
+		Spec: appsv1.StatefulSetSpec{Replicas: ptr.To[int32](1)},
+	}
+	if err := c.Create(context.TODO(), workerSts); err != nil {
+		t.Fatal(err)
+	}
+	condition = updateStatus()
+	if condition == nil || condition.Status != metav1.ConditionFalse {
+		t.Errorf("Expected the WaitingForLeader condition to be cleared, got %v", condition)
+	}
+	if got := testutil.ToFloat64(metrics.GroupsWaitingForLeader.WithLabelValues("default", "test-sample")); got != 0 {
+		t.Errorf("Expected no group waiting for its leader, got %v", got)
+	}
+}
+
+func TestUpdateStatusCoordinationOnlyLeaderStartup(t *testing.T) {
+	lws := wrappers.BuildLeaderWorkerSet("default").Replica(1).StartupPolicy(leaderworkerset.LeaderReadyStartupPolicy).CoordinationOnlyLeader().Obj()
+	leaderSts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sample",
+			Namespace: "default",
+		},
+		Spec: appsv1.StatefulSetSpec{Replicas: ptr.To[int32](1)},
+	}
+	// The coordination-only leader is running but only becomes Ready once it reaches its workers.
+	leaderPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sample-0",
+			Namespace: "default",
+			Labels: map[string]string{
+				leaderworkerset.SetNameLabelKey:     "test-sample",
+				leaderworkerset.WorkerIndexLabelKey: "0",
+				leaderworkerset.GroupIndexLabelKey:  "0",
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}},
+		},
+	}
+	if waitingForLeaders(lws, leaderPod, false) {
+		t.Errorf("Expected the workers of the running coordination-only leader not to wait")
+	}
+	if !leadersReady(lws, []corev1.Pod{*leaderPod}) {
+		t.Errorf("Expected the running coordination-only leaders to count as ready for the AllLeadersReady startup policy")
+	}
+	pendingLeader := leaderPod.DeepCopy()
+	pendingLeader.Status.Phase = corev1.PodPending
+	if !waitingForLeaders(lws, pendingLeader, false) {
+		t.Errorf("Expected the workers of the pending coordination-only leader to wait")
+	}
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	workerSts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sample-0",
+			Namespace: "default",
+		},
+		Spec:   appsv1.StatefulSetSpec{Replicas: ptr.To[int32](1)},
+		Status: appsv1.StatefulSetStatus{Replicas: 1, ReadyReplicas: 1},
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(lws.DeepCopy(), leaderSts, leaderPod, workerSts).
+		WithStatusSubresource(&leaderworkerset.LeaderWorkerSet{}).
+		Build()
+	r := NewLeaderWorkerSetReconciler(c, nil, record.NewFakeRecorder(10), &configapi.Configuration{})
+	if err := c.Get(context.TODO(), client.ObjectKeyFromObject(lws), lws); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := r.updateStatus(context.TODO(), lws, "", false, ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := testutil.ToFloat64(metrics.GroupsWaitingForLeader.WithLabelValues("default", "test-sample")); got != 0 {
+		t.Errorf("Expected no group waiting for its leader, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.ReadyGroupsWithUnreadyLeader.WithLabelValues("default", "test-sample")); got != 1 {
+		t.Errorf("Expected 1 ready group with an unready leader, got %v", got)
+	}
+}
+
+func TestCrashLoopPartition(t *testing.T) {
+	lws := wrappers.BuildLeaderWorkerSet("default").Replica(4).Obj()
+	leaderSts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sample",
+			Namespace: "default",
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: ptr.To[int32](4),
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+				Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: ptr.To[int32](3)},
+			},
+		},
+	}
+	// The group 3 was updated to the new revision and its leader keeps crashing.
+	updatedPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sample-3",
+			Namespace: "default",
+			Labels: map[string]string{
+				leaderworkerset.SetNameLabelKey: "test-sample",
+				leaderworkerset.RevisionKey:     "new-revision",
+			},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "leader",
+				RestartCount: 5,
+				LastTerminationState: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{FinishedAt: metav1.Now()},
+				},
+			}},
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(leaderSts, updatedPod).Build()
+	r := NewLeaderWorkerSetReconciler(c, nil, record.NewFakeRecorder(10), &configapi.Configuration{
+		CrashLoopDetection: &configapi.CrashLoopDetection{
+			RestartThreshold: ptr.To[int32](3),
+			Window:           &metav1.Duration{Duration: 10 * time.Minute},
+		},
+	})
+
+	// The rolling update would move on to the group 2, it is paused instead.
+	partition, stalled, err := r.crashLoopPartition(context.TODO(), r.config.Load(), lws, leaderSts, "new-revision", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if partition != 3 {
+		t.Errorf("Expected the partition to stay at 3, got %d", partition)
+	}
+	if want := "container leader of pod test-sample-3 restarted at least 3 times within 10m0s"; stalled != want {
+		t.Errorf("Expected the rollout to be stalled with %q, got %q", want, stalled)
+	}
+
+	// The group recovers, the last restart is out of the window.
+	updatedPod.Status.ContainerStatuses[0].LastTerminationState.Terminated.FinishedAt = metav1.NewTime(time.Now().Add(-time.Hour))
+	if err := c.Status().Update(context.TODO(), updatedPod); err != nil {
+		t.Fatal(err)
+	}
+	partition, stalled, err = r.crashLoopPartition(context.TODO(), r.config.Load(), lws, leaderSts, "new-revision", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if partition != 2 || stalled != "" {
+		t.Errorf("Expected the rollout to resume at partition 2, got %d, stalled: %q", partition, stalled)
+	}
+}
+
+func TestCrashLoopPartitionGroupRecreations(t *testing.T) {
+	t.Cleanup(func() { revisionRecreations = &groupRevisionRecreations{} })
+	cfg := &configapi.Configuration{
+		CrashLoopDetection: &configapi.CrashLoopDetection{
+			RestartThreshold: ptr.To[int32](3),
+			Window:           &metav1.Duration{Duration: 10 * time.Minute},
+		},
+	}
+	// The default restart policy recreates the group on the first container restart.
+	lws := wrappers.BuildLeaderWorkerSet("default").Replica(4).Obj()
+	if lws.Spec.LeaderWorkerTemplate.RestartPolicy != leaderworkerset.RecreateGroupOnPodRestart {
+		t.Fatalf("Expected the default restart policy, got %s", lws.Spec.LeaderWorkerTemplate.RestartPolicy)
+	}
+	leaderSts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sample",
+			Namespace: "default",
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: ptr.To[int32](4),
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+				Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: ptr.To[int32](3)},
+			},
+		},
+	}
+	// The leader pod of the group 3, updated to the new revision, whose container restarted once.
+	restartedLeader := func() *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-sample-3",
+				Namespace: "default",
+				Labels: map[string]string{
+					leaderworkerset.SetNameLabelKey:     "test-sample",
+					leaderworkerset.WorkerIndexLabelKey: "0",
+					leaderworkerset.GroupIndexLabelKey:  "3",
+					leaderworkerset.RevisionKey:         "new-revision",
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:         "leader",
+					RestartCount: 1,
+					LastTerminationState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{FinishedAt: metav1.Now()},
+					},
+				}},
+			},
+		}
+	}
+	c := fake.NewClientBuilder().WithObjects(leaderSts).Build()
+	podReconciler := NewPodReconciler(c, nil, record.NewFakeRecorder(10), cfg)
+	r := NewLeaderWorkerSetReconciler(c, nil, record.NewFakeRecorder(10), cfg)
+
+	for i := range 3 {
+		// The group is recreated with its leader pod, none of its containers accumulates restarts.
+		leader := restartedLeader()
+		if err := c.Create(context.TODO(), leader); err != nil {
+			t.Fatal(err)
+		}
+		partition, stalled, err := r.crashLoopPartition(context.TODO(), r.config.Load(), lws, leaderSts, "new-revision", 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if partition != 2 || stalled != "" {
+			t.Fatalf("Expected the rollout to progress after %d recreations, got partition %d, stalled: %q", i, partition, stalled)
+		}
+		deleted, _, err := podReconciler.handleRestartPolicy(context.TODO(), podReconciler.config.Load(), *leader, *lws)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !deleted {
+			t.Fatalf("Expected the group to be recreated")
+		}
+	}
+
+	if err := c.Create(context.TODO(), restartedLeader()); err != nil {
+		t.Fatal(err)
+	}
+	partition, stalled, err := r.crashLoopPartition(context.TODO(), r.config.Load(), lws, leaderSts, "new-revision", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if partition != 3 {
+		t.Errorf("Expected the partition to stay at 3, got %d", partition)
+	}
+	if want := "group 3 was recreated 3 times within 10m0s"; stalled != want {
+		t.Errorf("Expected the rollout to be stalled with %q, got %q", want, stalled)
+	}
+}
+
+func TestGroupIndexAcrossControllerUpgrade(t *testing.T) {
+	lws := wrappers.BuildLeaderWorkerSet("default").Replica(3).Size(1).Obj()
+	makeLeaderPod := func(groupIndex int, labeled bool) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("test-sample-%d", groupIndex),
+				Namespace: "default",
+				Labels: map[string]string{
+					leaderworkerset.SetNameLabelKey:     "test-sample",
+					leaderworkerset.WorkerIndexLabelKey: "0",
+					leaderworkerset.RevisionKey:         "rev",
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		}
+		if labeled {
+			pod.Labels[leaderworkerset.GroupIndexLabelKey] = strconv.Itoa(groupIndex)
+		}
+		return pod
+	}
+	// The groups 1 and 2 were created by a controller version which didn't persist their index.
+	c := fake.NewClientBuilder().WithObjects(makeLeaderPod(0, true), makeLeaderPod(1, false), makeLeaderPod(2, false)).WithInterceptorFuncs(podApplyAsStrategicMerge).Build()
+	cfg := &configapi.Configuration{}
+	r := NewLeaderWorkerSetReconciler(c, nil, record.NewFakeRecorder(10), cfg)
+
+	wantStates := []replicaState{{ready: true, updated: true}, {ready: true, updated: true}, {ready: true, updated: true}}
+	states, err := r.getReplicaStates(context.TODO(), lws, 3, "rev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(wantStates, states, cmp.AllowUnexported(replicaState{})); diff != "" {
+		t.Errorf("Unexpected replica states before persisting the group indexes (-want +got):\n%s", diff)
+	}
+
+	// The upgraded pod controller persists the group index of the legacy leader pods.
+	podReconciler := NewPodReconciler(c, nil, record.NewFakeRecorder(10), cfg)
+	for i := range 3 {
+		var pod corev1.Pod
+		if err := c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: fmt.Sprintf("test-sample-%d", i)}, &pod); err != nil {
+			t.Fatal(err)
+		}
+		if _, found := pod.Labels[leaderworkerset.GroupIndexLabelKey]; !found {
+			if err := podReconciler.persistGroupIndex(context.TODO(), &pod, lws.Name); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := c.Get(context.TODO(), client.ObjectKeyFromObject(&pod), &pod); err != nil {
+			t.Fatal(err)
+		}
+		if got := pod.Labels[leaderworkerset.GroupIndexLabelKey]; got != strconv.Itoa(i) {
+			t.Errorf("Expected pod %s to keep the group index %d, got %q", pod.Name, i, got)
+		}
+	}
+
+	states, err = r.getReplicaStates(context.TODO(), lws, 3, "rev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(wantStates, states, cmp.AllowUnexported(replicaState{})); diff != "" {
+		t.Errorf("Unexpected replica states after persisting the group indexes (-want +got):\n%s", diff)
+	}
+}
+
+func TestUpdateStatusPrintColumnFields(t *testing.T) {
+	makeLeaderPod := func(groupIndex int, revisionKey string, ready bool) *corev1.Pod {
+		readyStatus := corev1.ConditionFalse
+		if ready {
+			readyStatus = corev1.ConditionTrue
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("test-sample-%d", groupIndex),
+				Namespace: "default",
+				Labels: map[string]string{
+					leaderworkerset.SetNameLabelKey:     "test-sample",
+					leaderworkerset.WorkerIndexLabelKey: "0",
+					leaderworkerset.GroupIndexLabelKey:  strconv.Itoa(groupIndex),
+					leaderworkerset.RevisionKey:         revisionKey,
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: readyStatus}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		replicas   int32
+		leaderPods []client.Object
+		wantStatus map[string]any
+	}{
+		{
+			name:       "no groups",
+			replicas:   0,
+			wantStatus: map[string]any{"replicas": 0, "readyReplicas": 0, "updatedReplicas": 0},
+		},
+		{
+			name:     "groups being created",
+			replicas: 2,
+			leaderPods: []client.Object{
+				makeLeaderPod(0, "rev", false),
+				makeLeaderPod(1, "rev", false),
+			},
+			wantStatus: map[string]any{"replicas": 2, "readyReplicas": 0, "updatedReplicas": 2},
+		},
+		{
+			name:     "rolling update in progress",
+			replicas: 3,
+			leaderPods: []client.Object{
+				makeLeaderPod(0, "old-rev", true),
+				makeLeaderPod(1, "old-rev", true),
+				makeLeaderPod(2, "rev", false),
+			},
+			wantStatus: map[string]any{"replicas": 3, "readyReplicas": 2, "updatedReplicas": 1},
+		},
+		{
+			name:     "all groups ready and updated",
+			replicas: 2,
+			leaderPods: []client.Object{
+				makeLeaderPod(0, "rev", true),
+				makeLeaderPod(1, "rev", true),
+			},
+			wantStatus: map[string]any{"replicas": 2, "readyReplicas": 2, "updatedReplicas": 2},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := wrappers.BuildLeaderWorkerSet("default").Replica(int(tc.replicas)).Size(1).Obj()
+			leaderSts := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-sample",
+					Namespace: "default",
+				},
+				Spec:   appsv1.StatefulSetSpec{Replicas: ptr.To(tc.replicas)},
+				Status: appsv1.StatefulSetStatus{Replicas: tc.replicas},
+			}
+			scheme := runtime.NewScheme()
+			if err := clientgoscheme.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+			if err := leaderworkerset.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(append(tc.leaderPods, lws.DeepCopy(), leaderSts)...).
+				WithStatusSubresource(&leaderworkerset.LeaderWorkerSet{}).
+				Build()
+			r := NewLeaderWorkerSetReconciler(c, nil, record.NewFakeRecorder(10), &configapi.Configuration{})
+
+			if err := c.Get(context.TODO(), client.ObjectKeyFromObject(lws), lws); err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err := r.updateStatus(context.TODO(), lws, "rev", false, ""); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.Get(context.TODO(), client.ObjectKeyFromObject(lws), lws); err != nil {
+				t.Fatal(err)
+			}
+
+			// The printer columns read the serialized status, the zero counts must be reported as well.
+			status, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&lws.Status)
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotStatus := map[string]any{}
+			for field := range tc.wantStatus {
+				value, found := status[field]
+				if !found {
+					t.Errorf("Expected the status field %s to be reported", field)
+					continue
+				}
+				gotStatus[field] = int(value.(int64))
+			}
+			if diff := cmp.Diff(tc.wantStatus, gotStatus); diff != "" {
+				t.Errorf("Unexpected status (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdateStatusScaleToZero(t *testing.T) {
+	makeLeaderPod := func(groupIndex int, ready bool) *corev1.Pod {
+		readyStatus := corev1.ConditionFalse
+		if ready {
+			readyStatus = corev1.ConditionTrue
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("test-sample-%d", groupIndex),
+				Namespace: "default",
+				Labels: map[string]string{
+					leaderworkerset.SetNameLabelKey:     "test-sample",
+					leaderworkerset.WorkerIndexLabelKey: "0",
+					leaderworkerset.GroupIndexLabelKey:  strconv.Itoa(groupIndex),
+					leaderworkerset.RevisionKey:         "rev",
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: readyStatus}},
+			},
+		}
+	}
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	lws := wrappers.BuildLeaderWorkerSet("default").Replica(0).Size(1).Obj()
+	leaderSts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sample",
+			Namespace: "default",
+		},
+		Spec: appsv1.StatefulSetSpec{Replicas: ptr.To[int32](0)},
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(lws.DeepCopy(), leaderSts, makeLeaderPod(0, true), makeLeaderPod(1, true)).
+		WithStatusSubresource(&leaderworkerset.LeaderWorkerSet{}).
+		Build()
+	r := NewLeaderWorkerSetReconciler(c, nil, record.NewFakeRecorder(10), &configapi.Configuration{})
+
+	// updateStatus refreshes the lws and checks the condition set on the stored object.
+	updateStatus := func(wantType leaderworkerset.LeaderWorkerSetConditionType, wantReason, wantMessage string) {
+		t.Helper()
+		if err := c.Get(context.TODO(), client.ObjectKeyFromObject(lws), lws); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := r.updateStatus(context.TODO(), lws, "rev", false, ""); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Get(context.TODO(), client.ObjectKeyFromObject(lws), lws); err != nil {
+			t.Fatal(err)
+		}
+		condition := apimeta.FindStatusCondition(lws.Status.Conditions, string(wantType))
+		if condition == nil || condition.Status != metav1.ConditionTrue {
+			t.Fatalf("Expected the condition %s to be true, got conditions %v", wantType, lws.Status.Conditions)
+		}
+		if wantReason != "" && condition.Reason != wantReason {
+			t.Errorf("Expected the reason %s for the condition %s, got %s", wantReason, wantType, condition.Reason)
+		}
+		if wantMessage != "" && condition.Message != wantMessage {
+			t.Errorf("Expected the message %q for the condition %s, got %q", wantMessage, wantType, condition.Message)
+		}
+	}
+
+	// The groups are still being deleted.
+	updateStatus(leaderworkerset.LeaderWorkerSetProgressing, "", "Scaling to zero, the groups are being deleted")
+
+	// All the groups are deleted, the lws is available with zero replicas.
+	for _, name := range []string{"test-sample-0", "test-sample-1"} {
+		if err := c.Delete(context.TODO(), &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	updateStatus(leaderworkerset.LeaderWorkerSetAvailable, ScaledToZero, "Scaled to zero, all the groups are deleted")
+	if lws.Status.Replicas != 0 || lws.Status.ReadyReplicas != 0 {
+		t.Errorf("Expected zero replicas and ready replicas, got %d and %d", lws.Status.Replicas, lws.Status.ReadyReplicas)
+	}
+	if progressing := apimeta.FindStatusCondition(lws.Status.Conditions, string(leaderworkerset.LeaderWorkerSetProgressing)); progressing == nil || progressing.Status != metav1.ConditionFalse {
+		t.Errorf("Expected the Progressing condition to be false, got %v", progressing)
+	}
+
+	// Scaling back up creates the groups again.
+	lws.Spec.Replicas = ptr.To[int32](2)
+	if err := c.Update(context.TODO(), lws); err != nil {
+		t.Fatal(err)
+	}
+	for _, pod := range []*corev1.Pod{makeLeaderPod(0, false), makeLeaderPod(1, false)} {
+		if err := c.Create(context.TODO(), pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+	updateStatus(leaderworkerset.LeaderWorkerSetProgressing, "", "")
+
+	// The groups are ready again.
+	for _, name := range []string{"test-sample-0", "test-sample-1"} {
+		pod := &corev1.Pod{}
+		if err := c.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: "default"}, pod); err != nil {
+			t.Fatal(err)
+		}
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+		if err := c.Status().Update(context.TODO(), pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+	updateStatus(leaderworkerset.LeaderWorkerSetAvailable, "AllGroupsReady", "")
+	if lws.Status.ReadyReplicas != 2 {
+		t.Errorf("Expected 2 ready replicas, got %d", lws.Status.ReadyReplicas)
+	}
+}
+
+func TestUpdateStatusCurrentLeaderPods(t *testing.T) {
+	makePod := func(groupIndex int, workerIndex int) *corev1.Pod {
+		name := fmt.Sprintf("test-sample-%d", groupIndex)
+		if workerIndex != 0 {
+			name = fmt.Sprintf("%s-%d", name, workerIndex)
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					leaderworkerset.SetNameLabelKey:     "test-sample",
+					leaderworkerset.WorkerIndexLabelKey: strconv.Itoa(workerIndex),
+					leaderworkerset.GroupIndexLabelKey:  strconv.Itoa(groupIndex),
+					leaderworkerset.RevisionKey:         "rev",
+				},
+			},
+		}
+	}
+	ctx := context.TODO()
+	lws := wrappers.BuildLeaderWorkerSet("default").Replica(11).Size(2).Obj()
+	leaderSts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sample",
+			Namespace: "default",
+		},
+		Spec:   appsv1.StatefulSetSpec{Replicas: ptr.To[int32](11)},
+		Status: appsv1.StatefulSetStatus{Replicas: 11},
+	}
+	restartingLeader := makePod(2, 0)
+	restartingLeader.Finalizers = []string{"example.com/block-deletion"}
+	// The leader pod of group 1 was created before the group index was persisted.
+	unlabeledLeader := makePod(1, 0)
+	delete(unlabeledLeader.Labels, leaderworkerset.GroupIndexLabelKey)
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(lws.DeepCopy(), leaderSts, makePod(10, 0), makePod(10, 1), restartingLeader, unlabeledLeader).
+		WithStatusSubresource(&leaderworkerset.LeaderWorkerSet{}).
+		Build()
+	r := NewLeaderWorkerSetReconciler(c, nil, record.NewFakeRecorder(10), &configapi.Configuration{})
+
+	checkLeaderPods := func(want []string) {
+		t.Helper()
+		if err := c.Get(ctx, client.ObjectKeyFromObject(lws), lws); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := r.updateStatus(ctx, lws, "rev", false, ""); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(lws), lws); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, lws.Status.CurrentLeaderPods); diff != "" {
+			t.Errorf("Unexpected current leader pods (-want +got):\n%s", diff)
+		}
+	}
+
+	// The leader pods are sorted by group index rather than by name.
+	checkLeaderPods([]string{"test-sample-1", "test-sample-2", "test-sample-10"})
+
+	// The leader pod of group 2 is restarted, it's not listed while terminating.
+	if err := c.Delete(ctx, restartingLeader); err != nil {
+		t.Fatal(err)
+	}
+	checkLeaderPods([]string{"test-sample-1", "test-sample-10"})
+
+	// The leader pod is recreated with the same name by the leader statefulset.
+	if err := c.Get(ctx, client.ObjectKeyFromObject(restartingLeader), restartingLeader); err != nil {
+		t.Fatal(err)
+	}
+	restartingLeader.Finalizers = nil
+	if err := c.Update(ctx, restartingLeader); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Create(ctx, makePod(2, 0)); err != nil {
+		t.Fatal(err)
+	}
+	checkLeaderPods([]string{"test-sample-1", "test-sample-2", "test-sample-10"})
+}
+
+func TestUpdateStatusRevisions(t *testing.T) {
+	makeLeaderPod := func(groupIndex int, revisionKey string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("test-sample-%d", groupIndex),
+				Namespace: "default",
+				Labels: map[string]string{
+					leaderworkerset.SetNameLabelKey:     "test-sample",
+					leaderworkerset.WorkerIndexLabelKey: "0",
+					leaderworkerset.GroupIndexLabelKey:  strconv.Itoa(groupIndex),
+					leaderworkerset.RevisionKey:         revisionKey,
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		}
+	}
+	ctx := context.TODO()
+	lws := wrappers.BuildLeaderWorkerSet("default").Replica(2).Size(1).Obj()
+	leaderSts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sample",
+			Namespace: "default",
+		},
+		Spec:   appsv1.StatefulSetSpec{Replicas: ptr.To[int32](2)},
+		Status: appsv1.StatefulSetStatus{Replicas: 2},
+	}
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(lws.DeepCopy(), leaderSts, makeLeaderPod(0, "old-rev"), makeLeaderPod(1, "old-rev")).
+		WithStatusSubresource(&leaderworkerset.LeaderWorkerSet{}).
+		Build()
+	r := NewLeaderWorkerSetReconciler(c, nil, record.NewFakeRecorder(10), &configapi.Configuration{})
+
+	checkRevisions := func(revisionKey, wantCurrent, wantUpdate string) {
+		t.Helper()
+		if err := c.Get(ctx, client.ObjectKeyFromObject(lws), lws); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := r.updateStatus(ctx, lws, revisionKey, false, ""); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(lws), lws); err != nil {
+			t.Fatal(err)
+		}
+		if lws.Status.CurrentRevision != wantCurrent || lws.Status.UpdateRevision != wantUpdate {
+			t.Errorf("Expected current revision %q and update revision %q, got %q and %q", wantCurrent, wantUpdate, lws.Status.CurrentRevision, lws.Status.UpdateRevision)
+		}
+	}
+	updateLeaderPod := func(groupIndex int) {
+		t.Helper()
+		pod := makeLeaderPod(groupIndex, "new-rev")
+		if err := c.Delete(ctx, pod); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Create(ctx, pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// All the groups run the initial revision.
+	checkRevisions("old-rev", "old-rev", "old-rev")
+
+	// The templates are updated, the rollout starts from the last group.
+	checkRevisions("new-rev", "old-rev", "new-rev")
+	updateLeaderPod(1)
+	checkRevisions("new-rev", "old-rev", "new-rev")
+
+	// The revisions converge once all the groups are updated and ready.
+	updateLeaderPod(0)
+	checkRevisions("new-rev", "new-rev", "new-rev")
+}
+
+func TestReapplyIdenticalTemplate(t *testing.T) {
+	ctx := context.TODO()
+	lws := wrappers.BuildLeaderWorkerSet("default").Replica(2).Size(1).Obj()
+	lws.Generation = 1
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(lws.DeepCopy()).
+		WithStatusSubresource(&leaderworkerset.LeaderWorkerSet{}).
+		Build()
+	r := NewLeaderWorkerSetReconciler(c, nil, record.NewFakeRecorder(10), &configapi.Configuration{})
+
+	revision, err := r.getOrCreateRevisionIfNonExist(ctx, nil, lws, record.NewFakeRecorder(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	revisionKey := revisionutils.GetRevisionKey(revision)
+	leaderSts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sample",
+			Namespace: "default",
+			Labels:    map[string]string{leaderworkerset.RevisionKey: revisionKey},
+		},
+		Spec:   appsv1.StatefulSetSpec{Replicas: ptr.To[int32](2)},
+		Status: appsv1.StatefulSetStatus{Replicas: 2},
+	}
+	if err := c.Create(ctx, leaderSts); err != nil {
+		t.Fatal(err)
+	}
+	for i := range 2 {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("test-sample-%d", i),
+				Namespace: "default",
+				Labels: map[string]string{
+					leaderworkerset.SetNameLabelKey:     "test-sample",
+					leaderworkerset.WorkerIndexLabelKey: "0",
+					leaderworkerset.GroupIndexLabelKey:  strconv.Itoa(i),
+					leaderworkerset.RevisionKey:         revisionKey,
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		}
+		if err := c.Create(ctx, pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var podsBefore corev1.PodList
+	if err := c.List(ctx, &podsBefore); err != nil {
+		t.Fatal(err)
+	}
+
+	// Re-applying the same spec may still bump the generation, e.g. when a GitOps tool rewrites the object.
+	if err := c.Get(ctx, client.ObjectKeyFromObject(lws), lws); err != nil {
+		t.Fatal(err)
+	}
+	lws.Generation = 2
+	stsRevision, err := r.getOrCreateRevisionIfNonExist(ctx, leaderSts, lws, record.NewFakeRecorder(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if revisionutils.GetRevisionKey(stsRevision) != revisionKey {
+		t.Errorf("Expected the existing revision %q to be reused, got %q", revisionKey, revisionutils.GetRevisionKey(stsRevision))
+	}
+	updatedRevision, err := r.getUpdatedRevision(ctx, leaderSts, lws, stsRevision)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updatedRevision != nil {
+		t.Errorf("Expected no update for an identical template, got revision %q", revisionutils.GetRevisionKey(updatedRevision))
+	}
+
+	if _, _, err := r.updateStatus(ctx, lws, revisionKey, false, ""); err != nil {
+		t.Fatal(err)
+	}
+	var got leaderworkerset.LeaderWorkerSet
+	if err := c.Get(ctx, client.ObjectKeyFromObject(lws), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.ObservedGeneration != 2 {
+		t.Errorf("Expected observedGeneration 2, got %d", got.Status.ObservedGeneration)
+	}
+	if got.Status.UpdateRevision != revisionKey || got.Status.CurrentRevision != revisionKey {
+		t.Errorf("Expected the revisions to stay %q, got current %q and update %q", revisionKey, got.Status.CurrentRevision, got.Status.UpdateRevision)
+	}
+	var podsAfter corev1.PodList
+	if err := c.List(ctx, &podsAfter); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(podsBefore.Items, podsAfter.Items); diff != "" {
+		t.Errorf("Expected the pods to be untouched (-before +after):\n%s", diff)
+	}
+}
+
+func TestLastReconcileTime(t *testing.T) {
+	ctx := context.TODO()
+	lws := wrappers.BuildLeaderWorkerSet("default").Replica(1).Size(1).Obj()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	leaderSts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sample", Namespace: "default"},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: ptr.To[int32](1),
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+				RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: ptr.To[int32](0)},
+			},
+		},
+		Status: appsv1.StatefulSetStatus{Replicas: 1},
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(lws.DeepCopy(), leaderSts).
+		WithStatusSubresource(&leaderworkerset.LeaderWorkerSet{}).
+		// The fake client doesn't support apply patches, the leader statefulset already exists.
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				if patch.Type() == types.ApplyPatchType {
+					return nil
+				}
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+		}).
+		Build()
+	r := NewLeaderWorkerSetReconciler(c, scheme, record.NewFakeRecorder(100), &configapi.Configuration{})
+	key := client.ObjectKeyFromObject(lws)
+	t.Cleanup(func() { metrics.LastReconciles.Forget(key) })
+
+	var result reconcile.Result
+	reconcileLws := func() *leaderworkerset.LeaderWorkerSet {
+		t.Helper()
+		var err error
+		if result, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key}); err != nil {
+			t.Fatal(err)
+		}
+		var got leaderworkerset.LeaderWorkerSet
+		if err := c.Get(ctx, key, &got); err != nil {
+			t.Fatal(err)
+		}
+		return &got
+	}
+
+	before := time.Now()
+	got := reconcileLws()
+	if last, found := metrics.LastReconciles.Get(key); !found || last.Before(before) {
+		t.Fatalf("Expected the last reconcile time to be recorded, got %v", last)
+	}
+	// Settle the status changes following the creation of the leader statefulset.
+	for range 3 {
+		got = reconcileLws()
+	}
+	// An idle lws is still reconciled periodically, so that the time since its last reconcile stays bounded.
+	if result.RequeueAfter == 0 || result.RequeueAfter > idleRequeuePeriod {
+		t.Errorf("Expected the idle lws to be requeued within %v, got %v", idleRequeuePeriod, result.RequeueAfter)
+	}
+
+	// A no-op reconcile advances the last reconcile time kept in memory without writing the status,
+	// as the write would trigger another reconcile.
+	last, found := metrics.LastReconciles.Get(key)
+	if !found {
+		t.Fatal("Expected the last reconcile time to be recorded")
+	}
+	resourceVersion := got.ResourceVersion
+	time.Sleep(10 * time.Millisecond)
+	if got = reconcileLws(); got.ResourceVersion != resourceVersion {
+		t.Errorf("Expected no status write on a no-op reconcile, resource version changed from %s to %s", resourceVersion, got.ResourceVersion)
+	}
+	if advanced, _ := metrics.LastReconciles.Get(key); !advanced.After(last) {
+		t.Errorf("Expected the last reconcile time to advance from %v, got %v", last, advanced)
+	}
+
+	// The time of a deleted lws is forgotten.
+	if err := c.Delete(ctx, got); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key}); err != nil {
+		t.Fatal(err)
+	}
+	if _, found := metrics.LastReconciles.Get(key); found {
+		t.Error("Expected the last reconcile time of the deleted lws to be forgotten")
+	}
+}
+
+func TestPersistentVolumeClaimEventsRequeueLeaderWorkerSet(t *testing.T) {
+	makeClaim := func(phase corev1.PersistentVolumeClaimPhase, labels map[string]string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "data-test-sample-0",
+				Namespace: "default",
+				Labels:    labels,
+			},
+			Status: corev1.PersistentVolumeClaimStatus{Phase: phase},
+		}
+	}
+	groupLabels := map[string]string{leaderworkerset.SetNameLabelKey: "test-sample"}
+
+	tests := []struct {
+		name         string
+		oldClaim     *corev1.PersistentVolumeClaim
+		newClaim     *corev1.PersistentVolumeClaim
+		wantRequests []reconcile.Request
+	}{
+		{
+			name:     "claim bound",
+			oldClaim: makeClaim(corev1.ClaimPending, groupLabels),
+			newClaim: makeClaim(corev1.ClaimBound, groupLabels),
+			wantRequests: []reconcile.Request{
+				{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-sample"}},
+			},
+		},
+		{
+			name:     "claim lost",
+			oldClaim: makeClaim(corev1.ClaimBound, groupLabels),
+			newClaim: makeClaim(corev1.ClaimLost, groupLabels),
+			wantRequests: []reconcile.Request{
+				{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-sample"}},
+			},
+		},
+		{
+			name:     "phase unchanged",
+			oldClaim: makeClaim(corev1.ClaimBound, groupLabels),
+			newClaim: makeClaim(corev1.ClaimBound, map[string]string{leaderworkerset.SetNameLabelKey: "test-sample", "updated": "true"}),
+		},
+		{
+			name:     "claim of another workload",
+			oldClaim: makeClaim(corev1.ClaimPending, nil),
+			newClaim: makeClaim(corev1.ClaimBound, nil),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			updateEvent := event.UpdateEvent{ObjectOld: tc.oldClaim, ObjectNew: tc.newClaim}
+			var gotRequests []reconcile.Request
+			if persistentVolumeClaimPhaseChanged.Update(updateEvent) {
+				queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+				defer queue.ShutDown()
+				handler.EnqueueRequestsFromMapFunc(lwsRequestsForObject).Update(context.TODO(), updateEvent, queue)
+				for queue.Len() > 0 {
+					request, _ := queue.Get()
+					gotRequests = append(gotRequests, request)
+					queue.Done(request)
+				}
+			}
+			if diff := cmp.Diff(tc.wantRequests, gotRequests); diff != "" {
+				t.Errorf("Unexpected requests (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReconcileHeadlessServicesRecommendedLabels(t *testing.T) {
+	lws := wrappers.BuildLeaderWorkerSet("default").Obj()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	cfg := &configapi.Configuration{
+		RecommendedLabels: &configapi.RecommendedLabels{
+			ManagedBy: ptr.To("platform-operator"),
+			PartOf:    ptr.To("inference"),
+		},
+	}
+	r := NewLeaderWorkerSetReconciler(c, scheme, record.NewFakeRecorder(10), cfg)
+	if err := r.reconcileHeadlessServices(context.TODO(), r.config.Load(), lws); err != nil {
+		t.Fatalf("failed with error: %s", err.Error())
+	}
+
+	var service corev1.Service
+	if err := c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "test-sample"}, &service); err != nil {
+		t.Fatal(err)
+	}
+	wantLabels := map[string]string{
+		leaderworkerset.SetNameLabelKey: "test-sample",
+		"app.kubernetes.io/managed-by":  "platform-operator",
+		"app.kubernetes.io/part-of":     "inference",
+	}
+	if diff := cmp.Diff(wantLabels, service.Labels); diff != "" {
+		t.Errorf("unexpected service labels (-want +got):\n%s", diff)
+	}
+}
+
+func TestReconcileHeadlessServicesDefaultAnnotations(t *testing.T) {
+	lws := wrappers.BuildLeaderWorkerSet("default").Obj()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	cfg := &configapi.Configuration{
+		DefaultAnnotations: map[string]string{"sidecar.istio.io/inject": "false"},
+	}
+	r := NewLeaderWorkerSetReconciler(c, scheme, record.NewFakeRecorder(10), cfg)
+	if err := r.reconcileHeadlessServices(context.TODO(), r.config.Load(), lws); err != nil {
+		t.Fatalf("failed with error: %s", err.Error())
+	}
+
+	var service corev1.Service
+	if err := c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "test-sample"}, &service); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(cfg.DefaultAnnotations, service.Annotations); diff != "" {
+		t.Errorf("unexpected service annotations (-want +got):\n%s", diff)
+	}
+}
+
+func TestReconcileHeadlessServicesCustomNamer(t *testing.T) {
+	lws := wrappers.BuildLeaderWorkerSet("default").Obj()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	r := NewLeaderWorkerSetReconciler(c, scheme, record.NewFakeRecorder(10), &configapi.Configuration{})
+	r.namer = suffixNamer{}
+	if err := r.reconcileHeadlessServices(context.TODO(), r.config.Load(), lws); err != nil {
+		t.Fatalf("failed with error: %s", err.Error())
+	}
+
+	var service corev1.Service
+	if err := c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "test-sample-svc"}, &service); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecreateParameters(t *testing.T) {
+	lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").
+		Replica(2).
+		RolloutStrategy(leaderworkerset.RolloutStrategy{Type: leaderworkerset.RecreateStrategyType}).Obj()
+	leaderSts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sample",
+			Namespace: "default",
+			Labels: map[string]string{
+				leaderworkerset.SetNameLabelKey: "test-sample",
+				leaderworkerset.RevisionKey:     "new",
+			},
+		},
+		Spec: appsv1.StatefulSetSpec{Replicas: ptr.To[int32](2)},
+	}
+	leaderPod := func(idx int, revision string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("test-sample-%d", idx),
+				Namespace: "default",
+				Labels: map[string]string{
+					leaderworkerset.SetNameLabelKey:     "test-sample",
+					leaderworkerset.WorkerIndexLabelKey: "0",
+					leaderworkerset.GroupIndexLabelKey:  strconv.Itoa(idx),
+					leaderworkerset.RevisionKey:         revision,
+				},
+			},
+		}
+	}
+	workerSts := func(idx int, revision string) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("test-sample-%d", idx),
+				Namespace: "default",
+				Labels: map[string]string{
+					leaderworkerset.SetNameLabelKey:    "test-sample",
+					leaderworkerset.GroupIndexLabelKey: strconv.Itoa(idx),
+					leaderworkerset.RevisionKey:        revision,
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		sts           *appsv1.StatefulSet
+		objects       []client.Object
+		wantPartition int32
+		wantReplicas  int32
+	}{
+		{
+			name:         "leader statefulset not created yet",
+			wantReplicas: 2,
+		},
+		{
+			name:         "all the groups are updated",
+			sts:          leaderSts,
+			objects:      []client.Object{leaderPod(0, "new"), leaderPod(1, "new"), workerSts(0, "new"), workerSts(1, "new")},
+			wantReplicas: 2,
+		},
+		{
+			name:         "outdated leader pods tear down all the groups",
+			sts:          leaderSts,
+			objects:      []client.Object{leaderPod(0, "new"), leaderPod(1, "old"), workerSts(0, "new"), workerSts(1, "old")},
+			wantReplicas: 0,
+		},
+		{
+			name:         "outdated worker statefulsets being deleted keep the groups torn down",
+			sts:          leaderSts,
+			objects:      []client.Object{workerSts(0, "old")},
+			wantReplicas: 0,
+		},
+		{
+			name:         "groups are recreated once the outdated ones are gone",
+			sts:          leaderSts,
+			wantReplicas: 2,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			objects := tc.objects
+			if tc.sts != nil {
+				objects = append(objects, tc.sts.DeepCopy())
+			}
+			r := NewLeaderWorkerSetReconciler(fake.NewClientBuilder().WithObjects(objects...).Build(), nil, record.NewFakeRecorder(10), &configapi.Configuration{})
+			partition, replicas, err := r.recreateParameters(context.TODO(), lws, tc.sts, "new")
+			if err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			if partition != tc.wantPartition {
+				t.Errorf("Expected partition %d, got %d", tc.wantPartition, partition)
+			}
+			if replicas != tc.wantReplicas {
+				t.Errorf("Expected replicas %d, got %d", tc.wantReplicas, replicas)
+			}
+		})
+	}
+}
+
+func TestUpdateMetadataInPlace(t *testing.T) {
+	ctx := context.TODO()
+	tests := []struct {
+		name        string
+		update      func(lws *leaderworkerset.LeaderWorkerSet)
+		wantInPlace bool
+	}{
+		{
+			name: "labels and annotations only",
+			update: func(lws *leaderworkerset.LeaderWorkerSet) {
+				lws.Spec.LeaderWorkerTemplate.LeaderTemplate.Labels = map[string]string{"team": "inference"}
+				lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Labels = map[string]string{"team": "inference"}
+				lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Annotations = map[string]string{"owner": "inference"}
+			},
+			wantInPlace: true,
+		},
+		{
+			name: "image",
+			update: func(lws *leaderworkerset.LeaderWorkerSet) {
+				lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Labels = map[string]string{"team": "inference"}
+				lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec.Containers[0].Image = "nginxinc/nginx-unprivileged:1.28"
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := wrappers.BuildLeaderWorkerSet("default").Obj()
+			lws.Spec.RolloutStrategy.MetadataUpdatePolicy = leaderworkerset.InPlaceMetadataUpdatePolicy
+			lws.Spec.LeaderWorkerTemplate.LeaderTemplate.Labels = map[string]string{"team": "training", "tier": "gold"}
+			lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Labels = map[string]string{"team": "training", "tier": "gold"}
+
+			scheme := runtime.NewScheme()
+			if err := clientgoscheme.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+			if err := leaderworkerset.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).Build()
+			previous, err := revisionutils.NewRevision(ctx, c, lws, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			previousKey := revisionutils.GetRevisionKey(previous)
+			makePod := func(name, workerIndex string) *corev1.Pod {
+				return &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: "default",
+						Labels: map[string]string{
+							leaderworkerset.SetNameLabelKey:     "test-sample",
+							leaderworkerset.WorkerIndexLabelKey: workerIndex,
+							leaderworkerset.RevisionKey:         previousKey,
+							"team":                              "training",
+							"tier":                              "gold",
+						},
+					},
+				}
+			}
+			objects := []client.Object{
+				makePod("test-sample-0", "0"), makePod("test-sample-0-1", "1"),
+				makePod("test-sample-1", "0"), makePod("test-sample-1-1", "1"),
+			}
+			for _, name := range []string{"test-sample-0", "test-sample-1"} {
+				objects = append(objects, &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "default",
+					Labels: map[string]string{
+						leaderworkerset.SetNameLabelKey: "test-sample",
+						leaderworkerset.RevisionKey:     previousKey,
+					},
+				}})
+			}
+			for _, obj := range objects {
+				if err := c.Create(ctx, obj); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			tc.update(lws)
+			updated, err := revisionutils.NewRevision(ctx, c, lws, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			updatedKey := revisionutils.GetRevisionKey(updated)
+			r := NewLeaderWorkerSetReconciler(c, scheme, record.NewFakeRecorder(10), &configapi.Configuration{})
+			inPlace, err := r.updateMetadataInPlace(ctx, lws, previous, updated)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if inPlace != tc.wantInPlace {
+				t.Fatalf("Expected the update to be applied in place to be %t, but was %t", tc.wantInPlace, inPlace)
+			}
+
+			var pods corev1.PodList
+			if err := c.List(ctx, &pods); err != nil {
+				t.Fatal(err)
+			}
+			for _, pod := range pods.Items {
+				wantLabels := map[string]string{
+					leaderworkerset.SetNameLabelKey:     "test-sample",
+					leaderworkerset.WorkerIndexLabelKey: pod.Labels[leaderworkerset.WorkerIndexLabelKey],
+					leaderworkerset.RevisionKey:         previousKey,
+					"team":                              "training",
+					"tier":                              "gold",
+				}
+				var wantAnnotations map[string]string
+				if tc.wantInPlace {
+					wantLabels[leaderworkerset.RevisionKey] = updatedKey
+					wantLabels["team"] = "inference"
+					delete(wantLabels, "tier")
+					if pod.Labels[leaderworkerset.WorkerIndexLabelKey] != "0" {
+						wantAnnotations = map[string]string{"owner": "inference"}
+					}
+				}
+				if diff := cmp.Diff(wantLabels, pod.Labels); diff != "" {
+					t.Errorf("Unexpected labels of pod %s (-want +got):\n%s", pod.Name, diff)
+				}
+				if diff := cmp.Diff(wantAnnotations, pod.Annotations); diff != "" {
+					t.Errorf("Unexpected annotations of pod %s (-want +got):\n%s", pod.Name, diff)
+				}
+			}
+
+			var stsList appsv1.StatefulSetList
+			if err := c.List(ctx, &stsList); err != nil {
+				t.Fatal(err)
+			}
+			wantKey := previousKey
+			if tc.wantInPlace {
+				wantKey = updatedKey
+			}
+			for _, sts := range stsList.Items {
+				if got := revisionutils.GetRevisionKey(&sts); got != wantKey {
+					t.Errorf("Unexpected revision of statefulset %s: %s, want %s", sts.Name, got, wantKey)
+				}
+			}
+		})
+	}
+}
+
+func TestInPlaceUpdatePartition(t *testing.T) {
+	ctx := context.TODO()
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sample", Namespace: "default", UID: "sts-uid", Generation: 2},
+	}
+	leaderPod := func(idx int, revision, hash string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("test-sample-%d", idx),
+				Namespace: "default",
+				Labels: map[string]string{
+					leaderworkerset.SetNameLabelKey:       "test-sample",
+					leaderworkerset.WorkerIndexLabelKey:   "0",
+					leaderworkerset.RevisionKey:           revision,
+					appsv1.ControllerRevisionHashLabelKey: hash,
+					"team":                                "a",
+				},
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(sts, appsv1.SchemeGroupVersion.WithKind("StatefulSet"))},
+			},
+		}
+	}
+	// The revision of the leader statefulset the groups were created with.
+	previousTemplate := func(image string) *appsv1.ControllerRevision {
+		data, err := json.Marshal(map[string]any{"spec": map[string]any{"template": corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{leaderworkerset.RevisionKey: "old", "team": "a"}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "leader", Image: image}}},
+		}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{Name: "sts-old", Namespace: "default"},
+			Data:       runtime.RawExtension{Raw: data},
+		}
+	}
+	otherPod := leaderPod(3, "old", "sts-old")
+	otherPod.OwnerReferences = nil
+	tests := []struct {
+		name          string
+		policy        leaderworkerset.MetadataUpdatePolicyType
+		templateKey   string
+		previousImage string
+		wantPartition int32
+		wantLabels    map[string]string
+	}{
+		{
+			name:          "template not observed yet",
+			policy:        leaderworkerset.InPlaceMetadataUpdatePolicy,
+			templateKey:   "old",
+			previousImage: "nginx:1.27",
+			wantPartition: 2,
+			wantLabels:    map[string]string{leaderworkerset.RevisionKey: "old", "team": "a"},
+		},
+		{
+			name:          "template observed",
+			policy:        leaderworkerset.InPlaceMetadataUpdatePolicy,
+			templateKey:   "new",
+			previousImage: "nginx:1.27",
+			// The leader pod recreated from the previous template is updated in place too.
+			wantPartition: 3,
+			wantLabels:    map[string]string{leaderworkerset.RevisionKey: "new", "team": "b"},
+		},
+		{
+			name:          "previous template with another image",
+			policy:        leaderworkerset.InPlaceMetadataUpdatePolicy,
+			templateKey:   "new",
+			previousImage: "nginx:1.26",
+			wantPartition: 2,
+			wantLabels:    map[string]string{leaderworkerset.RevisionKey: "old", "team": "a"},
+		},
+		{
+			name:          "recreate policy",
+			templateKey:   "new",
+			previousImage: "nginx:1.27",
+			wantPartition: 0,
+			wantLabels:    map[string]string{leaderworkerset.RevisionKey: "old", "team": "a"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := wrappers.BuildLeaderWorkerSet("default").Replica(4).Obj()
+			lws.Spec.RolloutStrategy.MetadataUpdatePolicy = tc.policy
+			sts := sts.DeepCopy()
+			sts.Spec.Template = corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{leaderworkerset.RevisionKey: tc.templateKey, "team": "b"}},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "leader", Image: "nginx:1.27"}}},
+			}
+			sts.Status = appsv1.StatefulSetStatus{ObservedGeneration: 2, UpdateRevision: "sts-" + tc.templateKey}
+			c := fake.NewClientBuilder().WithObjects(
+				previousTemplate(tc.previousImage),
+				// Updated in place.
+				leaderPod(0, "new", "sts-old"),
+				leaderPod(1, "new", "sts-old"),
+				// Recreated by the statefulset from the previous template.
+				leaderPod(2, "old", "sts-old"),
+				// Owned by another controller.
+				otherPod,
+			).Build()
+			r := NewLeaderWorkerSetReconciler(c, c.Scheme(), record.NewFakeRecorder(10), &configapi.Configuration{})
+			partition, err := r.inPlaceUpdatePartition(ctx, lws, sts, "new", 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if partition != tc.wantPartition {
+				t.Errorf("Unexpected partition: %d, want %d", partition, tc.wantPartition)
+			}
+			var pods corev1.PodList
+			if err := c.List(ctx, &pods); err != nil {
+				t.Fatal(err)
+			}
+			for _, pod := range pods.Items {
+				// The controller revision hashes are left to the statefulset controller.
+				if got := pod.Labels[appsv1.ControllerRevisionHashLabelKey]; got != "sts-old" {
+					t.Errorf("Unexpected controller revision hash of pod %s: %s", pod.Name, got)
+				}
+			}
+			var recreated corev1.Pod
+			if err := c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-sample-2"}, &recreated); err != nil {
+				t.Fatal(err)
+			}
+			gotLabels := map[string]string{leaderworkerset.RevisionKey: recreated.Labels[leaderworkerset.RevisionKey], "team": recreated.Labels["team"]}
+			if diff := cmp.Diff(tc.wantLabels, gotLabels); diff != "" {
+				t.Errorf("Unexpected labels of the recreated leader pod (-want +got):\n%s", diff)
+			}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(otherPod), &recreated); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(otherPod.Labels, recreated.Labels); diff != "" {
+				t.Errorf("Unexpected labels of the pod owned by another controller (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStickyGroupsPartition(t *testing.T) {
+	ctx := context.TODO()
+	leaderPod := func(idx int, revision, hash string, owner metav1.OwnerReference) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("test-sample-%d", idx),
+				Namespace: "default",
+				Labels: map[string]string{
+					leaderworkerset.SetNameLabelKey:       "test-sample",
+					leaderworkerset.WorkerIndexLabelKey:   "0",
+					leaderworkerset.GroupIndexLabelKey:    strconv.Itoa(idx),
+					leaderworkerset.RevisionKey:           revision,
+					appsv1.ControllerRevisionHashLabelKey: hash,
+				},
+				OwnerReferences: []metav1.OwnerReference{owner},
+			},
+		}
+	}
+	stsOwner := metav1.OwnerReference{APIVersion: "apps/v1", Kind: "StatefulSet", Name: "test-sample", UID: "sts-uid", Controller: ptr.To(true)}
+	otherOwner := metav1.OwnerReference{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "other", UID: "replicaset-uid", Controller: ptr.To(true)}
+	tests := []struct {
+		name          string
+		strategy      leaderworkerset.RolloutStrategyType
+		stickyGroups  string
+		partition     int32
+		wantPartition int32
+	}{
+		{
+			name:          "outdated sticky group",
+			stickyGroups:  "1",
+			wantPartition: 2,
+		},
+		{
+			name:          "lower outdated sticky group",
+			stickyGroups:  "0",
+			wantPartition: 1,
+		},
+		{
+			name:          "higher partition is kept",
+			stickyGroups:  "0",
+			partition:     2,
+			wantPartition: 2,
+		},
+		{
+			name:          "updated sticky group",
+			stickyGroups:  "2",
+			wantPartition: 0,
+		},
+		{
+			name:          "no sticky groups",
+			wantPartition: 0,
+		},
+		{
+			name:          "pod owned by another controller",
+			stickyGroups:  "3",
+			wantPartition: 0,
+		},
+		{
+			name:          "recreate strategy",
+			strategy:      leaderworkerset.RecreateStrategyType,
+			stickyGroups:  "1",
+			wantPartition: 0,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := wrappers.BuildLeaderWorkerSet("default").Replica(4).Obj()
+			if tc.strategy != "" {
+				lws.Spec.RolloutStrategy.Type = tc.strategy
+			}
+			if tc.stickyGroups != "" {
+				lws.Annotations = map[string]string{leaderworkerset.StickyGroupsAnnotationKey: tc.stickyGroups}
+			}
+			sts := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-sample", Namespace: "default", UID: "sts-uid"},
+				Status:     appsv1.StatefulSetStatus{UpdateRevision: "sts-new"},
+			}
+			c := fake.NewClientBuilder().WithObjects(
+				leaderPod(0, "old", "sts-old", stsOwner),
+				leaderPod(1, "old", "sts-old", stsOwner),
+				leaderPod(2, "new", "sts-new", stsOwner),
+				leaderPod(3, "old", "sts-old", otherOwner),
+			).Build()
+			var podsBefore corev1.PodList
+			if err := c.List(ctx, &podsBefore); err != nil {
+				t.Fatal(err)
+			}
+			r := NewLeaderWorkerSetReconciler(c, c.Scheme(), record.NewFakeRecorder(10), &configapi.Configuration{})
+			partition, err := r.stickyGroupsPartition(ctx, lws, sts, "new", tc.partition)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if partition != tc.wantPartition {
+				t.Errorf("Unexpected partition: %d, want %d", partition, tc.wantPartition)
+			}
+			// The groups are only held by the partition, the pods are untouched.
+			var podsAfter corev1.PodList
+			if err := c.List(ctx, &podsAfter); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(podsBefore.Items, podsAfter.Items); diff != "" {
+				t.Errorf("Expected the pods to be untouched (-before +after):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRollingUpdateParametersStickyGroups(t *testing.T) {
+	ctx := context.TODO()
+	leaderPod := func(idx int, revision string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("test-sample-%d", idx),
+				Namespace: "default",
+				Labels: map[string]string{
+					leaderworkerset.SetNameLabelKey:     "test-sample",
+					leaderworkerset.WorkerIndexLabelKey: "0",
+					leaderworkerset.GroupIndexLabelKey:  strconv.Itoa(idx),
+					leaderworkerset.RevisionKey:         revision,
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		}
+	}
+	tests := []struct {
+		name          string
+		stickyGroups  string
+		wantPartition int32
+	}{
+		{
+			name:          "outdated group holds back the rollout",
+			wantPartition: 1,
+		},
+		{
+			name:          "sticky group is skipped",
+			stickyGroups:  "1",
+			wantPartition: 0,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := wrappers.BuildLeaderWorkerSet("default").Replica(3).Size(1).Obj()
+			if tc.stickyGroups != "" {
+				lws.Annotations = map[string]string{leaderworkerset.StickyGroupsAnnotationKey: tc.stickyGroups}
+			}
+			sts := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-sample",
+					Namespace:   "default",
+					Annotations: map[string]string{leaderworkerset.ReplicasAnnotationKey: "3"},
+				},
+				Spec: appsv1.StatefulSetSpec{
+					Replicas: ptr.To[int32](3),
+					UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+						RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: ptr.To[int32](2)},
+					},
+				},
+			}
+			c := fake.NewClientBuilder().WithObjects(
+				leaderPod(0, "old"),
+				leaderPod(1, "old"),
+				leaderPod(2, "new"),
+			).Build()
+			r := NewLeaderWorkerSetReconciler(c, c.Scheme(), record.NewFakeRecorder(10), &configapi.Configuration{})
+			partition, replicas, err := r.rollingUpdateParameters(ctx, lws, sts, "new", false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if partition != tc.wantPartition || replicas != 3 {
+				t.Errorf("Unexpected (partition, replicas): (%d, %d), want (%d, 3)", partition, replicas, tc.wantPartition)
+			}
+		})
+	}
+}
+
+func TestUpdateStatusHeldReplicas(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	leaderPod := func(idx int, revision string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("test-sample-%d", idx),
+				Namespace: "default",
+				Labels: map[string]string{
+					leaderworkerset.SetNameLabelKey:     "test-sample",
+					leaderworkerset.WorkerIndexLabelKey: "0",
+					leaderworkerset.GroupIndexLabelKey:  strconv.Itoa(idx),
+					leaderworkerset.RevisionKey:         revision,
+				},
+			},
+		}
+	}
+	lws := wrappers.BuildLeaderWorkerSet("default").Replica(3).Size(1).
+		Annotation(map[string]string{leaderworkerset.StickyGroupsAnnotationKey: "0,1"}).Obj()
+	leaderSts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sample", Namespace: "default"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To[int32](3)},
+		Status:     appsv1.StatefulSetStatus{Replicas: 3},
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(lws.DeepCopy(), leaderSts, leaderPod(0, "old"), leaderPod(1, "new"), leaderPod(2, "old")).
+		WithStatusSubresource(&leaderworkerset.LeaderWorkerSet{}).
+		Build()
+	r := NewLeaderWorkerSetReconciler(c, nil, record.NewFakeRecorder(10), &configapi.Configuration{})
+
+	reconcileStatus := func(stickyGroups string) *leaderworkerset.LeaderWorkerSet {
+		t.Helper()
+		var got leaderworkerset.LeaderWorkerSet
+		if err := c.Get(ctx, client.ObjectKeyFromObject(lws), &got); err != nil {
+			t.Fatal(err)
+		}
+		got.Annotations[leaderworkerset.StickyGroupsAnnotationKey] = stickyGroups
+		updateDone, _, err := r.updateStatus(ctx, &got, "new", false, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if updateDone {
+			t.Error("Expected the update to be in progress while groups are outdated")
+		}
+		return &got
+	}
+
+	// The group 1 is updated, only the outdated sticky group 0 is held.
+	if got := reconcileStatus("0,1"); got.Status.HeldReplicas != 1 || got.Status.UpdatedReplicas != 1 {
+		t.Errorf("Unexpected (held, updated) replicas: (%d, %d), want (1, 1)", got.Status.HeldReplicas, got.Status.UpdatedReplicas)
+	}
+	// Once no longer sticky, the group is updated as any other outdated group.
+	if got := reconcileStatus("1"); got.Status.HeldReplicas != 0 {
+		t.Errorf("Unexpected held replicas: %d, want 0", got.Status.HeldReplicas)
+	}
+	// The outdated groups of lower indices are held along with the sticky group 2.
+	if got := reconcileStatus("2"); got.Status.HeldReplicas != 2 {
+		t.Errorf("Unexpected held replicas: %d, want 2", got.Status.HeldReplicas)
+	}
+}
+
+func TestGetReplicaStatesGroupReadinessPolicy(t *testing.T) {
+	leaderPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sample-0",
+			Namespace: "default",
+			Labels: map[string]string{
+				leaderworkerset.SetNameLabelKey:     "test-sample",
+				leaderworkerset.WorkerIndexLabelKey: "0",
+				leaderworkerset.GroupIndexLabelKey:  "0",
+				leaderworkerset.RevisionKey:         "new",
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	// One of the three workers of the group is missing.
+	workerSts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sample-0",
+			Namespace: "default",
+			Labels: map[string]string{
+				leaderworkerset.SetNameLabelKey:    "test-sample",
+				leaderworkerset.GroupIndexLabelKey: "0",
+				leaderworkerset.RevisionKey:        "new",
+			},
+		},
+		Spec: appsv1.StatefulSetSpec{Replicas: ptr.To[int32](3)},
+		Status: appsv1.StatefulSetStatus{
+			Replicas:        2,
+			ReadyReplicas:   2,
+			CurrentRevision: "test-sample-0-1",
+			UpdateRevision:  "test-sample-0-1",
+		},
+	}
+
+	tests := []struct {
+		name           string
+		lws            *leaderworkerset.LeaderWorkerSet
+		leaderNotReady bool
+		wantReady      bool
+	}{
+		{
+			name:      "all the workers are required by default",
+			lws:       wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").Replica(1).Size(4).Obj(),
+			wantReady: false,
+		},
+		{
+			name:      "quorum of ready workers",
+			lws:       wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").Replica(1).Size(4).MinReadyWorkers(2).Obj(),
+			wantReady: true,
+		},
+		{
+			name:      "quorum of ready workers not reached",
+			lws:       wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").Replica(1).Size(4).MinReadyWorkers(3).Obj(),
+			wantReady: false,
+		},
+		{
+			name:           "leader not ready",
+			lws:            wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").Replica(1).Size(4).MinReadyWorkers(2).Obj(),
+			leaderNotReady: true,
+			wantReady:      false,
+		},
+		{
+			name:           "coordination-only leader running but not ready",
+			lws:            wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").Replica(1).Size(4).MinReadyWorkers(2).CoordinationOnlyLeader().Obj(),
+			leaderNotReady: true,
+			wantReady:      true,
+		},
+		{
+			name:           "coordination-only leader with missing workers",
+			lws:            wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").Replica(1).Size(4).CoordinationOnlyLeader().Obj(),
+			leaderNotReady: true,
+			wantReady:      false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := leaderPod.DeepCopy()
+			if tc.leaderNotReady {
+				pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}}
+			}
+			r := NewLeaderWorkerSetReconciler(fake.NewClientBuilder().WithObjects(pod, workerSts.DeepCopy()).Build(), nil, record.NewFakeRecorder(10), &configapi.Configuration{})
+			states, err := r.getReplicaStates(context.TODO(), tc.lws, 1, "new")
+			if err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			want := []replicaState{{ready: tc.wantReady, updated: true}}
+			if diff := cmp.Diff(want, states, cmp.AllowUnexported(replicaState{})); diff != "" {
+				t.Errorf("unexpected replica states (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSummarizeGroupPods(t *testing.T) {
+	makePod := func(groupIndex, workerIndex int, conditions ...corev1.PodCondition) corev1.Pod {
+		name := fmt.Sprintf("test-sample-%d", groupIndex)
+		if workerIndex != 0 {
+			name = fmt.Sprintf("%s-%d", name, workerIndex)
+		}
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					leaderworkerset.SetNameLabelKey:     "test-sample",
+					leaderworkerset.GroupIndexLabelKey:  strconv.Itoa(groupIndex),
+					leaderworkerset.WorkerIndexLabelKey: strconv.Itoa(workerIndex),
+				},
+			},
+			Status: corev1.PodStatus{Conditions: conditions},
+		}
+	}
+	readyConditions := []corev1.PodCondition{
+		{Type: corev1.PodScheduled, Status: corev1.ConditionTrue},
+		{Type: corev1.PodInitialized, Status: corev1.ConditionTrue},
+		{Type: corev1.ContainersReady, Status: corev1.ConditionTrue},
+		{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+	}
+	allTrue := func(pods int32) []leaderworkerset.GroupPodCondition {
+		return []leaderworkerset.GroupPodCondition{
+			{Type: corev1.PodScheduled, TruePods: pods},
+			{Type: corev1.PodInitialized, TruePods: pods},
+			{Type: corev1.ContainersReady, TruePods: pods},
+			{Type: corev1.PodReady, TruePods: pods},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		pods       []corev1.Pod
+		wantGroups []leaderworkerset.GroupStatus
+	}{
+		{
+			name: "no pods",
+		},
+		{
+			name: "groups are sorted by index",
+			pods: []corev1.Pod{
+				makePod(1, 0, readyConditions...),
+				makePod(0, 1, readyConditions...),
+				makePod(0, 0, readyConditions...),
+				makePod(1, 1, readyConditions...),
+			},
+			wantGroups: []leaderworkerset.GroupStatus{
+				{Index: 0, Pods: 2, Conditions: allTrue(2)},
+				{Index: 1, Pods: 2, Conditions: allTrue(2)},
+			},
+		},
+		{
+			name: "unschedulable and crashing workers",
+			pods: []corev1.Pod{
+				makePod(0, 0, readyConditions...),
+				makePod(0, 2, corev1.PodCondition{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: corev1.PodReasonUnschedulable, Message: "0/3 nodes are available"}),
+				makePod(0, 1,
+					corev1.PodCondition{Type: corev1.PodScheduled, Status: corev1.ConditionTrue},
+					corev1.PodCondition{Type: corev1.PodInitialized, Status: corev1.ConditionTrue},
+					corev1.PodCondition{Type: corev1.ContainersReady, Status: corev1.ConditionFalse, Reason: "ContainersNotReady", Message: "containers with unready status: [worker]"},
+					corev1.PodCondition{Type: corev1.PodReady, Status: corev1.ConditionFalse, Reason: "ContainersNotReady", Message: "containers with unready status: [worker]"},
+				),
+			},
+			wantGroups: []leaderworkerset.GroupStatus{
+				{
+					Index: 0,
+					Pods:  3,
+					Conditions: []leaderworkerset.GroupPodCondition{
+						{Type: corev1.PodScheduled, TruePods: 2, Reason: corev1.PodReasonUnschedulable, Message: "test-sample-0-2: 0/3 nodes are available"},
+						{Type: corev1.PodInitialized, TruePods: 2},
+						{Type: corev1.ContainersReady, TruePods: 1, Reason: "ContainersNotReady", Message: "test-sample-0-1: containers with unready status: [worker]"},
+						{Type: corev1.PodReady, TruePods: 1, Reason: "ContainersNotReady", Message: "test-sample-0-1: containers with unready status: [worker]"},
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.wantGroups, summarizeGroupPods(tc.pods)); diff != "" {
+				t.Errorf("unexpected group statuses (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSummarizeGroupPodsBounded(t *testing.T) {
+	var pods []corev1.Pod
+	for i := maxGroupStatuses + 10; i >= 0; i-- {
+		pods = append(pods, corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: fmt.Sprintf("test-sample-%d", i),
+				Labels: map[string]string{
+					leaderworkerset.GroupIndexLabelKey:  strconv.Itoa(i),
+					leaderworkerset.WorkerIndexLabelKey: "0",
+				},
+			},
+		})
+	}
+	groups := summarizeGroupPods(pods)
+	if len(groups) != maxGroupStatuses {
+		t.Fatalf("expected %d groups, got %d", maxGroupStatuses, len(groups))
+	}
+	for i, group := range groups {
+		if group.Index != int32(i) {
+			t.Errorf("expected group %d at position %d, got %d", i, i, group.Index)
+		}
+	}
+}
+
+func TestCountSubGroups(t *testing.T) {
+	makePod := func(groupIndex, workerIndex int, ready bool) corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: fmt.Sprintf("test-sample-%d-%d", groupIndex, workerIndex),
+				Labels: map[string]string{
+					leaderworkerset.GroupIndexLabelKey:  strconv.Itoa(groupIndex),
+					leaderworkerset.WorkerIndexLabelKey: strconv.Itoa(workerIndex),
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+			},
+		}
+	}
+	makePods := func(groups, size int, unready ...[2]int) []corev1.Pod {
+		var pods []corev1.Pod
+		for groupIndex := range groups {
+			for workerIndex := range size {
+				pods = append(pods, makePod(groupIndex, workerIndex, !slices.Contains(unready, [2]int{groupIndex, workerIndex})))
+			}
+		}
+		return pods
+	}
+
+	tests := []struct {
+		name               string
+		lws                *leaderworkerset.LeaderWorkerSet
+		replicas           int
+		pods               []corev1.Pod
+		wantSubGroups      int32
+		wantReadySubGroups int32
+	}{
+		{
+			name:     "no subgroup policy",
+			lws:      wrappers.BuildLeaderWorkerSet("default").Replica(2).Size(4).Obj(),
+			replicas: 2,
+			pods:     makePods(2, 4),
+		},
+		{
+			name:               "all the subgroups are ready",
+			lws:                wrappers.BuildLeaderWorkerSet("default").Replica(2).Size(4).SubGroupSize(2).Obj(),
+			replicas:           2,
+			pods:               makePods(2, 4),
+			wantSubGroups:      4,
+			wantReadySubGroups: 4,
+		},
+		{
+			name:               "unready worker",
+			lws:                wrappers.BuildLeaderWorkerSet("default").Replica(2).Size(4).SubGroupSize(2).Obj(),
+			replicas:           2,
+			pods:               makePods(2, 4, [2]int{1, 3}),
+			wantSubGroups:      4,
+			wantReadySubGroups: 3,
+		},
+		{
+			name:               "unready leader",
+			lws:                wrappers.BuildLeaderWorkerSet("default").Replica(2).Size(4).SubGroupSize(2).Obj(),
+			replicas:           2,
+			pods:               makePods(2, 4, [2]int{0, 0}),
+			wantSubGroups:      4,
+			wantReadySubGroups: 3,
+		},
+		{
+			name:               "missing pods",
+			lws:                wrappers.BuildLeaderWorkerSet("default").Replica(2).Size(4).SubGroupSize(2).Obj(),
+			replicas:           2,
+			pods:               makePods(1, 4),
+			wantSubGroups:      4,
+			wantReadySubGroups: 2,
+		},
+		{
+			name:               "leader as the extra pod of the first subgroup",
+			lws:                wrappers.BuildLeaderWorkerSet("default").Replica(1).Size(5).SubGroupSize(2).Obj(),
+			replicas:           1,
+			pods:               makePods(1, 5, [2]int{0, 0}),
+			wantSubGroups:      2,
+			wantReadySubGroups: 1,
+		},
+		{
+			name: "leader excluded from the subgroups",
+			lws: wrappers.BuildLeaderWorkerSet("default").Replica(1).Size(5).SubGroupSize(2).
+				SubGroupType(leaderworkerset.SubGroupPolicyTypeLeaderExcluded).Obj(),
+			replicas:           1,
+			pods:               makePods(1, 5, [2]int{0, 0}),
+			wantSubGroups:      2,
+			wantReadySubGroups: 2,
+		},
+		{
+			name:               "groups not created yet",
+			lws:                wrappers.BuildLeaderWorkerSet("default").Replica(3).Size(4).SubGroupSize(2).Obj(),
+			replicas:           1,
+			pods:               makePods(2, 4),
+			wantSubGroups:      2,
+			wantReadySubGroups: 2,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			subGroups, readySubGroups := countSubGroups(tc.lws, tc.replicas, tc.pods)
+			if subGroups != tc.wantSubGroups || readySubGroups != tc.wantReadySubGroups {
+				t.Errorf("Expected %d/%d ready subgroups, got %d/%d", tc.wantReadySubGroups, tc.wantSubGroups, readySubGroups, subGroups)
+			}
+		})
+	}
+}
+
+func TestRolloutWave(t *testing.T) {
+	tests := []struct {
+		name      string
+		lws       *leaderworkerset.LeaderWorkerSet
+		wantWaves []int32
+	}{
+		{
+			name:      "one group at a time",
+			lws:       wrappers.BuildLeaderWorkerSet("default").Replica(4).MaxUnavailable(1).MaxSurge(0).Obj(),
+			wantWaves: []int32{3, 2, 1, 0},
+		},
+		{
+			name:      "maxUnavailable and maxSurge",
+			lws:       wrappers.BuildLeaderWorkerSet("default").Replica(4).MaxUnavailable(2).MaxSurge(2).Obj(),
+			wantWaves: []int32{1, 1, 0, 0, 0, 0},
+		},
+		{
+			name:      "maxSurge only",
+			lws:       wrappers.BuildLeaderWorkerSet("default").Replica(3).MaxUnavailable(0).MaxSurge(1).Obj(),
+			wantWaves: []int32{3, 2, 1, 0},
+		},
+		{
+			name: "recreate",
+			lws: wrappers.BuildLeaderWorkerSet("default").Replica(3).RolloutStrategy(leaderworkerset.RolloutStrategy{
+				Type: leaderworkerset.RecreateStrategyType,
+			}).Obj(),
+			wantWaves: []int32{0, 0, 0},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var waves []int32
+			for groupIndex := range tc.wantWaves {
+				wave, err := rolloutWave(tc.lws, int32(groupIndex))
+				if err != nil {
+					t.Fatal(err)
+				}
+				waves = append(waves, wave)
+			}
+			if diff := cmp.Diff(tc.wantWaves, waves); diff != "" {
+				t.Errorf("Unexpected rollout waves (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLabelRolloutWaves(t *testing.T) {
+	const waveLabel = "example.com/rollout-wave"
+	makePod := func(groupIndex int, workerIndex int) *corev1.Pod {
+		name := fmt.Sprintf("test-sample-%d", groupIndex)
+		if workerIndex != 0 {
+			name = fmt.Sprintf("%s-%d", name, workerIndex)
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					leaderworkerset.SetNameLabelKey:     "test-sample",
+					leaderworkerset.WorkerIndexLabelKey: strconv.Itoa(workerIndex),
+					leaderworkerset.GroupIndexLabelKey:  strconv.Itoa(groupIndex),
+				},
+			},
+		}
+	}
+	ctx := context.TODO()
+	// The surge group 4 was created by the partitioned rollout, 2 groups are updated at a time.
+	lws := wrappers.BuildLeaderWorkerSet("default").Replica(4).Size(2).MaxUnavailable(1).MaxSurge(1).Obj()
+	var objects []client.Object
+	for groupIndex := range 5 {
+		objects = append(objects, makePod(groupIndex, 0), makePod(groupIndex, 1))
+	}
+	// The label of a recreated pod is stale until it's reconciled.
+	objects[0].SetLabels(map[string]string{
+		leaderworkerset.SetNameLabelKey:     "test-sample",
+		leaderworkerset.WorkerIndexLabelKey: "0",
+		leaderworkerset.GroupIndexLabelKey:  "0",
+		waveLabel:                           "0",
+	})
+	unrelated := makePod(0, 0)
+	unrelated.Name = "unrelated-0"
+	unrelated.Labels[leaderworkerset.SetNameLabelKey] = "unrelated"
+	objects = append(objects, unrelated)
+	c := fake.NewClientBuilder().WithObjects(objects...).Build()
+	r := NewLeaderWorkerSetReconciler(c, nil, record.NewFakeRecorder(10), &configapi.Configuration{RolloutWaveLabel: waveLabel})
+
+	checkWaves := func(want map[string]string) {
+		t.Helper()
+		if err := r.labelRolloutWaves(ctx, r.config.Load(), lws); err != nil {
+			t.Fatal(err)
+		}
+		var pods corev1.PodList
+		if err := c.List(ctx, &pods); err != nil {
+			t.Fatal(err)
+		}
+		got := map[string]string{}
+		for _, pod := range pods.Items {
+			if wave, found := pod.Labels[waveLabel]; found {
+				got[pod.Name] = wave
+			}
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Unexpected rollout wave labels (-want +got):\n%s", diff)
+		}
+	}
+
+	checkWaves(map[string]string{
+		"test-sample-0": "2", "test-sample-0-1": "2",
+		"test-sample-1": "1", "test-sample-1-1": "1",
+		"test-sample-2": "1", "test-sample-2-1": "1",
+		"test-sample-3": "0", "test-sample-3-1": "0",
+		"test-sample-4": "0", "test-sample-4-1": "0",
+	})
+
+	// Widening the rolling step during the rollout relabels the pods.
+	lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxUnavailable = intstr.FromInt32(3)
+	checkWaves(map[string]string{
+		"test-sample-0": "1", "test-sample-0-1": "1",
+		"test-sample-1": "0", "test-sample-1-1": "0",
+		"test-sample-2": "0", "test-sample-2-1": "0",
+		"test-sample-3": "0", "test-sample-3-1": "0",
+		"test-sample-4": "0", "test-sample-4-1": "0",
+	})
+}
+
+func TestLeaderStatefulSetPodInjectionAnnotation(t *testing.T) {
+	for _, disabled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("injection disabled %v", disabled), func(t *testing.T) {
+			lws := wrappers.BuildLeaderWorkerSet("default").Obj()
+			if disabled {
+				lws.Annotations = map[string]string{leaderworkerset.DisablePodInjectionAnnotationKey: "True"}
+			}
+			sts, err := constructLeaderStatefulSetApplyConfiguration(lws, 0, 1, "rev", naming.Default())
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, found := sts.Spec.Template.Annotations[leaderworkerset.DisablePodInjectionAnnotationKey]
+			if found != disabled || (found && got != "true") {
+				t.Errorf("Unexpected %s pod annotation %q, found: %v", leaderworkerset.DisablePodInjectionAnnotationKey, got, found)
+			}
+		})
+	}
+}
+
+func TestLeaderStatefulSetInjectedContainerResourcesAnnotation(t *testing.T) {
+	for _, resources := range []string{"", `{"requests":{"cpu":"50m"}}`} {
+		t.Run(fmt.Sprintf("resources %q", resources), func(t *testing.T) {
+			lws := wrappers.BuildLeaderWorkerSet("default").Obj()
+			if resources != "" {
+				lws.Annotations = map[string]string{leaderworkerset.InjectedContainerResourcesAnnotationKey: resources}
+			}
+			sts, err := constructLeaderStatefulSetApplyConfiguration(lws, 0, 1, "rev", naming.Default())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sts.Spec.Template.Annotations[leaderworkerset.InjectedContainerResourcesAnnotationKey]; got != resources {
+				t.Errorf("Unexpected %s pod annotation %q, want %q", leaderworkerset.InjectedContainerResourcesAnnotationKey, got, resources)
+			}
+		})
+	}
+}
+
+func TestLeaderStatefulSetQueueNameAnnotation(t *testing.T) {
+	for _, queueName := range []string{"", "team-a"} {
+		t.Run(fmt.Sprintf("queue %q", queueName), func(t *testing.T) {
+			lws := wrappers.BuildLeaderWorkerSet("default").Obj()
+			if queueName != "" {
+				lws.Annotations = map[string]string{leaderworkerset.QueueNameAnnotationKey: queueName}
+			}
+			sts, err := constructLeaderStatefulSetApplyConfiguration(lws, 0, 1, "rev", naming.Default())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sts.Spec.Template.Annotations[leaderworkerset.QueueNameAnnotationKey]; got != queueName {
+				t.Errorf("Unexpected %s pod annotation %q, want %q", leaderworkerset.QueueNameAnnotationKey, got, queueName)
+			}
+		})
+	}
+}
+
+func TestLeaderStatefulSetWorkloadIdentity(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("workload identity %v", enabled), func(t *testing.T) {
+			lws := wrappers.BuildLeaderWorkerSet("default").Obj()
+			lws.UID = "6f1a2b3c-uid"
+			if enabled {
+				lws.Annotations = map[string]string{leaderworkerset.InjectWorkloadIdentityAnnotationKey: "true"}
+			}
+			sts, err := constructLeaderStatefulSetApplyConfiguration(lws, 0, 1, "rev", naming.Default())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The pod webhook injects the identity carried by the pods created from the template.
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-sample-0",
+					Namespace:   lws.Namespace,
+					Labels:      sts.Spec.Template.Labels,
+					Annotations: sts.Spec.Template.Annotations,
+				},
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "leader"}}},
+			}
+			podutils.AddWorkloadIdentityVariables(pod)
+			var want []corev1.EnvVar
+			if enabled {
+				want = []corev1.EnvVar{
+					{Name: leaderworkerset.LwsUID, Value: string(lws.UID)},
+					{Name: leaderworkerset.LwsName, Value: lws.Name},
+					{Name: leaderworkerset.LwsNamespace, Value: lws.Namespace},
+				}
+			}
+			if diff := cmp.Diff(want, pod.Spec.Containers[0].Env); diff != "" {
+				t.Errorf("Unexpected env (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLeaderStatefulSetGroupReadinessGate(t *testing.T) {
+	for _, gate := range []bool{false, true} {
+		t.Run(fmt.Sprintf("readiness gate %v", gate), func(t *testing.T) {
+			lws := wrappers.BuildLeaderWorkerSet("default").Obj()
+			if gate {
+				lws.Annotations = map[string]string{leaderworkerset.GroupReadinessGateAnnotationKey: "true"}
+			}
+			sts, err := constructLeaderStatefulSetApplyConfiguration(lws, 0, 1, "rev", naming.Default())
+			if err != nil {
+				t.Fatal(err)
+			}
+			var want []coreapplyv1.PodReadinessGateApplyConfiguration
+			if gate {
+				want = []coreapplyv1.PodReadinessGateApplyConfiguration{*coreapplyv1.PodReadinessGate().WithConditionType(leaderworkerset.GroupReadyPodConditionType)}
+			}
+			if diff := cmp.Diff(want, sts.Spec.Template.Spec.ReadinessGates); diff != "" {
+				t.Errorf("Unexpected readiness gates (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAdoptOrphanPods(t *testing.T) {
+	ctx := context.TODO()
+	lws := wrappers.BuildLeaderWorkerSet("default").Replica(2).Size(2).Obj()
+	lws.UID = "lws-uid"
+	leaderSts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "test-sample",
+			Namespace:       "default",
+			UID:             "leader-sts-uid",
+			Labels:          map[string]string{leaderworkerset.SetNameLabelKey: "test-sample"},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(lws, leaderworkerset.GroupVersion.WithKind("LeaderWorkerSet"))},
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: ptr.To[int32](2),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{
+				leaderworkerset.SetNameLabelKey:     "test-sample",
+				leaderworkerset.WorkerIndexLabelKey: "0",
+			}},
+		},
+	}
+	makePod := func(name string, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    labels,
+			},
+		}
+	}
+	leaderLabels := func(groupIndex string) map[string]string {
+		return map[string]string{
+			leaderworkerset.SetNameLabelKey:     "test-sample",
+			leaderworkerset.WorkerIndexLabelKey: "0",
+			leaderworkerset.GroupIndexLabelKey:  groupIndex,
+		}
+	}
+	workerSts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sample-0",
+			Namespace: "default",
+			UID:       "worker-sts-uid",
+			Labels:    map[string]string{leaderworkerset.SetNameLabelKey: "test-sample"},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(makePod("test-sample-0", nil), corev1.SchemeGroupVersion.WithKind("Pod")),
+			},
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: ptr.To[int32](1),
+			Ordinals: &appsv1.StatefulSetOrdinals{Start: 1},
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{
+				leaderworkerset.SetNameLabelKey:    "test-sample",
+				leaderworkerset.GroupIndexLabelKey: "0",
+			}},
+		},
+	}
+	replicaSet := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default", UID: "replicaset-uid"}}
+	// Owned by another controller.
+	ownedLeader := makePod("test-sample-1", leaderLabels("1"))
+	ownedLeader.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(replicaSet, appsv1.SchemeGroupVersion.WithKind("ReplicaSet"))}
+	// Labeled like a worker statefulset of the lws, but not controlled by one of its leader pods.
+	foreignSts := workerSts.DeepCopy()
+	foreignSts.Name, foreignSts.UID = "test-sample-9", "foreign-sts-uid"
+	foreignSts.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(replicaSet, appsv1.SchemeGroupVersion.WithKind("ReplicaSet"))}
+	objects := []client.Object{
+		lws, leaderSts, workerSts, foreignSts,
+		makePod("test-sample-9-1", map[string]string{
+			leaderworkerset.SetNameLabelKey:     "test-sample",
+			leaderworkerset.WorkerIndexLabelKey: "1",
+			leaderworkerset.GroupIndexLabelKey:  "0",
+		}),
+		makePod("test-sample-0", leaderLabels("0")),
+		makePod("test-sample-0-1", map[string]string{
+			leaderworkerset.SetNameLabelKey:     "test-sample",
+			leaderworkerset.WorkerIndexLabelKey: "1",
+			leaderworkerset.GroupIndexLabelKey:  "0",
+		}),
+		ownedLeader,
+		// Beyond the replicas of the leader statefulset.
+		makePod("test-sample-2", leaderLabels("2")),
+		// The group index doesn't match the ordinal.
+		makePod("test-sample-3", leaderLabels("0")),
+		// The worker statefulset of the group doesn't exist yet.
+		makePod("test-sample-1-1", map[string]string{
+			leaderworkerset.SetNameLabelKey:     "test-sample",
+			leaderworkerset.WorkerIndexLabelKey: "1",
+			leaderworkerset.GroupIndexLabelKey:  "1",
+		}),
+		// Not matching the selector of the leader statefulset.
+		makePod("test-sample-4", map[string]string{leaderworkerset.SetNameLabelKey: "test-sample"}),
+	}
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	r := NewLeaderWorkerSetReconciler(c, scheme, record.NewFakeRecorder(10), &configapi.Configuration{})
+	if err := r.adoptOrphanPods(ctx, lws); err != nil {
+		t.Fatal(err)
+	}
+
+	var pods corev1.PodList
+	if err := c.List(ctx, &pods); err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]types.UID{}
+	for _, pod := range pods.Items {
+		if owner := metav1.GetControllerOf(&pod); owner != nil {
+			got[pod.Name] = owner.UID
+		}
+	}
+	want := map[string]types.UID{
+		"test-sample-0":   "leader-sts-uid",
+		"test-sample-0-1": "worker-sts-uid",
+		"test-sample-1":   "replicaset-uid",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected pod controllers (-want +got):\n%s", diff)
+	}
+}
+
+func TestDeleteDuplicatePods(t *testing.T) {
+	ctx := context.TODO()
+	lws := wrappers.BuildLeaderWorkerSet("default").Replica(2).Size(2).Obj()
+	makePod := func(name, stsName, groupIndex, workerIndex string) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					leaderworkerset.SetNameLabelKey:     "test-sample",
+					leaderworkerset.GroupIndexLabelKey:  groupIndex,
+					leaderworkerset.WorkerIndexLabelKey: workerIndex,
+				},
+			},
+		}
+		if stsName != "" {
+			sts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: stsName, UID: types.UID(stsName)}}
+			pod.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(sts, appsv1.SchemeGroupVersion.WithKind("StatefulSet"))}
+		}
+		return pod
+	}
+	objects := []client.Object{
+		lws,
+		makePod("test-sample-0", "test-sample", "0", "0"),
+		makePod("test-sample-0-1", "test-sample-0", "0", "1"),
+		makePod("test-sample-1", "test-sample", "1", "0"),
+		makePod("test-sample-1-1", "test-sample-1", "1", "1"),
+		// Leader pod of a removed group relabeled with the group index 1.
+		makePod("test-sample-2", "test-sample", "1", "0"),
+		// Orphan worker pod claiming the worker index 1 of the group 0.
+		makePod("test-sample-0-9", "", "0", "1"),
+		// Duplicates of an index without an owned pod are kept.
+		makePod("test-sample-5", "", "3", "0"),
+		makePod("test-sample-6", "", "3", "0"),
+	}
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	r := NewLeaderWorkerSetReconciler(c, scheme, record.NewFakeRecorder(10), &configapi.Configuration{})
+	if err := r.deleteDuplicatePods(ctx, lws); err != nil {
+		t.Fatal(err)
+	}
+
+	var pods corev1.PodList
+	if err := c.List(ctx, &pods); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, pod := range pods.Items {
+		got = append(got, pod.Name)
+	}
+	want := []string{"test-sample-0", "test-sample-0-1", "test-sample-1", "test-sample-1-1", "test-sample-5", "test-sample-6"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected pods (-want +got):\n%s", diff)
+	}
+
+	// The reconciliation converged, a second pass deletes nothing.
+	if err := r.deleteDuplicatePods(ctx, lws); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.List(ctx, &pods); err != nil {
+		t.Fatal(err)
+	}
+	if len(pods.Items) != len(want) {
+		t.Errorf("Unexpected pods after the second pass: %d, want %d", len(pods.Items), len(want))
+	}
+}
+
+func TestDeleteRemovedWorkerStatefulSets(t *testing.T) {
+	for _, leaderless := range []bool{false, true} {
+		t.Run(fmt.Sprintf("leaderless %v", leaderless), func(t *testing.T) {
+			ctx := context.TODO()
+			lws := wrappers.BuildLeaderWorkerSet("default").Replica(2).Size(2).Obj()
+			lws.UID = "lws-uid"
+			if leaderless {
+				lws.Annotations = map[string]string{leaderworkerset.LeaderlessAnnotationKey: "true"}
+			}
+			leaderSts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{
+				Name:            "test-sample",
+				Namespace:       "default",
+				Labels:          map[string]string{leaderworkerset.SetNameLabelKey: "test-sample"},
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(lws, leaderworkerset.GroupVersion.WithKind("LeaderWorkerSet"))},
+			}}
+			objects := []client.Object{lws, leaderSts}
+			for i := range 3 {
+				objects = append(objects, &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("test-sample-%d", i),
+					Namespace: "default",
+					Labels: map[string]string{
+						leaderworkerset.SetNameLabelKey:    "test-sample",
+						leaderworkerset.GroupIndexLabelKey: strconv.Itoa(i),
+					},
+				}})
+			}
+			scheme := runtime.NewScheme()
+			if err := clientgoscheme.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+			if err := leaderworkerset.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+			r := NewLeaderWorkerSetReconciler(c, scheme, record.NewFakeRecorder(10), &configapi.Configuration{})
+			if err := r.deleteRemovedWorkerStatefulSets(ctx, lws, 2); err != nil {
+				t.Fatal(err)
+			}
+
+			var stsList appsv1.StatefulSetList
+			if err := c.List(ctx, &stsList); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, sts := range stsList.Items {
+				got = append(got, sts.Name)
+			}
+			// The worker statefulsets are garbage collected with the leader pods of the other lws.
+			want := []string{"test-sample", "test-sample-0", "test-sample-1", "test-sample-2"}
+			if leaderless {
+				want = []string{"test-sample", "test-sample-0", "test-sample-1"}
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Unexpected statefulsets (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdateStatusDebounce(t *testing.T) {
+	makeLeaderPod := func(groupIndex int) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("test-sample-%d", groupIndex),
+				Namespace: "default",
+				Labels: map[string]string{
+					leaderworkerset.SetNameLabelKey:     "test-sample",
+					leaderworkerset.WorkerIndexLabelKey: "0",
+					leaderworkerset.GroupIndexLabelKey:  strconv.Itoa(groupIndex),
+					leaderworkerset.RevisionKey:         "rev",
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		debounce   *metav1.Duration
+		wantWrites int
+	}{
+		{
+			name:       "every change written",
+			wantWrites: 4,
+		},
+		{
+			name:     "changes coalesced",
+			debounce: &metav1.Duration{Duration: time.Hour},
+			// The first write, then the Available condition, the ready replicas in between are coalesced.
+			wantWrites: 2,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := wrappers.BuildLeaderWorkerSet("default").Replica(3).Size(1).Obj()
+			leaderSts := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-sample",
+					Namespace: "default",
+				},
+				Spec:   appsv1.StatefulSetSpec{Replicas: ptr.To[int32](3)},
+				Status: appsv1.StatefulSetStatus{Replicas: 3},
+			}
+			scheme := runtime.NewScheme()
+			if err := clientgoscheme.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+			if err := leaderworkerset.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+			pods := []*corev1.Pod{makeLeaderPod(0), makeLeaderPod(1), makeLeaderPod(2)}
+			writes := 0
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(pods[0], pods[1], pods[2], lws.DeepCopy(), leaderSts).
+				WithStatusSubresource(&leaderworkerset.LeaderWorkerSet{}).
+				WithInterceptorFuncs(interceptor.Funcs{
+					SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+						if _, ok := obj.(*leaderworkerset.LeaderWorkerSet); ok {
+							writes++
+						}
+						return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+					},
+				}).
+				Build()
+			r := NewLeaderWorkerSetReconciler(c, nil, record.NewFakeRecorder(10), &configapi.Configuration{StatusUpdateDebounce: tc.debounce})
+
+			// The leaders get ready one by one, each triggering a reconciliation.
+			for i := -1; i < len(pods); i++ {
+				if i >= 0 {
+					pods[i].Status.Conditions[0].Status = corev1.ConditionTrue
+					if err := c.Status().Update(context.TODO(), pods[i]); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if err := c.Get(context.TODO(), client.ObjectKeyFromObject(lws), lws); err != nil {
+					t.Fatal(err)
+				}
+				_, delay, err := r.updateStatus(context.TODO(), lws, "rev", false, "")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if deferred := delay > 0; deferred != (tc.debounce != nil && i >= 0 && i < len(pods)-1) {
+					t.Errorf("Unexpected status write delay %v after %d ready leaders", delay, i+1)
+				}
+			}
+			if writes != tc.wantWrites {
+				t.Errorf("Expected %d status writes, got %d", tc.wantWrites, writes)
+			}
+
+			// The coalesced changes are not lost.
+			if err := c.Get(context.TODO(), client.ObjectKeyFromObject(lws), lws); err != nil {
+				t.Fatal(err)
+			}
+			if lws.Status.ReadyReplicas != 3 {
+				t.Errorf("Expected 3 ready replicas, got %d", lws.Status.ReadyReplicas)
+			}
+			if !apimeta.IsStatusConditionTrue(lws.Status.Conditions, string(leaderworkerset.LeaderWorkerSetAvailable)) {
+				t.Errorf("Expected the Available condition, got %v", lws.Status.Conditions)
+			}
+		})
+	}
+}
+
+func TestLeaderStatefulSetCandidateLabel(t *testing.T) {
+	for _, leaderless := range []bool{false, true} {
+		t.Run(fmt.Sprintf("leaderless %v", leaderless), func(t *testing.T) {
+			lws := wrappers.BuildLeaderWorkerSet("default").Obj()
+			if leaderless {
+				lws.Annotations = map[string]string{leaderworkerset.LeaderlessAnnotationKey: "true"}
+				lws.Spec.LeaderWorkerTemplate.LeaderTemplate = nil
+			}
+			sts, err := constructLeaderStatefulSetApplyConfiguration(lws, 0, 1, "rev", naming.Default())
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, found := sts.Spec.Template.Labels[leaderworkerset.CandidateLabelKey]
+			if found != leaderless || (found && got != "true") {
+				t.Errorf("Unexpected %s pod label %q, found: %v", leaderworkerset.CandidateLabelKey, got, found)
+			}
+		})
+	}
+}
+
+func TestGroupReadyLeaderless(t *testing.T) {
+	tests := []struct {
+		name         string
+		leaderReady  bool
+		noWorkerSts  bool
+		readyWorkers int32
+		wantReady    bool
+	}{
+		{
+			name:         "all the candidates ready",
+			leaderReady:  true,
+			readyWorkers: 2,
+			wantReady:    true,
+		},
+		{
+			name:         "quorum with the leader pod",
+			leaderReady:  true,
+			readyWorkers: 1,
+			wantReady:    true,
+		},
+		{
+			name:         "quorum without the leader pod",
+			readyWorkers: 2,
+			wantReady:    true,
+		},
+		{
+			name:         "quorum not reached",
+			leaderReady:  true,
+			readyWorkers: 0,
+			wantReady:    false,
+		},
+		{
+			name:        "worker statefulset not created yet",
+			leaderReady: true,
+			noWorkerSts: true,
+			wantReady:   false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			leaderPod := corev1.Pod{
+				Status: corev1.PodStatus{
+					Phase:      corev1.PodRunning,
+					Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}},
+				},
+			}
+			if tc.leaderReady {
+				leaderPod.Status.Conditions[0].Status = corev1.ConditionTrue
+			}
+			var workerSts *appsv1.StatefulSet
+			if !tc.noWorkerSts {
+				workerSts = &appsv1.StatefulSet{
+					Spec:   appsv1.StatefulSetSpec{Replicas: ptr.To[int32](2)},
+					Status: appsv1.StatefulSetStatus{Replicas: 2, ReadyReplicas: tc.readyWorkers},
+				}
+			}
+
+			lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").Size(3).Obj()
+			lws.Annotations = map[string]string{leaderworkerset.LeaderlessAnnotationKey: "true"}
+			if got := groupReady(lws, leaderPod, workerSts); got != tc.wantReady {
+				t.Errorf("Expected the leaderless group to be ready: %v, got %v", tc.wantReady, got)
+			}
+		})
+	}
+}
@@ -20,52 +20,296 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	appsapplyv1 "k8s.io/client-go/applyconfigurations/apps/v1"
 	coreapplyv1 "k8s.io/client-go/applyconfigurations/core/v1"
 	metaapplyv1 "k8s.io/client-go/applyconfigurations/meta/v1"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/features"
+	"sigs.k8s.io/lws/pkg/metrics"
+	"sigs.k8s.io/lws/pkg/utils"
 	acceleratorutils "sigs.k8s.io/lws/pkg/utils/accelerators"
 	controllerutils "sigs.k8s.io/lws/pkg/utils/controller"
+	"sigs.k8s.io/lws/pkg/utils/naming"
 	podutils "sigs.k8s.io/lws/pkg/utils/pod"
 	revisionutils "sigs.k8s.io/lws/pkg/utils/revision"
 	statefulsetutils "sigs.k8s.io/lws/pkg/utils/statefulset"
 )
 
+// podControllerFieldManager is the field manager of the pod controller server-side applies, distinct
+// from the lws controller's so that each only owns the fields it sets.
+const podControllerFieldManager = "lws-pod-controller"
+
 // PodReconciler reconciles a LeaderWorkerSet object
 type PodReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
 	Record record.EventRecorder
+
+	// config holds the fields applied again when the configuration is reloaded, loaded once
+	// per reconciliation.
+	config atomic.Pointer[podReconcilerConfig]
+	// recommendedLabels are stamped on the worker statefulsets and the per-group headless services.
+	recommendedLabels map[string]string
+	// reconcileTimeout bounds the duration of each reconciliation, 0 means unbounded.
+	reconcileTimeout time.Duration
+	// drainQueueOnShutdown reconciles the queued requests to completion when the manager stops.
+	drainQueueOnShutdown bool
+	// namer names the headless services.
+	namer naming.Namer
+	// forceDeleteDryRun only counts the pods stuck terminating which would be force deleted,
+	// when the ForceDeleteStuckTerminatingPods feature gate is evaluated in dry-run.
+	forceDeleteDryRun bool
+	// dryRunForceDeletes tracks the pods counted by the dry-run force deletion.
+	dryRunForceDeletes dryRunActions
+	// recreations tracks the recreations of each group for the backoff.
+	recreations groupRecreations
+}
+
+// podReconcilerConfig are the fields of the PodReconciler which can be changed without a restart.
+type podReconcilerConfig struct {
+	// defaultAnnotations are stamped on the per-group headless services.
+	defaultAnnotations map[string]string
+	// leaderPollInterval requeues the groups waiting for the leaders to be ready, 0 means
+	// they only wait for the readiness changes of the leader pods.
+	leaderPollInterval time.Duration
+	// stuckTerminatingPodTimeout is how long the pods may remain terminating past their grace
+	// period before they are force deleted, 0 means never.
+	stuckTerminatingPodTimeout time.Duration
+	// recreationBackoff delays the successive recreations of the groups by the restart policy,
+	// nil means the groups are recreated immediately.
+	recreationBackoff *configapi.RecreationBackoff
+	// crashLoopWindow is how long the recreations of the groups are recorded in revisionRecreations
+	// for the crash loop detection, 0 means the detection is disabled.
+	crashLoopWindow time.Duration
+}
+
+// dryRunActions tracks the objects a dry-run action was counted for, so that the action is only
+// counted once per object across its reconciliations. The objects are keyed by their name, and
+// their UID tells apart an object recreated with the same name.
+type dryRunActions struct {
+	sync.Mutex
+	counted map[types.NamespacedName]types.UID
+}
+
+// count returns whether the action wasn't counted for the object yet, and records it.
+func (d *dryRunActions) count(obj client.Object) bool {
+	d.Lock()
+	defer d.Unlock()
+	key := client.ObjectKeyFromObject(obj)
+	if uid, found := d.counted[key]; found && uid == obj.GetUID() {
+		return false
+	}
+	if d.counted == nil {
+		d.counted = make(map[types.NamespacedName]types.UID)
+	}
+	d.counted[key] = obj.GetUID()
+	return true
+}
+
+func (d *dryRunActions) forget(key types.NamespacedName) {
+	d.Lock()
+	defer d.Unlock()
+	delete(d.counted, key)
+}
+
+// groupRecreations tracks the recreations of each group, keyed by its leader pod.
+type groupRecreations struct {
+	sync.Mutex
+	last map[types.NamespacedName]groupRecreation
+}
+
+type groupRecreation struct {
+	// count is the number of recreations since the backoff was last reset.
+	count int
+	time  time.Time
+	// next is the time from which the group can be recreated again.
+	next time.Time
+}
+
+// delay returns how long the recreation of the group is deferred by the backoff, 0 if it can be
+// recreated now. The backoff of a group not recreated within resetAfter is reset.
+func (g *groupRecreations) delay(key types.NamespacedName, backoff *configapi.RecreationBackoff, now time.Time) time.Duration {
+	g.Lock()
+	defer g.Unlock()
+	last, found := g.last[key]
+	if !found {
+		return 0
+	}
+	if now.Sub(last.time) >= backoff.ResetAfter.Duration {
+		delete(g.last, key)
+		return 0
+	}
+	return max(last.next.Sub(now), 0)
+}
+
+// record records the recreation of the group and returns the delay before its next recreation,
+// the base doubled on each recreation since the reset, with jitter, up to the cap.
+func (g *groupRecreations) record(key types.NamespacedName, backoff *configapi.RecreationBackoff, now time.Time) time.Duration {
+	g.Lock()
+	defer g.Unlock()
+	if g.last == nil {
+		g.last = make(map[types.NamespacedName]groupRecreation)
+	}
+	recreation := g.last[key]
+	delay := backoff.Base.Duration
+	for i := 0; i < recreation.count && delay < backoff.Cap.Duration; i++ {
+		delay *= 2
+	}
+	delay = min(delay, backoff.Cap.Duration)
+	if jitter := *backoff.JitterPercent; jitter > 0 {
+		delay = min(wait.Jitter(delay, float64(jitter)/100), backoff.Cap.Duration)
+	}
+	g.last[key] = groupRecreation{count: recreation.count + 1, time: now, next: now.Add(delay)}
+	return delay
+}
+
+func (g *groupRecreations) forget(key types.NamespacedName) {
+	g.Lock()
+	defer g.Unlock()
+	delete(g.last, key)
+}
+
+// revisionRecreations is recorded by the pod controller and read by the lws controller to detect
+// the groups of the new revision crash-looping during a rolling update. The groups recreated by the
+// RecreateGroupOnPodRestart restart policy on the first container restart don't accumulate restarts,
+// their recreations are counted instead.
+var revisionRecreations = &groupRevisionRecreations{}
+
+// groupRevisionRecreations tracks the recent recreations of each group at its revision, keyed by its
+// leader pod.
+type groupRevisionRecreations struct {
+	sync.Mutex
+	last map[types.NamespacedName]revisionRecreation
+}
+
+type revisionRecreation struct {
+	revision string
+	times    []time.Time
+}
+
+// record records the recreation of the group at the revision, the recreations at the previous
+// revisions or older than the window are dropped.
+func (g *groupRevisionRecreations) record(key types.NamespacedName, revision string, window time.Duration, now time.Time) {
+	g.Lock()
+	defer g.Unlock()
+	if g.last == nil {
+		g.last = make(map[types.NamespacedName]revisionRecreation)
+	}
+	recreation := g.last[key]
+	if recreation.revision != revision {
+		recreation = revisionRecreation{revision: revision}
+	}
+	recreation.times = append(slices.DeleteFunc(recreation.times, func(t time.Time) bool {
+		return now.Sub(t) >= window
+	}), now)
+	g.last[key] = recreation
+}
+
+// count returns the number of recreations of the group at the revision within the window before now.
+func (g *groupRevisionRecreations) count(key types.NamespacedName, revision string, window time.Duration, now time.Time) int {
+	g.Lock()
+	defer g.Unlock()
+	recreation, found := g.last[key]
+	if !found || recreation.revision != revision {
+		return 0
+	}
+	count := 0
+	for _, t := range recreation.times {
+		if now.Sub(t) < window {
+			count++
+		}
+	}
+	return count
+}
+
+func (g *groupRevisionRecreations) forget(key types.NamespacedName) {
+	g.Lock()
+	defer g.Unlock()
+	delete(g.last, key)
+}
+
+func NewPodReconciler(client client.Client, schema *runtime.Scheme, record record.EventRecorder, cfg *configapi.Configuration) *PodReconciler {
+	r := &PodReconciler{
+		Client:               client,
+		Scheme:               schema,
+		Record:               record,
+		recommendedLabels:    utils.RecommendedLabels(cfg.RecommendedLabels),
+		reconcileTimeout:     reconcileTimeout(cfg),
+		drainQueueOnShutdown: cfg.DrainQueueOnShutdown,
+		namer:                naming.Default(),
+		forceDeleteDryRun:    !features.Enabled(features.ForceDeleteStuckTerminatingPods) && features.Enabled(features.DryRunReconcile),
+	}
+	r.ApplyConfiguration(cfg)
+	return r
 }
 
-func NewPodReconciler(client client.Client, schema *runtime.Scheme, record record.EventRecorder) *PodReconciler {
-	return &PodReconciler{Client: client, Scheme: schema, Record: record}
+// ApplyConfiguration sets the fields of the reconciler which can be changed without a restart,
+// the reconciliations already started keep the configuration they loaded.
+func (r *PodReconciler) ApplyConfiguration(cfg *configapi.Configuration) {
+	config := &podReconcilerConfig{
+		defaultAnnotations:         cfg.DefaultAnnotations,
+		leaderPollInterval:         leaderReadyPollInterval(cfg),
+		stuckTerminatingPodTimeout: stuckTerminatingPodTimeout(cfg),
+		recreationBackoff:          cfg.RecreationBackoff,
+	}
+	if cfg.CrashLoopDetection != nil {
+		config.crashLoopWindow = cfg.CrashLoopDetection.Window.Duration
+	}
+	r.config.Store(config)
 }
 
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;watch;update;patch
 //+kubebuilder:rbac:groups=core,resources=pods,verbs=create;delete;get;list;patch;update;watch
 //+kubebuilder:rbac:groups=core,resources=pods/finalizers,verbs=update
+//+kubebuilder:rbac:groups=core,resources=pods/status,verbs=get;patch;update
 //+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;update;patch
 
-func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	cfg := r.config.Load()
+
 	var pod corev1.Pod
 	if err := r.Get(ctx, types.NamespacedName{Name: req.Name, Namespace: req.Namespace}, &pod); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.dryRunForceDeletes.forget(req.NamespacedName)
+			// The leader pod deleted by a recreation comes back under the same name, the recreations
+			// of its group are only forgotten once the group is removed.
+			removed, err := r.groupRemoved(ctx, req.NamespacedName)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if removed {
+				r.recreations.forget(req.NamespacedName)
+				revisionRecreations.forget(req.NamespacedName)
+			}
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 	log := ctrl.LoggerFrom(ctx).WithValues("pod", klog.KObj(&pod))
@@ -83,15 +327,57 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 	var leaderWorkerSet leaderworkerset.LeaderWorkerSet
 	if err := r.Get(ctx, types.NamespacedName{Name: lwsName, Namespace: pod.Namespace}, &leaderWorkerSet); err != nil {
 		// If lws not found, it's mostly because deleted, ignore the error as Pods will be GCed finally.
+		if apierrors.IsNotFound(err) && podutils.LeaderPod(pod) {
+			r.recreations.forget(client.ObjectKeyFromObject(&pod))
+			revisionRecreations.forget(client.ObjectKeyFromObject(&pod))
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
-	leaderDeleted, err := r.handleRestartPolicy(ctx, pod, leaderWorkerSet)
+	if pod.DeletionTimestamp != nil && cfg.stuckTerminatingPodTimeout > 0 && r.forceDeleteDryRun {
+		// The pod is reconciled as with the feature gate disabled, it is only requeued to be
+		// counted once it exceeds the timeout.
+		if countAfter := r.countStuckTerminatingPod(ctx, cfg, &pod); countAfter > 0 {
+			defer func() {
+				if err == nil && (result.RequeueAfter == 0 || result.RequeueAfter > countAfter) {
+					result.RequeueAfter = countAfter
+				}
+			}()
+		}
+	} else if pod.DeletionTimestamp != nil && cfg.stuckTerminatingPodTimeout > 0 {
+		requeueAfter, err := r.forceDeleteStuckTerminatingPod(ctx, cfg, &pod, &leaderWorkerSet)
+		if err != nil || requeueAfter == 0 {
+			return ctrl.Result{}, err
+		}
+		if leaderWorkerSet.DeletionTimestamp != nil {
+			return ctrl.Result{RequeueAfter: requeueAfter}, nil
+		}
+		// The pod is still terminating in time, only the restart policy applies to it.
+		_, recreateAfter, err := r.handleRestartPolicy(ctx, cfg, pod, leaderWorkerSet)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if recreateAfter > 0 {
+			requeueAfter = min(requeueAfter, recreateAfter)
+		}
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+	// The lws is being deleted, its pods and statefulsets are garbage collected, recreating the
+	// pods or the worker statefulsets only churns until the deletion completes. The pods stuck
+	// terminating are still force deleted above, since they would hold the deletion back.
+	if leaderWorkerSet.DeletionTimestamp != nil {
+		log.V(2).Info("skip reconciling the pod since the leaderworkerset is being deleted")
+		return ctrl.Result{}, nil
+	}
+	leaderDeleted, recreateAfter, err := r.handleRestartPolicy(ctx, cfg, pod, leaderWorkerSet)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 	if leaderDeleted {
 		return ctrl.Result{}, nil
 	}
+	if recreateAfter > 0 {
+		return ctrl.Result{RequeueAfter: recreateAfter}, nil
+	}
 
 	// worker pods' reconciliation is only done to handle restart policy
 	if !podutils.LeaderPod(pod) {
@@ -106,12 +392,18 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 			pod.Name,
 			leaderworkerset.LeaderPodNameAnnotationKey)
 		log.Error(errors.New(errMsg), "validate leader's annotations")
-		r.Record.Eventf(&leaderWorkerSet, corev1.EventTypeWarning, FailedCreate, errMsg)
+		r.Record.Event(&leaderWorkerSet, corev1.EventTypeWarning, FailedCreate, errMsg)
 		return ctrl.Result{}, nil
 	}
 
+	if _, found := pod.Labels[leaderworkerset.GroupIndexLabelKey]; !found {
+		if err := r.persistGroupIndex(ctx, &pod, lwsName); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	if leaderWorkerSet.Spec.NetworkConfig != nil && *leaderWorkerSet.Spec.NetworkConfig.SubdomainPolicy == leaderworkerset.SubdomainUniquePerReplica {
-		if err := controllerutils.CreateHeadlessServiceIfNotExists(ctx, r.Client, r.Scheme, &leaderWorkerSet, pod.Name, map[string]string{leaderworkerset.SetNameLabelKey: leaderWorkerSet.Name, leaderworkerset.GroupIndexLabelKey: pod.Labels[leaderworkerset.GroupIndexLabelKey]}, &pod); err != nil {
+		if err := controllerutils.ReconcileHeadlessService(ctx, r.Client, r.Scheme, &leaderWorkerSet, r.namer.GroupServiceName(pod.Name), map[string]string{leaderworkerset.SetNameLabelKey: leaderWorkerSet.Name, leaderworkerset.GroupIndexLabelKey: pod.Labels[leaderworkerset.GroupIndexLabelKey]}, &pod, r.recommendedLabels, cfg.defaultAnnotations); err != nil {
 			return ctrl.Result{}, err
 		}
 	}
@@ -124,15 +416,27 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		return ctrl.Result{}, nil
 	}
 
+	if hasGroupReadinessGate(pod) {
+		if err := r.updateGroupReadyCondition(ctx, &pod, &leaderWorkerSet); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Once size = 1, no need to create worker statefulSets.
 	if *leaderWorkerSet.Spec.LeaderWorkerTemplate.Size == 1 {
 		return ctrl.Result{}, nil
 	}
 
 	// logic for handling leader pod
-	if leaderWorkerSet.Spec.StartupPolicy == leaderworkerset.LeaderReadyStartupPolicy && !podutils.IsPodReady(&pod) {
-		log.V(2).Info("defer the creation of the worker statefulset because leader pod is not ready.")
-		return ctrl.Result{}, nil
+	allLeadersReady := false
+	if leaderWorkerSet.Spec.StartupPolicy == leaderworkerset.AllLeadersReadyStartupPolicy {
+		if allLeadersReady, err = r.allLeadersReady(ctx, &leaderWorkerSet); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if waitingForLeaders(&leaderWorkerSet, &pod, allLeadersReady) {
+		log.V(2).Info("defer the creation of the worker statefulset because the leader pods are not ready.")
+		return ctrl.Result{RequeueAfter: cfg.leaderPollInterval}, nil
 	}
 	revision, err := revisionutils.GetRevision(ctx, r.Client, &leaderWorkerSet, revisionutils.GetRevisionKey(&pod))
 	if err != nil {
@@ -143,13 +447,19 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		log.V(2).Info(fmt.Sprintf("Revision has not been created yet, requeing reconciler for pod %s", pod.Name))
 		return ctrl.Result{Requeue: true, RequeueAfter: time.Second}, nil
 	}
-	statefulSet, err := constructWorkerStatefulSetApplyConfiguration(pod, leaderWorkerSet, revision)
+	statefulSet, err := constructWorkerStatefulSetApplyConfiguration(pod, leaderWorkerSet, revision, r.namer)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	statefulSet.WithLabels(r.recommendedLabels)
 
 	// if exclusive placement is enabled but leader pod is not scheduled, don't create the worker sts
-	if topologyKey, found := leaderWorkerSet.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey]; found {
+	topologyKey, found, err := utils.ParseExclusiveTopology(leaderWorkerSet.Annotations)
+	if err != nil {
+		log.Error(err, "Parsing exclusive topology")
+		return ctrl.Result{}, err
+	}
+	if found {
 		// check if the leader pod is scheduled.
 		if pod.Spec.NodeName == "" {
 			log.V(2).Info(fmt.Sprintf("Pod %q is not scheduled yet", pod.Name))
@@ -165,68 +475,338 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		log.Error(err, "Setting controller reference.")
 		return ctrl.Result{}, nil
 	}
-
-	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(statefulSet)
+	leaderless, err := utils.ParseLeaderless(leaderWorkerSet.Annotations)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	workerStatefulSet := &unstructured.Unstructured{
-		Object: obj,
+	if leaderless {
+		// Candidate 0 is no more essential than the other candidates, the lws also owns the worker
+		// statefulset so that losing candidate 0 doesn't garbage collect them.
+		if err := setOwnerReferenceWithStatefulSet(&leaderWorkerSet, statefulSet, r.Scheme, false); err != nil {
+			log.Error(err, "Setting owner reference.")
+			return ctrl.Result{}, nil
+		}
 	}
 
 	var workerSts appsv1.StatefulSet
+	workerStsFound := true
 	if err := r.Get(ctx, types.NamespacedName{Name: pod.Name, Namespace: leaderWorkerSet.Namespace}, &workerSts); err != nil {
 		if client.IgnoreNotFound(err) != nil {
 			return ctrl.Result{}, err
 		}
-		if err = r.Create(ctx, workerStatefulSet); err != nil {
-			r.Record.Eventf(&leaderWorkerSet, corev1.EventTypeWarning, FailedCreate, fmt.Sprintf("Failed to create worker statefulset for leader pod %s", pod.Name))
-			return ctrl.Result{}, client.IgnoreAlreadyExists(err)
+		workerStsFound = false
+	}
+	if workerStsFound && leaderless {
+		// The worker statefulset of a leaderless group outlives candidate 0, it's only recreated once
+		// it's gone after candidate 0 was replaced by a pod of another revision.
+		if workerSts.DeletionTimestamp != nil {
+			log.V(2).Info("waiting for the deletion of the outdated worker statefulset")
+			return ctrl.Result{RequeueAfter: time.Second}, nil
+		}
+		if revisionutils.GetRevisionKey(&workerSts) != revisionutils.GetRevisionKey(&pod) {
+			if err := deleteWorkerStatefulSet(ctx, r.Client, &workerSts); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: time.Second}, nil
 		}
-		r.Record.Eventf(&leaderWorkerSet, corev1.EventTypeNormal, GroupsProgressing, fmt.Sprintf("Created worker statefulset for leader pod %s", pod.Name))
+	}
+	if workerStsFound {
+		// The pod template of an existing worker statefulset is kept, changing it would roll the workers
+		// in place, while the groups are updated by recreating their leader pod.
+		if err := setWorkerPodTemplate(statefulSet, &workerSts); err != nil {
+			return ctrl.Result{}, err
+		}
+		upToDate, err := workerStatefulSetUpToDate(statefulSet, &workerSts)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if upToDate {
+			log.V(2).Info("Worker Reconcile completed.")
+			return ctrl.Result{}, nil
+		}
+	}
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(statefulSet)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	workerStatefulSet := &unstructured.Unstructured{
+		Object: obj,
+	}
+	// Server-side apply the worker statefulset so that the controller only owns the fields it sets, and
+	// the fields set by other systems, e.g. their mutating webhooks, are preserved. The ownership isn't
+	// forced, an apply based on a stale cache conflicts instead of overriding the live object.
+	if err = r.Patch(ctx, workerStatefulSet, client.Apply, &client.PatchOptions{
+		FieldManager: podControllerFieldManager,
+	}); err != nil {
+		if workerStsFound {
+			log.Error(err, "Using server side apply to update worker statefulset")
+			return ctrl.Result{}, err
+		}
+		r.Record.Eventf(&leaderWorkerSet, corev1.EventTypeWarning, FailedCreate, "Failed to create worker statefulset for leader pod %s", pod.Name)
+		// Each group is reconciled through the request of its leader pod, so only this group is
+		// retried with the backoff of the controller, the other groups aren't re-examined.
+		return ctrl.Result{}, err
+	}
+	if !workerStsFound {
+		r.Record.Eventf(&leaderWorkerSet, corev1.EventTypeNormal, GroupsProgressing, "Created worker statefulset for leader pod %s", pod.Name)
 	}
 	log.V(2).Info("Worker Reconcile completed.")
 	return ctrl.Result{}, nil
 }
 
-func (r *PodReconciler) handleRestartPolicy(ctx context.Context, pod corev1.Pod, leaderWorkerSet leaderworkerset.LeaderWorkerSet) (bool, error) {
+// groupRemoved returns whether the group of the leader pod named by the key was removed on scale-down
+// or along with its lws, the leader pods being named after their lws and their group index.
+func (r *PodReconciler) groupRemoved(ctx context.Context, key types.NamespacedName) (bool, error) {
+	lwsName, groupIndex := statefulsetutils.GetParentNameAndOrdinal(key.Name)
+	if groupIndex == -1 {
+		return true, nil
+	}
+	var lws leaderworkerset.LeaderWorkerSet
+	if err := r.Get(ctx, types.NamespacedName{Name: lwsName, Namespace: key.Namespace}, &lws); err != nil {
+		return apierrors.IsNotFound(err), client.IgnoreNotFound(err)
+	}
+	return lws.DeletionTimestamp != nil || groupIndex >= int(*lws.Spec.Replicas), nil
+}
+
+// leaderReadyPollInterval returns the configured interval of the waits for the leaders, 0 if not set.
+func leaderReadyPollInterval(cfg *configapi.Configuration) time.Duration {
+	if cfg.LeaderReadyPollInterval == nil {
+		return 0
+	}
+	return cfg.LeaderReadyPollInterval.Duration
+}
+
+func stuckTerminatingPodTimeout(cfg *configapi.Configuration) time.Duration {
+	if cfg.StuckTerminatingPodTimeout == nil || (!features.Enabled(features.ForceDeleteStuckTerminatingPods) && !features.Enabled(features.DryRunReconcile)) {
+		return 0
+	}
+	return cfg.StuckTerminatingPodTimeout.Duration
+}
+
+// forceDeleteStuckTerminatingPod force deletes the terminating pod once it exceeded its grace period
+// by the stuckTerminatingPodTimeout, e.g. on an unreachable node whose kubelet can't confirm the
+// deletion, so that its statefulset can recreate it. It returns how long to wait for the pod to
+// terminate otherwise, 0 once the pod is force deleted.
+func (r *PodReconciler) forceDeleteStuckTerminatingPod(ctx context.Context, cfg *podReconcilerConfig, pod *corev1.Pod, lws *leaderworkerset.LeaderWorkerSet) (time.Duration, error) {
+	// The deletion timestamp is the end of the grace period of the pod.
+	if wait := time.Until(pod.DeletionTimestamp.Add(cfg.stuckTerminatingPodTimeout)); wait > 0 {
+		return wait, nil
+	}
+	if err := r.Delete(ctx, pod, client.GracePeriodSeconds(0)); err != nil {
+		return 0, client.IgnoreNotFound(err)
+	}
+	ctrl.LoggerFrom(ctx).Info("Force deleted the pod stuck terminating", "deletionTimestamp", pod.DeletionTimestamp)
+	r.Record.Eventf(lws, corev1.EventTypeWarning, ForceDeletedStuckPod, "Force deleted pod %s, stuck terminating since %s on node %s", pod.Name, pod.DeletionTimestamp.Format(time.RFC3339), pod.Spec.NodeName)
+	return 0, nil
+}
+
+// countStuckTerminatingPod is the dry-run of forceDeleteStuckTerminatingPod, it counts the pod in
+// the dry-run actions once it exceeded its grace period by the stuckTerminatingPodTimeout, without
+// deleting it nor recording any event. It returns how long to wait for the pod to exceed it.
+func (r *PodReconciler) countStuckTerminatingPod(ctx context.Context, cfg *podReconcilerConfig, pod *corev1.Pod) time.Duration {
+	if wait := time.Until(pod.DeletionTimestamp.Add(cfg.stuckTerminatingPodTimeout)); wait > 0 {
+		return wait
+	}
+	if r.dryRunForceDeletes.count(pod) {
+		metrics.DryRunActions.WithLabelValues(string(features.ForceDeleteStuckTerminatingPods), "ForceDeletePod").Inc()
+		ctrl.LoggerFrom(ctx).Info("The pod stuck terminating would be force deleted, dry-run", "deletionTimestamp", pod.DeletionTimestamp)
+	}
+	return 0
+}
+
+// allLeadersReady returns whether the leader pods of all the groups of the lws are ready.
+func (r *PodReconciler) allLeadersReady(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) (bool, error) {
+	var leaderPods corev1.PodList
+	if err := r.List(ctx, &leaderPods, client.InNamespace(lws.Namespace), client.MatchingLabels{
+		leaderworkerset.SetNameLabelKey:     lws.Name,
+		leaderworkerset.WorkerIndexLabelKey: "0",
+	}); err != nil {
+		return false, err
+	}
+	return leadersReady(lws, leaderPods.Items), nil
+}
+
+// hasGroupReadinessGate returns whether the leader pod has the readiness gate of the group-readiness-gate annotation.
+func hasGroupReadinessGate(pod corev1.Pod) bool {
+	for _, gate := range pod.Spec.ReadinessGates {
+		if gate.ConditionType == leaderworkerset.GroupReadyPodConditionType {
+			return true
+		}
+	}
+	return false
+}
+
+// updateGroupReadyCondition sets the condition of the readiness gate of a leader pod to whether the
+// workers of its group are ready, so that the leader pod is only a ready endpoint of the Services
+// selecting it while the whole group is ready. The worker statefulset is owned by the leader pod, so
+// the changes of its ready replicas trigger the reconciliation of the leader pod.
+func (r *PodReconciler) updateGroupReadyCondition(ctx context.Context, leaderPod *corev1.Pod, lws *leaderworkerset.LeaderWorkerSet) error {
+	workersReady := true
+	if *lws.Spec.LeaderWorkerTemplate.Size > 1 {
+		var sts appsv1.StatefulSet
+		if err := r.Get(ctx, types.NamespacedName{Name: leaderPod.Name, Namespace: leaderPod.Namespace}, &sts); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return err
+			}
+			workersReady = false
+		} else {
+			// The Ready workers are counted, rather than the created ones, so that a worker going
+			// down pulls the leader pod out of the endpoints.
+			minReadyWorkers := *sts.Spec.Replicas
+			if policy := lws.Spec.GroupReadinessPolicy; policy != nil && policy.MinReadyWorkers != nil {
+				minReadyWorkers = *policy.MinReadyWorkers
+			}
+			workersReady = statefulsetutils.StatefulsetQuorumReady(sts, minReadyWorkers)
+		}
+	}
+
+	condition := corev1.PodCondition{
+		Type:               leaderworkerset.GroupReadyPodConditionType,
+		Status:             corev1.ConditionFalse,
+		Reason:             "WorkersNotReady",
+		LastTransitionTime: metav1.Now(),
+	}
+	if workersReady {
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = "WorkersReady"
+	}
+	index, current := podutils.GetPodCondition(&leaderPod.Status, leaderworkerset.GroupReadyPodConditionType)
+	if current != nil && current.Status == condition.Status {
+		return nil
+	}
+	if current == nil {
+		leaderPod.Status.Conditions = append(leaderPod.Status.Conditions, condition)
+	} else {
+		leaderPod.Status.Conditions[index] = condition
+	}
+	podApplyConfiguration := coreapplyv1.Pod(leaderPod.Name, leaderPod.Namespace).
+		WithStatus(coreapplyv1.PodStatus().WithConditions(coreapplyv1.PodCondition().
+			WithType(condition.Type).
+			WithStatus(condition.Status).
+			WithReason(condition.Reason).
+			WithLastTransitionTime(condition.LastTransitionTime)))
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(podApplyConfiguration)
+	if err != nil {
+		return err
+	}
+	// The conditions are keyed by their type, the apply only owns the condition of the gate, not the ones
+	// of the kubelet. The condition of the gate is only set by the controller, the ownership is forced to
+	// take it over from the updates of the previous versions of the controller.
+	return r.Status().Patch(ctx, &unstructured.Unstructured{Object: obj}, client.Apply, &client.SubResourcePatchOptions{
+		PatchOptions: client.PatchOptions{
+			FieldManager: podControllerFieldManager,
+			Force:        ptr.To(true),
+		},
+	})
+}
+
+// persistGroupIndex labels a leader pod created before the group index was persisted with the
+// index of its group, which the worker statefulset and the later controller versions then rely on.
+func (r *PodReconciler) persistGroupIndex(ctx context.Context, pod *corev1.Pod, lwsName string) error {
+	groupIndex, err := utils.GroupIndex(pod, lwsName)
+	if err != nil {
+		return err
+	}
+	podApplyConfiguration := coreapplyv1.Pod(pod.Name, pod.Namespace).
+		WithLabels(map[string]string{leaderworkerset.GroupIndexLabelKey: strconv.Itoa(groupIndex)})
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(podApplyConfiguration)
+	if err != nil {
+		return err
+	}
+	patch := &unstructured.Unstructured{Object: obj}
+	if err := r.Patch(ctx, patch, client.Apply, &client.PatchOptions{
+		FieldManager: podControllerFieldManager,
+	}); err != nil {
+		return err
+	}
+	pod.Labels[leaderworkerset.GroupIndexLabelKey] = strconv.Itoa(groupIndex)
+	ctrl.LoggerFrom(ctx).V(2).Info("Persisted the group index of the leader pod", "groupIndex", groupIndex)
+	return nil
+}
+
+// handleRestartPolicy deletes the leader pod of the group of a failed pod to recreate the group,
+// and returns whether the leader pod is deleted. When the recreation is deferred by the backoff,
+// it returns how long to wait before the group can be recreated.
+func (r *PodReconciler) handleRestartPolicy(ctx context.Context, cfg *podReconcilerConfig, pod corev1.Pod, leaderWorkerSet leaderworkerset.LeaderWorkerSet) (bool, time.Duration, error) {
+	ctx, span := controllerutils.StartSpan(ctx, "Pod.handleRestartPolicy")
+	defer span.End()
 	if leaderWorkerSet.Spec.LeaderWorkerTemplate.RestartPolicy != leaderworkerset.RecreateGroupOnPodRestart {
-		return false, nil
+		return false, 0, nil
 	}
-	// the leader pod will be deleted if the worker pod is deleted or any containes were restarted
-	if !podutils.ContainerRestarted(pod) && !podutils.PodDeleted(pod) {
-		return false, nil
+	// the leader pod will be deleted if the worker pod is deleted, any containes were restarted
+	// or the pod exceeded its active deadline
+	if !podutils.ContainerRestarted(pod) && !podutils.PodDeleted(pod) && !podutils.PodDeadlineExceeded(pod) {
+		return false, 0, nil
 	}
 	var leader corev1.Pod
 	if !podutils.LeaderPod(pod) {
 		leaderPodName, ordinal := statefulsetutils.GetParentNameAndOrdinal(pod.Name)
 		if ordinal == -1 {
-			return false, fmt.Errorf("parsing pod name for pod %s", pod.Name)
+			return false, 0, fmt.Errorf("parsing pod name for pod %s", pod.Name)
 		}
 		if err := r.Get(ctx, types.NamespacedName{Name: leaderPodName, Namespace: pod.Namespace}, &leader); err != nil {
 			// If the error is not found, it is likely caused by the fact that the leader was deleted but the worker statefulset
 			// deletion hasn't deleted all the worker pods
-			return false, client.IgnoreNotFound(err)
+			return false, 0, client.IgnoreNotFound(err)
 		}
 		// Different revision key means that this pod will be deleted soon and alternative will be created with the matching key
 		if revisionutils.GetRevisionKey(&leader) != revisionutils.GetRevisionKey(&pod) {
-			return false, nil
+			return false, 0, nil
 		}
 	} else {
 		leader = pod
 	}
 	// if the leader pod is being deleted, we don't need to send deletion requests
 	if leader.DeletionTimestamp != nil {
-		return true, nil
+		return true, 0, nil
+	}
+	leaderless, err := utils.ParseLeaderless(leaderWorkerSet.Annotations)
+	if err != nil {
+		return false, 0, err
+	}
+	var workerSts *appsv1.StatefulSet
+	if leaderless {
+		var sts appsv1.StatefulSet
+		if err := r.Get(ctx, types.NamespacedName{Name: leader.Name, Namespace: leader.Namespace}, &sts); client.IgnoreNotFound(err) != nil {
+			return false, 0, err
+		} else if err == nil {
+			workerSts = &sts
+		}
+		// The candidates elect another leader among themselves, the group is only recreated once
+		// it lost the majority of its candidates.
+		if (workerSts == nil || workerSts.DeletionTimestamp == nil) && groupReady(&leaderWorkerSet, leader, workerSts) {
+			ctrl.LoggerFrom(ctx).V(2).Info("Skipping the recreation of the group keeping a majority of ready candidates", "leader", klog.KObj(&leader))
+			return false, 0, nil
+		}
+	}
+	groupKey := client.ObjectKeyFromObject(&leader)
+	now := time.Now()
+	if cfg.recreationBackoff != nil {
+		if delay := r.recreations.delay(groupKey, cfg.recreationBackoff, now); delay > 0 {
+			ctrl.LoggerFrom(ctx).V(2).Info("Deferring the recreation of the group by the backoff", "leader", klog.KObj(&leader), "delay", delay)
+			return false, delay, nil
+		}
+	}
+	if workerSts != nil && workerSts.DeletionTimestamp == nil {
+		if err := deleteWorkerStatefulSet(ctx, r.Client, workerSts); err != nil {
+			return false, 0, err
+		}
 	}
 	deletionOpt := metav1.DeletePropagationForeground
 	if err := r.Delete(ctx, &leader, &client.DeleteOptions{
 		PropagationPolicy: &deletionOpt,
 	}); err != nil {
-		return false, err
+		return false, 0, err
+	}
+	if cfg.recreationBackoff != nil {
+		r.recreations.record(groupKey, cfg.recreationBackoff, now)
+	}
+	if cfg.crashLoopWindow > 0 {
+		revisionRecreations.record(groupKey, revisionutils.GetRevisionKey(&leader), cfg.crashLoopWindow, now)
 	}
-	r.Record.Eventf(&leaderWorkerSet, corev1.EventTypeNormal, "RecreateGroupOnPodRestart", fmt.Sprintf("Worker pod %s failed, deleted leader pod %s to recreate group %s", pod.Name, leader.Name, leader.Labels[leaderworkerset.GroupIndexLabelKey]))
-	return true, nil
+	r.Record.Eventf(&leaderWorkerSet, corev1.EventTypeNormal, "RecreateGroupOnPodRestart", "Worker pod %s failed, deleted leader pod %s to recreate group %s", pod.Name, leader.Name, leader.Labels[leaderworkerset.GroupIndexLabelKey])
+	return true, 0, nil
 }
 
 func (r *PodReconciler) setNodeSelectorForWorkerPods(ctx context.Context, pod *corev1.Pod, sts *appsapplyv1.StatefulSetApplyConfiguration, topologyKey string) error {
@@ -270,8 +850,77 @@ func (r *PodReconciler) topologyValueFromPod(ctx context.Context, pod *corev1.Po
 	return topology, nil
 }
 
+// deleteWorkerStatefulSet deletes the worker statefulset of a leaderless group along with its pods,
+// it isn't garbage collected with candidate 0 since the lws owns it as well.
+func deleteWorkerStatefulSet(ctx context.Context, c client.Client, sts *appsv1.StatefulSet) error {
+	deletionOpt := metav1.DeletePropagationForeground
+	return client.IgnoreNotFound(c.Delete(ctx, sts, &client.DeleteOptions{
+		PropagationPolicy: &deletionOpt,
+	}))
+}
+
+// setWorkerPodTemplate sets the pod template of the worker statefulset apply configuration to the
+// fields of the template of the existing statefulset owned by the controller, so that the apply doesn't
+// take over the fields set by other systems. The whole template is used for the statefulsets which
+// weren't applied by the controller yet, e.g. created by a previous version.
+func setWorkerPodTemplate(sts *appsapplyv1.StatefulSetApplyConfiguration, existing *appsv1.StatefulSet) error {
+	owned, err := appsapplyv1.ExtractStatefulSet(existing, podControllerFieldManager)
+	if err != nil {
+		return err
+	}
+	if owned.Spec != nil && owned.Spec.Template != nil {
+		sts.Spec.WithTemplate(owned.Spec.Template)
+		return nil
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&existing.Spec.Template)
+	if err != nil {
+		return err
+	}
+	var podTemplateApplyConfiguration coreapplyv1.PodTemplateSpecApplyConfiguration
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, &podTemplateApplyConfiguration); err != nil {
+		return err
+	}
+	sts.Spec.WithTemplate(&podTemplateApplyConfiguration)
+	return nil
+}
+
+// workerStatefulSetUpToDate returns whether the existing worker statefulset already has the fields of
+// the apply configuration besides the pod template, which is kept, so that the apply can be skipped.
+func workerStatefulSetUpToDate(sts *appsapplyv1.StatefulSetApplyConfiguration, existing *appsv1.StatefulSet) (bool, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(sts)
+	if err != nil {
+		return false, err
+	}
+	var desired appsv1.StatefulSet
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, &desired); err != nil {
+		return false, err
+	}
+	for key, value := range desired.Labels {
+		if existingValue, found := existing.Labels[key]; !found || existingValue != value {
+			return false, nil
+		}
+	}
+	for _, ownerReference := range desired.OwnerReferences {
+		if !slices.ContainsFunc(existing.OwnerReferences, func(existingReference metav1.OwnerReference) bool {
+			return equality.Semantic.DeepEqual(existingReference, ownerReference)
+		}) {
+			return false, nil
+		}
+	}
+	return equality.Semantic.DeepEqual(desired.Spec.Replicas, existing.Spec.Replicas) &&
+		desired.Spec.ServiceName == existing.Spec.ServiceName &&
+		desired.Spec.PodManagementPolicy == existing.Spec.PodManagementPolicy &&
+		equality.Semantic.DeepEqual(desired.Spec.Ordinals, existing.Spec.Ordinals) &&
+		equality.Semantic.DeepEqual(desired.Spec.Selector, existing.Spec.Selector), nil
+}
+
 // setControllerReferenceWithStatefulSet set controller reference for the StatefulSet
 func setControllerReferenceWithStatefulSet(owner metav1.Object, sts *appsapplyv1.StatefulSetApplyConfiguration, scheme *runtime.Scheme) error {
+	return setOwnerReferenceWithStatefulSet(owner, sts, scheme, true)
+}
+
+// setOwnerReferenceWithStatefulSet adds an owner reference for the StatefulSet, the controller one if controller is set.
+func setOwnerReferenceWithStatefulSet(owner metav1.Object, sts *appsapplyv1.StatefulSetApplyConfiguration, scheme *runtime.Scheme, controller bool) error {
 	// Validate the owner.
 	ro, ok := owner.(runtime.Object)
 	if !ok {
@@ -287,17 +936,114 @@ func setControllerReferenceWithStatefulSet(owner metav1.Object, sts *appsapplyv1
 		WithName(owner.GetName()).
 		WithUID(owner.GetUID()).
 		WithBlockOwnerDeletion(true).
-		WithController(true))
+		WithController(controller))
+	return nil
+}
+
+// setExclusiveTopologyAnnotations propagates the exclusive placement annotations of the lws to the pod annotations,
+// the subgroup exclusive placement only applies when the subGroupPolicy is set.
+func setExclusiveTopologyAnnotations(lws *leaderworkerset.LeaderWorkerSet, podAnnotations map[string]string) error {
+	topologyKey, found, err := utils.ParseExclusiveTopology(lws.Annotations)
+	if err != nil {
+		return err
+	}
+	if found {
+		podAnnotations[leaderworkerset.ExclusiveKeyAnnotationKey] = topologyKey
+	}
+	if lws.Spec.LeaderWorkerTemplate.SubGroupPolicy == nil {
+		return nil
+	}
+	subGroupTopologyKey, found, err := utils.ParseSubGroupExclusiveTopology(lws.Annotations)
+	if err != nil {
+		return err
+	}
+	if found {
+		podAnnotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey] = subGroupTopologyKey
+	}
+	return nil
+}
+
+// setPodInjectionAnnotation propagates the opt-out of the pod webhook injection of the lws to the pod annotations.
+func setPodInjectionAnnotation(lws *leaderworkerset.LeaderWorkerSet, podAnnotations map[string]string) error {
+	disabled, err := utils.ParsePodInjectionDisabled(lws.Annotations)
+	if err != nil {
+		return err
+	}
+	if disabled {
+		podAnnotations[leaderworkerset.DisablePodInjectionAnnotationKey] = "true"
+	}
+	return nil
+}
+
+// setInjectedContainerResourcesAnnotation propagates the resources of the containers injected by the
+// pod webhook, overridden by the lws, to the pod annotations.
+func setInjectedContainerResourcesAnnotation(lws *leaderworkerset.LeaderWorkerSet, podAnnotations map[string]string) {
+	if value, found := lws.Annotations[leaderworkerset.InjectedContainerResourcesAnnotationKey]; found {
+		podAnnotations[leaderworkerset.InjectedContainerResourcesAnnotationKey] = value
+	}
+}
+
+// setQueueNameAnnotation propagates the queue of the lws to the pod annotations, for the pod webhook
+// to record it in the gang annotation.
+func setQueueNameAnnotation(lws *leaderworkerset.LeaderWorkerSet, podAnnotations map[string]string) {
+	if value, found := lws.Annotations[leaderworkerset.QueueNameAnnotationKey]; found {
+		podAnnotations[leaderworkerset.QueueNameAnnotationKey] = value
+	}
+}
+
+// setActiveDeadlineSecondsAnnotation propagates the active deadline of the lws to the pod annotations,
+// for the pod webhook to set it on the pods since the statefulsets don't accept it in their templates.
+func setActiveDeadlineSecondsAnnotation(lws *leaderworkerset.LeaderWorkerSet, podAnnotations map[string]string) {
+	if seconds := lws.Spec.LeaderWorkerTemplate.ActiveDeadlineSeconds; seconds != nil {
+		podAnnotations[leaderworkerset.ActiveDeadlineSecondsAnnotationKey] = strconv.FormatInt(*seconds, 10)
+	}
+}
+
+// setWorkloadIdentityAnnotation propagates the UID of the lws to the pod annotations when the lws
+// opted in the injection of its identity, for the pod webhook to inject it.
+func setWorkloadIdentityAnnotation(lws *leaderworkerset.LeaderWorkerSet, podAnnotations map[string]string) error {
+	enabled, err := utils.ParseInjectWorkloadIdentity(lws.Annotations)
+	if err != nil {
+		return err
+	}
+	if enabled {
+		podAnnotations[leaderworkerset.WorkloadUIDAnnotationKey] = string(lws.UID)
+	}
+	return nil
+}
+
+// setCandidateLabel labels the pods of the groups of a leaderless lws as leader candidates.
+func setCandidateLabel(lws *leaderworkerset.LeaderWorkerSet, podTemplate *coreapplyv1.PodTemplateSpecApplyConfiguration) error {
+	leaderless, err := utils.ParseLeaderless(lws.Annotations)
+	if err != nil {
+		return err
+	}
+	if leaderless {
+		podTemplate.WithLabels(map[string]string{leaderworkerset.CandidateLabelKey: "true"})
+	}
 	return nil
 }
 
 // constructWorkerStatefulSetApplyConfiguration constructs the applied configuration for the leader StatefulSet
-func constructWorkerStatefulSetApplyConfiguration(leaderPod corev1.Pod, lws leaderworkerset.LeaderWorkerSet, currentRevision *appsv1.ControllerRevision) (*appsapplyv1.StatefulSetApplyConfiguration, error) {
+func constructWorkerStatefulSetApplyConfiguration(leaderPod corev1.Pod, lws leaderworkerset.LeaderWorkerSet, currentRevision *appsv1.ControllerRevision, namer naming.Namer) (*appsapplyv1.StatefulSetApplyConfiguration, error) {
 	currentLws, err := revisionutils.ApplyRevision(&lws, currentRevision)
 	if err != nil {
 		return nil, err
 	}
-	podTemplateSpec := *currentLws.Spec.LeaderWorkerTemplate.WorkerTemplate.DeepCopy()
+	groupIndex, err := strconv.Atoi(leaderPod.Labels[leaderworkerset.GroupIndexLabelKey])
+	if err != nil {
+		return nil, fmt.Errorf("parsing the group index of leader pod %s: %w", leaderPod.Name, err)
+	}
+	coordinatorGroup := groupIndex == 0 && currentLws.Spec.LeaderWorkerTemplate.CoordinatorTemplate != nil
+	var podTemplateSpec corev1.PodTemplateSpec
+	if coordinatorGroup {
+		podTemplateSpec = *coordinatorTemplate(currentLws)
+	} else {
+		podTemplateSpec = *currentLws.Spec.LeaderWorkerTemplate.WorkerTemplate.DeepCopy()
+	}
+	if overrides := currentLws.Spec.LeaderWorkerTemplate.GroupResourceOverrides; len(overrides) != 0 {
+		podutils.ApplyGroupResourceOverrides(&podTemplateSpec.Spec, overrides, int32(groupIndex))
+	}
 	// construct pod template spec configuration
 	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&podTemplateSpec)
 	if err != nil {
@@ -321,23 +1067,35 @@ func constructWorkerStatefulSetApplyConfiguration(leaderPod corev1.Pod, lws lead
 	}
 
 	podTemplateApplyConfiguration.WithLabels(labelMap)
+	if coordinatorGroup {
+		podTemplateApplyConfiguration.WithLabels(map[string]string{leaderworkerset.CoordinatorGroupLabelKey: "true"})
+	}
+	if err := setCandidateLabel(&lws, &podTemplateApplyConfiguration); err != nil {
+		return nil, err
+	}
 	podAnnotations := make(map[string]string)
 	podAnnotations[leaderworkerset.SizeAnnotationKey] = strconv.Itoa(int(*lws.Spec.LeaderWorkerTemplate.Size))
 	podAnnotations[leaderworkerset.LeaderPodNameAnnotationKey] = leaderPod.Name
-	if lws.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey] != "" {
-		podAnnotations[leaderworkerset.ExclusiveKeyAnnotationKey] = lws.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey]
+	if err := setExclusiveTopologyAnnotations(&lws, podAnnotations); err != nil {
+		return nil, err
+	}
+	if err := setPodInjectionAnnotation(&lws, podAnnotations); err != nil {
+		return nil, err
+	}
+	setInjectedContainerResourcesAnnotation(&lws, podAnnotations)
+	setQueueNameAnnotation(&lws, podAnnotations)
+	setActiveDeadlineSecondsAnnotation(currentLws, podAnnotations)
+	if err := setWorkloadIdentityAnnotation(&lws, podAnnotations); err != nil {
+		return nil, err
 	}
 	if lws.Spec.LeaderWorkerTemplate.SubGroupPolicy != nil {
 		podAnnotations[leaderworkerset.SubGroupSizeAnnotationKey] = strconv.Itoa(int(*lws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize))
-		if lws.Annotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey] != "" {
-			podAnnotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey] = lws.Annotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey]
-		}
 	}
 	acceleratorutils.AddTPUAnnotations(leaderPod, podAnnotations)
 	podTemplateApplyConfiguration.WithAnnotations(podAnnotations)
-	serviceName := leaderPod.Name
+	serviceName := namer.GroupServiceName(leaderPod.Name)
 	if lws.Spec.NetworkConfig == nil || *lws.Spec.NetworkConfig.SubdomainPolicy == leaderworkerset.SubdomainShared {
-		serviceName = lws.Name
+		serviceName = namer.ServiceName(lws.Name)
 	}
 	// construct statefulset apply configuration
 	statefulSetConfig := appsapplyv1.StatefulSet(leaderPod.Name, leaderPod.Namespace).
@@ -366,5 +1124,52 @@ func (r *PodReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				return exist
 			}
 			return false
-		})).Owns(&appsv1.StatefulSet{}).Complete(r)
+		})).Owns(&appsv1.StatefulSet{}).
+		// The groups using the AllLeadersReady startup policy wait for the leaders of the other groups.
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(r.leaderPodsWaitingForLeader),
+			builder.WithPredicates(leaderReadinessChanged)).
+		Complete(controllerutils.WithShutdownDrain(controllerutils.WithTracing(controllerutils.WithReconcileTimeout(r, r.reconcileTimeout), "Pod.Reconcile"), r.drainQueueOnShutdown))
+}
+
+// leaderReadinessChanged filters the events of the leader pods becoming ready or unready, or
+// changing phase for the coordination-only leaders which only need to be running.
+var leaderReadinessChanged = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldPod, newPod := e.ObjectOld.(*corev1.Pod), e.ObjectNew.(*corev1.Pod)
+		return podutils.LeaderPod(*newPod) && (podutils.IsPodReady(oldPod) != podutils.IsPodReady(newPod) || oldPod.Status.Phase != newPod.Status.Phase)
+	},
+}
+
+// leaderPodsWaitingForLeader enqueues the other leader pods of the lws of a leader pod when the
+// lws uses the AllLeadersReady startup policy, since their workers wait for all the leaders.
+func (r *PodReconciler) leaderPodsWaitingForLeader(ctx context.Context, obj client.Object) []reconcile.Request {
+	if !features.Enabled(features.AllLeadersReadyStartupPolicy) {
+		return nil
+	}
+	lwsName := obj.GetLabels()[leaderworkerset.SetNameLabelKey]
+	var lws leaderworkerset.LeaderWorkerSet
+	if err := r.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: lwsName}, &lws); err != nil {
+		return nil
+	}
+	if lws.Spec.StartupPolicy != leaderworkerset.AllLeadersReadyStartupPolicy {
+		return nil
+	}
+	var leaderPods corev1.PodList
+	if err := r.List(ctx, &leaderPods, client.InNamespace(lws.Namespace), client.MatchingLabels{
+		leaderworkerset.SetNameLabelKey:     lws.Name,
+		leaderworkerset.WorkerIndexLabelKey: "0",
+	}); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "Listing the leader pods waiting for the leaders")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, pod := range leaderPods.Items {
+		if pod.Name != obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}})
+		}
+	}
+	return requests
 }
@@ -18,18 +18,44 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	appsapplyv1 "k8s.io/client-go/applyconfigurations/apps/v1"
 	coreapplyv1 "k8s.io/client-go/applyconfigurations/core/v1"
 	metaapplyv1 "k8s.io/client-go/applyconfigurations/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/features"
+	"sigs.k8s.io/lws/pkg/metrics"
+	"sigs.k8s.io/lws/pkg/utils"
+	"sigs.k8s.io/lws/pkg/utils/naming"
+	podutils "sigs.k8s.io/lws/pkg/utils/pod"
 	revisionutils "sigs.k8s.io/lws/pkg/utils/revision"
 	"sigs.k8s.io/lws/test/wrappers"
 )
@@ -284,7 +310,7 @@ func TestConstructWorkerStatefulSetApplyConfiguration(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			statefulSetConfig, err := constructWorkerStatefulSetApplyConfiguration(*tc.pod, *tc.lws, tc.revision)
+			statefulSetConfig, err := constructWorkerStatefulSetApplyConfiguration(*tc.pod, *tc.lws, tc.revision, naming.Default())
 			if err != nil {
 				t.Errorf("failed with error %s", err.Error())
 			}
@@ -294,3 +320,1569 @@ func TestConstructWorkerStatefulSetApplyConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func TestTemplateImagePullPolicies(t *testing.T) {
+	leaderPodSpec := wrappers.MakeLeaderPodSpec()
+	leaderPodSpec.Containers[0].ImagePullPolicy = corev1.PullAlways
+	workerPodSpec := wrappers.MakeWorkerPodSpec()
+	workerPodSpec.Containers[0].ImagePullPolicy = corev1.PullIfNotPresent
+	lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").
+		Replica(1).
+		Size(2).
+		LeaderTemplateSpec(leaderPodSpec).
+		WorkerTemplateSpec(workerPodSpec).Obj()
+	revision, err := revisionutils.NewRevision(context.TODO(), fake.NewClientBuilder().Build(), lws, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	revisionKey := revisionutils.GetRevisionKey(revision)
+
+	leaderStatefulSetConfig, err := constructLeaderStatefulSetApplyConfiguration(lws, 0, 1, revisionKey, naming.Default())
+	if err != nil {
+		t.Fatalf("failed with error %s", err.Error())
+	}
+	if got := *leaderStatefulSetConfig.Spec.Template.Spec.Containers[0].ImagePullPolicy; got != corev1.PullAlways {
+		t.Errorf("Expected the leader pods to use the %s pull policy, got %s", corev1.PullAlways, got)
+	}
+
+	leaderPod := corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "test-sample-0",
+			Namespace: "default",
+			Labels: map[string]string{
+				leaderworkerset.WorkerIndexLabelKey:     "0",
+				leaderworkerset.SetNameLabelKey:         "test-sample",
+				leaderworkerset.GroupIndexLabelKey:      "0",
+				leaderworkerset.GroupUniqueHashLabelKey: "test-key",
+				leaderworkerset.RevisionKey:             revisionKey,
+			},
+		},
+	}
+	workerStatefulSetConfig, err := constructWorkerStatefulSetApplyConfiguration(leaderPod, *lws, revision, naming.Default())
+	if err != nil {
+		t.Fatalf("failed with error %s", err.Error())
+	}
+	if got := *workerStatefulSetConfig.Spec.Template.Spec.Containers[0].ImagePullPolicy; got != corev1.PullIfNotPresent {
+		t.Errorf("Expected the worker pods to use the %s pull policy, got %s", corev1.PullIfNotPresent, got)
+	}
+}
+
+// The apiserver rejects the statefulsets whose pod template sets activeDeadlineSeconds, it is
+// only carried by an annotation for the pod webhook to set it on the pods.
+func TestStatefulSetTemplatesActiveDeadlineSeconds(t *testing.T) {
+	for _, coordinator := range []bool{false, true} {
+		t.Run(fmt.Sprintf("coordinator template %v", coordinator), func(t *testing.T) {
+			lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").
+				Replica(1).
+				Size(2).
+				ActiveDeadlineSeconds(3600).Obj()
+			if coordinator {
+				lws.Spec.LeaderWorkerTemplate.CoordinatorTemplate = &corev1.PodTemplateSpec{Spec: wrappers.MakeLeaderPodSpec()}
+			}
+			revision, err := revisionutils.NewRevision(context.TODO(), fake.NewClientBuilder().Build(), lws, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			revisionKey := revisionutils.GetRevisionKey(revision)
+			leaderStatefulSetConfig, err := constructLeaderStatefulSetApplyConfiguration(lws, 0, 1, revisionKey, naming.Default())
+			if err != nil {
+				t.Fatalf("failed with error %s", err.Error())
+			}
+			leaderPod := corev1.Pod{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "test-sample-0",
+					Namespace: "default",
+					Labels: map[string]string{
+						leaderworkerset.WorkerIndexLabelKey:     "0",
+						leaderworkerset.SetNameLabelKey:         "test-sample",
+						leaderworkerset.GroupIndexLabelKey:      "0",
+						leaderworkerset.GroupUniqueHashLabelKey: "test-key",
+						leaderworkerset.RevisionKey:             revisionKey,
+					},
+				},
+			}
+			workerStatefulSetConfig, err := constructWorkerStatefulSetApplyConfiguration(leaderPod, *lws, revision, naming.Default())
+			if err != nil {
+				t.Fatalf("failed with error %s", err.Error())
+			}
+
+			for _, template := range []*coreapplyv1.PodTemplateSpecApplyConfiguration{leaderStatefulSetConfig.Spec.Template, workerStatefulSetConfig.Spec.Template} {
+				if template.Spec.ActiveDeadlineSeconds != nil {
+					t.Errorf("Expected the statefulset template not to set activeDeadlineSeconds, got %d", *template.Spec.ActiveDeadlineSeconds)
+				}
+				if got := template.Annotations[leaderworkerset.ActiveDeadlineSecondsAnnotationKey]; got != "3600" {
+					t.Errorf("Expected the %s pod annotation to be 3600, got %q", leaderworkerset.ActiveDeadlineSecondsAnnotationKey, got)
+				}
+			}
+			if coordinator {
+				template, err := utils.ParseCoordinatorTemplate(leaderStatefulSetConfig.Spec.Template.Annotations)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if template.Spec.ActiveDeadlineSeconds != nil {
+					t.Errorf("Expected the coordinator template not to set activeDeadlineSeconds, got %d", *template.Spec.ActiveDeadlineSeconds)
+				}
+			}
+		})
+	}
+}
+
+func TestTemplateShareProcessNamespace(t *testing.T) {
+	workerPodSpec := wrappers.MakeWorkerPodSpec()
+	workerPodSpec.ShareProcessNamespace = ptr.To(true)
+	lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").
+		Replica(1).
+		Size(2).
+		WorkerTemplateSpec(workerPodSpec).Obj()
+	revision, err := revisionutils.NewRevision(context.TODO(), fake.NewClientBuilder().Build(), lws, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	revisionKey := revisionutils.GetRevisionKey(revision)
+
+	// Without a leader template, the leader pods share their process namespace as well.
+	leaderStatefulSetConfig, err := constructLeaderStatefulSetApplyConfiguration(lws, 0, 1, revisionKey, naming.Default())
+	if err != nil {
+		t.Fatalf("failed with error %s", err.Error())
+	}
+	if !ptr.Deref(leaderStatefulSetConfig.Spec.Template.Spec.ShareProcessNamespace, false) {
+		t.Errorf("Expected the leader pods to share their process namespace")
+	}
+
+	leaderPod := corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "test-sample-0",
+			Namespace: "default",
+			Labels: map[string]string{
+				leaderworkerset.WorkerIndexLabelKey:     "0",
+				leaderworkerset.SetNameLabelKey:         "test-sample",
+				leaderworkerset.GroupIndexLabelKey:      "0",
+				leaderworkerset.GroupUniqueHashLabelKey: "test-key",
+				leaderworkerset.RevisionKey:             revisionKey,
+			},
+		},
+	}
+	workerStatefulSetConfig, err := constructWorkerStatefulSetApplyConfiguration(leaderPod, *lws, revision, naming.Default())
+	if err != nil {
+		t.Fatalf("failed with error %s", err.Error())
+	}
+	if !ptr.Deref(workerStatefulSetConfig.Spec.Template.Spec.ShareProcessNamespace, false) {
+		t.Errorf("Expected the worker pods to share their process namespace")
+	}
+}
+
+func TestHandleRestartPolicyDeadlineExceeded(t *testing.T) {
+	leader := corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "test-sample-0",
+			Namespace: "default",
+			Labels: map[string]string{
+				leaderworkerset.WorkerIndexLabelKey: "0",
+				leaderworkerset.SetNameLabelKey:     "test-sample",
+				leaderworkerset.GroupIndexLabelKey:  "0",
+				leaderworkerset.RevisionKey:         "test-revision",
+			},
+		},
+	}
+	deadlineExceededWorker := corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "test-sample-0-1",
+			Namespace: "default",
+			Labels: map[string]string{
+				leaderworkerset.WorkerIndexLabelKey: "1",
+				leaderworkerset.SetNameLabelKey:     "test-sample",
+				leaderworkerset.GroupIndexLabelKey:  "0",
+				leaderworkerset.RevisionKey:         "test-revision",
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase:  corev1.PodFailed,
+			Reason: "DeadlineExceeded",
+		},
+	}
+
+	tests := []struct {
+		name              string
+		pod               corev1.Pod
+		restartPolicy     leaderworkerset.RestartPolicyType
+		wantDeleted       bool
+		wantLeaderDeleted bool
+	}{
+		{
+			name:              "worker exceeded deadline with RecreateGroupOnPodRestart",
+			pod:               deadlineExceededWorker,
+			restartPolicy:     leaderworkerset.RecreateGroupOnPodRestart,
+			wantDeleted:       true,
+			wantLeaderDeleted: true,
+		},
+		{
+			name: "leader exceeded deadline with RecreateGroupOnPodRestart",
+			pod: func() corev1.Pod {
+				pod := *leader.DeepCopy()
+				pod.Status = deadlineExceededWorker.Status
+				return pod
+			}(),
+			restartPolicy:     leaderworkerset.RecreateGroupOnPodRestart,
+			wantDeleted:       true,
+			wantLeaderDeleted: true,
+		},
+		{
+			name:          "worker exceeded deadline with None",
+			pod:           deadlineExceededWorker,
+			restartPolicy: leaderworkerset.NoneRestartPolicy,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := fake.NewClientBuilder().WithObjects(leader.DeepCopy()).Build()
+			r := NewPodReconciler(client, nil, record.NewFakeRecorder(10), &configapi.Configuration{})
+			lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").
+				RestartPolicy(tc.restartPolicy).
+				ActiveDeadlineSeconds(60).Obj()
+
+			deleted, _, err := r.handleRestartPolicy(context.TODO(), r.config.Load(), tc.pod, *lws)
+			if err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			if deleted != tc.wantDeleted {
+				t.Errorf("expected deleted %t, got %t", tc.wantDeleted, deleted)
+			}
+			var got corev1.Pod
+			err = client.Get(context.TODO(), types.NamespacedName{Name: leader.Name, Namespace: leader.Namespace}, &got)
+			if leaderDeleted := apierrors.IsNotFound(err); leaderDeleted != tc.wantLeaderDeleted {
+				t.Errorf("expected leader deleted %t, got %t", tc.wantLeaderDeleted, leaderDeleted)
+			}
+		})
+	}
+}
+
+func TestGroupRecreationsBackoff(t *testing.T) {
+	backoff := &configapi.RecreationBackoff{
+		Base:          &v1.Duration{Duration: 10 * time.Second},
+		Cap:           &v1.Duration{Duration: 40 * time.Second},
+		JitterPercent: ptr.To[int32](0),
+		ResetAfter:    &v1.Duration{Duration: 10 * time.Minute},
+	}
+	key := types.NamespacedName{Name: "test-sample-0", Namespace: "default"}
+	var recreations groupRecreations
+	now := time.Now()
+
+	if delay := recreations.delay(key, backoff, now); delay != 0 {
+		t.Fatalf("Expected the first recreation not to be delayed, got %v", delay)
+	}
+	// The successive recreations are delayed increasingly, up to the cap.
+	for _, want := range []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, 40 * time.Second} {
+		if got := recreations.record(key, backoff, now); got != want {
+			t.Fatalf("Expected the next recreation to be delayed by %v, got %v", want, got)
+		}
+		if delay := recreations.delay(key, backoff, now.Add(want/2)); delay != want/2 {
+			t.Errorf("Expected the recreation to be deferred by %v, got %v", want/2, delay)
+		}
+		now = now.Add(want)
+		if delay := recreations.delay(key, backoff, now); delay != 0 {
+			t.Errorf("Expected the recreation not to be deferred once the delay elapsed, got %v", delay)
+		}
+	}
+
+	// The backoff is reset once the group ran without recreation for resetAfter.
+	now = now.Add(backoff.ResetAfter.Duration)
+	if delay := recreations.delay(key, backoff, now); delay != 0 {
+		t.Fatalf("Expected the recreation not to be delayed after the reset, got %v", delay)
+	}
+	if got := recreations.record(key, backoff, now); got != backoff.Base.Duration {
+		t.Errorf("Expected the next recreation to be delayed by the base after the reset, got %v", got)
+	}
+
+	// The jitter is bounded by its percentage and the cap.
+	backoff.JitterPercent = ptr.To[int32](50)
+	other := types.NamespacedName{Name: "test-sample-1", Namespace: "default"}
+	for range 10 {
+		recreations.forget(other)
+		if got := recreations.record(other, backoff, now); got < 10*time.Second || got > 15*time.Second {
+			t.Errorf("Expected the jittered delay within [10s, 15s], got %v", got)
+		}
+	}
+}
+
+func TestHandleRestartPolicyRecreationBackoff(t *testing.T) {
+	lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").RestartPolicy(leaderworkerset.RecreateGroupOnPodRestart).Obj()
+	leader := corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "test-sample-0",
+			Namespace: "default",
+			Labels: map[string]string{
+				leaderworkerset.WorkerIndexLabelKey: "0",
+				leaderworkerset.SetNameLabelKey:     "test-sample",
+				leaderworkerset.GroupIndexLabelKey:  "0",
+				leaderworkerset.RevisionKey:         "test-revision",
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "leader", RestartCount: 1}},
+		},
+	}
+	client := fake.NewClientBuilder().Build()
+	r := NewPodReconciler(client, nil, record.NewFakeRecorder(10), &configapi.Configuration{
+		RecreationBackoff: &configapi.RecreationBackoff{
+			Base:          &v1.Duration{Duration: time.Minute},
+			Cap:           &v1.Duration{Duration: 5 * time.Minute},
+			JitterPercent: ptr.To[int32](0),
+			ResetAfter:    &v1.Duration{Duration: 10 * time.Minute},
+		},
+	})
+
+	for i, wantDeleted := range []bool{true, false} {
+		// The statefulset recreated the leader pod, which restarted again.
+		if err := client.Create(context.TODO(), leader.DeepCopy()); err != nil && !apierrors.IsAlreadyExists(err) {
+			t.Fatal(err)
+		}
+		deleted, recreateAfter, err := r.handleRestartPolicy(context.TODO(), r.config.Load(), leader, *lws)
+		if err != nil {
+			t.Fatalf("failed with error: %s", err.Error())
+		}
+		if deleted != wantDeleted {
+			t.Errorf("recreation %d: expected deleted %t, got %t", i, wantDeleted, deleted)
+		}
+		if wantDeleted && recreateAfter != 0 {
+			t.Errorf("recreation %d: expected the recreation not to be deferred, got %v", i, recreateAfter)
+		}
+		if !wantDeleted && (recreateAfter <= 0 || recreateAfter > time.Minute) {
+			t.Errorf("recreation %d: expected the recreation to be deferred by up to 1m, got %v", i, recreateAfter)
+		}
+	}
+}
+
+func TestReconcileForgetsRecreationsOfRemovedGroups(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	backoff := &configapi.RecreationBackoff{
+		Base:          &v1.Duration{Duration: time.Minute},
+		Cap:           &v1.Duration{Duration: 5 * time.Minute},
+		JitterPercent: ptr.To[int32](0),
+		ResetAfter:    &v1.Duration{Duration: 10 * time.Minute},
+	}
+	lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").Replica(1).Obj()
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(lws).Build()
+	r := NewPodReconciler(c, scheme, record.NewFakeRecorder(10), &configapi.Configuration{RecreationBackoff: backoff})
+
+	now := time.Now()
+	kept := types.NamespacedName{Name: "test-sample-0", Namespace: "default"}
+	removed := types.NamespacedName{Name: "test-sample-1", Namespace: "default"}
+	for _, key := range []types.NamespacedName{kept, removed} {
+		r.recreations.record(key, backoff, now)
+		revisionRecreations.record(key, "test-revision", time.Hour, now)
+	}
+
+	// The leader pod of group 0 is being recreated, the one of group 1 was removed on scale-down.
+	for _, key := range []types.NamespacedName{kept, removed} {
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+			t.Fatalf("failed with error: %s", err.Error())
+		}
+	}
+	if delay := r.recreations.delay(kept, backoff, now); delay == 0 {
+		t.Errorf("Expected the recreations of the group being recreated to be kept")
+	}
+	if count := revisionRecreations.count(kept, "test-revision", time.Hour, now); count != 1 {
+		t.Errorf("Expected the revision recreations of the group being recreated to be kept, got %d", count)
+	}
+	if delay := r.recreations.delay(removed, backoff, now); delay != 0 {
+		t.Errorf("Expected the recreations of the removed group to be forgotten, got a delay of %v", delay)
+	}
+	if count := revisionRecreations.count(removed, "test-revision", time.Hour, now); count != 0 {
+		t.Errorf("Expected the revision recreations of the removed group to be forgotten, got %d", count)
+	}
+	revisionRecreations.forget(kept)
+}
+
+// suffixNamer names the services after the default names with a suffix.
+type suffixNamer struct{}
+
+func (suffixNamer) ServiceName(lwsName string) string {
+	return lwsName + "-svc"
+}
+
+func (suffixNamer) GroupServiceName(leaderPodName string) string {
+	return leaderPodName + "-svc"
+}
+
+func TestCustomNamer(t *testing.T) {
+	tests := []struct {
+		name            string
+		subdomainPolicy leaderworkerset.SubdomainPolicy
+		wantServiceName string
+	}{
+		{
+			name:            "shared subdomain",
+			subdomainPolicy: leaderworkerset.SubdomainShared,
+			wantServiceName: "test-sample-svc",
+		},
+		{
+			name:            "unique subdomain per replica",
+			subdomainPolicy: leaderworkerset.SubdomainUniquePerReplica,
+			wantServiceName: "test-sample-0-svc",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").
+				Replica(1).
+				Size(2).
+				SubdomainPolicy(tc.subdomainPolicy).Obj()
+			revision, err := revisionutils.NewRevision(context.TODO(), fake.NewClientBuilder().Build(), lws, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			revisionKey := revisionutils.GetRevisionKey(revision)
+
+			// The leader statefulset always uses the shared service, the group services are
+			// created by the pod controller.
+			leaderStatefulSetConfig, err := constructLeaderStatefulSetApplyConfiguration(lws, 0, 1, revisionKey, suffixNamer{})
+			if err != nil {
+				t.Fatalf("failed with error %s", err.Error())
+			}
+			if got := *leaderStatefulSetConfig.Spec.ServiceName; got != "test-sample-svc" {
+				t.Errorf("Expected the leader statefulset to use the test-sample-svc service, got %s", got)
+			}
+
+			leaderPod := corev1.Pod{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "test-sample-0",
+					Namespace: "default",
+					Labels: map[string]string{
+						leaderworkerset.WorkerIndexLabelKey:     "0",
+						leaderworkerset.SetNameLabelKey:         "test-sample",
+						leaderworkerset.GroupIndexLabelKey:      "0",
+						leaderworkerset.GroupUniqueHashLabelKey: "test-key",
+						leaderworkerset.RevisionKey:             revisionKey,
+					},
+				},
+			}
+			workerStatefulSetConfig, err := constructWorkerStatefulSetApplyConfiguration(leaderPod, *lws, revision, suffixNamer{})
+			if err != nil {
+				t.Fatalf("failed with error %s", err.Error())
+			}
+			if got := *workerStatefulSetConfig.Spec.ServiceName; got != tc.wantServiceName {
+				t.Errorf("Expected the worker statefulset to use the %s service, got %s", tc.wantServiceName, got)
+			}
+			// The workers are named after their statefulset, starting at the ordinal 1.
+			wantAddress := fmt.Sprintf("%s-1.%s.default", *workerStatefulSetConfig.Name, *workerStatefulSetConfig.Spec.ServiceName)
+			if got := podutils.GroupPodAddress(lws, suffixNamer{}, 0, 1); got != wantAddress {
+				t.Errorf("Expected the address of the first worker to be %s, got %s", wantAddress, got)
+			}
+		})
+	}
+}
+
+func TestWorkerStatefulSetCreateFailureRequeuesOnlyItsGroup(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").
+		Replica(4).
+		Size(2).
+		WorkerTemplateSpec(wrappers.MakeWorkerPodSpec()).Obj()
+	revision, err := revisionutils.NewRevision(ctx, fake.NewClientBuilder().Build(), lws, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	revisionKey := revisionutils.GetRevisionKey(revision)
+
+	objects := []client.Object{lws, revision}
+	for i := range 4 {
+		objects = append(objects, &corev1.Pod{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      fmt.Sprintf("test-sample-%d", i),
+				Namespace: "default",
+				UID:       types.UID(fmt.Sprintf("leader-%d", i)),
+				Labels: map[string]string{
+					leaderworkerset.WorkerIndexLabelKey:     "0",
+					leaderworkerset.SetNameLabelKey:         "test-sample",
+					leaderworkerset.GroupIndexLabelKey:      strconv.Itoa(i),
+					leaderworkerset.GroupUniqueHashLabelKey: fmt.Sprintf("key-%d", i),
+					leaderworkerset.RevisionKey:             revisionKey,
+				},
+			},
+		})
+	}
+
+	// The fake client doesn't support apply patches, the worker statefulsets are created instead,
+	// except the one of group 3 which keeps failing.
+	applies := map[string]int{}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if patch.Type() != types.ApplyPatchType {
+				return c.Patch(ctx, obj, patch, opts...)
+			}
+			applies[obj.GetName()]++
+			if obj.GetName() == "test-sample-3" {
+				return errors.New("admission denied")
+			}
+			var sts appsv1.StatefulSet
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.(*unstructured.Unstructured).Object, &sts); err != nil {
+				return err
+			}
+			return c.Create(ctx, &sts)
+		},
+	}).Build()
+	r := NewPodReconciler(c, scheme, record.NewFakeRecorder(100), &configapi.Configuration{})
+
+	// Each group is reconciled through the request of its leader pod, only the failing ones are
+	// requeued by the controller, while the others are only reconciled again on their own events.
+	var requeued []string
+	for i := range 4 {
+		requeued = append(requeued, fmt.Sprintf("test-sample-%d", i))
+	}
+	for range 3 {
+		var failed []string
+		for _, name := range requeued {
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: name}}); err != nil {
+				failed = append(failed, name)
+			}
+		}
+		if diff := cmp.Diff([]string{"test-sample-3"}, failed); diff != "" {
+			t.Fatalf("Unexpected failed groups (-want +got):\n%s", diff)
+		}
+		requeued = failed
+	}
+
+	wantApplies := map[string]int{
+		"test-sample-0": 1,
+		"test-sample-1": 1,
+		"test-sample-2": 1,
+		"test-sample-3": 3,
+	}
+	if diff := cmp.Diff(wantApplies, applies); diff != "" {
+		t.Errorf("Unexpected worker statefulset applies (-want +got):\n%s", diff)
+	}
+
+	// Reconciling a healthy group again doesn't apply its worker statefulset anymore.
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-sample-0"}}); err != nil {
+		t.Fatalf("failed with error: %s", err.Error())
+	}
+	if applies["test-sample-0"] != 1 {
+		t.Errorf("Expected the worker statefulset of group 0 to be applied once, got %d", applies["test-sample-0"])
+	}
+}
+
+func TestReconcileDuringLeaderWorkerSetDeletion(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").
+		Replica(1).
+		Size(2).
+		WorkerTemplateSpec(wrappers.MakeWorkerPodSpec()).Obj()
+	revision, err := revisionutils.NewRevision(ctx, fake.NewClientBuilder().Build(), lws, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The fake client only accepts objects being deleted if they carry a finalizer.
+	lws.Finalizers = []string{"example.com/finalizer"}
+	lws.DeletionTimestamp = ptr.To(v1.Now())
+	leader := &corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "test-sample-0",
+			Namespace: "default",
+			UID:       "leader-0",
+			Labels: map[string]string{
+				leaderworkerset.WorkerIndexLabelKey:     "0",
+				leaderworkerset.SetNameLabelKey:         "test-sample",
+				leaderworkerset.GroupIndexLabelKey:      "0",
+				leaderworkerset.GroupUniqueHashLabelKey: "key-0",
+				leaderworkerset.RevisionKey:             revisionutils.GetRevisionKey(revision),
+			},
+		},
+	}
+
+	var applied []string
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(lws, revision, leader).WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if patch.Type() != types.ApplyPatchType {
+				return c.Patch(ctx, obj, patch, opts...)
+			}
+			applied = append(applied, obj.GetName())
+			return nil
+		},
+	}).Build()
+	r := NewPodReconciler(c, scheme, record.NewFakeRecorder(100), &configapi.Configuration{})
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: leader.Name}}); err != nil {
+		t.Fatalf("failed with error: %s", err.Error())
+	}
+	if len(applied) != 0 {
+		t.Errorf("Expected no worker statefulset to be applied during the deletion, got %v", applied)
+	}
+}
+
+func TestForceDeleteStuckTerminatingPod(t *testing.T) {
+	tests := []struct {
+		name              string
+		featureEnabled    bool
+		terminatingSince  time.Duration
+		lwsDeleting       bool
+		wantForceDeletion bool
+		wantRequeue       bool
+	}{
+		{
+			name:             "feature disabled",
+			terminatingSince: time.Hour,
+		},
+		{
+			name:             "terminating within the timeout",
+			featureEnabled:   true,
+			terminatingSince: time.Minute,
+			wantRequeue:      true,
+		},
+		{
+			name:              "stuck terminating past the timeout",
+			featureEnabled:    true,
+			terminatingSince:  time.Hour,
+			wantForceDeletion: true,
+		},
+		{
+			name:              "stuck terminating while the lws is deleted",
+			featureEnabled:    true,
+			terminatingSince:  time.Hour,
+			lwsDeleting:       true,
+			wantForceDeletion: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			featuregatetesting.SetFeatureGateDuringTest(t, features.DefaultFeatureGate, features.ForceDeleteStuckTerminatingPods, tc.featureEnabled)
+			ctx := context.TODO()
+			scheme := runtime.NewScheme()
+			if err := clientgoscheme.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+			if err := leaderworkerset.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+			lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").
+				Replica(1).
+				Size(2).
+				WorkerTemplateSpec(wrappers.MakeWorkerPodSpec()).Obj()
+			revision, err := revisionutils.NewRevision(ctx, fake.NewClientBuilder().Build(), lws, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.lwsDeleting {
+				// The fake client only accepts objects being deleted if they carry a finalizer.
+				lws.Finalizers = []string{"example.com/finalizer"}
+				lws.DeletionTimestamp = ptr.To(v1.Now())
+			}
+			makeLeader := func() *corev1.Pod {
+				return &corev1.Pod{
+					ObjectMeta: v1.ObjectMeta{
+						Name:      "test-sample-0",
+						Namespace: "default",
+						UID:       "leader-0",
+						Labels: map[string]string{
+							leaderworkerset.WorkerIndexLabelKey:     "0",
+							leaderworkerset.SetNameLabelKey:         "test-sample",
+							leaderworkerset.GroupIndexLabelKey:      "0",
+							leaderworkerset.GroupUniqueHashLabelKey: "key-0",
+							leaderworkerset.RevisionKey:             revisionutils.GetRevisionKey(revision),
+						},
+					},
+					Spec: corev1.PodSpec{NodeName: "unreachable-node"},
+				}
+			}
+			// The node of the pod is unreachable, its kubelet never confirms the deletion.
+			stuckLeader := makeLeader()
+			stuckLeader.Finalizers = []string{"example.com/finalizer"}
+			stuckLeader.DeletionTimestamp = ptr.To(v1.NewTime(time.Now().Add(-tc.terminatingSince)))
+
+			var forceDeleted bool
+			var applied []string
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(lws, revision, stuckLeader).WithInterceptorFuncs(interceptor.Funcs{
+				Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+					deleteOpts := &client.DeleteOptions{}
+					deleteOpts.ApplyOptions(opts)
+					if ptr.Deref(deleteOpts.GracePeriodSeconds, -1) != 0 {
+						return c.Delete(ctx, obj, opts...)
+					}
+					// Like the API server, a deletion without grace period removes the pod at once.
+					forceDeleted = true
+					obj.SetFinalizers(nil)
+					return c.Update(ctx, obj)
+				},
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					if patch.Type() != types.ApplyPatchType {
+						return c.Patch(ctx, obj, patch, opts...)
+					}
+					applied = append(applied, obj.GetName())
+					return nil
+				},
+			}).Build()
+			recorder := record.NewFakeRecorder(100)
+			r := NewPodReconciler(c, scheme, recorder, &configapi.Configuration{
+				StuckTerminatingPodTimeout: &v1.Duration{Duration: 10 * time.Minute},
+			})
+			request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: stuckLeader.Name}}
+			result, err := r.Reconcile(ctx, request)
+			if err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			if forceDeleted != tc.wantForceDeletion {
+				t.Errorf("Expected the pod to be force deleted: %v, got %v", tc.wantForceDeletion, forceDeleted)
+			}
+			if gotRequeue := result.RequeueAfter > 0; gotRequeue != tc.wantRequeue {
+				t.Errorf("Expected a requeue: %v, got %v", tc.wantRequeue, result.RequeueAfter)
+			}
+			if len(applied) != 0 {
+				t.Errorf("Expected no worker statefulset to be applied for the terminating leader, got %v", applied)
+			}
+			if !tc.wantForceDeletion {
+				return
+			}
+			if len(recorder.Events) != 1 {
+				t.Fatalf("Expected one event, got %d", len(recorder.Events))
+			}
+			if event := <-recorder.Events; !strings.Contains(event, ForceDeletedStuckPod) || !strings.Contains(event, "unreachable-node") {
+				t.Errorf("Unexpected event %q", event)
+			}
+			if tc.lwsDeleting {
+				return
+			}
+
+			// The leader statefulset recreates the leader pod, and with it the group.
+			if err := c.Create(ctx, makeLeader()); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := r.Reconcile(ctx, request); err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			if diff := cmp.Diff([]string{"test-sample-0"}, applied); diff != "" {
+				t.Errorf("Unexpected worker statefulsets applied (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestForceDeleteStuckTerminatingPodDryRun(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, features.DefaultFeatureGate, features.ForceDeleteStuckTerminatingPods, false)
+	featuregatetesting.SetFeatureGateDuringTest(t, features.DefaultFeatureGate, features.DryRunReconcile, true)
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").Replica(2).Size(2).Obj()
+	makeStuckLeader := func(name string, terminatingSince time.Duration) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				UID:       types.UID(name),
+				Labels: map[string]string{
+					leaderworkerset.WorkerIndexLabelKey: "0",
+					leaderworkerset.SetNameLabelKey:     "test-sample",
+					leaderworkerset.GroupIndexLabelKey:  "0",
+				},
+				Finalizers:        []string{"example.com/finalizer"},
+				DeletionTimestamp: ptr.To(v1.NewTime(time.Now().Add(-terminatingSince))),
+			},
+			Spec: corev1.PodSpec{NodeName: "unreachable-node"},
+		}
+	}
+	stuckLeader := makeStuckLeader("test-sample-0", time.Hour)
+	terminatingLeader := makeStuckLeader("test-sample-1", time.Minute)
+
+	var writes []string
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(lws, stuckLeader, terminatingLeader).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			writes = append(writes, "create "+obj.GetName())
+			return nil
+		},
+		Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+			writes = append(writes, "delete "+obj.GetName())
+			return nil
+		},
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			writes = append(writes, "update "+obj.GetName())
+			return nil
+		},
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			writes = append(writes, "patch "+obj.GetName())
+			return nil
+		},
+	}).Build()
+	recorder := record.NewFakeRecorder(100)
+	r := NewPodReconciler(c, scheme, recorder, &configapi.Configuration{
+		StuckTerminatingPodTimeout: &v1.Duration{Duration: 10 * time.Minute},
+	})
+	counter := metrics.DryRunActions.WithLabelValues(string(features.ForceDeleteStuckTerminatingPods), "ForceDeletePod")
+	before := testutil.ToFloat64(counter)
+
+	// The pod is only counted once across its reconciliations.
+	for range 2 {
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(stuckLeader)})
+		if err != nil {
+			t.Fatalf("failed with error: %s", err.Error())
+		}
+		if result.RequeueAfter != 0 {
+			t.Errorf("Expected no requeue for the counted pod, got %v", result.RequeueAfter)
+		}
+	}
+	// The pod terminating within the timeout is requeued to be counted once it exceeds it.
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(terminatingLeader)})
+	if err != nil {
+		t.Fatalf("failed with error: %s", err.Error())
+	}
+	if result.RequeueAfter <= 0 || result.RequeueAfter > 9*time.Minute {
+		t.Errorf("Expected a requeue within 9m, got %v", result.RequeueAfter)
+	}
+
+	if got := testutil.ToFloat64(counter) - before; got != 1 {
+		t.Errorf("Expected 1 dry-run force deletion, got %v", got)
+	}
+	if len(writes) != 0 {
+		t.Errorf("Expected no write in dry-run, got %v", writes)
+	}
+	if len(recorder.Events) != 0 {
+		t.Errorf("Expected no event in dry-run, got %d", len(recorder.Events))
+	}
+	var pod corev1.Pod
+	if err := c.Get(ctx, client.ObjectKeyFromObject(stuckLeader), &pod); err != nil {
+		t.Errorf("Expected the stuck pod to be kept, got %v", err)
+	}
+}
+
+func TestTemplateHostNetworkAndDNS(t *testing.T) {
+	workerPodSpec := wrappers.MakeWorkerPodSpec()
+	workerPodSpec.HostNetwork = true
+	workerPodSpec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+	workerPodSpec.DNSConfig = &corev1.PodDNSConfig{Searches: []string{"hpc.example.com"}}
+	lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").
+		Replica(1).
+		Size(2).
+		WorkerTemplateSpec(workerPodSpec).Obj()
+	revision, err := revisionutils.NewRevision(context.TODO(), fake.NewClientBuilder().Build(), lws, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	revisionKey := revisionutils.GetRevisionKey(revision)
+
+	check := func(role string, podSpec *coreapplyv1.PodSpecApplyConfiguration) {
+		if !ptr.Deref(podSpec.HostNetwork, false) {
+			t.Errorf("Expected the %s pods to use the host network", role)
+		}
+		if got := ptr.Deref(podSpec.DNSPolicy, ""); got != corev1.DNSClusterFirstWithHostNet {
+			t.Errorf("Expected the %s pods to use the %s dns policy, got %s", role, corev1.DNSClusterFirstWithHostNet, got)
+		}
+		if podSpec.DNSConfig == nil || !cmp.Equal(podSpec.DNSConfig.Searches, []string{"hpc.example.com"}) {
+			t.Errorf("Expected the %s pods to use the dns config, got %+v", role, podSpec.DNSConfig)
+		}
+	}
+
+	// Without a leader template, the leader pods use the host network as well.
+	leaderStatefulSetConfig, err := constructLeaderStatefulSetApplyConfiguration(lws, 0, 1, revisionKey, naming.Default())
+	if err != nil {
+		t.Fatalf("failed with error %s", err.Error())
+	}
+	check("leader", leaderStatefulSetConfig.Spec.Template.Spec)
+
+	leaderPod := corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "test-sample-0",
+			Namespace: "default",
+			Labels: map[string]string{
+				leaderworkerset.WorkerIndexLabelKey:     "0",
+				leaderworkerset.SetNameLabelKey:         "test-sample",
+				leaderworkerset.GroupIndexLabelKey:      "0",
+				leaderworkerset.GroupUniqueHashLabelKey: "test-key",
+				leaderworkerset.RevisionKey:             revisionKey,
+			},
+		},
+	}
+	workerStatefulSetConfig, err := constructWorkerStatefulSetApplyConfiguration(leaderPod, *lws, revision, naming.Default())
+	if err != nil {
+		t.Fatalf("failed with error %s", err.Error())
+	}
+	check("worker", workerStatefulSetConfig.Spec.Template.Spec)
+}
+
+func TestAllLeadersReadyStartupPolicy(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, features.DefaultFeatureGate, features.AllLeadersReadyStartupPolicy, true)
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").
+		Replica(2).
+		Size(2).
+		StartupPolicy(leaderworkerset.AllLeadersReadyStartupPolicy).
+		WorkerTemplateSpec(wrappers.MakeWorkerPodSpec()).Obj()
+	revision, err := revisionutils.NewRevision(ctx, fake.NewClientBuilder().Build(), lws, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	revisionKey := revisionutils.GetRevisionKey(revision)
+	makeLeaderPod := func(groupIndex int, ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      fmt.Sprintf("test-sample-%d", groupIndex),
+				Namespace: "default",
+				UID:       types.UID(fmt.Sprintf("leader-%d", groupIndex)),
+				Labels: map[string]string{
+					leaderworkerset.WorkerIndexLabelKey:     "0",
+					leaderworkerset.SetNameLabelKey:         "test-sample",
+					leaderworkerset.GroupIndexLabelKey:      strconv.Itoa(groupIndex),
+					leaderworkerset.GroupUniqueHashLabelKey: fmt.Sprintf("key-%d", groupIndex),
+					leaderworkerset.RevisionKey:             revisionKey,
+				},
+			},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+			},
+		}
+	}
+	readyLeader, unreadyLeader := makeLeaderPod(0, corev1.ConditionTrue), makeLeaderPod(1, corev1.ConditionFalse)
+
+	var applied []string
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(lws, revision, readyLeader, unreadyLeader).WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if patch.Type() != types.ApplyPatchType {
+				return c.Patch(ctx, obj, patch, opts...)
+			}
+			applied = append(applied, obj.GetName())
+			return nil
+		},
+	}).Build()
+	r := NewPodReconciler(c, scheme, record.NewFakeRecorder(100), &configapi.Configuration{})
+	reconcileLeader := func(name string) {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: name}}); err != nil {
+			t.Fatalf("failed with error: %s", err.Error())
+		}
+	}
+
+	// The leader of group 0 is ready, but its workers wait for the leader of group 1.
+	reconcileLeader("test-sample-0")
+	reconcileLeader("test-sample-1")
+	if len(applied) != 0 {
+		t.Fatalf("Expected the worker statefulsets to be held, got %v applied", applied)
+	}
+
+	// The leader of group 1 becoming ready requeues the leader of group 0.
+	oldLeader := unreadyLeader.DeepCopy()
+	unreadyLeader.Status.Conditions[0].Status = corev1.ConditionTrue
+	if err := c.Status().Update(ctx, unreadyLeader); err != nil {
+		t.Fatal(err)
+	}
+	if !leaderReadinessChanged.Update(event.UpdateEvent{ObjectOld: oldLeader, ObjectNew: unreadyLeader}) {
+		t.Errorf("Expected the readiness change of the leader pod to be handled")
+	}
+	wantRequests := []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-sample-0"}}}
+	if diff := cmp.Diff(wantRequests, r.leaderPodsWaitingForLeader(ctx, unreadyLeader)); diff != "" {
+		t.Errorf("Unexpected requests (-want +got):\n%s", diff)
+	}
+
+	reconcileLeader("test-sample-0")
+	reconcileLeader("test-sample-1")
+	if diff := cmp.Diff([]string{"test-sample-0", "test-sample-1"}, applied); diff != "" {
+		t.Errorf("Unexpected worker statefulset applies (-want +got):\n%s", diff)
+	}
+}
+
+func TestLeaderReadyPollInterval(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").
+		Size(2).
+		StartupPolicy(leaderworkerset.LeaderReadyStartupPolicy).
+		WorkerTemplateSpec(wrappers.MakeWorkerPodSpec()).Obj()
+	leader := &corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "test-sample-0",
+			Namespace: "default",
+			Labels: map[string]string{
+				leaderworkerset.WorkerIndexLabelKey: "0",
+				leaderworkerset.SetNameLabelKey:     "test-sample",
+				leaderworkerset.GroupIndexLabelKey:  "0",
+			},
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		cfg              *configapi.Configuration
+		wantRequeueAfter time.Duration
+	}{
+		{
+			name: "waits for the readiness changes by default",
+			cfg:  &configapi.Configuration{},
+		},
+		{
+			name:             "configured interval",
+			cfg:              &configapi.Configuration{LeaderReadyPollInterval: &v1.Duration{Duration: 5 * time.Second}},
+			wantRequeueAfter: 5 * time.Second,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(lws.DeepCopy(), leader.DeepCopy()).Build()
+			r := NewPodReconciler(c, scheme, record.NewFakeRecorder(10), tc.cfg)
+			result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: leader.Name}})
+			if err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			if diff := cmp.Diff(ctrl.Result{RequeueAfter: tc.wantRequeueAfter}, result); diff != "" {
+				t.Errorf("Unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWorkerStatefulSetGroupResourceOverrides(t *testing.T) {
+	lws := wrappers.BuildLeaderWorkerSet("default").Replica(3).Obj()
+	lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec.Containers[0].Resources = corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("1Gi")},
+	}
+	lws.Spec.LeaderWorkerTemplate.GroupResourceOverrides = []leaderworkerset.GroupResourceOverride{{
+		GroupIndices: []int32{0, 2},
+		Containers: []leaderworkerset.ContainerResourceOverride{{
+			Name: "leader",
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8")},
+			},
+		}},
+	}}
+	revision, err := revisionutils.NewRevision(context.TODO(), fake.NewClientBuilder().Build(), lws, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	overridden := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4"), corev1.ResourceMemory: resource.MustParse("1Gi")},
+		Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8")},
+	}
+	original := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("1Gi")},
+	}
+
+	for groupIndex, want := range []corev1.ResourceRequirements{overridden, original, overridden} {
+		t.Run(fmt.Sprintf("group %d", groupIndex), func(t *testing.T) {
+			leaderPod := corev1.Pod{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      fmt.Sprintf("test-sample-%d", groupIndex),
+					Namespace: "default",
+					Labels: map[string]string{
+						leaderworkerset.WorkerIndexLabelKey:     "0",
+						leaderworkerset.SetNameLabelKey:         "test-sample",
+						leaderworkerset.GroupIndexLabelKey:      strconv.Itoa(groupIndex),
+						leaderworkerset.GroupUniqueHashLabelKey: "test-key",
+						leaderworkerset.RevisionKey:             revisionutils.GetRevisionKey(revision),
+					},
+				},
+			}
+			sts, err := constructWorkerStatefulSetApplyConfiguration(leaderPod, *lws, revision, naming.Default())
+			if err != nil {
+				t.Fatal(err)
+			}
+			resources := sts.Spec.Template.Spec.Containers[0].Resources
+			got := corev1.ResourceRequirements{}
+			if resources.Requests != nil {
+				got.Requests = *resources.Requests
+			}
+			if resources.Limits != nil {
+				got.Limits = *resources.Limits
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Unexpected worker resources (-want +got):\n%s", diff)
+			}
+		})
+	}
+	if diff := cmp.Diff(original, lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec.Containers[0].Resources); diff != "" {
+		t.Errorf("The worker template was mutated (-want +got):\n%s", diff)
+	}
+}
+
+func TestWorkerStatefulSetCoordinatorTemplate(t *testing.T) {
+	lws := wrappers.BuildLeaderWorkerSet("default").Replica(2).Obj()
+	lws.Spec.LeaderWorkerTemplate.CoordinatorTemplate = &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "coordinator", Image: "coordinator"}}},
+	}
+	revision, err := revisionutils.NewRevision(context.TODO(), fake.NewClientBuilder().Build(), lws, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for groupIndex, wantCoordinator := range []bool{true, false} {
+		t.Run(fmt.Sprintf("group %d", groupIndex), func(t *testing.T) {
+			leaderPod := corev1.Pod{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      fmt.Sprintf("test-sample-%d", groupIndex),
+					Namespace: "default",
+					Labels: map[string]string{
+						leaderworkerset.WorkerIndexLabelKey:     "0",
+						leaderworkerset.SetNameLabelKey:         "test-sample",
+						leaderworkerset.GroupIndexLabelKey:      strconv.Itoa(groupIndex),
+						leaderworkerset.GroupUniqueHashLabelKey: "test-key",
+						leaderworkerset.RevisionKey:             revisionutils.GetRevisionKey(revision),
+					},
+				},
+			}
+			sts, err := constructWorkerStatefulSetApplyConfiguration(leaderPod, *lws, revision, naming.Default())
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantImage := lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec.Containers[0].Image
+			if wantCoordinator {
+				wantImage = "coordinator"
+			}
+			if got := *sts.Spec.Template.Spec.Containers[0].Image; got != wantImage {
+				t.Errorf("Expected the workers to use the %s image, got %s", wantImage, got)
+			}
+			_, coordinatorLabel := sts.Spec.Template.Labels[leaderworkerset.CoordinatorGroupLabelKey]
+			if coordinatorLabel != wantCoordinator {
+				t.Errorf("Expected the coordinator group label to be %t, got %t", wantCoordinator, coordinatorLabel)
+			}
+		})
+	}
+}
+
+func TestUpdateGroupReadyCondition(t *testing.T) {
+	workerSts := func(readyReplicas int32) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "test-sample-0",
+				Namespace: "default",
+				Labels: map[string]string{
+					leaderworkerset.SetNameLabelKey:    "test-sample",
+					leaderworkerset.GroupIndexLabelKey: "0",
+				},
+			},
+			Spec: appsv1.StatefulSetSpec{Replicas: ptr.To[int32](3)},
+			Status: appsv1.StatefulSetStatus{
+				Replicas:        3,
+				ReadyReplicas:   readyReplicas,
+				CurrentRevision: "test-sample-0-1",
+				UpdateRevision:  "test-sample-0-1",
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		conditions []corev1.PodCondition
+		workerSts  *appsv1.StatefulSet
+		wantStatus corev1.ConditionStatus
+	}{
+		{
+			name:       "workers ready",
+			workerSts:  workerSts(3),
+			wantStatus: corev1.ConditionTrue,
+		},
+		{
+			name:       "workers not created yet",
+			wantStatus: corev1.ConditionFalse,
+		},
+		{
+			name: "worker down pulls the leader out of the endpoints",
+			conditions: []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+				{Type: leaderworkerset.GroupReadyPodConditionType, Status: corev1.ConditionTrue},
+			},
+			workerSts:  workerSts(2),
+			wantStatus: corev1.ConditionFalse,
+		},
+		{
+			name: "workers ready again",
+			conditions: []corev1.PodCondition{
+				{Type: leaderworkerset.GroupReadyPodConditionType, Status: corev1.ConditionFalse},
+			},
+			workerSts:  workerSts(3),
+			wantStatus: corev1.ConditionTrue,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			leader := &corev1.Pod{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "test-sample-0",
+					Namespace: "default",
+					Labels: map[string]string{
+						leaderworkerset.WorkerIndexLabelKey: "0",
+						leaderworkerset.SetNameLabelKey:     "test-sample",
+						leaderworkerset.GroupIndexLabelKey:  "0",
+					},
+				},
+				Spec: corev1.PodSpec{
+					ReadinessGates: []corev1.PodReadinessGate{{ConditionType: leaderworkerset.GroupReadyPodConditionType}},
+				},
+				Status: corev1.PodStatus{Conditions: tc.conditions},
+			}
+			builder := fake.NewClientBuilder().WithObjects(leader).WithStatusSubresource(&corev1.Pod{}).WithInterceptorFuncs(podApplyAsStrategicMerge)
+			if tc.workerSts != nil {
+				builder.WithObjects(tc.workerSts)
+			}
+			client := builder.Build()
+			r := NewPodReconciler(client, nil, record.NewFakeRecorder(10), &configapi.Configuration{})
+			lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").Size(4).Obj()
+
+			if !hasGroupReadinessGate(*leader) {
+				t.Fatalf("expected the leader pod to have the group readiness gate")
+			}
+			if err := r.updateGroupReadyCondition(context.TODO(), leader.DeepCopy(), lws); err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			var got corev1.Pod
+			if err := client.Get(context.TODO(), types.NamespacedName{Name: leader.Name, Namespace: leader.Namespace}, &got); err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			_, condition := podutils.GetPodCondition(&got.Status, leaderworkerset.GroupReadyPodConditionType)
+			if condition == nil || condition.Status != tc.wantStatus {
+				t.Errorf("expected the group ready condition %s, got %v", tc.wantStatus, condition)
+			}
+			// The other conditions, e.g. the ones of the kubelet, are kept.
+			if len(got.Status.Conditions) != max(len(tc.conditions), 1) {
+				t.Errorf("unexpected conditions %v", got.Status.Conditions)
+			}
+		})
+	}
+}
+
+func TestWorkerStatefulSetCandidateLabel(t *testing.T) {
+	for _, leaderless := range []bool{false, true} {
+		t.Run(fmt.Sprintf("leaderless %v", leaderless), func(t *testing.T) {
+			lws := wrappers.BuildLeaderWorkerSet("default").Obj()
+			if leaderless {
+				lws.Annotations = map[string]string{leaderworkerset.LeaderlessAnnotationKey: "true"}
+				lws.Spec.LeaderWorkerTemplate.LeaderTemplate = nil
+			}
+			revision, err := revisionutils.NewRevision(context.TODO(), fake.NewClientBuilder().Build(), lws, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			leaderPod := corev1.Pod{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "test-sample-0",
+					Namespace: "default",
+					Labels: map[string]string{
+						leaderworkerset.WorkerIndexLabelKey:     "0",
+						leaderworkerset.SetNameLabelKey:         "test-sample",
+						leaderworkerset.GroupIndexLabelKey:      "0",
+						leaderworkerset.GroupUniqueHashLabelKey: "test-key",
+						leaderworkerset.RevisionKey:             revisionutils.GetRevisionKey(revision),
+					},
+				},
+			}
+			sts, err := constructWorkerStatefulSetApplyConfiguration(leaderPod, *lws, revision, naming.Default())
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, found := sts.Spec.Template.Labels[leaderworkerset.CandidateLabelKey]
+			if found != leaderless || (found && got != "true") {
+				t.Errorf("Unexpected %s pod label %q, found: %v", leaderworkerset.CandidateLabelKey, got, found)
+			}
+		})
+	}
+}
+
+func TestWorkerStatefulSetApplyOwnedFields(t *testing.T) {
+	lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").
+		Replica(1).
+		Size(2).
+		WorkerTemplateSpec(wrappers.MakeWorkerPodSpec()).Obj()
+	revision, err := revisionutils.NewRevision(context.TODO(), fake.NewClientBuilder().Build(), lws, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaderPod := corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "test-sample-0",
+			Namespace: "default",
+			UID:       "leader-uid",
+			Labels: map[string]string{
+				leaderworkerset.WorkerIndexLabelKey:     "0",
+				leaderworkerset.SetNameLabelKey:         "test-sample",
+				leaderworkerset.GroupIndexLabelKey:      "0",
+				leaderworkerset.GroupUniqueHashLabelKey: "test-key",
+				leaderworkerset.RevisionKey:             revisionutils.GetRevisionKey(revision),
+			},
+		},
+	}
+	construct := func() *appsapplyv1.StatefulSetApplyConfiguration {
+		sts, err := constructWorkerStatefulSetApplyConfiguration(leaderPod, *lws, revision, naming.Default())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := setControllerReferenceWithStatefulSet(&leaderPod, sts, clientgoscheme.Scheme); err != nil {
+			t.Fatal(err)
+		}
+		return sts
+	}
+	// The existing worker statefulset as applied by the controller, then mutated by another system
+	// which added a sidecar container to its pod template.
+	var existing appsv1.StatefulSet
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(construct())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, &existing); err != nil {
+		t.Fatal(err)
+	}
+	existing.ManagedFields = []v1.ManagedFieldsEntry{{
+		Manager:    podControllerFieldManager,
+		Operation:  v1.ManagedFieldsOperationApply,
+		APIVersion: "apps/v1",
+		FieldsType: "FieldsV1",
+		FieldsV1:   &v1.FieldsV1{Raw: []byte(`{"f:spec":{"f:template":{"f:spec":{"f:containers":{"k:{\"name\":\"leader\"}":{".":{},"f:image":{},"f:name":{}}}}}}}`)},
+	}}
+	existing.Spec.Template.Spec.Containers = append(existing.Spec.Template.Spec.Containers, corev1.Container{Name: "sidecar", Image: "sidecar"})
+
+	sts := construct()
+	if err := setWorkerPodTemplate(sts, &existing); err != nil {
+		t.Fatal(err)
+	}
+	want := []coreapplyv1.ContainerApplyConfiguration{{Name: ptr.To("leader"), Image: ptr.To("nginxinc/nginx-unprivileged:1.27")}}
+	if diff := cmp.Diff(want, sts.Spec.Template.Spec.Containers); diff != "" {
+		t.Errorf("Unexpected containers applied to the existing worker statefulset (-want +got):\n%s", diff)
+	}
+	upToDate, err := workerStatefulSetUpToDate(sts, &existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !upToDate {
+		t.Errorf("Expected the existing worker statefulset to be up to date")
+	}
+
+	// Another system dropped a label owned by the controller, the worker statefulset is applied again.
+	delete(existing.Labels, leaderworkerset.GroupUniqueHashLabelKey)
+	upToDate, err = workerStatefulSetUpToDate(sts, &existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if upToDate {
+		t.Errorf("Expected the worker statefulset missing a label not to be up to date")
+	}
+}
+
+// podApplyAsStrategicMerge emulates the server-side applies of the pods, which the fake client doesn't
+// support, with strategic merge patches of the applied fields.
+var podApplyAsStrategicMerge = interceptor.Funcs{
+	Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+		if patch.Type() != types.ApplyPatchType {
+			return c.Patch(ctx, obj, patch, opts...)
+		}
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		return c.Patch(ctx, obj, client.RawPatch(types.StrategicMergePatchType, data))
+	},
+	SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+		if patch.Type() != types.ApplyPatchType {
+			return c.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+		}
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		return c.SubResource(subResourceName).Patch(ctx, obj, client.RawPatch(types.StrategicMergePatchType, data))
+	},
+}
+
+func TestLeaderlessWorkerStatefulSet(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").
+		Replica(1).
+		Size(3).
+		WorkerTemplateSpec(wrappers.MakeWorkerPodSpec()).Obj()
+	lws.UID = "lws-uid"
+	lws.Annotations = map[string]string{leaderworkerset.LeaderlessAnnotationKey: "true"}
+	revision, err := revisionutils.NewRevision(ctx, fake.NewClientBuilder().Build(), lws, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaderPod := &corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "test-sample-0",
+			Namespace: "default",
+			UID:       "leader-uid",
+			Labels: map[string]string{
+				leaderworkerset.WorkerIndexLabelKey:     "0",
+				leaderworkerset.SetNameLabelKey:         "test-sample",
+				leaderworkerset.GroupIndexLabelKey:      "0",
+				leaderworkerset.GroupUniqueHashLabelKey: "test-key",
+				leaderworkerset.RevisionKey:             revisionutils.GetRevisionKey(revision),
+			},
+		},
+	}
+	// The fake client doesn't support apply patches, the worker statefulset is created instead.
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(lws, revision, leaderPod).WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if patch.Type() != types.ApplyPatchType {
+				return c.Patch(ctx, obj, patch, opts...)
+			}
+			var sts appsv1.StatefulSet
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.(*unstructured.Unstructured).Object, &sts); err != nil {
+				return err
+			}
+			return c.Create(ctx, &sts)
+		},
+	}).Build()
+	r := NewPodReconciler(c, scheme, record.NewFakeRecorder(100), &configapi.Configuration{})
+	request := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(leaderPod)}
+
+	if _, err := r.Reconcile(ctx, request); err != nil {
+		t.Fatalf("failed with error: %s", err.Error())
+	}
+	var sts appsv1.StatefulSet
+	if err := c.Get(ctx, request.NamespacedName, &sts); err != nil {
+		t.Fatal(err)
+	}
+	// The lws owns the worker statefulset along with candidate 0, which controls it.
+	wantOwners := []types.UID{"leader-uid", "lws-uid"}
+	var gotOwners []types.UID
+	for _, owner := range sts.OwnerReferences {
+		gotOwners = append(gotOwners, owner.UID)
+	}
+	if diff := cmp.Diff(wantOwners, gotOwners); diff != "" {
+		t.Errorf("Unexpected owners of the worker statefulset (-want +got):\n%s", diff)
+	}
+	if controller := v1.GetControllerOf(&sts); controller == nil || controller.UID != "leader-uid" {
+		t.Errorf("Expected candidate 0 to control the worker statefulset, got %v", controller)
+	}
+
+	// The worker statefulset outlived the candidate 0 of a previous revision, it's deleted to be
+	// recreated at the revision of the replacing candidate 0.
+	sts.Labels[leaderworkerset.RevisionKey] = "outdated"
+	if err := c.Update(ctx, &sts); err != nil {
+		t.Fatal(err)
+	}
+	result, err := r.Reconcile(ctx, request)
+	if err != nil {
+		t.Fatalf("failed with error: %s", err.Error())
+	}
+	if result.RequeueAfter == 0 {
+		t.Errorf("Expected the group to be requeued once the outdated worker statefulset is deleted")
+	}
+	if err := c.Get(ctx, request.NamespacedName, &sts); !apierrors.IsNotFound(err) {
+		t.Errorf("Expected the outdated worker statefulset to be deleted, got %v", err)
+	}
+}
+
+func TestHandleRestartPolicyLeaderless(t *testing.T) {
+	leader := corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "test-sample-0",
+			Namespace: "default",
+			Labels: map[string]string{
+				leaderworkerset.WorkerIndexLabelKey: "0",
+				leaderworkerset.SetNameLabelKey:     "test-sample",
+				leaderworkerset.GroupIndexLabelKey:  "0",
+				leaderworkerset.RevisionKey:         "test-revision",
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	restartedWorker := corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "test-sample-0-1",
+			Namespace: "default",
+			Labels: map[string]string{
+				leaderworkerset.WorkerIndexLabelKey: "1",
+				leaderworkerset.SetNameLabelKey:     "test-sample",
+				leaderworkerset.GroupIndexLabelKey:  "0",
+				leaderworkerset.RevisionKey:         "test-revision",
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "worker", RestartCount: 1}},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		readyWorkers int32
+		wantDeleted  bool
+	}{
+		{
+			name:         "quorum held by the other candidates",
+			readyWorkers: 1,
+		},
+		{
+			name:        "quorum lost",
+			wantDeleted: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			workerSts := &appsv1.StatefulSet{
+				ObjectMeta: v1.ObjectMeta{Name: "test-sample-0", Namespace: "default"},
+				Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To[int32](2)},
+				Status:     appsv1.StatefulSetStatus{Replicas: 2, ReadyReplicas: tc.readyWorkers},
+			}
+			client := fake.NewClientBuilder().WithObjects(leader.DeepCopy(), workerSts).Build()
+			r := NewPodReconciler(client, nil, record.NewFakeRecorder(10), &configapi.Configuration{})
+			lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").
+				RestartPolicy(leaderworkerset.RecreateGroupOnPodRestart).
+				Size(3).Obj()
+			lws.Annotations = map[string]string{leaderworkerset.LeaderlessAnnotationKey: "true"}
+
+			deleted, _, err := r.handleRestartPolicy(context.TODO(), r.config.Load(), restartedWorker, *lws)
+			if err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			if deleted != tc.wantDeleted {
+				t.Errorf("expected deleted %t, got %t", tc.wantDeleted, deleted)
+			}
+			// The worker statefulset isn't garbage collected with candidate 0, it's deleted along with it.
+			err = client.Get(context.TODO(), types.NamespacedName{Name: leader.Name, Namespace: leader.Namespace}, &corev1.Pod{})
+			if leaderDeleted := apierrors.IsNotFound(err); leaderDeleted != tc.wantDeleted {
+				t.Errorf("expected leader deleted %t, got %t", tc.wantDeleted, leaderDeleted)
+			}
+			err = client.Get(context.TODO(), types.NamespacedName{Name: workerSts.Name, Namespace: workerSts.Namespace}, &appsv1.StatefulSet{})
+			if stsDeleted := apierrors.IsNotFound(err); stsDeleted != tc.wantDeleted {
+				t.Errorf("expected worker statefulset deleted %t, got %t", tc.wantDeleted, stsDeleted)
+			}
+		})
+	}
+}
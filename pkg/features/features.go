@@ -0,0 +1,61 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/component-base/featuregate"
+)
+
+const (
+	// AllLeadersReadyStartupPolicy enables the AllLeadersReady startup policy, which holds the
+	// workers of all the groups until the leader pods of all the groups are ready.
+	AllLeadersReadyStartupPolicy featuregate.Feature = "AllLeadersReadyStartupPolicy"
+
+	// ForceDeleteStuckTerminatingPods enables the force deletion of the pods remaining
+	// Terminating longer than the stuckTerminatingPodTimeout, which block the recreation
+	// of their group, e.g. on an unreachable node.
+	ForceDeleteStuckTerminatingPods featuregate.Feature = "ForceDeleteStuckTerminatingPods"
+
+	// DryRunReconcile evaluates the behaviors of the disabled feature gates supporting it in
+	// dry-run, the actions they would take are counted in the lws_dry_run_actions_total metric
+	// but never applied, so that their impact can be estimated before enabling them. It covers
+	// ForceDeleteStuckTerminatingPods.
+	DryRunReconcile featuregate.Feature = "DryRunReconcile"
+)
+
+var defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
+	AllLeadersReadyStartupPolicy:    {Default: false, PreRelease: featuregate.Alpha},
+	ForceDeleteStuckTerminatingPods: {Default: false, PreRelease: featuregate.Alpha},
+	DryRunReconcile:                 {Default: false, PreRelease: featuregate.Alpha},
+}
+
+// DefaultMutableFeatureGate is the feature gate of the controller, set from the featureGates
+// of the configuration.
+var DefaultMutableFeatureGate featuregate.MutableFeatureGate = featuregate.NewFeatureGate()
+
+// DefaultFeatureGate is the read-only view of DefaultMutableFeatureGate.
+var DefaultFeatureGate featuregate.FeatureGate = DefaultMutableFeatureGate
+
+func init() {
+	utilruntime.Must(DefaultMutableFeatureGate.Add(defaultFeatureGates))
+}
+
+// Enabled returns whether the feature is enabled.
+func Enabled(f featuregate.Feature) bool {
+	return DefaultFeatureGate.Enabled(f)
+}
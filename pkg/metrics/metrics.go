@@ -0,0 +1,231 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+const (
+	namespace = "lws"
+
+	// managedObjectsSyncPeriod is how often the managed objects gauges are refreshed.
+	managedObjectsSyncPeriod = 30 * time.Second
+)
+
+var (
+	// ManagedSets reports the number of LeaderWorkerSets managed by the controller.
+	ManagedSets = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "managed_sets",
+		Help:      "The number of LeaderWorkerSets managed by the active controller.",
+	})
+
+	// ManagedPods reports the number of pods owned by LeaderWorkerSets managed by the controller.
+	ManagedPods = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "managed_pods",
+		Help:      "The number of leader and worker pods managed by the active controller.",
+	})
+
+	// GroupsWaitingForLeader reports the number of groups of a LeaderWorkerSet using the
+	// LeaderReady or AllLeadersReady startup policy whose workers are waiting for the leader
+	// pods to be ready.
+	GroupsWaitingForLeader = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "groups_waiting_for_leader",
+		Help:      "The number of groups of a LeaderWorkerSet waiting for their leader pod to be ready to create their workers.",
+	}, []string{"namespace", "name"})
+
+	// ReadyGroupsWithUnreadyLeader reports the number of groups of a LeaderWorkerSet with a
+	// coordination-only leader counted as Ready while their leader pod isn't, since it only
+	// needs to be running.
+	ReadyGroupsWithUnreadyLeader = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "ready_groups_with_unready_leader",
+		Help:      "The number of Ready groups of a LeaderWorkerSet with a coordination-only leader pod which is not Ready.",
+	}, []string{"namespace", "name"})
+
+	// SecondsSinceLastReconcile reports the time elapsed since the last successful reconcile of
+	// each LeaderWorkerSet, as recorded in LastReconciles, to alert on the objects the controller
+	// stopped servicing.
+	SecondsSinceLastReconcile = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "seconds_since_last_reconcile",
+		Help:      "The number of seconds since the last successful reconcile of a LeaderWorkerSet.",
+	}, []string{"namespace", "name"})
+
+	// DryRunActions counts the actions the behaviors evaluated in dry-run by the DryRunReconcile
+	// feature gate would have taken, by feature gate and action.
+	DryRunActions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dry_run_actions_total",
+		Help:      "The number of actions the behaviors of the disabled feature gates evaluated in dry-run would have taken.",
+	}, []string{"feature", "action"})
+
+	// LeaderElectionIsLeader reports whether this instance holds the leader election lease.
+	LeaderElectionIsLeader = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "leader_election_is_leader",
+		Help:      "Whether this instance of the controller is the elected leader (1) or not (0).",
+	})
+)
+
+// LastReconciles holds the time of the last successful reconcile of each LeaderWorkerSet.
+// It is kept in memory rather than in the status, whose writes would trigger another reconcile.
+var LastReconciles = &ReconcileTimes{}
+
+// ReconcileTimes tracks when each LeaderWorkerSet was last reconciled successfully.
+type ReconcileTimes struct {
+	sync.Mutex
+	last map[types.NamespacedName]time.Time
+}
+
+func (t *ReconcileTimes) Record(key types.NamespacedName, now time.Time) {
+	t.Lock()
+	defer t.Unlock()
+	if t.last == nil {
+		t.last = make(map[types.NamespacedName]time.Time)
+	}
+	t.last[key] = now
+}
+
+// Get returns the time of the last successful reconcile of the LeaderWorkerSet, false if it
+// wasn't reconciled since the controller started.
+func (t *ReconcileTimes) Get(key types.NamespacedName) (time.Time, bool) {
+	t.Lock()
+	defer t.Unlock()
+	last, found := t.last[key]
+	return last, found
+}
+
+func (t *ReconcileTimes) Forget(key types.NamespacedName) {
+	t.Lock()
+	defer t.Unlock()
+	delete(t.last, key)
+}
+
+// Register registers the LWS metrics with the controller-runtime metrics registry.
+func Register() {
+	metrics.Registry.MustRegister(
+		ManagedSets,
+		ManagedPods,
+		GroupsWaitingForLeader,
+		ReadyGroupsWithUnreadyLeader,
+		SecondsSinceLastReconcile,
+		DryRunActions,
+		LeaderElectionIsLeader,
+	)
+}
+
+// LeaderElectionTracker reports the leadership of this instance. It is started once the instance
+// is elected, and stopped when it loses the lease or shuts down. Without leader election, the
+// instance is always the leader.
+type LeaderElectionTracker struct{}
+
+var _ manager.LeaderElectionRunnable = &LeaderElectionTracker{}
+
+func NewLeaderElectionTracker() *LeaderElectionTracker {
+	return &LeaderElectionTracker{}
+}
+
+func (t *LeaderElectionTracker) NeedLeaderElection() bool {
+	return true
+}
+
+func (t *LeaderElectionTracker) Start(ctx context.Context) error {
+	LeaderElectionIsLeader.Set(1)
+	<-ctx.Done()
+	LeaderElectionIsLeader.Set(0)
+	return nil
+}
+
+// ManagedObjectsCollector periodically refreshes the managed objects gauges. It only runs
+// on the elected leader, and resets the gauges when it stops so that a replica which lost
+// the leadership doesn't keep reporting stale values.
+type ManagedObjectsCollector struct {
+	client         client.Client
+	period         time.Duration
+	reconcileTimes *ReconcileTimes
+}
+
+var _ manager.LeaderElectionRunnable = &ManagedObjectsCollector{}
+
+func NewManagedObjectsCollector(client client.Client) *ManagedObjectsCollector {
+	return &ManagedObjectsCollector{client: client, period: managedObjectsSyncPeriod, reconcileTimes: LastReconciles}
+}
+
+func (c *ManagedObjectsCollector) NeedLeaderElection() bool {
+	return true
+}
+
+func (c *ManagedObjectsCollector) Start(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx).WithName("managed-objects-collector")
+	defer reset()
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := c.update(ctx); err != nil {
+			log.Error(err, "updating managed objects metrics")
+		}
+	}, c.period)
+	return nil
+}
+
+func (c *ManagedObjectsCollector) update(ctx context.Context) error {
+	var lwsList leaderworkerset.LeaderWorkerSetList
+	if err := c.client.List(ctx, &lwsList); err != nil {
+		return err
+	}
+	var podList corev1.PodList
+	if err := c.client.List(ctx, &podList, client.HasLabels{leaderworkerset.SetNameLabelKey}); err != nil {
+		return err
+	}
+	ManagedSets.Set(float64(len(lwsList.Items)))
+	ManagedPods.Set(float64(len(podList.Items)))
+
+	// Rebuilt on every refresh to drop the deleted LeaderWorkerSets. The objects not reconciled
+	// since the controller started aren't reported until their first reconcile.
+	now := time.Now()
+	SecondsSinceLastReconcile.Reset()
+	for _, lws := range lwsList.Items {
+		last, found := c.reconcileTimes.Get(client.ObjectKeyFromObject(&lws))
+		if !found {
+			continue
+		}
+		SecondsSinceLastReconcile.WithLabelValues(lws.Namespace, lws.Name).Set(now.Sub(last).Seconds())
+	}
+	return nil
+}
+
+func reset() {
+	ManagedSets.Set(0)
+	ManagedPods.Set(0)
+	SecondsSinceLastReconcile.Reset()
+}
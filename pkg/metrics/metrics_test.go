@@ -0,0 +1,188 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/test/wrappers"
+)
+
+func makePod(name string, labels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    labels,
+		},
+	}
+}
+
+func TestManagedObjectsCollector(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		objects  []client.Object
+		wantSets float64
+		wantPods float64
+	}{
+		{
+			name: "no objects",
+		},
+		{
+			name: "leaderworkersets with pods",
+			objects: []client.Object{
+				wrappers.BuildBasicLeaderWorkerSet("lws-a", "default").Obj(),
+				wrappers.BuildBasicLeaderWorkerSet("lws-b", "default").Obj(),
+				makePod("lws-a-0", map[string]string{leaderworkerset.SetNameLabelKey: "lws-a"}),
+				makePod("lws-a-0-1", map[string]string{leaderworkerset.SetNameLabelKey: "lws-a"}),
+				makePod("lws-b-0", map[string]string{leaderworkerset.SetNameLabelKey: "lws-b"}),
+				makePod("unmanaged", map[string]string{"app": "unmanaged"}),
+			},
+			wantSets: 2,
+			wantPods: 3,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.objects...).Build()
+			collector := &ManagedObjectsCollector{client: c, period: time.Hour, reconcileTimes: &ReconcileTimes{}}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				_ = collector.Start(ctx)
+			}()
+
+			if err := waitFor(func() bool {
+				return testutil.ToFloat64(ManagedSets) == tc.wantSets && testutil.ToFloat64(ManagedPods) == tc.wantPods
+			}); err != nil {
+				t.Errorf("unexpected gauges, sets: %v, pods: %v", testutil.ToFloat64(ManagedSets), testutil.ToFloat64(ManagedPods))
+			}
+
+			// Losing the leadership stops the collector, which must reset the gauges.
+			cancel()
+			<-done
+			if got := testutil.ToFloat64(ManagedSets); got != 0 {
+				t.Errorf("expected managed sets to be reset, got %v", got)
+			}
+			if got := testutil.ToFloat64(ManagedPods); got != 0 {
+				t.Errorf("expected managed pods to be reset, got %v", got)
+			}
+		})
+	}
+}
+
+func TestSecondsSinceLastReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	reconciled := wrappers.BuildBasicLeaderWorkerSet("reconciled", "default").Obj()
+	neverReconciled := wrappers.BuildBasicLeaderWorkerSet("never-reconciled", "default").Obj()
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(reconciled, neverReconciled).Build()
+	reconcileTimes := &ReconcileTimes{}
+	collector := &ManagedObjectsCollector{client: c, period: time.Hour, reconcileTimes: reconcileTimes}
+
+	reconcileTimes.Record(client.ObjectKeyFromObject(reconciled), time.Now().Add(-10*time.Minute))
+	if err := collector.update(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := testutil.ToFloat64(SecondsSinceLastReconcile.WithLabelValues("default", "reconciled")); got < 600 || got > 660 {
+		t.Errorf("Expected about 600 seconds since the last reconcile, got %v", got)
+	}
+	// The object which wasn't reconciled since the controller started isn't reported.
+	if got := testutil.CollectAndCount(SecondsSinceLastReconcile); got != 1 {
+		t.Errorf("Expected a single series, got %d", got)
+	}
+
+	// The next reconcile resets the time.
+	reconcileTimes.Record(client.ObjectKeyFromObject(reconciled), time.Now().Add(-time.Minute))
+	if err := collector.update(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := testutil.ToFloat64(SecondsSinceLastReconcile.WithLabelValues("default", "reconciled")); got < 60 || got > 120 {
+		t.Errorf("Expected about 60 seconds since the last reconcile, got %v", got)
+	}
+
+	// The deleted objects are dropped on the next refresh.
+	if err := c.Delete(context.Background(), reconciled); err != nil {
+		t.Fatal(err)
+	}
+	if err := collector.update(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := testutil.CollectAndCount(SecondsSinceLastReconcile); got != 0 {
+		t.Errorf("Expected the deleted object to be dropped, got %d series", got)
+	}
+}
+
+func TestLeaderElectionTracker(t *testing.T) {
+	tracker := NewLeaderElectionTracker()
+	for range 2 {
+		if got := testutil.ToFloat64(LeaderElectionIsLeader); got != 0 {
+			t.Errorf("expected not to be the leader before the election, got %v", got)
+		}
+
+		// The manager starts the tracker once the lease is acquired.
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_ = tracker.Start(ctx)
+		}()
+		if err := waitFor(func() bool { return testutil.ToFloat64(LeaderElectionIsLeader) == 1 }); err != nil {
+			t.Errorf("expected to be the leader once elected, got %v", testutil.ToFloat64(LeaderElectionIsLeader))
+		}
+
+		// and stops it once the lease is lost.
+		cancel()
+		<-done
+		if got := testutil.ToFloat64(LeaderElectionIsLeader); got != 0 {
+			t.Errorf("expected not to be the leader once the lease is lost, got %v", got)
+		}
+	}
+}
+
+func waitFor(cond func() bool) error {
+	return wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 5*time.Second, true, func(context.Context) (bool, error) {
+		return cond(), nil
+	})
+}
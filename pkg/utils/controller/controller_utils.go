@@ -18,30 +18,47 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"maps"
+	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
 )
 
-func CreateHeadlessServiceIfNotExists(ctx context.Context, k8sClient client.Client, Scheme *runtime.Scheme, lws *leaderworkerset.LeaderWorkerSet, serviceName string, serviceSelector map[string]string, owner metav1.Object) error {
+// ReconcileHeadlessService creates the headless service if it does not exist. The selector and
+// publishNotReadyAddresses of an existing service controlled by the owner are patched in place
+// when they drifted, the service is never recreated so that its endpoints are kept.
+func ReconcileHeadlessService(ctx context.Context, k8sClient client.Client, Scheme *runtime.Scheme, lws *leaderworkerset.LeaderWorkerSet, serviceName string, serviceSelector map[string]string, owner metav1.Object, recommendedLabels, defaultAnnotations map[string]string) error {
 	log := ctrl.LoggerFrom(ctx)
-	// If the headless service does not exist in the namespace, create it.
 	var headlessService corev1.Service
 	if err := k8sClient.Get(ctx, types.NamespacedName{Name: serviceName, Namespace: lws.Namespace}, &headlessService); err != nil {
 		if client.IgnoreNotFound(err) != nil {
 			return err
 		}
+		labels := maps.Clone(recommendedLabels)
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[leaderworkerset.SetNameLabelKey] = lws.Name
 		headlessService := corev1.Service{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      serviceName,
-				Namespace: lws.Namespace,
-				Labels:    map[string]string{leaderworkerset.SetNameLabelKey: lws.Name},
+				Name:        serviceName,
+				Namespace:   lws.Namespace,
+				Labels:      labels,
+				Annotations: maps.Clone(defaultAnnotations),
 			},
 			Spec: corev1.ServiceSpec{
 				ClusterIP:                "None", // defines service as headless
@@ -56,9 +73,77 @@ func CreateHeadlessServiceIfNotExists(ctx context.Context, k8sClient client.Clie
 		}
 		// create the service in the cluster
 		log.V(2).Info("Creating headless service.")
-		if err := k8sClient.Create(ctx, &headlessService); err != nil {
-			return err
+		return k8sClient.Create(ctx, &headlessService)
+	}
+
+	// A service of the same name created by someone else is left alone.
+	if !metav1.IsControlledBy(&headlessService, owner) {
+		return nil
+	}
+	if maps.Equal(headlessService.Spec.Selector, serviceSelector) && headlessService.Spec.PublishNotReadyAddresses {
+		return nil
+	}
+	patch := client.MergeFrom(headlessService.DeepCopy())
+	headlessService.Spec.Selector = serviceSelector
+	headlessService.Spec.PublishNotReadyAddresses = true
+	log.V(2).Info("Patching headless service.", "service", klog.KObj(&headlessService))
+	return k8sClient.Patch(ctx, &headlessService, patch)
+}
+
+// WithReconcileTimeout bounds the duration of each Reconcile call of r to timeout, or returns
+// r as is when timeout is 0. A reconciliation exceeding it has its context cancelled and returns
+// an error, so that the request is requeued with backoff instead of holding a worker.
+func WithReconcileTimeout(r reconcile.Reconciler, timeout time.Duration) reconcile.Reconciler {
+	if timeout == 0 {
+		return r
+	}
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		result, err := r.Reconcile(ctx, req)
+		if ctx.Err() == context.DeadlineExceeded {
+			return reconcile.Result{}, fmt.Errorf("reconcile timed out after %s: %w", timeout, ctx.Err())
 		}
+		return result, err
+	})
+}
+
+// WithShutdownDrain detaches each Reconcile call of r from the cancellation of the controller
+// when drain is true, or returns r as is. A stopped controller keeps handing out the queued
+// requests to its workers, they are then reconciled to completion instead of failing on the
+// cancelled context, until the graceful shutdown timeout of the manager.
+func WithShutdownDrain(r reconcile.Reconciler, drain bool) reconcile.Reconciler {
+	if !drain {
+		return r
 	}
-	return nil
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		return r.Reconcile(context.WithoutCancel(ctx), req)
+	})
+}
+
+// tracerName is the instrumentation scope of the spans of the controllers.
+const tracerName = "sigs.k8s.io/lws"
+
+// StartSpan starts a span named name as a child of the span of ctx, with the tracer provider
+// registered globally, which doesn't record anything unless the tracing is configured.
+func StartSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, opts...)
+}
+
+// WithTracing wraps each Reconcile call of r in a span named name, carrying the namespace and
+// name of the request, so that the spans of the sub-steps of the reconciliation are nested.
+func WithTracing(r reconcile.Reconciler, name string) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		ctx, span := StartSpan(ctx, name, trace.WithAttributes(
+			attribute.String("namespace", req.Namespace),
+			attribute.String("name", req.Name),
+		))
+		defer span.End()
+		result, err := r.Reconcile(ctx, req)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		return result, err
+	})
 }
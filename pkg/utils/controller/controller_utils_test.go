@@ -0,0 +1,296 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+func TestWithReconcileTimeout(t *testing.T) {
+	slowReconciler := reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+		select {
+		case <-ctx.Done():
+			return reconcile.Result{}, ctx.Err()
+		case <-time.After(time.Minute):
+			return reconcile.Result{RequeueAfter: time.Second}, nil
+		}
+	})
+	fastReconciler := reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+		return reconcile.Result{RequeueAfter: time.Second}, nil
+	})
+
+	testCases := []struct {
+		name       string
+		reconciler reconcile.Reconciler
+		wantResult reconcile.Result
+		wantErr    error
+	}{
+		{
+			name:       "slow reconcile is cut off",
+			reconciler: slowReconciler,
+			wantErr:    context.DeadlineExceeded,
+		},
+		{
+			name:       "fast reconcile completes",
+			reconciler: fastReconciler,
+			wantResult: reconcile.Result{RequeueAfter: time.Second},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			start := time.Now()
+			result, err := WithReconcileTimeout(tc.reconciler, 10*time.Millisecond).Reconcile(context.Background(), reconcile.Request{})
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("unexpected error, want %v, got %v", tc.wantErr, err)
+			}
+			if result != tc.wantResult {
+				t.Errorf("unexpected result, want %+v, got %+v", tc.wantResult, result)
+			}
+			if elapsed := time.Since(start); elapsed > 10*time.Second {
+				t.Errorf("expected the reconcile to be cut off, took %s", elapsed)
+			}
+		})
+	}
+}
+
+func TestWithShutdownDrain(t *testing.T) {
+	requests := []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-sample-0"}},
+		{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-sample-1"}},
+		{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-sample-2"}},
+	}
+	testCases := []struct {
+		name          string
+		drain         bool
+		wantProcessed []string
+	}{
+		{
+			name:  "queued requests fail on the cancelled context",
+			drain: false,
+		},
+		{
+			name:          "queued requests are drained",
+			drain:         true,
+			wantProcessed: []string{"test-sample-0", "test-sample-1", "test-sample-2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// The first reconcile holds the only worker until the controller is stopped, so that
+			// the other requests are still queued. Like the API calls, the reconciles only complete
+			// with a live context.
+			started, release := make(chan struct{}), make(chan struct{})
+			var processed []string
+			r := reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+				if req.Name == "test-sample-0" {
+					close(started)
+					<-release
+				}
+				if err := ctx.Err(); err != nil {
+					return reconcile.Result{}, err
+				}
+				processed = append(processed, req.Name)
+				return reconcile.Result{}, nil
+			})
+			c, err := controller.NewUnmanaged("test", controller.Options{
+				Reconciler:              WithShutdownDrain(r, tc.drain),
+				MaxConcurrentReconciles: 1,
+				SkipNameValidation:      ptr.To(true),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := c.Watch(source.Func(func(_ context.Context, queue workqueue.TypedRateLimitingInterface[reconcile.Request]) error {
+				for _, req := range requests {
+					queue.Add(req)
+				}
+				return nil
+			})); err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			stopped := make(chan error)
+			go func() { stopped <- c.Start(ctx) }()
+			<-started
+			cancel()
+			close(release)
+			if err := <-stopped; err != nil {
+				t.Fatalf("Unexpected error stopping the controller: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantProcessed, processed); diff != "" {
+				t.Errorf("Unexpected processed requests (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWithTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(previous)
+
+	reconcileErr := errors.New("reconcile failed")
+	r := WithTracing(reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+		_, span := StartSpan(ctx, "step")
+		span.End()
+		return reconcile.Result{}, reconcileErr
+	}), "Test.Reconcile")
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-sample"}}); !errors.Is(err, reconcileErr) {
+		t.Fatalf("Expected the error of the reconciler, got %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+	step, root := spans[0], spans[1]
+	if step.Name() != "step" || root.Name() != "Test.Reconcile" {
+		t.Errorf("Unexpected span names %q and %q", step.Name(), root.Name())
+	}
+	if step.Parent().SpanID() != root.SpanContext().SpanID() {
+		t.Errorf("Expected the span of the step to be nested in the span of the reconciliation")
+	}
+	if root.Status().Code != codes.Error {
+		t.Errorf("Expected the span of the reconciliation to report the error, got status %v", root.Status())
+	}
+	wantAttributes := []attribute.KeyValue{attribute.String("namespace", "default"), attribute.String("name", "test-sample")}
+	if diff := cmp.Diff(wantAttributes, root.Attributes(), cmp.AllowUnexported(attribute.Value{})); diff != "" {
+		t.Errorf("Unexpected attributes (-want +got):\n%s", diff)
+	}
+}
+
+func TestReconcileHeadlessService(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	lws := &leaderworkerset.LeaderWorkerSet{ObjectMeta: metav1.ObjectMeta{Name: "test-sample", Namespace: "default", UID: "lws-uid"}}
+	selector := map[string]string{leaderworkerset.SetNameLabelKey: "test-sample"}
+
+	tests := []struct {
+		name        string
+		service     *corev1.Service
+		unowned     bool
+		wantService corev1.ServiceSpec
+	}{
+		{
+			name: "missing service is created",
+			wantService: corev1.ServiceSpec{
+				ClusterIP:                "None",
+				Selector:                 selector,
+				PublishNotReadyAddresses: true,
+			},
+		},
+		{
+			name: "drifted publishNotReadyAddresses is patched",
+			service: &corev1.Service{
+				Spec: corev1.ServiceSpec{ClusterIP: "None", Selector: selector},
+			},
+			wantService: corev1.ServiceSpec{
+				ClusterIP:                "None",
+				Selector:                 selector,
+				PublishNotReadyAddresses: true,
+			},
+		},
+		{
+			name: "drifted selector is patched",
+			service: &corev1.Service{
+				Spec: corev1.ServiceSpec{
+					ClusterIP:                "None",
+					Selector:                 map[string]string{"app": "other"},
+					PublishNotReadyAddresses: true,
+				},
+			},
+			wantService: corev1.ServiceSpec{
+				ClusterIP:                "None",
+				Selector:                 selector,
+				PublishNotReadyAddresses: true,
+			},
+		},
+		{
+			name: "service of someone else is left alone",
+			service: &corev1.Service{
+				Spec: corev1.ServiceSpec{ClusterIP: "None", Selector: map[string]string{"app": "other"}},
+			},
+			unowned:     true,
+			wantService: corev1.ServiceSpec{ClusterIP: "None", Selector: map[string]string{"app": "other"}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			if tc.service != nil {
+				service := tc.service.DeepCopy()
+				service.Name, service.Namespace, service.UID = "test-sample", "default", "service-uid"
+				if !tc.unowned {
+					if err := ctrl.SetControllerReference(lws, service, scheme); err != nil {
+						t.Fatal(err)
+					}
+				}
+				builder = builder.WithObjects(service)
+			}
+			c := builder.Build()
+
+			if err := ReconcileHeadlessService(ctx, c, scheme, lws, "test-sample", selector, lws, nil, nil); err != nil {
+				t.Fatal(err)
+			}
+			var got corev1.Service
+			if err := c.Get(ctx, types.NamespacedName{Name: "test-sample", Namespace: "default"}, &got); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.wantService, got.Spec); diff != "" {
+				t.Errorf("Unexpected service spec (-want +got):\n%s", diff)
+			}
+			// The existing service is patched in place rather than recreated.
+			if tc.service != nil && got.UID != "service-uid" {
+				t.Errorf("Expected the service to be kept, got UID %q", got.UID)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// The readiness subchecks, registered under the readiness endpoint by name so that
+// /readyz?verbose lists the status of each subsystem.
+const (
+	// CacheSyncCheck passes once the informers of the manager are synced.
+	CacheSyncCheck = "cache-sync"
+	// WebhookReadyCheck passes once the webhook certificates are ready and the webhook
+	// server serves, or always when the webhooks are disabled.
+	WebhookReadyCheck = "webhook-ready"
+	// LeaderStatusCheck passes once the replica is the elected leader, or always when the
+	// leader election is disabled.
+	LeaderStatusCheck = "leader-status"
+)
+
+// cacheSyncTimeout bounds how long a probe waits for the informers to sync.
+const cacheSyncTimeout = time.Second
+
+// Checks returns the supported readiness subchecks, sorted.
+func Checks() []string {
+	return []string{CacheSyncCheck, LeaderStatusCheck, WebhookReadyCheck}
+}
+
+// cacheSyncer is the part of the cache of the manager inspected by the cache-sync subcheck.
+type cacheSyncer interface {
+	WaitForCacheSync(ctx context.Context) bool
+}
+
+// CacheSyncChecker returns a checker which passes once the informers of the cache are synced.
+func CacheSyncChecker(cache cacheSyncer) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), cacheSyncTimeout)
+		defer cancel()
+		if !cache.WaitForCacheSync(ctx) {
+			return errors.New("the informers are not synced")
+		}
+		return nil
+	}
+}
+
+// WebhookReadyChecker returns a checker which passes once the certificates are ready and the
+// webhook server serves. A nil server means the webhooks are disabled, the checker then passes.
+func WebhookReadyChecker(server webhook.Server, certsReady <-chan struct{}) healthz.Checker {
+	return func(req *http.Request) error {
+		if server == nil {
+			return nil
+		}
+		select {
+		case <-certsReady:
+			return server.StartedChecker()(req)
+		default:
+			return errors.New("the webhook certificates are not ready")
+		}
+	}
+}
+
+// LeaderStatusChecker returns a checker which passes once the elected channel is closed.
+func LeaderStatusChecker(elected <-chan struct{}) healthz.Checker {
+	return func(_ *http.Request) error {
+		select {
+		case <-elected:
+			return nil
+		default:
+			return errors.New("not the elected leader")
+		}
+	}
+}
+
+// AddReadinessChecks registers the named readiness subchecks with the manager. The webhook
+// server is nil when the webhooks are disabled.
+func AddReadinessChecks(mgr manager.Manager, checks []string, server webhook.Server, certsReady <-chan struct{}) error {
+	for _, name := range checks {
+		var checker healthz.Checker
+		switch name {
+		case CacheSyncCheck:
+			checker = CacheSyncChecker(mgr.GetCache())
+		case WebhookReadyCheck:
+			checker = WebhookReadyChecker(server, certsReady)
+		case LeaderStatusCheck:
+			checker = LeaderStatusChecker(mgr.Elected())
+		default:
+			return fmt.Errorf("unsupported readiness check %q, must be one of %v", name, Checks())
+		}
+		if err := mgr.AddReadyzCheck(name, checker); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+type fakeCache struct {
+	synced bool
+}
+
+func (c fakeCache) WaitForCacheSync(context.Context) bool {
+	return c.synced
+}
+
+type fakeWebhookServer struct {
+	webhook.Server
+	started error
+}
+
+func (s fakeWebhookServer) StartedChecker() healthz.Checker {
+	return func(*http.Request) error {
+		return s.started
+	}
+}
+
+func closed() chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}
+
+func TestCheckers(t *testing.T) {
+	testCases := []struct {
+		name    string
+		checker healthz.Checker
+		wantErr bool
+	}{
+		{
+			name:    "cache synced",
+			checker: CacheSyncChecker(fakeCache{synced: true}),
+		},
+		{
+			name:    "cache not synced",
+			checker: CacheSyncChecker(fakeCache{}),
+			wantErr: true,
+		},
+		{
+			name:    "webhooks disabled",
+			checker: WebhookReadyChecker(nil, make(chan struct{})),
+		},
+		{
+			name:    "webhook certificates not ready",
+			checker: WebhookReadyChecker(fakeWebhookServer{}, make(chan struct{})),
+			wantErr: true,
+		},
+		{
+			name:    "webhook server not started",
+			checker: WebhookReadyChecker(fakeWebhookServer{started: errors.New("not started")}, closed()),
+			wantErr: true,
+		},
+		{
+			name:    "webhook server started",
+			checker: WebhookReadyChecker(fakeWebhookServer{}, closed()),
+		},
+		{
+			name:    "elected leader",
+			checker: LeaderStatusChecker(closed()),
+		},
+		{
+			name:    "not the elected leader",
+			checker: LeaderStatusChecker(make(chan struct{})),
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.checker(httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("Unexpected error, want error %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAggregatedReadiness(t *testing.T) {
+	elected := make(chan struct{})
+	handler := &healthz.Handler{Checks: map[string]healthz.Checker{
+		"readyz":          healthz.Ping,
+		CacheSyncCheck:    CacheSyncChecker(fakeCache{synced: true}),
+		WebhookReadyCheck: WebhookReadyChecker(fakeWebhookServer{}, closed()),
+		LeaderStatusCheck: LeaderStatusChecker(elected),
+	}}
+	readyz := func() (int, string) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/?verbose", nil))
+		return recorder.Code, recorder.Body.String()
+	}
+
+	// A failing subcheck fails the aggregate, and is listed along the passing ones.
+	code, body := readyz()
+	if code != http.StatusInternalServerError {
+		t.Errorf("Expected the aggregate to fail while not elected, got status %d", code)
+	}
+	wantBody := `[+]cache-sync ok
+[-]leader-status failed: reason withheld
+[+]readyz ok
+[+]webhook-ready ok
+healthz check failed
+`
+	if diff := cmp.Diff(wantBody, body); diff != "" {
+		t.Errorf("Unexpected verbose output (-want +got):\n%s", diff)
+	}
+
+	close(elected)
+	code, body = readyz()
+	if code != http.StatusOK {
+		t.Errorf("Expected the aggregate to pass once elected, got status %d", code)
+	}
+	wantBody = `[+]cache-sync ok
+[+]leader-status ok
+[+]readyz ok
+[+]webhook-ready ok
+healthz check passed
+`
+	if diff := cmp.Diff(wantBody, body); diff != "" {
+		t.Errorf("Unexpected verbose output (-want +got):\n%s", diff)
+	}
+}
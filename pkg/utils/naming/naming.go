@@ -0,0 +1,44 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package naming
+
+// Namer names the headless services created for the LeaderWorkerSets, which are also the
+// subdomains of their pods. The statefulsets and the pods are named after the lws and their
+// leader pod, since the workers find their leader by name, and are not pluggable.
+type Namer interface {
+	// ServiceName returns the name of the headless service shared by the groups of the lws.
+	ServiceName(lwsName string) string
+	// GroupServiceName returns the name of the headless service of the group of the leader
+	// pod, used with the UniquePerReplica subdomain policy.
+	GroupServiceName(leaderPodName string) string
+}
+
+// defaultNamer names the services after the lws and the leader pods.
+type defaultNamer struct{}
+
+func (defaultNamer) ServiceName(lwsName string) string {
+	return lwsName
+}
+
+func (defaultNamer) GroupServiceName(leaderPodName string) string {
+	return leaderPodName
+}
+
+// Default returns the Namer naming the services after the lws and the leader pods.
+func Default() Namer {
+	return defaultNamer{}
+}
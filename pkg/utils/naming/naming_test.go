@@ -0,0 +1,31 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package naming
+
+import (
+	"testing"
+)
+
+func TestDefault(t *testing.T) {
+	namer := Default()
+	if got := namer.ServiceName("test-sample"); got != "test-sample" {
+		t.Errorf("Expected service name test-sample, got %s", got)
+	}
+	if got := namer.GroupServiceName("test-sample-1"); got != "test-sample-1" {
+		t.Errorf("Expected group service name test-sample-1, got %s", got)
+	}
+}
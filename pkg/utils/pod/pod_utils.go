@@ -17,14 +17,119 @@ limitations under the License.
 package pod
 
 import (
+	"cmp"
+	"context"
 	"fmt"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/utils"
+	"sigs.k8s.io/lws/pkg/utils/naming"
 )
 
+// PodRole is the role of a pod within its group.
+type PodRole string
+
+const (
+	// LeaderPodRole is the role of the pod with the worker index 0 of a group.
+	LeaderPodRole PodRole = "Leader"
+	// WorkerPodRole is the role of the other pods of a group.
+	WorkerPodRole PodRole = "Worker"
+)
+
+// ManagedPod is a pod of a LeaderWorkerSet along with its position in the LeaderWorkerSet,
+// parsed from the labels set by the controllers and the pod webhook.
+type ManagedPod struct {
+	Pod  *corev1.Pod
+	Role PodRole
+	// GroupIndex is the index of the group of the pod.
+	GroupIndex int
+	// WorkerIndex is the index of the pod within its group, 0 for the leader pod.
+	WorkerIndex int
+	// SubGroupIndex is the index of the subgroup of the pod, nil if the LeaderWorkerSet has
+	// no subgroups or the pod is a leader excluded from them.
+	SubGroupIndex *int
+	// CoordinatorGroup is whether the pod belongs to the coordinator group.
+	CoordinatorGroup bool
+}
+
+// ListManagedPods returns the pods of the lws, ordered by group index and worker index.
+func ListManagedPods(ctx context.Context, c client.Reader, lws *leaderworkerset.LeaderWorkerSet) ([]ManagedPod, error) {
+	var podList corev1.PodList
+	if err := c.List(ctx, &podList, client.InNamespace(lws.Namespace), client.MatchingLabels{
+		leaderworkerset.SetNameLabelKey: lws.Name,
+	}); err != nil {
+		return nil, err
+	}
+	pods := make([]ManagedPod, 0, len(podList.Items))
+	for i := range podList.Items {
+		pod, err := ParseManagedPod(&podList.Items[i], lws.Name)
+		if err != nil {
+			return nil, err
+		}
+		pods = append(pods, pod)
+	}
+	slices.SortFunc(pods, func(a, b ManagedPod) int {
+		return cmp.Or(cmp.Compare(a.GroupIndex, b.GroupIndex), cmp.Compare(a.WorkerIndex, b.WorkerIndex))
+	})
+	return pods, nil
+}
+
+// ParseManagedPod returns the position of a pod of the lws with the given name. The group index
+// is parsed like the controllers do, see utils.GroupIndex.
+func ParseManagedPod(pod *corev1.Pod, lwsName string) (ManagedPod, error) {
+	managedPod := ManagedPod{
+		Pod:              pod,
+		Role:             WorkerPodRole,
+		CoordinatorGroup: pod.Labels[leaderworkerset.CoordinatorGroupLabelKey] == "true",
+	}
+	workerIndex, err := strconv.Atoi(pod.Labels[leaderworkerset.WorkerIndexLabelKey])
+	if err != nil {
+		return ManagedPod{}, fmt.Errorf("parsing the worker index of pod %s: %w", pod.Name, err)
+	}
+	managedPod.WorkerIndex = workerIndex
+	if LeaderPod(*pod) {
+		managedPod.Role = LeaderPodRole
+	}
+	if managedPod.GroupIndex, err = utils.GroupIndex(pod, lwsName); err != nil {
+		return ManagedPod{}, fmt.Errorf("parsing the group index of pod %s: %w", pod.Name, err)
+	}
+	if value, found := pod.Labels[leaderworkerset.SubGroupIndexLabelKey]; found {
+		subGroupIndex, err := strconv.Atoi(value)
+		if err != nil {
+			return ManagedPod{}, fmt.Errorf("parsing the subgroup index of pod %s: %w", pod.Name, err)
+		}
+		managedPod.SubGroupIndex = &subGroupIndex
+	}
+	return managedPod, nil
+}
+
+// GroupPodAddress returns the address of the pod at the worker index of the group, the leader
+// being the worker index 0, as published by the headless services of the lws. Like the
+// LWS_LEADER_ADDRESS, it's not fully qualified but relative to the cluster domain:
+// <hostname>.<service>.<namespace>.
+func GroupPodAddress(lws *leaderworkerset.LeaderWorkerSet, namer naming.Namer, groupIndex, workerIndex int) string {
+	leaderName := fmt.Sprintf("%s-%d", lws.Name, groupIndex)
+	hostname := leaderName
+	if workerIndex != 0 {
+		hostname = fmt.Sprintf("%s-%d", leaderName, workerIndex)
+	}
+	subdomain := namer.ServiceName(lws.Name)
+	if lws.Spec.NetworkConfig != nil && lws.Spec.NetworkConfig.SubdomainPolicy != nil && *lws.Spec.NetworkConfig.SubdomainPolicy == leaderworkerset.SubdomainUniquePerReplica {
+		subdomain = namer.GroupServiceName(leaderName)
+	}
+	return fmt.Sprintf("%s.%s.%s", hostname, subdomain, lws.Namespace)
+}
+
 // ContainerRestarted return true when there is any container in the pod that gets restarted
 func ContainerRestarted(pod corev1.Pod) bool {
 	if pod.Status.Phase == corev1.PodRunning || pod.Status.Phase == corev1.PodPending {
@@ -44,6 +149,28 @@ func ContainerRestarted(pod corev1.Pod) bool {
 	return false
 }
 
+// CrashLoopingContainer returns the name of a container of the pod which restarted at least
+// restartThreshold times and last terminated within the window before now, if any.
+func CrashLoopingContainer(pod corev1.Pod, restartThreshold int32, window time.Duration, now time.Time) (string, bool) {
+	for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, stat := range statuses {
+			if stat.RestartCount < restartThreshold {
+				continue
+			}
+			if terminated := stat.LastTerminationState.Terminated; terminated != nil && now.Sub(terminated.FinishedAt.Time) < window {
+				return stat.Name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// PodDeadlineExceeded checks if the pod was terminated by the kubelet for running
+// longer than its activeDeadlineSeconds.
+func PodDeadlineExceeded(pod corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodFailed && pod.Status.Reason == "DeadlineExceeded"
+}
+
 // PodDeleted checks if the worker pod has been deleted
 func PodDeleted(pod corev1.Pod) bool {
 	return pod.DeletionTimestamp != nil
@@ -92,10 +219,137 @@ func getPodConditionFromList(conditions []corev1.PodCondition, conditionType cor
 	return -1, nil
 }
 
-// addEnvVarsIfNotExists adds env vars to the container if they don't already exist.
-// It takes a slice of existing env vars and appends new env vars to the beginning of it,
-// ensuring that the 'firstEnv' and 'e' env vars maintain their order at the front.
-// The function then adds any existing env vars from 'c.Env' that are not in 'e' to the newEnvVars slice.
+// ApplyGroupResourceOverrides merges the resources of the overrides targeting the group index
+// into the containers of the pod spec with the same names.
+func ApplyGroupResourceOverrides(spec *corev1.PodSpec, overrides []leaderworkerset.GroupResourceOverride, groupIndex int32) {
+	for _, override := range overrides {
+		if !slices.Contains(override.GroupIndices, groupIndex) {
+			continue
+		}
+		for _, containerOverride := range override.Containers {
+			for i := range spec.Containers {
+				resources := &spec.Containers[i].Resources
+				if spec.Containers[i].Name != containerOverride.Name {
+					continue
+				}
+				resources.Requests = mergeResourceList(resources.Requests, containerOverride.Resources.Requests)
+				resources.Limits = mergeResourceList(resources.Limits, containerOverride.Resources.Limits)
+				if len(containerOverride.Resources.Claims) != 0 {
+					resources.Claims = slices.Clone(containerOverride.Resources.Claims)
+				}
+			}
+		}
+	}
+}
+
+func mergeResourceList(list, override corev1.ResourceList) corev1.ResourceList {
+	if len(override) == 0 {
+		return list
+	}
+	if list == nil {
+		list = make(corev1.ResourceList, len(override))
+	}
+	for name, quantity := range override {
+		list[name] = quantity.DeepCopy()
+	}
+	return list
+}
+
+// ApplyCoordinatorTemplate replaces the spec of a leader pod created from the shared leader
+// template with the one of the coordinator template, and adds the labels and annotations of the
+// template to the ones of the pod. The fields set on the pod before, by the statefulset, the
+// controller and the admission plugins, e.g. the volume of the service account token, are kept.
+func ApplyCoordinatorTemplate(pod *corev1.Pod, template *corev1.PodTemplateSpec) {
+	spec := template.Spec.DeepCopy()
+	spec.Hostname = pod.Spec.Hostname
+	spec.Subdomain = pod.Spec.Subdomain
+	if spec.PriorityClassName == pod.Spec.PriorityClassName {
+		spec.Priority = pod.Spec.Priority
+	}
+	for _, gate := range pod.Spec.ReadinessGates {
+		if !slices.Contains(spec.ReadinessGates, gate) {
+			spec.ReadinessGates = append(spec.ReadinessGates, gate)
+		}
+	}
+
+	// The volumes missing from the template were added by the admission plugins, along with their
+	// mounts in every container.
+	var addedVolumes []string
+	for _, volume := range pod.Spec.Volumes {
+		if !slices.ContainsFunc(spec.Volumes, func(v corev1.Volume) bool { return v.Name == volume.Name }) {
+			spec.Volumes = append(spec.Volumes, volume)
+			addedVolumes = append(addedVolumes, volume.Name)
+		}
+	}
+	var addedMounts []corev1.VolumeMount
+	for _, container := range slices.Concat(pod.Spec.InitContainers, pod.Spec.Containers) {
+		for _, mount := range container.VolumeMounts {
+			if slices.Contains(addedVolumes, mount.Name) && !slices.ContainsFunc(addedMounts, func(m corev1.VolumeMount) bool { return m.Name == mount.Name }) {
+				addedMounts = append(addedMounts, mount)
+			}
+		}
+	}
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for i := range containers {
+			for _, mount := range addedMounts {
+				if !slices.ContainsFunc(containers[i].VolumeMounts, func(m corev1.VolumeMount) bool { return m.MountPath == mount.MountPath }) {
+					containers[i].VolumeMounts = append(containers[i].VolumeMounts, mount)
+				}
+			}
+		}
+	}
+	pod.Spec = *spec
+
+	for key, value := range template.Labels {
+		if _, found := pod.Labels[key]; !found {
+			pod.Labels[key] = value
+		}
+	}
+	for key, value := range template.Annotations {
+		if _, found := pod.Annotations[key]; !found {
+			pod.Annotations[key] = value
+		}
+	}
+	pod.Labels[leaderworkerset.CoordinatorGroupLabelKey] = "true"
+}
+
+// ApplySecurityContextDefaults merges the defaults into the security context of the pod and of
+// all its containers and init containers. Only the fields left unset are defaulted, the fields
+// set by the pod, e.g. a list of dropped capabilities, are kept as they are.
+func ApplySecurityContextDefaults(spec *corev1.PodSpec, podDefaults *corev1.PodSecurityContext, containerDefaults *corev1.SecurityContext) {
+	if podDefaults != nil {
+		if spec.SecurityContext == nil {
+			spec.SecurityContext = &corev1.PodSecurityContext{}
+		}
+		mergeUnsetFields(spec.SecurityContext, podDefaults.DeepCopy())
+	}
+	if containerDefaults == nil {
+		return
+	}
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for i := range containers {
+			if containers[i].SecurityContext == nil {
+				containers[i].SecurityContext = &corev1.SecurityContext{}
+			}
+			mergeUnsetFields(containers[i].SecurityContext, containerDefaults.DeepCopy())
+		}
+	}
+}
+
+// mergeUnsetFields sets the zero fields of the struct pointed by dst to the ones of src,
+// which must point to a struct of the same type.
+func mergeUnsetFields(dst, src any) {
+	dstValue, srcValue := reflect.ValueOf(dst).Elem(), reflect.ValueOf(src).Elem()
+	for i := range dstValue.NumField() {
+		if dstValue.Field(i).IsZero() {
+			dstValue.Field(i).Set(srcValue.Field(i))
+		}
+	}
+}
+
+// addEnvVarsIfNotExists puts the env vars ahead of the env of the container, in the given order,
+// followed by the env of the container in its own order without the env vars of the same names.
+// The order only depends on the inputs, so that every render of a pod yields the same spec.
 func addEnvVarsIfNotExists(c *corev1.Container, firstEnv corev1.EnvVar, e ...corev1.EnvVar) {
 	newEnvVars := make([]corev1.EnvVar, 0)
 
@@ -159,16 +413,180 @@ func AddLWSVariables(pod *corev1.Pod) error {
 
 	// The order of injection needs attention, see
 	// https://github.com/kubernetes-sigs/lws/pull/152
+	envVars := []corev1.EnvVar{sizeEnvVar, workerIndexEnvVar}
+	placeholders := map[string]string{
+		leaderworkerset.LwsLeaderAddress: leaderAddressEnvVar.Value,
+		leaderworkerset.LwsGroupSize:     sizeEnvVar.Value,
+		leaderworkerset.LwsWorkerIndex:   workerIndexEnvVar.Value,
+	}
+	// The candidates have no fixed leader, they find each other through the service of their group.
+	if pod.Labels[leaderworkerset.CandidateLabelKey] == "true" {
+		leaderAddressEnvVar = corev1.EnvVar{
+			Name:  leaderworkerset.LwsGroupAddress,
+			Value: fmt.Sprintf("%s.%s", pod.Spec.Subdomain, pod.ObjectMeta.Namespace),
+		}
+		delete(placeholders, leaderworkerset.LwsLeaderAddress)
+		placeholders[leaderworkerset.LwsGroupAddress] = leaderAddressEnvVar.Value
+	}
+	if pod.Labels[leaderworkerset.CoordinatorGroupLabelKey] == "true" {
+		envVars = append(envVars, corev1.EnvVar{Name: leaderworkerset.LwsCoordinatorGroup, Value: "true"})
+		placeholders[leaderworkerset.LwsCoordinatorGroup] = "true"
+	}
 	for i := range pod.Spec.Containers {
-		addEnvVarsIfNotExists(&pod.Spec.Containers[i], leaderAddressEnvVar, sizeEnvVar, workerIndexEnvVar)
+		addEnvVarsIfNotExists(&pod.Spec.Containers[i], leaderAddressEnvVar, envVars...)
+		resolvePlaceholders(&pod.Spec.Containers[i], placeholders)
 	}
 	for i := range pod.Spec.InitContainers {
-		addEnvVarsIfNotExists(&pod.Spec.InitContainers[i], leaderAddressEnvVar, sizeEnvVar, workerIndexEnvVar)
+		addEnvVarsIfNotExists(&pod.Spec.InitContainers[i], leaderAddressEnvVar, envVars...)
+		resolvePlaceholders(&pod.Spec.InitContainers[i], placeholders)
 	}
 
 	return nil
 }
 
+// AddWorkloadIdentityVariables adds the UID, name and namespace of the lws as environment variables
+// to every container of a pod carrying the UID of its lws. The variables already set by a container
+// are kept as they are.
+func AddWorkloadIdentityVariables(pod *corev1.Pod) {
+	uid, found := pod.Annotations[leaderworkerset.WorkloadUIDAnnotationKey]
+	if !found {
+		return
+	}
+	envVars := []corev1.EnvVar{
+		{Name: leaderworkerset.LwsUID, Value: uid},
+		{Name: leaderworkerset.LwsName, Value: pod.Labels[leaderworkerset.SetNameLabelKey]},
+		{Name: leaderworkerset.LwsNamespace, Value: pod.Namespace},
+	}
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for i := range containers {
+			for _, envVar := range envVars {
+				if !slices.ContainsFunc(containers[i].Env, func(env corev1.EnvVar) bool { return env.Name == envVar.Name }) {
+					containers[i].Env = append(containers[i].Env, envVar)
+				}
+			}
+		}
+	}
+}
+
+// resolvePlaceholders replaces the $(NAME) placeholders of the LWS env vars within the values
+// of the env vars of the container. Unknown placeholders and escaped ones, i.e. $$(NAME), are
+// left untouched for the kubelet to expand.
+func resolvePlaceholders(c *corev1.Container, placeholders map[string]string) {
+	for i := range c.Env {
+		c.Env[i].Value = expandPlaceholders(c.Env[i].Value, placeholders)
+	}
+}
+
+func expandPlaceholders(value string, placeholders map[string]string) string {
+	if !strings.Contains(value, "$(") {
+		return value
+	}
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '$' || i+1 == len(value) {
+			b.WriteByte(value[i])
+			continue
+		}
+		switch value[i+1] {
+		case '$':
+			b.WriteString("$$")
+			i++
+			continue
+		case '(':
+			if end := strings.IndexByte(value[i+2:], ')'); end != -1 {
+				name := value[i+2 : i+2+end]
+				if resolved, ok := placeholders[name]; ok {
+					b.WriteString(resolved)
+					i += end + 2
+					continue
+				}
+			}
+		}
+		b.WriteByte(value[i])
+	}
+	return b.String()
+}
+
+const (
+	// TopologyInitContainerName is the name of the init container rendering the topology file.
+	TopologyInitContainerName = "lws-topology"
+	// TopologyVolumeName is the name of the emptyDir volume holding the topology file.
+	TopologyVolumeName = "lws-topology"
+	// TopologyFileName is the name of the topology file under the volume mount path.
+	TopologyFileName = "topology"
+)
+
+// AddTopologyFileInitContainer injects an init container which writes the group topology
+// into a file on an emptyDir volume mounted into every container at mountPath. The LWS
+// environment variables are expected to be injected into the init container afterwards,
+// while the name and group index are read from the pod labels via the downward API.
+// The init container gets a copy of resources, none if nil.
+func AddTopologyFileInitContainer(pod *corev1.Pod, image string, mountPath string, resources *corev1.ResourceRequirements) {
+	for _, c := range pod.Spec.InitContainers {
+		if c.Name == TopologyInitContainerName {
+			return
+		}
+	}
+
+	volumeMount := corev1.VolumeMount{
+		Name:      TopologyVolumeName,
+		MountPath: mountPath,
+	}
+	for i := range pod.Spec.Containers {
+		pod.Spec.Containers[i].VolumeMounts = append(pod.Spec.Containers[i].VolumeMounts, volumeMount)
+	}
+	for i := range pod.Spec.InitContainers {
+		pod.Spec.InitContainers[i].VolumeMounts = append(pod.Spec.InitContainers[i].VolumeMounts, volumeMount)
+	}
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		Name: TopologyVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+
+	script := fmt.Sprintf(`cat > %s <<EOF
+LWS_NAME=${LWS_NAME}
+LWS_GROUP_INDEX=${LWS_GROUP_INDEX}
+%s=${%s}
+%s=${%s}
+%s=${%s}
+EOF`, path.Join(mountPath, TopologyFileName),
+		leaderworkerset.LwsLeaderAddress, leaderworkerset.LwsLeaderAddress,
+		leaderworkerset.LwsGroupSize, leaderworkerset.LwsGroupSize,
+		leaderworkerset.LwsWorkerIndex, leaderworkerset.LwsWorkerIndex)
+
+	initContainer := corev1.Container{
+		Name:    TopologyInitContainerName,
+		Image:   image,
+		Command: []string{"sh", "-c", script},
+		Env: []corev1.EnvVar{
+			{
+				Name: "LWS_NAME",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{
+						FieldPath: fmt.Sprintf("metadata.labels['%s']", leaderworkerset.SetNameLabelKey),
+					},
+				},
+			},
+			{
+				Name: "LWS_GROUP_INDEX",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{
+						FieldPath: fmt.Sprintf("metadata.labels['%s']", leaderworkerset.GroupIndexLabelKey),
+					},
+				},
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{volumeMount},
+	}
+	if resources != nil {
+		initContainer.Resources = *resources.DeepCopy()
+	}
+	// The topology init container goes first so that the file is available to the user init containers as well.
+	pod.Spec.InitContainers = append([]corev1.Container{initContainer}, pod.Spec.InitContainers...)
+}
+
 // IsPodReady returns true if a pod is ready; false otherwise.
 func IsPodReady(pod *corev1.Pod) bool {
 	return IsPodReadyConditionTrue(pod.Status)
@@ -17,11 +17,25 @@ limitations under the License.
 package pod
 
 import (
+	"context"
+	"encoding/json"
+	"slices"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/utils"
+	"sigs.k8s.io/lws/pkg/utils/naming"
 	"sigs.k8s.io/lws/test/wrappers"
 )
 
@@ -100,6 +114,125 @@ func TestContainerRestarted(t *testing.T) {
 	}
 }
 
+func TestPodDeadlineExceeded(t *testing.T) {
+	tests := []struct {
+		name                   string
+		pod                    corev1.Pod
+		expectDeadlineExceeded bool
+	}{
+		{
+			name: "Pod failed with DeadlineExceeded reason",
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					Phase:  corev1.PodFailed,
+					Reason: "DeadlineExceeded",
+				},
+			},
+			expectDeadlineExceeded: true,
+		},
+		{
+			name: "Pod failed with other reason",
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					Phase:  corev1.PodFailed,
+					Reason: "Evicted",
+				},
+			},
+		},
+		{
+			name: "Pod in running phase",
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+				},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			deadlineExceeded := PodDeadlineExceeded(tc.pod)
+			if deadlineExceeded != tc.expectDeadlineExceeded {
+				t.Errorf("Expected value %t, got %t", tc.expectDeadlineExceeded, deadlineExceeded)
+			}
+		})
+	}
+}
+
+func TestCrashLoopingContainer(t *testing.T) {
+	now := time.Now()
+	containerStatus := func(name string, restartCount int32, finishedAgo time.Duration) corev1.ContainerStatus {
+		return corev1.ContainerStatus{
+			Name:         name,
+			RestartCount: restartCount,
+			LastTerminationState: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{FinishedAt: metav1.NewTime(now.Add(-finishedAgo))},
+			},
+		}
+	}
+	tests := []struct {
+		name          string
+		pod           corev1.Pod
+		wantContainer string
+		wantFound     bool
+	}{
+		{
+			name: "no restarts",
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{{Name: "worker"}},
+				},
+			},
+		},
+		{
+			name: "restarts below the threshold",
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{containerStatus("worker", 2, time.Minute)},
+				},
+			},
+		},
+		{
+			name: "restarts reaching the threshold within the window",
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{
+						containerStatus("sidecar", 0, time.Minute),
+						containerStatus("worker", 3, time.Minute),
+					},
+				},
+			},
+			wantContainer: "worker",
+			wantFound:     true,
+		},
+		{
+			name: "init container restarts reaching the threshold within the window",
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					InitContainerStatuses: []corev1.ContainerStatus{containerStatus("init", 5, time.Minute)},
+				},
+			},
+			wantContainer: "init",
+			wantFound:     true,
+		},
+		{
+			name: "restarts reaching the threshold before the window",
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{containerStatus("worker", 3, time.Hour)},
+				},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			container, found := CrashLoopingContainer(tc.pod, 3, 10*time.Minute, now)
+			if container != tc.wantContainer || found != tc.wantFound {
+				t.Errorf("Expected (%q, %t), got (%q, %t)", tc.wantContainer, tc.wantFound, container, found)
+			}
+		})
+	}
+}
+
 func TestAddLWSVariables(t *testing.T) {
 	tests := []struct {
 		name                     string
@@ -184,3 +317,385 @@ func TestAddLWSVariables(t *testing.T) {
 		})
 	}
 }
+
+func TestAddLWSVariablesPlaceholders(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		wantValue string
+	}{
+		{
+			name:      "no placeholder",
+			value:     "--tensor-parallel-size=8",
+			wantValue: "--tensor-parallel-size=8",
+		},
+		{
+			name:      "group size placeholder",
+			value:     "--pipeline-parallel-size=$(LWS_GROUP_SIZE)",
+			wantValue: "--pipeline-parallel-size=3",
+		},
+		{
+			name:      "several placeholders",
+			value:     "$(LWS_LEADER_ADDRESS):8080/$(LWS_WORKER_INDEX)",
+			wantValue: "test-sample-0.test-sample.default:8080/1",
+		},
+		{
+			name:      "unknown placeholder",
+			value:     "$(POD_NAME)-$(LWS_GROUP_SIZE)",
+			wantValue: "$(POD_NAME)-3",
+		},
+		{
+			name:      "escaped placeholder",
+			value:     "$$(LWS_GROUP_SIZE)",
+			wantValue: "$$(LWS_GROUP_SIZE)",
+		},
+		{
+			name:      "unterminated placeholder",
+			value:     "$(LWS_GROUP_SIZE",
+			wantValue: "$(LWS_GROUP_SIZE",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := wrappers.MakePodWithLabels("test-sample", "0", "1", "default", 3)
+			pod.Spec.Subdomain = "test-sample"
+			pod.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "ARGS", Value: tc.value}}
+			if err := AddLWSVariables(pod); err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			for _, env := range pod.Spec.Containers[0].Env {
+				if env.Name != "ARGS" {
+					continue
+				}
+				if env.Value != tc.wantValue {
+					t.Errorf("Expected value %q, got %q", tc.wantValue, env.Value)
+				}
+				return
+			}
+			t.Errorf("Expected the ARGS env var to be kept")
+		})
+	}
+}
+
+func TestAddLWSVariablesCandidate(t *testing.T) {
+	pod := wrappers.MakePodWithLabels("test-sample", "1", "2", "default", 3)
+	pod.Labels[leaderworkerset.CandidateLabelKey] = "true"
+	pod.Spec.Subdomain = "test-sample-1"
+	pod.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "ARGS", Value: "--peers=$(LWS_GROUP_ADDRESS) --leader=$(LWS_LEADER_ADDRESS)"}}
+	if err := AddLWSVariables(pod); err != nil {
+		t.Fatalf("failed with error: %s", err.Error())
+	}
+	want := []corev1.EnvVar{
+		{Name: leaderworkerset.LwsGroupAddress, Value: "test-sample-1.default"},
+		{Name: leaderworkerset.LwsGroupSize, Value: "3"},
+		{Name: leaderworkerset.LwsWorkerIndex, Value: "2"},
+		{Name: "ARGS", Value: "--peers=test-sample-1.default --leader=$(LWS_LEADER_ADDRESS)"},
+	}
+	if diff := cmp.Diff(want, pod.Spec.Containers[0].Env); diff != "" {
+		t.Errorf("Unexpected env (-want +got):\n%s", diff)
+	}
+}
+
+func TestAddTopologyFileInitContainer(t *testing.T) {
+	tests := []struct {
+		name      string
+		pod       *corev1.Pod
+		mountPath string
+		resources *corev1.ResourceRequirements
+	}{
+		{
+			name:      "Leader pod",
+			pod:       wrappers.MakePodWithLabels("test-sample", "0", "0", "default", 3),
+			mountPath: "/etc/lws",
+		},
+		{
+			name:      "Worker pod, custom mount path",
+			pod:       wrappers.MakePodWithLabels("test-sample", "1", "2", "default", 3),
+			mountPath: "/var/run/lws",
+		},
+		{
+			name:      "Leader pod, resources",
+			pod:       wrappers.MakePodWithLabels("test-sample", "0", "0", "default", 3),
+			mountPath: "/etc/lws",
+			resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10m")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("32Mi")},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			wantVolumeMount := corev1.VolumeMount{Name: TopologyVolumeName, MountPath: tc.mountPath}
+			initContainersCount := len(tc.pod.Spec.InitContainers)
+
+			AddTopologyFileInitContainer(tc.pod, "busybox:1.36", tc.mountPath, tc.resources)
+			// Injecting twice must be a no-op.
+			AddTopologyFileInitContainer(tc.pod, "busybox:1.36", tc.mountPath, tc.resources)
+
+			if len(tc.pod.Spec.InitContainers) != initContainersCount+1 {
+				t.Fatalf("Expected %d init containers, got %d", initContainersCount+1, len(tc.pod.Spec.InitContainers))
+			}
+			initContainer := tc.pod.Spec.InitContainers[0]
+			if initContainer.Name != TopologyInitContainerName || initContainer.Image != "busybox:1.36" {
+				t.Errorf("Unexpected first init container %s with image %s", initContainer.Name, initContainer.Image)
+			}
+			if len(initContainer.Command) != 3 || !strings.Contains(initContainer.Command[2], tc.mountPath+"/"+TopologyFileName) {
+				t.Errorf("Unexpected init container command %v", initContainer.Command)
+			}
+			if diff := cmp.Diff(ptr.Deref(tc.resources, corev1.ResourceRequirements{}), initContainer.Resources); diff != "" {
+				t.Errorf("Unexpected init container resources (-want +got):\n%s", diff)
+			}
+
+			wantVolumes := []corev1.Volume{{
+				Name:         TopologyVolumeName,
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+			}}
+			if diff := cmp.Diff(wantVolumes, tc.pod.Spec.Volumes); diff != "" {
+				t.Errorf("Unexpected volumes (-want +got):\n%s", diff)
+			}
+			for _, container := range append(tc.pod.Spec.Containers, tc.pod.Spec.InitContainers...) {
+				if diff := cmp.Diff([]corev1.VolumeMount{wantVolumeMount}, container.VolumeMounts); diff != "" {
+					t.Errorf("Unexpected volume mounts for container %s (-want +got):\n%s", container.Name, diff)
+				}
+			}
+
+			// The LWS env vars injected afterwards must reach the topology init container too.
+			if err := AddLWSVariables(tc.pod); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tc.pod.Spec.InitContainers[0].Env[0].Name != leaderworkerset.LwsLeaderAddress {
+				t.Errorf("Expected %s to be injected into the topology init container", leaderworkerset.LwsLeaderAddress)
+			}
+		})
+	}
+}
+
+func TestAddWorkloadIdentityVariables(t *testing.T) {
+	pod := wrappers.MakePodWithLabels("test-sample", "1", "2", "default", 4)
+	pod.Annotations[leaderworkerset.WorkloadUIDAnnotationKey] = "6f1a2b3c-uid"
+	pod.Spec = corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "init"}},
+		Containers:     []corev1.Container{{Name: "worker"}, {Name: "sidecar"}},
+	}
+	pod.Spec.Containers[1].Env = []corev1.EnvVar{{Name: leaderworkerset.LwsName, Value: "user-defined"}}
+	AddWorkloadIdentityVariables(pod)
+	// The webhook may be reinvoked on the same pod.
+	AddWorkloadIdentityVariables(pod)
+
+	identity := []corev1.EnvVar{
+		{Name: leaderworkerset.LwsUID, Value: "6f1a2b3c-uid"},
+		{Name: leaderworkerset.LwsName, Value: "test-sample"},
+		{Name: leaderworkerset.LwsNamespace, Value: "default"},
+	}
+	wantEnv := map[string][]corev1.EnvVar{
+		"init":   identity,
+		"worker": identity,
+		"sidecar": {
+			{Name: leaderworkerset.LwsName, Value: "user-defined"},
+			{Name: leaderworkerset.LwsUID, Value: "6f1a2b3c-uid"},
+			{Name: leaderworkerset.LwsNamespace, Value: "default"},
+		},
+	}
+	for _, container := range slices.Concat(pod.Spec.InitContainers, pod.Spec.Containers) {
+		if diff := cmp.Diff(wantEnv[container.Name], container.Env); diff != "" {
+			t.Errorf("Unexpected env of container %s (-want +got):\n%s", container.Name, diff)
+		}
+	}
+
+	// The pods of the lws not opted in don't get the identity.
+	pod = wrappers.MakePodWithLabels("test-sample", "1", "2", "default", 4)
+	wantPod := pod.DeepCopy()
+	AddWorkloadIdentityVariables(pod)
+	if diff := cmp.Diff(wantPod, pod); diff != "" {
+		t.Errorf("Unexpected pod change (-want +got):\n%s", diff)
+	}
+}
+
+func TestAddLWSVariablesDeterministicOrder(t *testing.T) {
+	newPod := func() *corev1.Pod {
+		pod := wrappers.MakePodWithLabels("test-sample", "1", "2", "default", 4)
+		pod.Spec.Containers[0].Env = []corev1.EnvVar{
+			{Name: "ZONE", Value: "a"},
+			{Name: "ADDRESS", Value: "$(LWS_LEADER_ADDRESS):8080"},
+			{Name: leaderworkerset.LwsWorkerIndex, Value: "user-defined"},
+			{Name: "MODEL", Value: "m"},
+		}
+		return pod
+	}
+	wantEnv := []corev1.EnvVar{
+		{Name: leaderworkerset.LwsLeaderAddress, Value: "test-sample-1.test-sample.default"},
+		{Name: leaderworkerset.LwsGroupSize, Value: "4"},
+		{Name: leaderworkerset.LwsWorkerIndex, Value: "2"},
+		{Name: "ZONE", Value: "a"},
+		{Name: "ADDRESS", Value: "test-sample-1.test-sample.default:8080"},
+		{Name: "MODEL", Value: "m"},
+	}
+
+	var wantHash string
+	for i := range 20 {
+		pod := newPod()
+		if err := AddLWSVariables(pod); err != nil {
+			t.Fatal(err)
+		}
+		// The webhook may be reinvoked on the same pod.
+		if i%2 == 1 {
+			if err := AddLWSVariables(pod); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if diff := cmp.Diff(wantEnv, pod.Spec.Containers[0].Env); diff != "" {
+			t.Fatalf("Unexpected env on render %d (-want +got):\n%s", i, diff)
+		}
+		spec, err := json.Marshal(pod.Spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hash := utils.Sha1Hash(string(spec))
+		if wantHash == "" {
+			wantHash = hash
+		} else if hash != wantHash {
+			t.Fatalf("Expected the pod spec hash %s on render %d, got %s", wantHash, i, hash)
+		}
+	}
+}
+
+func TestListManagedPods(t *testing.T) {
+	lws := wrappers.BuildLeaderWorkerSet("default").Obj()
+	pod := func(groupIndex, workerIndex, subGroupIndex string) *corev1.Pod {
+		pod := wrappers.MakePodWithLabels("test-sample", groupIndex, workerIndex, "default", 4)
+		if subGroupIndex != "" {
+			pod.Labels[leaderworkerset.SubGroupIndexLabelKey] = subGroupIndex
+		}
+		return pod
+	}
+	coordinatorLeader := pod("0", "0", "0")
+	coordinatorLeader.Labels[leaderworkerset.CoordinatorGroupLabelKey] = "true"
+	// The leader pods created before the group index label was persisted fall back to their ordinal.
+	unlabeledLeader := pod("1", "0", "")
+	delete(unlabeledLeader.Labels, leaderworkerset.GroupIndexLabelKey)
+	otherPod := wrappers.MakePodWithLabels("other", "0", "0", "default", 4)
+	objects := []client.Object{pod("1", "2", "1"), pod("0", "3", "1"), unlabeledLeader, pod("0", "1", "0"), coordinatorLeader, otherPod}
+	c := fake.NewClientBuilder().WithObjects(objects...).Build()
+
+	pods, err := ListManagedPods(context.TODO(), c, lws)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type position struct {
+		Name             string
+		Role             PodRole
+		GroupIndex       int
+		WorkerIndex      int
+		SubGroupIndex    *int
+		CoordinatorGroup bool
+	}
+	var got []position
+	for _, pod := range pods {
+		got = append(got, position{pod.Pod.Name, pod.Role, pod.GroupIndex, pod.WorkerIndex, pod.SubGroupIndex, pod.CoordinatorGroup})
+	}
+	want := []position{
+		{"test-sample-0", LeaderPodRole, 0, 0, ptr.To(0), true},
+		{"test-sample-0-1", WorkerPodRole, 0, 1, ptr.To(0), false},
+		{"test-sample-0-3", WorkerPodRole, 0, 3, ptr.To(1), false},
+		{"test-sample-1", LeaderPodRole, 1, 0, nil, false},
+		{"test-sample-1-2", WorkerPodRole, 1, 2, ptr.To(1), false},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected managed pods (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseManagedPodErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		label string
+		value string
+	}{
+		{name: "invalid worker index", label: leaderworkerset.WorkerIndexLabelKey, value: "leader"},
+		{name: "invalid group index", label: leaderworkerset.GroupIndexLabelKey, value: "first"},
+		{name: "invalid subgroup index", label: leaderworkerset.SubGroupIndexLabelKey, value: "first"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := wrappers.MakePodWithLabels("test-sample", "0", "1", "default", 4)
+			pod.Labels[tc.label] = tc.value
+			if _, err := ParseManagedPod(pod, "test-sample"); err == nil {
+				t.Error("Expected an error parsing the pod")
+			}
+		})
+	}
+}
+
+func TestGroupPodAddress(t *testing.T) {
+	tests := []struct {
+		name            string
+		subdomainPolicy leaderworkerset.SubdomainPolicy
+		groupIndex      int
+		workerIndex     int
+		wantAddress     string
+		// wantSubdomain is the subdomain of the pod, set by the pod webhook for the leaders and
+		// by the service of the worker statefulsets for the workers.
+		wantSubdomain string
+	}{
+		{
+			name:            "leader with a shared subdomain",
+			subdomainPolicy: leaderworkerset.SubdomainShared,
+			groupIndex:      1,
+			wantAddress:     "test-sample-1.test-sample.default",
+			wantSubdomain:   "test-sample",
+		},
+		{
+			name:            "worker with a shared subdomain",
+			subdomainPolicy: leaderworkerset.SubdomainShared,
+			groupIndex:      1,
+			workerIndex:     2,
+			wantAddress:     "test-sample-1-2.test-sample.default",
+			wantSubdomain:   "test-sample",
+		},
+		{
+			name:            "leader with a subdomain per replica",
+			subdomainPolicy: leaderworkerset.SubdomainUniquePerReplica,
+			groupIndex:      1,
+			wantAddress:     "test-sample-1.test-sample-1.default",
+			wantSubdomain:   "test-sample-1",
+		},
+		{
+			name:            "worker with a subdomain per replica",
+			subdomainPolicy: leaderworkerset.SubdomainUniquePerReplica,
+			groupIndex:      1,
+			workerIndex:     2,
+			wantAddress:     "test-sample-1-2.test-sample-1.default",
+			wantSubdomain:   "test-sample-1",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").SubdomainPolicy(tc.subdomainPolicy).Obj()
+			got := GroupPodAddress(lws, naming.Default(), tc.groupIndex, tc.workerIndex)
+			if got != tc.wantAddress {
+				t.Errorf("Expected the address %s, got %s", tc.wantAddress, got)
+			}
+
+			// The address is the hostname of the pod, within its subdomain, the leader's one being
+			// the address the workers reach their leader at.
+			pod := wrappers.MakePodWithLabels("test-sample", strconv.Itoa(tc.groupIndex), strconv.Itoa(tc.workerIndex), "default", 3)
+			pod.Spec.Subdomain = tc.wantSubdomain
+			if want := pod.Name + "." + pod.Spec.Subdomain + "." + pod.Namespace; got != want {
+				t.Errorf("Expected the address to match the pod hostname %s, got %s", want, got)
+			}
+			if err := AddLWSVariables(pod); err != nil {
+				t.Fatal(err)
+			}
+			env := pod.Spec.Containers[0].Env
+			i := slices.IndexFunc(env, func(e corev1.EnvVar) bool { return e.Name == leaderworkerset.LwsLeaderAddress })
+			if i == -1 {
+				t.Fatalf("Expected the %s variable to be set", leaderworkerset.LwsLeaderAddress)
+			}
+			if want := GroupPodAddress(lws, naming.Default(), tc.groupIndex, 0); env[i].Value != want {
+				t.Errorf("Expected the leader address %s, got %s", want, env[i].Value)
+			}
+		})
+	}
+}
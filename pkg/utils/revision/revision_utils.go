@@ -23,9 +23,12 @@ import (
 	"fmt"
 	"hash"
 	"hash/fnv"
+	"slices"
+	"strconv"
 
 	"github.com/davecgh/go-spew/spew"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -43,11 +46,22 @@ import (
 // Functions in this package are adapted from https://github.com/kubernetes/kubernetes/blob/master/pkg/controller/statefulset/ and
 // https://github.com/kubernetes/kubernetes/blob/master/pkg/controller/history/controller_history.go
 
+// HashFunc hashes the data of a revision into its revision key. The probe is 0 on the first attempt, and
+// incremented each time the key is already used by a revision with different data.
+type HashFunc func(revision *appsv1.ControllerRevision, probe int32) string
+
 // NewRevision instantiates a new ControllerRevision containing a patch that reapplies the target state of LeaderWorkerSet.
 // The Revision of the returned ControllerRevision is set to revision. If the returned error is nil, the returned
 // ControllerRevision is valid. LeaderWorkerSet revisions are stored as patches that re-apply the current state of set
 // to a new LeaderWorkerSet using a strategic merge patch to replace the saved state of the new LeaderWorkerSet.
 func NewRevision(ctx context.Context, k8sClient client.Client, lws *leaderworkerset.LeaderWorkerSet, revisionKey string) (*appsv1.ControllerRevision, error) {
+	return NewRevisionWithHash(ctx, k8sClient, lws, revisionKey, hashRevision)
+}
+
+// NewRevisionWithHash is NewRevision with the revision key hashed by hashFunc when revisionKey is empty. Like the
+// collision count of the StatefulSets, the key is probed again while it is used by a revision with different data,
+// so that the pods of two different templates never share a revision key.
+func NewRevisionWithHash(ctx context.Context, k8sClient client.Client, lws *leaderworkerset.LeaderWorkerSet, revisionKey string, hashFunc HashFunc) (*appsv1.ControllerRevision, error) {
 	var controllerKind = leaderworkerset.GroupVersion.WithKind("LeaderWorkerSet")
 	selector, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchLabels: map[string]string{
 		leaderworkerset.SetNameLabelKey: lws.Name,
@@ -81,7 +95,10 @@ func NewRevision(ctx context.Context, k8sClient client.Client, lws *leaderworker
 		Revision: revision,
 	}
 
-	hash := hashRevision(cr)
+	hash, err := uniqueHash(cr, revisions, hashFunc)
+	if err != nil {
+		return nil, err
+	}
 	if revisionKey == "" {
 		revisionKey = hash
 	}
@@ -190,6 +207,33 @@ func EqualRevision(lhs *appsv1.ControllerRevision, rhs *appsv1.ControllerRevisio
 	return bytes.Equal(lhs.Data.Raw, rhs.Data.Raw) && apiequality.Semantic.DeepEqual(lhs.Data.Object, rhs.Data.Object)
 }
 
+// PodImpactingChange returns whether the leaderWorkerTemplate or the networkConfig of the LeaderWorkerSets,
+// i.e. the state saved in their revisions, differ in more than the labels and annotations of the leader and
+// worker templates. Only these differences can be applied to the existing pods without recreating them.
+func PodImpactingChange(lhs, rhs *leaderworkerset.LeaderWorkerSet) bool {
+	return !apiequality.Semantic.DeepEqual(podImpactingSpec(lhs), podImpactingSpec(rhs))
+}
+
+// podImpactingSpec returns the state of the LeaderWorkerSet saved in the revisions, without the labels and
+// annotations of the templates.
+func podImpactingSpec(lws *leaderworkerset.LeaderWorkerSet) leaderworkerset.LeaderWorkerSetSpec {
+	spec := leaderworkerset.LeaderWorkerSetSpec{
+		LeaderWorkerTemplate: *lws.Spec.LeaderWorkerTemplate.DeepCopy(),
+		NetworkConfig:        lws.Spec.NetworkConfig,
+	}
+	// Like in the patches, a nil NetworkConfig is the Shared subdomain policy.
+	if spec.NetworkConfig == nil {
+		subdomainPolicy := leaderworkerset.SubdomainShared
+		spec.NetworkConfig = &leaderworkerset.NetworkConfig{SubdomainPolicy: &subdomainPolicy}
+	}
+	for _, template := range []*corev1.PodTemplateSpec{spec.LeaderWorkerTemplate.LeaderTemplate, &spec.LeaderWorkerTemplate.WorkerTemplate} {
+		if template != nil {
+			template.Labels, template.Annotations = nil, nil
+		}
+	}
+	return spec
+}
+
 // TruncateRevisions cleans up all other controller revisions except the currentRevision.
 // currentRevision is the one that matches the revisionKey that is passed
 func TruncateRevisions(ctx context.Context, k8sClient client.Client, lws *leaderworkerset.LeaderWorkerSet, revisionKey string) error {
@@ -284,9 +328,25 @@ func revisionName(prefix string, hash string, revisionNumber int64) string {
 	return fmt.Sprintf("%s-%s-%v", prefix, hash, revisionNumber)
 }
 
-// hashRevision hashes the contents of revision's Data using FNV hashing.
-// The returned hash will be a safe encoded string to avoid bad words.
-func hashRevision(revision *appsv1.ControllerRevision) string {
+// uniqueHash returns the first hash of revision, by increasing probe, which isn't the revision key of one
+// of the revisions with different data. The hash of a revision equal to an existing one is its key.
+func uniqueHash(revision *appsv1.ControllerRevision, revisions []*appsv1.ControllerRevision, hashFunc HashFunc) (string, error) {
+	// Each collision is with a different revision, unless the hash function ignores the probe.
+	for probe := range int32(len(revisions) + 1) {
+		hash := hashFunc(revision, probe)
+		collision := slices.ContainsFunc(revisions, func(existing *appsv1.ControllerRevision) bool {
+			return GetRevisionKey(existing) == hash && !EqualRevision(existing, revision)
+		})
+		if !collision {
+			return hash, nil
+		}
+	}
+	return "", fmt.Errorf("no revision key available for revision %d, every probed hash collides with an existing revision", revision.Revision)
+}
+
+// hashRevision hashes the contents of revision's Data using FNV hashing, followed by the probe after
+// a collision. The returned hash will be a safe encoded string to avoid bad words.
+func hashRevision(revision *appsv1.ControllerRevision, probe int32) string {
 	hf := fnv.New32()
 	if len(revision.Data.Raw) > 0 {
 		hf.Write(revision.Data.Raw)
@@ -294,6 +354,10 @@ func hashRevision(revision *appsv1.ControllerRevision) string {
 	if revision.Data.Object != nil {
 		deepHashObject(hf, revision.Data.Object)
 	}
+	// The first probe isn't hashed, so that the keys of the revisions without collision don't change.
+	if probe > 0 {
+		hf.Write([]byte(strconv.FormatInt(int64(probe), 10)))
+	}
 	return rand.SafeEncodeString(fmt.Sprint(hf.Sum32()))
 }
 
@@ -18,11 +18,13 @@ package revision
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
 	"sigs.k8s.io/lws/test/wrappers"
@@ -77,6 +79,70 @@ func TestApplyRevision(t *testing.T) {
 	}
 }
 
+func TestNewRevisionHashCollision(t *testing.T) {
+	ctx := context.TODO()
+	client := fake.NewClientBuilder().Build()
+	// The hash ignores the data of the revisions, so that all the templates collide on the first probe.
+	collidingHash := func(_ *appsv1.ControllerRevision, probe int32) string {
+		return fmt.Sprintf("collision-%d", probe)
+	}
+	newRevision := func(lws *leaderworkerset.LeaderWorkerSet) *appsv1.ControllerRevision {
+		t.Helper()
+		revision, err := NewRevisionWithHash(ctx, client, lws, "", collidingHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return revision
+	}
+
+	lws := wrappers.BuildLeaderWorkerSet("default").Obj()
+	original := newRevision(lws)
+	if _, err := CreateRevision(ctx, client, original, lws); err != nil {
+		t.Fatal(err)
+	}
+	if got := GetRevisionKey(original); got != "collision-0" {
+		t.Errorf("Expected the first revision key to be collision-0, got %s", got)
+	}
+
+	// An updated template hashing to the key of another revision gets the next probe.
+	updatedLws := lws.DeepCopy()
+	updatedLws.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec.Containers[0].Image = "nginx:updated"
+	updated := newRevision(updatedLws)
+	if _, err := CreateRevision(ctx, client, updated, updatedLws); err != nil {
+		t.Fatal(err)
+	}
+	if got := GetRevisionKey(updated); got != "collision-1" {
+		t.Errorf("Expected the colliding revision key to be collision-1, got %s", got)
+	}
+
+	// The keys are stable, the revisions equal to an existing one keep its key.
+	if got := GetRevisionKey(newRevision(lws)); got != "collision-0" {
+		t.Errorf("Expected the original template to keep the key collision-0, got %s", got)
+	}
+	if got := GetRevisionKey(newRevision(updatedLws)); got != "collision-1" {
+		t.Errorf("Expected the updated template to keep the key collision-1, got %s", got)
+	}
+	revision, err := GetRevision(ctx, client, lws, "collision-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !EqualRevision(revision, updated) {
+		t.Errorf("Expected the key collision-1 to only match the updated revision")
+	}
+
+	// A hash function ignoring the probe runs out of keys.
+	otherLws := lws.DeepCopy()
+	otherLws.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec.Containers[0].Image = "nginx:other"
+	if _, err := NewRevisionWithHash(ctx, client, otherLws, "", func(*appsv1.ControllerRevision, int32) string { return "collision-0" }); err == nil {
+		t.Errorf("Expected an error when every probed key collides")
+	}
+
+	// The default hash changes with the probe.
+	if hashRevision(original, 0) == hashRevision(original, 1) {
+		t.Errorf("Expected the probe to change the hash of the revision")
+	}
+}
+
 func TestEqualRevision(t *testing.T) {
 	client := fake.NewClientBuilder().Build()
 	tests := []struct {
@@ -177,6 +243,72 @@ func TestEqualRevision(t *testing.T) {
 	}
 }
 
+func TestPodImpactingChange(t *testing.T) {
+	tests := []struct {
+		name   string
+		update func(lws *leaderworkerset.LeaderWorkerSet)
+		want   bool
+	}{
+		{
+			name:   "no change",
+			update: func(lws *leaderworkerset.LeaderWorkerSet) {},
+		},
+		{
+			name: "labels and annotations of the templates",
+			update: func(lws *leaderworkerset.LeaderWorkerSet) {
+				lws.Spec.LeaderWorkerTemplate.LeaderTemplate.Labels = map[string]string{"team": "serving"}
+				lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Annotations = map[string]string{"owner": "serving"}
+			},
+		},
+		{
+			name: "shared subdomain policy and nil",
+			update: func(lws *leaderworkerset.LeaderWorkerSet) {
+				lws.Spec.NetworkConfig = nil
+			},
+		},
+		{
+			name: "fields outside of the revisions",
+			update: func(lws *leaderworkerset.LeaderWorkerSet) {
+				lws.Spec.Replicas = ptr.To[int32](5)
+				lws.Spec.RolloutStrategy.MetadataUpdatePolicy = leaderworkerset.InPlaceMetadataUpdatePolicy
+			},
+		},
+		{
+			name: "image of the workers",
+			update: func(lws *leaderworkerset.LeaderWorkerSet) {
+				lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec.Containers[0].Image = "nginxinc/nginx-unprivileged:1.28"
+			},
+			want: true,
+		},
+		{
+			name: "labels and size",
+			update: func(lws *leaderworkerset.LeaderWorkerSet) {
+				lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Labels = map[string]string{"team": "serving"}
+				lws.Spec.LeaderWorkerTemplate.Size = ptr.To[int32](4)
+			},
+			want: true,
+		},
+		{
+			name: "subdomain policy",
+			update: func(lws *leaderworkerset.LeaderWorkerSet) {
+				subdomainPolicy := leaderworkerset.SubdomainUniquePerReplica
+				lws.Spec.NetworkConfig = &leaderworkerset.NetworkConfig{SubdomainPolicy: &subdomainPolicy}
+			},
+			want: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := wrappers.BuildLeaderWorkerSet("default").Obj()
+			updated := lws.DeepCopy()
+			tc.update(updated)
+			if got := PodImpactingChange(lws, updated); got != tc.want {
+				t.Errorf("Expected the change to be pod-impacting to be %t, but was %t", tc.want, got)
+			}
+		})
+	}
+}
+
 func TestGetHighestRevision(t *testing.T) {
 	client := fake.NewClientBuilder().Build()
 	lws := wrappers.BuildLeaderWorkerSet("default").Obj()
@@ -49,3 +49,10 @@ func StatefulsetReady(sts appsv1.StatefulSet) bool {
 	return *sts.Spec.Replicas == sts.Status.Replicas &&
 		sts.Status.CurrentRevision == sts.Status.UpdateRevision
 }
+
+// StatefulsetQuorumReady checks whether a sts has at least minReadyReplicas ready replicas,
+// all of them on the current revision.
+func StatefulsetQuorumReady(sts appsv1.StatefulSet, minReadyReplicas int32) bool {
+	return sts.Status.ReadyReplicas >= minReadyReplicas &&
+		sts.Status.CurrentRevision == sts.Status.UpdateRevision
+}
@@ -20,6 +20,8 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/utils/ptr"
 )
 
 func TestGetParentNameAndOrdinal(t *testing.T) {
@@ -76,3 +78,48 @@ func TestGetParentNameAndOrdinal(t *testing.T) {
 		})
 	}
 }
+
+func TestStatefulsetQuorumReady(t *testing.T) {
+	tests := []struct {
+		name             string
+		status           appsv1.StatefulSetStatus
+		minReadyReplicas int32
+		want             bool
+	}{
+		{
+			name:             "quorum reached with missing replicas",
+			status:           appsv1.StatefulSetStatus{Replicas: 2, ReadyReplicas: 2, CurrentRevision: "r1", UpdateRevision: "r1"},
+			minReadyReplicas: 2,
+			want:             true,
+		},
+		{
+			name:             "quorum not reached",
+			status:           appsv1.StatefulSetStatus{Replicas: 3, ReadyReplicas: 1, CurrentRevision: "r1", UpdateRevision: "r1"},
+			minReadyReplicas: 2,
+			want:             false,
+		},
+		{
+			name:             "rolling update in progress",
+			status:           appsv1.StatefulSetStatus{Replicas: 3, ReadyReplicas: 3, CurrentRevision: "r1", UpdateRevision: "r2"},
+			minReadyReplicas: 2,
+			want:             false,
+		},
+		{
+			name:             "no ready replica required",
+			status:           appsv1.StatefulSetStatus{CurrentRevision: "r1", UpdateRevision: "r1"},
+			minReadyReplicas: 0,
+			want:             true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sts := appsv1.StatefulSet{
+				Spec:   appsv1.StatefulSetSpec{Replicas: ptr.To[int32](3)},
+				Status: tc.status,
+			}
+			if got := StatefulsetQuorumReady(sts, tc.minReadyReplicas); got != tc.want {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -19,17 +19,48 @@ package utils
 import (
 	"crypto/sha1"
 	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
 )
 
 const (
 	defaultNamespace = "lws-system"
+
+	// ManagedByLabelKey is the recommended label naming the tool managing an object.
+	ManagedByLabelKey = "app.kubernetes.io/managed-by"
+	// PartOfLabelKey is the recommended label naming the application an object is part of.
+	PartOfLabelKey = "app.kubernetes.io/part-of"
 )
 
+// RecommendedLabels returns the recommended labels to stamp on the objects created for the
+// LeaderWorkerSets, or nil if they are not configured.
+func RecommendedLabels(cfg *configapi.RecommendedLabels) map[string]string {
+	if cfg == nil {
+		return nil
+	}
+	labels := map[string]string{}
+	if cfg.ManagedBy != nil {
+		labels[ManagedByLabelKey] = *cfg.ManagedBy
+	}
+	if cfg.PartOf != nil {
+		labels[PartOfLabelKey] = *cfg.PartOf
+	}
+	return labels
+}
+
 // Sha1Hash accepts an input string and returns the 40 character SHA1 hash digest of the input string.
 func Sha1Hash(s string) string {
 	h := sha1.New()
@@ -65,6 +96,31 @@ func SortByIndex[T appsv1.StatefulSet | corev1.Pod | int](indexFunc func(T) (int
 	return result
 }
 
+// GroupIndex returns the index of the group of a leader pod or a worker statefulset of the lws.
+// The index persisted in the group index label is authoritative, the objects created before it
+// was persisted fall back to the ordinal of their name, so that the groups keep their index
+// across controller upgrades.
+func GroupIndex(obj metav1.Object, lwsName string) (int, error) {
+	if index, found := obj.GetLabels()[leaderworkerset.GroupIndexLabelKey]; found {
+		return strconv.Atoi(index)
+	}
+	ordinal, found := strings.CutPrefix(obj.GetName(), lwsName+"-")
+	if !found {
+		return 0, fmt.Errorf("%s is not a group of %s", obj.GetName(), lwsName)
+	}
+	return strconv.Atoi(ordinal)
+}
+
+// SubGroupIndex returns the index of the subgroup of the worker index in a group of groupSize
+// pods. When groupSize - 1 is divisible by subGroupSize, the leader is considered as the extra
+// pod and is part of the first subgroup.
+func SubGroupIndex(groupSize, subGroupSize, workerIndex int) int {
+	if (groupSize-1)%subGroupSize == 0 {
+		return max(workerIndex-1, 0) / subGroupSize
+	}
+	return workerIndex / subGroupSize
+}
+
 // GetOperatorNamespace will pick the namespace based on the serviceaccount
 func GetOperatorNamespace() string {
 	if data, err := os.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace"); err == nil {
@@ -74,3 +130,178 @@ func GetOperatorNamespace() string {
 	}
 	return defaultNamespace
 }
+
+// ParseExclusiveTopology returns the topology key of the exclusive-topology annotation and whether
+// the annotation is set. A malformed value is reported as a *field.Error.
+func ParseExclusiveTopology(annotations map[string]string) (string, bool, error) {
+	return parseTopologyAnnotation(annotations, leaderworkerset.ExclusiveKeyAnnotationKey)
+}
+
+// ParseSubGroupExclusiveTopology returns the topology key of the subgroup-exclusive-topology annotation
+// and whether the annotation is set. A malformed value is reported as a *field.Error.
+func ParseSubGroupExclusiveTopology(annotations map[string]string) (string, bool, error) {
+	return parseTopologyAnnotation(annotations, leaderworkerset.SubGroupExclusiveKeyAnnotationKey)
+}
+
+// ParsePodInjectionDisabled returns whether the disable-pod-injection annotation is set to true.
+// A malformed value is reported as a *field.Error.
+func ParsePodInjectionDisabled(annotations map[string]string) (bool, error) {
+	return parseBoolAnnotation(annotations, leaderworkerset.DisablePodInjectionAnnotationKey)
+}
+
+// ParseGroupReadinessGate returns whether the group-readiness-gate annotation is set to true.
+// A malformed value is reported as a *field.Error.
+func ParseGroupReadinessGate(annotations map[string]string) (bool, error) {
+	return parseBoolAnnotation(annotations, leaderworkerset.GroupReadinessGateAnnotationKey)
+}
+
+// ParseInjectWorkloadIdentity returns whether the inject-workload-identity annotation is set to true.
+// A malformed value is reported as a *field.Error.
+func ParseInjectWorkloadIdentity(annotations map[string]string) (bool, error) {
+	return parseBoolAnnotation(annotations, leaderworkerset.InjectWorkloadIdentityAnnotationKey)
+}
+
+// ParseLeaderless returns whether the leaderless annotation is set to true.
+// A malformed value is reported as a *field.Error.
+func ParseLeaderless(annotations map[string]string) (bool, error) {
+	return parseBoolAnnotation(annotations, leaderworkerset.LeaderlessAnnotationKey)
+}
+
+func parseBoolAnnotation(annotations map[string]string, key string) (bool, error) {
+	value, found := annotations[key]
+	if !found {
+		return false, nil
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, field.Invalid(field.NewPath("metadata", "annotations").Key(key), value, "must be a boolean")
+	}
+	return enabled, nil
+}
+
+// ParseStickyGroups returns the group indices of the sticky-groups annotation, or nil if the
+// annotation is not set. A malformed value is reported as a *field.Error.
+func ParseStickyGroups(annotations map[string]string) (sets.Set[int], error) {
+	value, found := annotations[leaderworkerset.StickyGroupsAnnotationKey]
+	if !found {
+		return nil, nil
+	}
+	groups := sets.New[int]()
+	for _, item := range strings.Split(value, ",") {
+		index, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil || index < 0 {
+			return nil, field.Invalid(field.NewPath("metadata", "annotations").Key(leaderworkerset.StickyGroupsAnnotationKey), value, "must be a comma-separated list of group indices")
+		}
+		groups.Insert(index)
+	}
+	return groups, nil
+}
+
+// ParseGroupResourceOverrides returns the overrides of the group-resource-overrides annotation,
+// or nil if the annotation is not set.
+func ParseGroupResourceOverrides(annotations map[string]string) ([]leaderworkerset.GroupResourceOverride, error) {
+	value, found := annotations[leaderworkerset.GroupResourceOverridesAnnotationKey]
+	if !found {
+		return nil, nil
+	}
+	var overrides []leaderworkerset.GroupResourceOverride
+	if err := json.Unmarshal([]byte(value), &overrides); err != nil {
+		return nil, fmt.Errorf("parsing the %s annotation: %w", leaderworkerset.GroupResourceOverridesAnnotationKey, err)
+	}
+	return overrides, nil
+}
+
+// ParseInjectedContainerResources returns the resources of the injected-container-resources
+// annotation, or nil if the annotation is not set. A malformed value is reported as a *field.Error.
+func ParseInjectedContainerResources(annotations map[string]string) (*corev1.ResourceRequirements, error) {
+	value, found := annotations[leaderworkerset.InjectedContainerResourcesAnnotationKey]
+	if !found {
+		return nil, nil
+	}
+	var resources corev1.ResourceRequirements
+	if err := json.Unmarshal([]byte(value), &resources); err != nil {
+		return nil, field.Invalid(field.NewPath("metadata", "annotations").Key(leaderworkerset.InjectedContainerResourcesAnnotationKey), value, fmt.Sprintf("must be resource requirements serialized in JSON: %v", err))
+	}
+	return &resources, nil
+}
+
+// ValidateResourceRequirements validates that the quantities of the resources are not negative, and
+// that the requests don't exceed the limits.
+func ValidateResourceRequirements(path *field.Path, resources corev1.ResourceRequirements) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for name, quantity := range resources.Limits {
+		if quantity.Sign() < 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("limits").Key(string(name)), quantity.String(), "must be greater than or equal to 0"))
+		}
+	}
+	for name, quantity := range resources.Requests {
+		if quantity.Sign() < 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("requests").Key(string(name)), quantity.String(), "must be greater than or equal to 0"))
+		}
+		if limit, found := resources.Limits[name]; found && quantity.Cmp(limit) > 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("requests").Key(string(name)), quantity.String(), fmt.Sprintf("must be less than or equal to %s limit of %s", name, limit.String())))
+		}
+	}
+	return allErrs
+}
+
+// Gang is the value of the gang annotation of the pods of a group.
+type Gang struct {
+	// Size is the number of pods of the group.
+	Size int32 `json:"size"`
+	// Queue is the queue of the LeaderWorkerSet, empty if none.
+	Queue string `json:"queue,omitempty"`
+}
+
+// ParseGang returns the gang of the gang annotation, or nil if the annotation is not set.
+func ParseGang(annotations map[string]string) (*Gang, error) {
+	value, found := annotations[leaderworkerset.GangAnnotationKey]
+	if !found {
+		return nil, nil
+	}
+	var gang Gang
+	if err := json.Unmarshal([]byte(value), &gang); err != nil {
+		return nil, fmt.Errorf("parsing the %s annotation: %w", leaderworkerset.GangAnnotationKey, err)
+	}
+	return &gang, nil
+}
+
+// ParseActiveDeadlineSeconds returns the seconds of the active-deadline-seconds annotation, or nil
+// if the annotation is not set.
+func ParseActiveDeadlineSeconds(annotations map[string]string) (*int64, error) {
+	value, found := annotations[leaderworkerset.ActiveDeadlineSecondsAnnotationKey]
+	if !found {
+		return nil, nil
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parsing the %s annotation: %w", leaderworkerset.ActiveDeadlineSecondsAnnotationKey, err)
+	}
+	return &seconds, nil
+}
+
+// ParseCoordinatorTemplate returns the template of the coordinator-template annotation, or nil
+// if the annotation is not set.
+func ParseCoordinatorTemplate(annotations map[string]string) (*corev1.PodTemplateSpec, error) {
+	value, found := annotations[leaderworkerset.CoordinatorTemplateAnnotationKey]
+	if !found {
+		return nil, nil
+	}
+	var template corev1.PodTemplateSpec
+	if err := json.Unmarshal([]byte(value), &template); err != nil {
+		return nil, fmt.Errorf("parsing the %s annotation: %w", leaderworkerset.CoordinatorTemplateAnnotationKey, err)
+	}
+	return &template, nil
+}
+
+func parseTopologyAnnotation(annotations map[string]string, annotationKey string) (string, bool, error) {
+	topologyKey, found := annotations[annotationKey]
+	if !found {
+		return "", false, nil
+	}
+	// The topology key is a node label key, so it must be a valid qualified name.
+	if errs := utilvalidation.IsQualifiedName(topologyKey); len(errs) != 0 {
+		return "", true, field.Invalid(field.NewPath("metadata", "annotations").Key(annotationKey), topologyKey, strings.Join(errs, "; "))
+	}
+	return topologyKey, true, nil
+}
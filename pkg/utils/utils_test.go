@@ -17,9 +17,17 @@ limitations under the License.
 package utils
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
 )
 
 func Test_SortByIndex(t *testing.T) {
@@ -63,3 +71,202 @@ func Test_SortByIndex(t *testing.T) {
 		})
 	}
 }
+
+func TestGroupIndex(t *testing.T) {
+	testCases := []struct {
+		name    string
+		obj     metav1.Object
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "persisted group index",
+			obj: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:   "lws-1-2",
+				Labels: map[string]string{leaderworkerset.GroupIndexLabelKey: "3"},
+			}},
+			want: 3,
+		},
+		{
+			name: "leader pod created before the group index was persisted",
+			obj:  &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "lws-1-2"}},
+			want: 2,
+		},
+		{
+			name: "worker statefulset created before the group index was persisted",
+			obj:  &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "lws-1-0"}},
+			want: 0,
+		},
+		{
+			name:    "leader statefulset",
+			obj:     &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "lws-1"}},
+			wantErr: true,
+		},
+		{
+			name:    "worker pod",
+			obj:     &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "lws-1-2-1"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := GroupIndex(tc.obj, "lws-1")
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("unexpected group index, want %d, got %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestParseExclusiveTopology(t *testing.T) {
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		wantKey     string
+		wantFound   bool
+		wantErr     bool
+	}{
+		{
+			name:        "annotation present",
+			annotations: map[string]string{leaderworkerset.ExclusiveKeyAnnotationKey: "cloud.google.com/gke-nodepool"},
+			wantKey:     "cloud.google.com/gke-nodepool",
+			wantFound:   true,
+		},
+		{
+			name:        "annotation absent",
+			annotations: map[string]string{leaderworkerset.SubGroupExclusiveKeyAnnotationKey: "topologyKey"},
+		},
+		{
+			name: "nil annotations",
+		},
+		{
+			name:        "empty annotation value",
+			annotations: map[string]string{leaderworkerset.ExclusiveKeyAnnotationKey: ""},
+			wantFound:   true,
+			wantErr:     true,
+		},
+		{
+			name:        "malformed annotation value",
+			annotations: map[string]string{leaderworkerset.ExclusiveKeyAnnotationKey: "cloud.google.com/gke nodepool"},
+			wantFound:   true,
+			wantErr:     true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, found, err := ParseExclusiveTopology(tc.annotations)
+			if key != tc.wantKey || found != tc.wantFound {
+				t.Errorf("Expected (%q, %t), got (%q, %t)", tc.wantKey, tc.wantFound, key, found)
+			}
+			if tc.wantErr {
+				var fieldErr *field.Error
+				if !errors.As(err, &fieldErr) || fieldErr.Field != "metadata.annotations[leaderworkerset.sigs.k8s.io/exclusive-topology]" {
+					t.Errorf("Expected a field error on the exclusive-topology annotation, got %v", err)
+				}
+			} else if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseSubGroupExclusiveTopology(t *testing.T) {
+	key, found, err := ParseSubGroupExclusiveTopology(map[string]string{leaderworkerset.SubGroupExclusiveKeyAnnotationKey: "topologyKey"})
+	if key != "topologyKey" || !found || err != nil {
+		t.Errorf("Unexpected result (%q, %t, %v)", key, found, err)
+	}
+	if _, found, err := ParseSubGroupExclusiveTopology(map[string]string{leaderworkerset.ExclusiveKeyAnnotationKey: "topologyKey"}); found || err != nil {
+		t.Errorf("Unexpected result (%t, %v) for absent annotation", found, err)
+	}
+}
+
+func TestParseStickyGroups(t *testing.T) {
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		want        sets.Set[int]
+		wantErr     bool
+	}{
+		{
+			name: "annotation absent",
+		},
+		{
+			name:        "group indices",
+			annotations: map[string]string{leaderworkerset.StickyGroupsAnnotationKey: "3, 0,3"},
+			want:        sets.New(0, 3),
+		},
+		{
+			name:        "empty annotation value",
+			annotations: map[string]string{leaderworkerset.StickyGroupsAnnotationKey: ""},
+			wantErr:     true,
+		},
+		{
+			name:        "negative group index",
+			annotations: map[string]string{leaderworkerset.StickyGroupsAnnotationKey: "0,-1"},
+			wantErr:     true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseStickyGroups(tc.annotations)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected sticky groups (-want +got):\n%s", diff)
+			}
+			if tc.wantErr {
+				var fieldErr *field.Error
+				if !errors.As(err, &fieldErr) || fieldErr.Field != "metadata.annotations[leaderworkerset.sigs.k8s.io/sticky-groups]" {
+					t.Errorf("Expected a field error on the sticky-groups annotation, got %v", err)
+				}
+			} else if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseLeaderless(t *testing.T) {
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		want        bool
+		wantErr     bool
+	}{
+		{
+			name: "annotation absent",
+		},
+		{
+			name:        "enabled",
+			annotations: map[string]string{leaderworkerset.LeaderlessAnnotationKey: "true"},
+			want:        true,
+		},
+		{
+			name:        "disabled",
+			annotations: map[string]string{leaderworkerset.LeaderlessAnnotationKey: "false"},
+		},
+		{
+			name:        "malformed value",
+			annotations: map[string]string{leaderworkerset.LeaderlessAnnotationKey: "yes"},
+			wantErr:     true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseLeaderless(tc.annotations)
+			if got != tc.want {
+				t.Errorf("Expected leaderless %v, got %v", tc.want, got)
+			}
+			if tc.wantErr {
+				var fieldErr *field.Error
+				if !errors.As(err, &fieldErr) || fieldErr.Field != "metadata.annotations[leaderworkerset.sigs.k8s.io/leaderless]" {
+					t.Errorf("Expected a field error on the leaderless annotation, got %v", err)
+				}
+			} else if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -20,29 +20,117 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"slices"
 	"strconv"
+	"strings"
+	"sync/atomic"
 
+	corev1 "k8s.io/api/core/v1"
 	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
 	v1 "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/features"
+	"sigs.k8s.io/lws/pkg/utils"
+	podutils "sigs.k8s.io/lws/pkg/utils/pod"
 )
 
-type LeaderWorkerSetWebhook struct{}
+// immutableAnnotations are the annotations which can't be changed once the lws is created. They are
+// all copied into the pod template of the leader statefulset, whose controller would then recreate the
+// groups without the lws revision changing, i.e. outside of maxUnavailable and maxSurge.
+var immutableAnnotations = []string{
+	// The pods of a group rely on the same injection, e.g. the workers find the leader through the
+	// injected LWS_LEADER_ADDRESS.
+	v1.DisablePodInjectionAnnotationKey,
+	// The readiness gate is added to the spec of the leader pods, and can't be added to or removed
+	// from the existing ones.
+	v1.GroupReadinessGateAnnotationKey,
+	// The identity of the pods is injected when they are created, the workers of the existing groups
+	// would keep the previous one.
+	v1.InjectWorkloadIdentityAnnotationKey,
+	// The roles of the pods, the candidate labels and the defaulted startup and subdomain policies
+	// depend on it.
+	v1.LeaderlessAnnotationKey,
+	// The resources of the injected containers are only applied when the pods are created, so that
+	// the only effect of a change would be the recreation of all the groups.
+	v1.InjectedContainerResourcesAnnotationKey,
+	// The gangs of the existing groups were admitted to the queue.
+	v1.QueueNameAnnotationKey,
+}
+
+type LeaderWorkerSetWebhook struct {
+	// config holds the fields applied again when the configuration is reloaded, each
+	// admission uses the configuration loaded when it starts.
+	config atomic.Pointer[leaderWorkerSetWebhookConfig]
+}
+
+// leaderWorkerSetWebhookConfig are the fields of the LeaderWorkerSetWebhook which can be changed
+// without a restart.
+type leaderWorkerSetWebhookConfig struct {
+	// rollingUpdateDefaults is applied to the LeaderWorkerSets omitting the rolling update configuration.
+	rollingUpdateDefaults v1.RollingUpdateConfiguration
+	// allowedImageRegistries are the prefixes the images of the templates must start with,
+	// all the images are allowed if empty.
+	allowedImageRegistries []string
+	// maxPodsPerLeaderWorkerSet caps the product of the replicas and size, zero means unlimited.
+	maxPodsPerLeaderWorkerSet int32
+	// requiredLabels are the keys of the labels the lws must carry, none are required if empty.
+	requiredLabels []string
+	// defaultStartupPolicy is applied to the LeaderWorkerSets omitting the startup policy.
+	defaultStartupPolicy v1.StartupPolicyType
+	// validatePodTemplates enables the validation of the templates as pod specs.
+	validatePodTemplates bool
+}
+
+func newLeaderWorkerSetWebhook(cfg *configapi.Configuration) *LeaderWorkerSetWebhook {
+	wh := &LeaderWorkerSetWebhook{}
+	wh.ApplyConfiguration(cfg)
+	return wh
+}
+
+// ApplyConfiguration sets the fields of the webhook which can be changed without a restart,
+// the in-flight admissions complete with the previous ones.
+func (r *LeaderWorkerSetWebhook) ApplyConfiguration(cfg *configapi.Configuration) {
+	config := &leaderWorkerSetWebhookConfig{
+		rollingUpdateDefaults: v1.RollingUpdateConfiguration{
+			MaxUnavailable: intstr.FromInt32(configapi.DefaultRollingUpdateMaxUnavailable),
+			MaxSurge:       intstr.FromInt32(configapi.DefaultRollingUpdateMaxSurge),
+		},
+		allowedImageRegistries:    cfg.AllowedImageRegistries,
+		maxPodsPerLeaderWorkerSet: cfg.MaxPodsPerLeaderWorkerSet,
+		requiredLabels:            cfg.RequiredLabels,
+		defaultStartupPolicy:      v1.LeaderCreatedStartupPolicy,
+		validatePodTemplates:      cfg.ValidatePodTemplates,
+	}
+	if cfg.RollingUpdateDefaults != nil {
+		config.rollingUpdateDefaults.MaxUnavailable = ptr.Deref(cfg.RollingUpdateDefaults.MaxUnavailable, config.rollingUpdateDefaults.MaxUnavailable)
+		config.rollingUpdateDefaults.MaxSurge = ptr.Deref(cfg.RollingUpdateDefaults.MaxSurge, config.rollingUpdateDefaults.MaxSurge)
+	}
+	if cfg.DefaultStartupPolicy != "" {
+		config.defaultStartupPolicy = v1.StartupPolicyType(cfg.DefaultStartupPolicy)
+	}
+	r.config.Store(config)
+}
 
-// SetupLeaderWorkerSetWebhook will setup the manager to manage the webhooks
-func SetupLeaderWorkerSetWebhook(mgr ctrl.Manager) error {
-	return ctrl.NewWebhookManagedBy(mgr).
+// SetupLeaderWorkerSetWebhook will setup the manager to manage the webhooks, the returned webhook
+// applies the reloaded configurations.
+func SetupLeaderWorkerSetWebhook(mgr ctrl.Manager, cfg *configapi.Configuration) (*LeaderWorkerSetWebhook, error) {
+	wh := newLeaderWorkerSetWebhook(cfg)
+	return wh, ctrl.NewWebhookManagedBy(mgr).
 		For(&v1.LeaderWorkerSet{}).
-		WithDefaulter(&LeaderWorkerSetWebhook{}).
-		WithValidator(&LeaderWorkerSetWebhook{}).
+		WithDefaulter(wh).
+		WithValidator(wh).
 		Complete()
 }
 
@@ -52,7 +140,12 @@ var _ webhook.CustomDefaulter = &LeaderWorkerSetWebhook{}
 
 // Default implements webhook.Defaulter so a webhook will be registered for the type
 func (r *LeaderWorkerSetWebhook) Default(ctx context.Context, obj runtime.Object) error {
+	cfg := r.config.Load()
 	lws := obj.(*v1.LeaderWorkerSet)
+	if lws.Spec.Replicas == nil {
+		lws.Spec.Replicas = ptr.To[int32](1)
+	}
+
 	if lws.Spec.LeaderWorkerTemplate.RestartPolicy == "" {
 		lws.Spec.LeaderWorkerTemplate.RestartPolicy = v1.RecreateGroupOnPodRestart
 	}
@@ -65,21 +158,28 @@ func (r *LeaderWorkerSetWebhook) Default(ctx context.Context, obj runtime.Object
 		lws.Spec.RolloutStrategy.Type = v1.RollingUpdateStrategyType
 	}
 
-	if lws.Spec.RolloutStrategy.Type == v1.RollingUpdateStrategyType && lws.Spec.RolloutStrategy.RollingUpdateConfiguration == nil {
-		lws.Spec.RolloutStrategy.RollingUpdateConfiguration = &v1.RollingUpdateConfiguration{
-			MaxUnavailable: intstr.FromInt32(1),
-			MaxSurge:       intstr.FromInt32(0),
+	// The candidates of the leaderless groups start at once and are exposed by the service of their group.
+	leaderless, _ := utils.ParseLeaderless(lws.Annotations)
+	if lws.Spec.StartupPolicy == "" {
+		lws.Spec.StartupPolicy = cfg.defaultStartupPolicy
+		if leaderless {
+			lws.Spec.StartupPolicy = v1.LeaderCreatedStartupPolicy
 		}
 	}
 
+	if lws.Spec.RolloutStrategy.Type == v1.RollingUpdateStrategyType && lws.Spec.RolloutStrategy.RollingUpdateConfiguration == nil {
+		lws.Spec.RolloutStrategy.RollingUpdateConfiguration = cfg.rollingUpdateDefaults.DeepCopy()
+	}
+
+	subdomainPolicy := v1.SubdomainShared
+	if leaderless {
+		subdomainPolicy = v1.SubdomainUniquePerReplica
+	}
 	if lws.Spec.NetworkConfig == nil {
-		lws.Spec.NetworkConfig = &v1.NetworkConfig{}
-		subdomainPolicy := v1.SubdomainShared
 		lws.Spec.NetworkConfig = &v1.NetworkConfig{
 			SubdomainPolicy: &subdomainPolicy,
 		}
 	} else if lws.Spec.NetworkConfig.SubdomainPolicy == nil {
-		subdomainPolicy := v1.SubdomainShared
 		lws.Spec.NetworkConfig.SubdomainPolicy = &subdomainPolicy
 	}
 
@@ -92,32 +192,29 @@ var _ webhook.CustomValidator = &LeaderWorkerSetWebhook{}
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *LeaderWorkerSetWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
-	allErrs := r.generalValidate(obj)
-	return nil, allErrs.ToAggregate()
+	lws := obj.(*v1.LeaderWorkerSet)
+	allErrs := r.generalValidate(lws, nil)
+	allErrs = append(allErrs, validateStartupPolicy(field.NewPath("spec", "startupPolicy"), lws.Spec.StartupPolicy)...)
+	return templateWarnings(lws), allErrs.ToAggregate()
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *LeaderWorkerSetWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
-	allErrs := r.generalValidate(newObj)
-	specPath := field.NewPath("spec")
-
 	oldLws := oldObj.(*v1.LeaderWorkerSet)
 	newLws := newObj.(*v1.LeaderWorkerSet)
+	allErrs := r.generalValidate(newLws, oldLws)
+	specPath := field.NewPath("spec")
 	allErrs = append(allErrs, apivalidation.ValidateImmutableField(*newLws.Spec.LeaderWorkerTemplate.Size, *oldLws.Spec.LeaderWorkerTemplate.Size, field.NewPath("spec", "leaderWorkerTemplate", "size"))...)
-	if newLws.Spec.LeaderWorkerTemplate.SubGroupPolicy != nil && oldLws.Spec.LeaderWorkerTemplate.SubGroupPolicy != nil {
-		allErrs = append(allErrs, apivalidation.ValidateImmutableField(*newLws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize, *oldLws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize, field.NewPath("spec", "leaderWorkerTemplate", "SubGroupPolicy", "subGroupSize"))...)
-	}
-	if newLws.Spec.LeaderWorkerTemplate.SubGroupPolicy != nil && oldLws.Spec.LeaderWorkerTemplate.SubGroupPolicy == nil {
-		allErrs = append(allErrs, field.Invalid(specPath.Child("leaderWorkerTemplate", "SubGroupPolicy", "subGroupSize"), newLws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize, "cannot enable subGroupSize after the lws is already created"))
-	}
-	if newLws.Spec.LeaderWorkerTemplate.SubGroupPolicy == nil && oldLws.Spec.LeaderWorkerTemplate.SubGroupPolicy != nil {
-		allErrs = append(allErrs, field.Invalid(specPath.Child("leaderWorkerTemplate", "SubGroupPolicy", "subGroupSize"), oldLws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize, "cannot remove subGroupSize after enabled"))
+	allErrs = append(allErrs, validateStartupPolicy(specPath.Child("startupPolicy"), newLws.Spec.StartupPolicy)...)
+	allErrs = append(allErrs, validateSubGroupPolicyUpdate(specPath.Child("leaderWorkerTemplate", "subGroupPolicy"), oldLws.Spec.LeaderWorkerTemplate.SubGroupPolicy, newLws.Spec.LeaderWorkerTemplate.SubGroupPolicy)...)
+	for _, key := range immutableAnnotations {
+		allErrs = append(allErrs, apivalidation.ValidateImmutableField(newLws.Annotations[key], oldLws.Annotations[key], field.NewPath("metadata", "annotations").Key(key))...)
 	}
 	if newLws.Spec.NetworkConfig != nil && newLws.Spec.NetworkConfig.SubdomainPolicy == nil {
 		allErrs = append(allErrs, field.Invalid(specPath.Child("networkConfig", "subdomainPolicy"), oldLws.Spec.NetworkConfig.SubdomainPolicy, "cannot set subdomainPolicy as null"))
 	}
 
-	return nil, allErrs.ToAggregate()
+	return templateWarnings(newLws), allErrs.ToAggregate()
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -125,14 +222,29 @@ func (r *LeaderWorkerSetWebhook) ValidateDelete(ctx context.Context, obj runtime
 	return nil, nil
 }
 
-func (r *LeaderWorkerSetWebhook) generalValidate(obj runtime.Object) field.ErrorList {
-	lws := obj.(*v1.LeaderWorkerSet)
+// generalValidate validates the lws on create and update, oldLws is nil on create. The checks of the
+// controller configuration, which can be changed on reload, only reject the updates introducing a
+// violation, so that the existing LeaderWorkerSets can still be updated, e.g. scaled.
+func (r *LeaderWorkerSetWebhook) generalValidate(lws, oldLws *v1.LeaderWorkerSet) field.ErrorList {
+	cfg := r.config.Load()
 	specPath := field.NewPath("spec")
 	metadataPath := field.NewPath("metadata")
 
 	// Since the lws name is used as the name for headless service, it must be DNS-1035 compliant
 	ValidateName := apivalidation.NameIsDNS1035Label
 	allErrs := apivalidation.ValidateObjectMeta(&lws.ObjectMeta, true, apivalidation.ValidateNameFunc(ValidateName), field.NewPath("metadata"))
+	for _, key := range cfg.requiredLabels {
+		if _, found := lws.Labels[key]; found {
+			continue
+		}
+		// The lws created before the label was required can still be updated, unless removing it.
+		if oldLws != nil {
+			if _, had := oldLws.Labels[key]; !had {
+				continue
+			}
+		}
+		allErrs = append(allErrs, field.Required(metadataPath.Child("labels").Key(key), "is required by the controller configuration"))
+	}
 	// Ensure replicas and groups number are valid
 	if lws.Spec.Replicas != nil && *lws.Spec.Replicas < 0 {
 		allErrs = append(allErrs, field.Invalid(specPath.Child("replicas"), lws.Spec.Replicas, "replicas must be equal or greater than 0"))
@@ -140,24 +252,143 @@ func (r *LeaderWorkerSetWebhook) generalValidate(obj runtime.Object) field.Error
 	if *lws.Spec.LeaderWorkerTemplate.Size < 1 {
 		allErrs = append(allErrs, field.Invalid(specPath.Child("leaderWorkerTemplate", "size"), lws.Spec.LeaderWorkerTemplate.Size, "size must be equal or greater than 1"))
 	}
-	if int64(*lws.Spec.Replicas)*int64(*lws.Spec.LeaderWorkerTemplate.Size) > math.MaxInt32 {
+	totalPods := int64(*lws.Spec.Replicas) * int64(*lws.Spec.LeaderWorkerTemplate.Size)
+	if totalPods > math.MaxInt32 {
 		allErrs = append(allErrs, field.Invalid(specPath.Child("replicas"), lws.Spec.Replicas, fmt.Sprintf("the product of replicas and worker replicas must not exceed %d", math.MaxInt32)))
+	} else if cfg.maxPodsPerLeaderWorkerSet > 0 && totalPods > int64(cfg.maxPodsPerLeaderWorkerSet) && (oldLws == nil || totalPods > int64(*oldLws.Spec.Replicas)*int64(*oldLws.Spec.LeaderWorkerTemplate.Size)) {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("replicas"), lws.Spec.Replicas, fmt.Sprintf("the %d pods of the replicas and size must not exceed the %d pods per LeaderWorkerSet allowed by the controller configuration", totalPods, cfg.maxPodsPerLeaderWorkerSet)))
 	}
 
-	maxUnavailable := lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxUnavailable
-	maxUnavailablePath := specPath.Child("rolloutStrategy", "rollingUpdateConfiguration", "maxUnavailable")
-	if lws.Spec.RolloutStrategy.RollingUpdateConfiguration != nil {
-		allErrs = append(allErrs, validatePositiveIntOrPercent(maxUnavailable, maxUnavailablePath)...)
-		// This is aligned with Statefulset.
-		allErrs = append(allErrs, isNotMoreThan100Percent(maxUnavailable, maxUnavailablePath)...)
+	templatePath := specPath.Child("leaderWorkerTemplate")
+	existingImages := templateImages(oldLws)
+	if lws.Spec.LeaderWorkerTemplate.LeaderTemplate != nil {
+		allErrs = append(allErrs, validatePodInjectionOptOut(templatePath.Child("leaderTemplate", "metadata"), &lws.Spec.LeaderWorkerTemplate.LeaderTemplate.ObjectMeta)...)
+		allErrs = append(allErrs, validateImagePullPolicies(templatePath.Child("leaderTemplate", "spec"), &lws.Spec.LeaderWorkerTemplate.LeaderTemplate.Spec)...)
+		allErrs = append(allErrs, validateProcessNamespaceSharing(templatePath.Child("leaderTemplate", "spec"), &lws.Spec.LeaderWorkerTemplate.LeaderTemplate.Spec)...)
+		allErrs = append(allErrs, validateImageRegistries(templatePath.Child("leaderTemplate", "spec"), &lws.Spec.LeaderWorkerTemplate.LeaderTemplate.Spec, cfg.allowedImageRegistries, existingImages)...)
+		allErrs = append(allErrs, validateDNS(templatePath.Child("leaderTemplate", "spec"), &lws.Spec.LeaderWorkerTemplate.LeaderTemplate.Spec)...)
+	}
+	allErrs = append(allErrs, validatePodInjectionOptOut(templatePath.Child("workerTemplate", "metadata"), &lws.Spec.LeaderWorkerTemplate.WorkerTemplate.ObjectMeta)...)
+	allErrs = append(allErrs, validateImagePullPolicies(templatePath.Child("workerTemplate", "spec"), &lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec)...)
+	allErrs = append(allErrs, validateProcessNamespaceSharing(templatePath.Child("workerTemplate", "spec"), &lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec)...)
+	allErrs = append(allErrs, validateImageRegistries(templatePath.Child("workerTemplate", "spec"), &lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec, cfg.allowedImageRegistries, existingImages)...)
+	allErrs = append(allErrs, validateDNS(templatePath.Child("workerTemplate", "spec"), &lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec)...)
+	if lws.Spec.LeaderWorkerTemplate.CoordinatorTemplate != nil {
+		allErrs = append(allErrs, validateCoordinatorTemplate(templatePath.Child("coordinatorTemplate"), lws.Spec.LeaderWorkerTemplate.CoordinatorTemplate, cfg.allowedImageRegistries, existingImages)...)
+	}
+	if cfg.validatePodTemplates {
+		if lws.Spec.LeaderWorkerTemplate.LeaderTemplate != nil {
+			allErrs = append(allErrs, validatePodSpec(templatePath.Child("leaderTemplate", "spec"), &lws.Spec.LeaderWorkerTemplate.LeaderTemplate.Spec)...)
+		}
+		allErrs = append(allErrs, validatePodSpec(templatePath.Child("workerTemplate", "spec"), &lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec)...)
+		if lws.Spec.LeaderWorkerTemplate.CoordinatorTemplate != nil {
+			allErrs = append(allErrs, validatePodSpec(templatePath.Child("coordinatorTemplate", "spec"), &lws.Spec.LeaderWorkerTemplate.CoordinatorTemplate.Spec)...)
+		}
 	}
 
-	maxSurge := lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxSurge
-	maxSurgePath := specPath.Child("rolloutStrategy", "rollingUpdateConfiguration", "maxSurge")
-	if lws.Spec.RolloutStrategy.RollingUpdateConfiguration != nil {
-		allErrs = append(allErrs, validatePositiveIntOrPercent(maxSurge, maxSurgePath)...)
-		allErrs = append(allErrs, isNotMoreThan100Percent(maxSurge, maxSurgePath)...)
+	allErrs = append(allErrs, validateRolloutStrategy(specPath.Child("rolloutStrategy"), lws)...)
+
+	if lws.Spec.GroupReadinessPolicy != nil {
+		allErrs = append(allErrs, validateGroupReadinessPolicy(specPath.Child("groupReadinessPolicy"), lws)...)
+	}
+
+	allErrs = append(allErrs, validateGroupResourceOverrides(templatePath.Child("groupResourceOverrides"), lws)...)
+
+	if _, _, err := utils.ParseExclusiveTopology(lws.Annotations); err != nil {
+		allErrs = append(allErrs, err.(*field.Error))
+	}
+	_, foundSubEpKey, err := utils.ParseSubGroupExclusiveTopology(lws.Annotations)
+	if err != nil {
+		allErrs = append(allErrs, err.(*field.Error))
+	}
+	if _, err := utils.ParsePodInjectionDisabled(lws.Annotations); err != nil {
+		allErrs = append(allErrs, err.(*field.Error))
+	}
+	if _, err := utils.ParseInjectWorkloadIdentity(lws.Annotations); err != nil {
+		allErrs = append(allErrs, err.(*field.Error))
+	}
+	if _, err := utils.ParseStickyGroups(lws.Annotations); err != nil {
+		allErrs = append(allErrs, err.(*field.Error))
+	}
+	if leaderless, err := utils.ParseLeaderless(lws.Annotations); err != nil {
+		allErrs = append(allErrs, err.(*field.Error))
+	} else if leaderless {
+		allErrs = append(allErrs, validateLeaderless(metadataPath.Child("annotations").Key(v1.LeaderlessAnnotationKey), lws)...)
 	}
+	if queueName, found := lws.Annotations[v1.QueueNameAnnotationKey]; found {
+		for _, msg := range utilvalidation.IsDNS1123Subdomain(queueName) {
+			allErrs = append(allErrs, field.Invalid(metadataPath.Child("annotations").Key(v1.QueueNameAnnotationKey), queueName, msg))
+		}
+	}
+	if resources, err := utils.ParseInjectedContainerResources(lws.Annotations); err != nil {
+		allErrs = append(allErrs, err.(*field.Error))
+	} else if resources != nil {
+		allErrs = append(allErrs, utils.ValidateResourceRequirements(metadataPath.Child("annotations").Key(v1.InjectedContainerResourcesAnnotationKey), *resources)...)
+	}
+	if readinessGate, err := utils.ParseGroupReadinessGate(lws.Annotations); err != nil {
+		allErrs = append(allErrs, err.(*field.Error))
+	} else if readinessGate && (lws.Spec.StartupPolicy == v1.LeaderReadyStartupPolicy || lws.Spec.StartupPolicy == v1.AllLeadersReadyStartupPolicy) {
+		// The leader pods would wait for the workers, which wait for the leader pods.
+		allErrs = append(allErrs, field.Invalid(metadataPath.Child("annotations").Key(v1.GroupReadinessGateAnnotationKey), lws.Annotations[v1.GroupReadinessGateAnnotationKey], fmt.Sprintf("requires the %s startup policy", v1.LeaderCreatedStartupPolicy)))
+	}
+	if lws.Spec.LeaderWorkerTemplate.SubGroupPolicy != nil {
+		allErrs = append(allErrs, validateUpdateSubGroupPolicy(specPath, lws)...)
+	} else if foundSubEpKey {
+		allErrs = append(allErrs, field.Invalid(metadataPath.Child("annotations").Key(v1.SubGroupExclusiveKeyAnnotationKey), lws.Annotations[v1.SubGroupExclusiveKeyAnnotationKey], "cannot have subgroup-exclusive-topology without subGroupSize set"))
+	}
+
+	if groupsPerMinute, found := lws.Annotations[v1.GroupsPerMinuteAnnotationKey]; found {
+		if value, err := strconv.Atoi(groupsPerMinute); err != nil || value < 1 {
+			allErrs = append(allErrs, field.Invalid(metadataPath.Child("annotations").Key(v1.GroupsPerMinuteAnnotationKey), groupsPerMinute, "must be a positive integer"))
+		}
+	}
+	if drainTimeout, found := lws.Annotations[v1.DrainTimeoutAnnotationKey]; found {
+		if value, err := strconv.Atoi(drainTimeout); err != nil || value < 1 {
+			allErrs = append(allErrs, field.Invalid(metadataPath.Child("annotations").Key(v1.DrainTimeoutAnnotationKey), drainTimeout, "must be a positive integer"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateRolloutStrategy(rolloutStrategyPath *field.Path, lws *v1.LeaderWorkerSet) field.ErrorList {
+	var allErrs field.ErrorList
+	switch policy := lws.Spec.RolloutStrategy.MetadataUpdatePolicy; policy {
+	case "", v1.RecreateMetadataUpdatePolicy, v1.InPlaceMetadataUpdatePolicy:
+	default:
+		allErrs = append(allErrs, field.NotSupported(rolloutStrategyPath.Child("metadataUpdatePolicy"), policy, []v1.MetadataUpdatePolicyType{v1.RecreateMetadataUpdatePolicy, v1.InPlaceMetadataUpdatePolicy}))
+	}
+	switch lws.Spec.RolloutStrategy.Type {
+	case v1.RecreateStrategyType:
+		rollingUpdateConfiguration := lws.Spec.RolloutStrategy.RollingUpdateConfiguration
+		if rollingUpdateConfiguration == nil {
+			return allErrs
+		}
+		// The surge contradicts the strategy rather than being merely unused, so it is called out.
+		// Any percentage of a hundred groups is non-zero, unless the percentage itself is.
+		if surge, err := intstr.GetScaledValueFromIntOrPercent(&rollingUpdateConfiguration.MaxSurge, 100, true); err != nil || surge != 0 {
+			return append(allErrs, field.Forbidden(rolloutStrategyPath.Child("rollingUpdateConfiguration", "maxSurge"), "may not be specified when type is Recreate, which deletes all the groups before creating the updated ones"))
+		}
+		return append(allErrs, field.Forbidden(rolloutStrategyPath.Child("rollingUpdateConfiguration"), "may not be specified when type is Recreate"))
+	case v1.RollingUpdateStrategyType:
+		if lws.Spec.RolloutStrategy.RollingUpdateConfiguration == nil {
+			return append(allErrs, field.Required(rolloutStrategyPath.Child("rollingUpdateConfiguration"), "must be specified when type is RollingUpdate"))
+		}
+	default:
+		return append(allErrs, field.NotSupported(rolloutStrategyPath.Child("type"), lws.Spec.RolloutStrategy.Type, []v1.RolloutStrategyType{v1.RollingUpdateStrategyType, v1.RecreateStrategyType}))
+	}
+
+	maxUnavailable := lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxUnavailable
+	maxUnavailablePath := rolloutStrategyPath.Child("rollingUpdateConfiguration", "maxUnavailable")
+	allErrs = append(allErrs, validatePositiveIntOrPercent(maxUnavailable, maxUnavailablePath)...)
+	// This is aligned with Statefulset.
+	allErrs = append(allErrs, isNotMoreThan100Percent(maxUnavailable, maxUnavailablePath)...)
+
+	maxSurge := lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxSurge
+	maxSurgePath := rolloutStrategyPath.Child("rollingUpdateConfiguration", "maxSurge")
+	allErrs = append(allErrs, validatePositiveIntOrPercent(maxSurge, maxSurgePath)...)
+	allErrs = append(allErrs, isNotMoreThan100Percent(maxSurge, maxSurgePath)...)
+
 	maxUnavailableValue, err := intstr.GetScaledValueFromIntOrPercent(&maxUnavailable, int(*lws.Spec.Replicas), false)
 	if err != nil {
 		allErrs = append(allErrs, field.Invalid(maxUnavailablePath, maxUnavailable, "invalid value"))
@@ -170,15 +401,6 @@ func (r *LeaderWorkerSetWebhook) generalValidate(obj runtime.Object) field.Error
 		// Both MaxSurge and MaxUnavailable cannot be zero.
 		allErrs = append(allErrs, field.Invalid(maxUnavailablePath, maxUnavailable, "must not be 0 when `maxSurge` is 0"))
 	}
-
-	if lws.Spec.LeaderWorkerTemplate.SubGroupPolicy != nil {
-		allErrs = append(allErrs, validateUpdateSubGroupPolicy(specPath, lws)...)
-	} else {
-		if _, foundSubEpKey := lws.Annotations[v1.SubGroupExclusiveKeyAnnotationKey]; foundSubEpKey {
-			allErrs = append(allErrs, field.Invalid(metadataPath.Child("annotations", v1.SubGroupExclusiveKeyAnnotationKey), lws.Annotations[v1.SubGroupExclusiveKeyAnnotationKey], "cannot have subgroup-exclusive-topology without subGroupSize set"))
-		}
-	}
-
 	return allErrs
 }
 
@@ -232,6 +454,249 @@ func validateNonnegativeField(value int64, fldPath *field.Path) field.ErrorList
 	return allErrs
 }
 
+var supportedPullPolicies = []corev1.PullPolicy{corev1.PullAlways, corev1.PullIfNotPresent, corev1.PullNever}
+
+// validateImagePullPolicies validates the image pull policies of the containers of a template,
+// so that a typo is rejected on admission rather than failing to apply the statefulsets.
+// The leader and worker templates are validated separately and may use different policies.
+func validateImagePullPolicies(podSpecPath *field.Path, podSpec *corev1.PodSpec) field.ErrorList {
+	allErrs := field.ErrorList{}
+	validate := func(containersPath *field.Path, containers []corev1.Container) {
+		for i, container := range containers {
+			// An empty policy is defaulted by the API server.
+			if container.ImagePullPolicy != "" && !slices.Contains(supportedPullPolicies, container.ImagePullPolicy) {
+				allErrs = append(allErrs, field.NotSupported(containersPath.Index(i).Child("imagePullPolicy"), container.ImagePullPolicy, supportedPullPolicies))
+			}
+		}
+	}
+	validate(podSpecPath.Child("initContainers"), podSpec.InitContainers)
+	validate(podSpecPath.Child("containers"), podSpec.Containers)
+	return allErrs
+}
+
+// validateProcessNamespaceSharing rejects sharing the process namespace between the containers
+// of the pods together with the host PID namespace, the API server rejects the combination in the
+// template of the statefulsets.
+func validateProcessNamespaceSharing(podSpecPath *field.Path, podSpec *corev1.PodSpec) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if ptr.Deref(podSpec.ShareProcessNamespace, false) && podSpec.HostPID {
+		allErrs = append(allErrs, field.Invalid(podSpecPath.Child("shareProcessNamespace"), true, "ShareProcessNamespace and HostPID cannot both be enabled"))
+	}
+	return allErrs
+}
+
+// validateImageRegistries rejects the containers of a template whose image doesn't start with
+// one of the allowed registries. All the images are allowed if no registry is configured, and
+// the existing images, already used by the templates before an update, are allowed as well.
+func validateImageRegistries(podSpecPath *field.Path, podSpec *corev1.PodSpec, allowedRegistries []string, existingImages sets.Set[string]) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(allowedRegistries) == 0 {
+		return allErrs
+	}
+	validate := func(containersPath *field.Path, containers []corev1.Container) {
+		for i, container := range containers {
+			if existingImages.Has(container.Image) {
+				continue
+			}
+			allowed := slices.ContainsFunc(allowedRegistries, func(registry string) bool {
+				return strings.HasPrefix(container.Image, registry)
+			})
+			if !allowed {
+				allErrs = append(allErrs, field.Forbidden(containersPath.Index(i).Child("image"), fmt.Sprintf("image %q is not from an allowed registry: %s", container.Image, strings.Join(allowedRegistries, ", "))))
+			}
+		}
+	}
+	validate(podSpecPath.Child("initContainers"), podSpec.InitContainers)
+	validate(podSpecPath.Child("containers"), podSpec.Containers)
+	return allErrs
+}
+
+// templateImages returns the images of the containers of the templates of the lws, none if nil.
+func templateImages(lws *v1.LeaderWorkerSet) sets.Set[string] {
+	images := sets.New[string]()
+	if lws == nil {
+		return images
+	}
+	for _, template := range []*corev1.PodTemplateSpec{lws.Spec.LeaderWorkerTemplate.LeaderTemplate, &lws.Spec.LeaderWorkerTemplate.WorkerTemplate, lws.Spec.LeaderWorkerTemplate.CoordinatorTemplate} {
+		if template == nil {
+			continue
+		}
+		for _, containers := range [][]corev1.Container{template.Spec.InitContainers, template.Spec.Containers} {
+			for _, container := range containers {
+				images.Insert(container.Image)
+			}
+		}
+	}
+	return images
+}
+
+// validatePodSpec runs a subset of the pod validation of the API server on a template, which
+// lives in k8s.io/kubernetes and can't be imported: the names of the containers, ports, env vars
+// and volumes, their uniqueness, the port numbers, the missing images and the mounts of undeclared
+// volumes. The other fields are only validated when the controller applies the statefulsets.
+func validatePodSpec(podSpecPath *field.Path, podSpec *corev1.PodSpec) field.ErrorList {
+	allErrs := field.ErrorList{}
+	volumeNames := map[string]bool{}
+	for i, volume := range podSpec.Volumes {
+		namePath := podSpecPath.Child("volumes").Index(i).Child("name")
+		for _, msg := range utilvalidation.IsDNS1123Label(volume.Name) {
+			allErrs = append(allErrs, field.Invalid(namePath, volume.Name, msg))
+		}
+		if volumeNames[volume.Name] {
+			allErrs = append(allErrs, field.Duplicate(namePath, volume.Name))
+		}
+		volumeNames[volume.Name] = true
+	}
+	// The names of the init containers and containers share a namespace.
+	containerNames := map[string]bool{}
+	validate := func(containersPath *field.Path, containers []corev1.Container) {
+		for i, container := range containers {
+			containerPath := containersPath.Index(i)
+			if container.Name == "" {
+				allErrs = append(allErrs, field.Required(containerPath.Child("name"), ""))
+			} else {
+				for _, msg := range utilvalidation.IsDNS1123Label(container.Name) {
+					allErrs = append(allErrs, field.Invalid(containerPath.Child("name"), container.Name, msg))
+				}
+			}
+			if containerNames[container.Name] {
+				allErrs = append(allErrs, field.Duplicate(containerPath.Child("name"), container.Name))
+			}
+			containerNames[container.Name] = true
+			if container.Image == "" {
+				allErrs = append(allErrs, field.Required(containerPath.Child("image"), ""))
+			}
+			portNames := map[string]bool{}
+			for j, port := range container.Ports {
+				portPath := containerPath.Child("ports").Index(j)
+				if port.Name != "" {
+					for _, msg := range utilvalidation.IsValidPortName(port.Name) {
+						allErrs = append(allErrs, field.Invalid(portPath.Child("name"), port.Name, msg))
+					}
+					if portNames[port.Name] {
+						allErrs = append(allErrs, field.Duplicate(portPath.Child("name"), port.Name))
+					}
+					portNames[port.Name] = true
+				}
+				for _, msg := range utilvalidation.IsValidPortNum(int(port.ContainerPort)) {
+					allErrs = append(allErrs, field.Invalid(portPath.Child("containerPort"), port.ContainerPort, msg))
+				}
+			}
+			for j, env := range container.Env {
+				for _, msg := range utilvalidation.IsEnvVarName(env.Name) {
+					allErrs = append(allErrs, field.Invalid(containerPath.Child("env").Index(j).Child("name"), env.Name, msg))
+				}
+			}
+			for j, volumeMount := range container.VolumeMounts {
+				if !volumeNames[volumeMount.Name] {
+					allErrs = append(allErrs, field.NotFound(containerPath.Child("volumeMounts").Index(j).Child("name"), volumeMount.Name))
+				}
+			}
+		}
+	}
+	validate(podSpecPath.Child("initContainers"), podSpec.InitContainers)
+	validate(podSpecPath.Child("containers"), podSpec.Containers)
+	return allErrs
+}
+
+var supportedDNSPolicies = []corev1.DNSPolicy{corev1.DNSClusterFirstWithHostNet, corev1.DNSClusterFirst, corev1.DNSDefault, corev1.DNSNone}
+
+// validateDNS rejects an unsupported DNS policy, and the None policy without a DNS config.
+func validateDNS(podSpecPath *field.Path, podSpec *corev1.PodSpec) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch {
+	// An empty policy is defaulted to ClusterFirst by the API server.
+	case podSpec.DNSPolicy == "":
+	case !slices.Contains(supportedDNSPolicies, podSpec.DNSPolicy):
+		allErrs = append(allErrs, field.NotSupported(podSpecPath.Child("dnsPolicy"), podSpec.DNSPolicy, supportedDNSPolicies))
+	case podSpec.DNSPolicy == corev1.DNSNone && podSpec.DNSConfig == nil:
+		allErrs = append(allErrs, field.Required(podSpecPath.Child("dnsConfig"), "must be specified when dnsPolicy is None"))
+	}
+	return allErrs
+}
+
+// templateWarnings warns about the templates using the host network with a DNS policy resolving
+// the names with the DNS of the node, which doesn't resolve the headless services the workers
+// reach their leader through.
+func templateWarnings(lws *v1.LeaderWorkerSet) admission.Warnings {
+	var warnings admission.Warnings
+	templatePath := field.NewPath("spec", "leaderWorkerTemplate")
+	warn := func(podSpecPath *field.Path, podSpec *corev1.PodSpec) {
+		if !podSpec.HostNetwork {
+			return
+		}
+		dnsPolicy := podSpec.DNSPolicy
+		if dnsPolicy == "" {
+			dnsPolicy = corev1.DNSClusterFirst
+		}
+		if dnsPolicy == corev1.DNSClusterFirst || dnsPolicy == corev1.DNSDefault {
+			warnings = append(warnings, fmt.Sprintf("%s: the pods using the host network with the %s dnsPolicy can't resolve the leader address through the headless service, use the %s dnsPolicy instead",
+				podSpecPath.Child("dnsPolicy"), dnsPolicy, corev1.DNSClusterFirstWithHostNet))
+		}
+	}
+	if lws.Spec.LeaderWorkerTemplate.LeaderTemplate != nil {
+		warn(templatePath.Child("leaderTemplate", "spec"), &lws.Spec.LeaderWorkerTemplate.LeaderTemplate.Spec)
+	}
+	warn(templatePath.Child("workerTemplate", "spec"), &lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec)
+	if lws.Spec.LeaderWorkerTemplate.CoordinatorTemplate != nil {
+		warn(templatePath.Child("coordinatorTemplate", "spec"), &lws.Spec.LeaderWorkerTemplate.CoordinatorTemplate.Spec)
+	}
+	return warnings
+}
+
+// validateStartupPolicy rejects the AllLeadersReady startup policy without the
+// AllLeadersReadyStartupPolicy feature gate, including on the updates of the LeaderWorkerSets
+// created while the gate was enabled, which must move to another policy.
+func validateStartupPolicy(path *field.Path, policy v1.StartupPolicyType) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if policy == v1.AllLeadersReadyStartupPolicy && !features.Enabled(features.AllLeadersReadyStartupPolicy) {
+		allErrs = append(allErrs, field.Forbidden(path, fmt.Sprintf("requires the %s feature gate", features.AllLeadersReadyStartupPolicy)))
+	}
+	return allErrs
+}
+
+// validateLeaderless rejects the settings giving the leader pods of a leaderless lws a role of their own.
+func validateLeaderless(path *field.Path, lws *v1.LeaderWorkerSet) field.ErrorList {
+	allErrs := field.ErrorList{}
+	value := lws.Annotations[v1.LeaderlessAnnotationKey]
+	if lws.Spec.LeaderWorkerTemplate.LeaderTemplate != nil {
+		allErrs = append(allErrs, field.Invalid(path, value, "cannot have a leaderTemplate, the candidates share the workerTemplate"))
+	}
+	if lws.Spec.LeaderWorkerTemplate.CoordinatorTemplate != nil {
+		allErrs = append(allErrs, field.Invalid(path, value, "cannot have a coordinatorTemplate"))
+	}
+	if lws.Spec.GroupReadinessPolicy != nil {
+		allErrs = append(allErrs, field.Invalid(path, value, "cannot have a groupReadinessPolicy, the group is Ready once a majority of its candidates are Ready"))
+	}
+	if readinessGate, _ := utils.ParseGroupReadinessGate(lws.Annotations); readinessGate {
+		allErrs = append(allErrs, field.Invalid(path, value, fmt.Sprintf("cannot be set along with the %s annotation", v1.GroupReadinessGateAnnotationKey)))
+	}
+	if lws.Spec.StartupPolicy != v1.LeaderCreatedStartupPolicy {
+		allErrs = append(allErrs, field.Invalid(path, value, fmt.Sprintf("requires the %s startup policy", v1.LeaderCreatedStartupPolicy)))
+	}
+	if lws.Spec.NetworkConfig == nil || lws.Spec.NetworkConfig.SubdomainPolicy == nil || *lws.Spec.NetworkConfig.SubdomainPolicy != v1.SubdomainUniquePerReplica {
+		allErrs = append(allErrs, field.Invalid(path, value, fmt.Sprintf("requires the %s subdomain policy", v1.SubdomainUniquePerReplica)))
+	}
+	return allErrs
+}
+
+// validateSubGroupPolicyUpdate rejects adding, removing or changing the subGroupPolicy of an
+// existing lws, like the size, since the subgroup indexes of the running pods would be reassigned.
+func validateSubGroupPolicyUpdate(path *field.Path, oldPolicy, newPolicy *v1.SubGroupPolicy) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch {
+	case oldPolicy == nil && newPolicy == nil:
+	case oldPolicy == nil:
+		allErrs = append(allErrs, field.Forbidden(path, "cannot be added after the lws is created, the pods would be reindexed into subgroups"))
+	case newPolicy == nil:
+		allErrs = append(allErrs, field.Forbidden(path, "cannot be removed after the lws is created, the pods would lose their subgroup indexes"))
+	default:
+		allErrs = append(allErrs, apivalidation.ValidateImmutableField(ptr.Deref(newPolicy.SubGroupSize, 0), ptr.Deref(oldPolicy.SubGroupSize, 0), path.Child("subGroupSize"))...)
+		allErrs = append(allErrs, apivalidation.ValidateImmutableField(ptr.Deref(newPolicy.Type, v1.SubGroupPolicyTypeLeaderWorker), ptr.Deref(oldPolicy.Type, v1.SubGroupPolicyTypeLeaderWorker), path.Child("subGroupPolicyType"))...)
+	}
+	return allErrs
+}
+
 func validateUpdateSubGroupPolicy(specPath *field.Path, lws *v1.LeaderWorkerSet) field.ErrorList {
 	allErrs := field.ErrorList{}
 	size := int32(*lws.Spec.LeaderWorkerTemplate.Size)
@@ -252,3 +717,115 @@ func validateUpdateSubGroupPolicy(specPath *field.Path, lws *v1.LeaderWorkerSet)
 	}
 	return allErrs
 }
+
+func validateGroupReadinessPolicy(groupReadinessPolicyPath *field.Path, lws *v1.LeaderWorkerSet) field.ErrorList {
+	allErrs := field.ErrorList{}
+	policy := lws.Spec.GroupReadinessPolicy
+	if policy.CoordinationOnlyLeader && *lws.Spec.LeaderWorkerTemplate.Size < 2 {
+		// The readiness of the group would not depend on any Ready pod.
+		allErrs = append(allErrs, field.Invalid(groupReadinessPolicyPath.Child("coordinationOnlyLeader"), policy.CoordinationOnlyLeader, "requires the groups to have workers"))
+	}
+	minReadyWorkers := policy.MinReadyWorkers
+	if minReadyWorkers == nil {
+		return allErrs
+	}
+	allErrs = append(allErrs, validateNonnegativeField(int64(*minReadyWorkers), groupReadinessPolicyPath.Child("minReadyWorkers"))...)
+	if workers := *lws.Spec.LeaderWorkerTemplate.Size - 1; *minReadyWorkers > workers {
+		allErrs = append(allErrs, field.Invalid(groupReadinessPolicyPath.Child("minReadyWorkers"), *minReadyWorkers, fmt.Sprintf("must not be greater than the number of workers (%d)", workers)))
+	}
+	if policy.CoordinationOnlyLeader && *minReadyWorkers == 0 {
+		allErrs = append(allErrs, field.Invalid(groupReadinessPolicyPath.Child("minReadyWorkers"), *minReadyWorkers, "must be greater than 0 when coordinationOnlyLeader is set"))
+	}
+	return allErrs
+}
+
+// validateCoordinatorTemplate checks the coordinator template like the leader and worker templates.
+// The pod webhook replaces the spec of the leader pod of the group 0 with its spec, so it must have
+// containers on its own.
+func validateCoordinatorTemplate(path *field.Path, template *corev1.PodTemplateSpec, allowedImageRegistries []string, existingImages sets.Set[string]) field.ErrorList {
+	allErrs := field.ErrorList{}
+	specPath := path.Child("spec")
+	if len(template.Spec.Containers) == 0 {
+		allErrs = append(allErrs, field.Required(specPath.Child("containers"), "must have at least one container"))
+	}
+	allErrs = append(allErrs, validatePodInjectionOptOut(path.Child("metadata"), &template.ObjectMeta)...)
+	allErrs = append(allErrs, validateImagePullPolicies(specPath, &template.Spec)...)
+	allErrs = append(allErrs, validateProcessNamespaceSharing(specPath, &template.Spec)...)
+	allErrs = append(allErrs, validateImageRegistries(specPath, &template.Spec, allowedImageRegistries, existingImages)...)
+	allErrs = append(allErrs, validateDNS(specPath, &template.Spec)...)
+	return allErrs
+}
+
+// validatePodInjectionOptOut rejects the opt-out of the injection in a template, the pod webhook honours
+// it on the pods, so it would bypass the annotation of the lws which can only be set on create.
+func validatePodInjectionOptOut(path *field.Path, meta *metav1.ObjectMeta) field.ErrorList {
+	if _, found := meta.Annotations[v1.DisablePodInjectionAnnotationKey]; !found {
+		return nil
+	}
+	return field.ErrorList{field.Forbidden(path.Child("annotations").Key(v1.DisablePodInjectionAnnotationKey), "can only be set on the LeaderWorkerSet")}
+}
+
+// validateGroupResourceOverrides checks that the overrides target distinct non-negative group
+// indices and containers of the templates, with requests not exceeding the limits once merged
+// into the resources of the containers of the templates.
+func validateGroupResourceOverrides(path *field.Path, lws *v1.LeaderWorkerSet) field.ErrorList {
+	allErrs := field.ErrorList{}
+	containerNames := map[string]bool{}
+	var templateSpecs []*corev1.PodSpec
+	for _, template := range []*corev1.PodTemplateSpec{&lws.Spec.LeaderWorkerTemplate.WorkerTemplate, lws.Spec.LeaderWorkerTemplate.LeaderTemplate, lws.Spec.LeaderWorkerTemplate.CoordinatorTemplate} {
+		if template == nil {
+			continue
+		}
+		templateSpecs = append(templateSpecs, &template.Spec)
+		for _, container := range template.Spec.Containers {
+			containerNames[container.Name] = true
+		}
+	}
+	groupIndices := map[int32]bool{}
+	for i, override := range lws.Spec.LeaderWorkerTemplate.GroupResourceOverrides {
+		overridePath := path.Index(i)
+		if len(override.GroupIndices) == 0 {
+			allErrs = append(allErrs, field.Required(overridePath.Child("groupIndices"), "must target at least one group"))
+		}
+		for j, groupIndex := range override.GroupIndices {
+			groupIndexPath := overridePath.Child("groupIndices").Index(j)
+			allErrs = append(allErrs, validateNonnegativeField(int64(groupIndex), groupIndexPath)...)
+			if groupIndices[groupIndex] {
+				allErrs = append(allErrs, field.Duplicate(groupIndexPath, groupIndex))
+			}
+			groupIndices[groupIndex] = true
+		}
+		if len(override.Containers) == 0 {
+			allErrs = append(allErrs, field.Required(overridePath.Child("containers"), "must override at least one container"))
+		}
+		for j, container := range override.Containers {
+			containerPath := overridePath.Child("containers").Index(j)
+			if !containerNames[container.Name] {
+				allErrs = append(allErrs, field.NotFound(containerPath.Child("name"), container.Name))
+			}
+		}
+		if len(override.GroupIndices) == 0 {
+			continue
+		}
+		// The groups targeted by an override get the same resources, those of the templates merged
+		// with the ones of the override, since the other overrides target other groups.
+		var mergedErrs field.ErrorList
+		for _, templateSpec := range templateSpecs {
+			spec := templateSpec.DeepCopy()
+			podutils.ApplyGroupResourceOverrides(spec, []v1.GroupResourceOverride{override}, override.GroupIndices[0])
+			for j, container := range override.Containers {
+				k := slices.IndexFunc(spec.Containers, func(c corev1.Container) bool { return c.Name == container.Name })
+				if k == -1 {
+					continue
+				}
+				for _, err := range utils.ValidateResourceRequirements(overridePath.Child("containers").Index(j).Child("resources"), spec.Containers[k].Resources) {
+					if !slices.ContainsFunc(mergedErrs, func(e *field.Error) bool { return e.Error() == err.Error() }) {
+						mergedErrs = append(mergedErrs, err)
+					}
+				}
+			}
+		}
+		allErrs = append(allErrs, mergedErrs...)
+	}
+	return allErrs
+}
@@ -17,13 +17,149 @@ limitations under the License.
 package webhooks
 
 import (
+	"context"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/ptr"
+
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
+	v1 "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/features"
+	"sigs.k8s.io/lws/test/wrappers"
 )
 
+func TestDefaultRolloutStrategy(t *testing.T) {
+	tests := []struct {
+		name                string
+		cfg                 *configapi.Configuration
+		rolloutStrategy     v1.RolloutStrategy
+		wantRolloutStrategy v1.RolloutStrategy
+	}{
+		{
+			name: "omitted rollout strategy",
+			cfg:  &configapi.Configuration{},
+			wantRolloutStrategy: v1.RolloutStrategy{
+				Type: v1.RollingUpdateStrategyType,
+				RollingUpdateConfiguration: &v1.RollingUpdateConfiguration{
+					MaxUnavailable: intstr.FromInt32(1),
+					MaxSurge:       intstr.FromInt32(0),
+				},
+			},
+		},
+		{
+			name: "omitted rollout strategy with configured defaults",
+			cfg: &configapi.Configuration{
+				RollingUpdateDefaults: &configapi.RollingUpdateDefaults{
+					MaxUnavailable: ptr.To(intstr.FromString("25%")),
+					MaxSurge:       ptr.To(intstr.FromInt32(2)),
+				},
+			},
+			wantRolloutStrategy: v1.RolloutStrategy{
+				Type: v1.RollingUpdateStrategyType,
+				RollingUpdateConfiguration: &v1.RollingUpdateConfiguration{
+					MaxUnavailable: intstr.FromString("25%"),
+					MaxSurge:       intstr.FromInt32(2),
+				},
+			},
+		},
+		{
+			name: "omitted rollout strategy with configured maxUnavailable",
+			cfg: &configapi.Configuration{
+				RollingUpdateDefaults: &configapi.RollingUpdateDefaults{
+					MaxUnavailable: ptr.To(intstr.FromInt32(2)),
+				},
+			},
+			wantRolloutStrategy: v1.RolloutStrategy{
+				Type: v1.RollingUpdateStrategyType,
+				RollingUpdateConfiguration: &v1.RollingUpdateConfiguration{
+					MaxUnavailable: intstr.FromInt32(2),
+					MaxSurge:       intstr.FromInt32(0),
+				},
+			},
+		},
+		{
+			name: "explicit rollout strategy is preserved",
+			cfg: &configapi.Configuration{
+				RollingUpdateDefaults: &configapi.RollingUpdateDefaults{
+					MaxUnavailable: ptr.To(intstr.FromString("25%")),
+					MaxSurge:       ptr.To(intstr.FromInt32(2)),
+				},
+			},
+			rolloutStrategy: v1.RolloutStrategy{
+				Type: v1.RollingUpdateStrategyType,
+				RollingUpdateConfiguration: &v1.RollingUpdateConfiguration{
+					MaxUnavailable: intstr.FromInt32(3),
+					MaxSurge:       intstr.FromInt32(1),
+				},
+			},
+			wantRolloutStrategy: v1.RolloutStrategy{
+				Type: v1.RollingUpdateStrategyType,
+				RollingUpdateConfiguration: &v1.RollingUpdateConfiguration{
+					MaxUnavailable: intstr.FromInt32(3),
+					MaxSurge:       intstr.FromInt32(1),
+				},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := &v1.LeaderWorkerSet{Spec: v1.LeaderWorkerSetSpec{RolloutStrategy: tc.rolloutStrategy}}
+			if err := newLeaderWorkerSetWebhook(tc.cfg).Default(context.TODO(), lws); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantRolloutStrategy, lws.Spec.RolloutStrategy); diff != "" {
+				t.Errorf("unexpected rollout strategy (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDefaultStartupPolicy(t *testing.T) {
+	tests := []struct {
+		name              string
+		cfg               *configapi.Configuration
+		startupPolicy     v1.StartupPolicyType
+		wantStartupPolicy v1.StartupPolicyType
+	}{
+		{
+			name:              "omitted startup policy",
+			cfg:               &configapi.Configuration{},
+			wantStartupPolicy: v1.LeaderCreatedStartupPolicy,
+		},
+		{
+			name:              "omitted startup policy with configured default",
+			cfg:               &configapi.Configuration{DefaultStartupPolicy: string(v1.LeaderReadyStartupPolicy)},
+			wantStartupPolicy: v1.LeaderReadyStartupPolicy,
+		},
+		{
+			name:              "explicit startup policy is preserved",
+			cfg:               &configapi.Configuration{DefaultStartupPolicy: string(v1.LeaderReadyStartupPolicy)},
+			startupPolicy:     v1.LeaderCreatedStartupPolicy,
+			wantStartupPolicy: v1.LeaderCreatedStartupPolicy,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := &v1.LeaderWorkerSet{Spec: v1.LeaderWorkerSetSpec{StartupPolicy: tc.startupPolicy}}
+			if err := newLeaderWorkerSetWebhook(tc.cfg).Default(context.TODO(), lws); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if lws.Spec.StartupPolicy != tc.wantStartupPolicy {
+				t.Errorf("unexpected startup policy, want %q, got %q", tc.wantStartupPolicy, lws.Spec.StartupPolicy)
+			}
+		})
+	}
+}
+
 func TestGetPercentValue(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -184,3 +320,1505 @@ func TestIsNotMoreThan100Percent(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateImagePullPolicies(t *testing.T) {
+	podSpecPath := field.NewPath("spec", "leaderWorkerTemplate", "workerTemplate", "spec")
+	tests := []struct {
+		name    string
+		podSpec corev1.PodSpec
+		want    field.ErrorList
+	}{
+		{
+			name: "omitted and supported policies",
+			podSpec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "init", ImagePullPolicy: corev1.PullNever}},
+				Containers: []corev1.Container{
+					{Name: "worker"},
+					{Name: "sidecar", ImagePullPolicy: corev1.PullAlways},
+					{Name: "agent", ImagePullPolicy: corev1.PullIfNotPresent},
+				},
+			},
+			want: field.ErrorList{},
+		},
+		{
+			name: "unsupported policies",
+			podSpec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "init", ImagePullPolicy: "always"}},
+				Containers: []corev1.Container{
+					{Name: "worker", ImagePullPolicy: corev1.PullAlways},
+					{Name: "sidecar", ImagePullPolicy: "Sometimes"},
+				},
+			},
+			want: field.ErrorList{
+				field.NotSupported(podSpecPath.Child("initContainers").Index(0).Child("imagePullPolicy"), corev1.PullPolicy("always"), supportedPullPolicies),
+				field.NotSupported(podSpecPath.Child("containers").Index(1).Child("imagePullPolicy"), corev1.PullPolicy("Sometimes"), supportedPullPolicies),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := validateImagePullPolicies(podSpecPath, &tc.podSpec)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected errors: (-want, +got) %s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateSubGroupPolicyUpdate(t *testing.T) {
+	path := field.NewPath("spec", "leaderWorkerTemplate", "subGroupPolicy")
+	leaderWorker, leaderExcluded := v1.SubGroupPolicyTypeLeaderWorker, v1.SubGroupPolicyTypeLeaderExcluded
+	tests := []struct {
+		name      string
+		oldPolicy *v1.SubGroupPolicy
+		newPolicy *v1.SubGroupPolicy
+		want      field.ErrorList
+	}{
+		{
+			name: "no subGroupPolicy",
+			want: field.ErrorList{},
+		},
+		{
+			name:      "unchanged subGroupPolicy",
+			oldPolicy: &v1.SubGroupPolicy{SubGroupSize: ptr.To[int32](2), Type: &leaderWorker},
+			newPolicy: &v1.SubGroupPolicy{SubGroupSize: ptr.To[int32](2), Type: &leaderWorker},
+			want:      field.ErrorList{},
+		},
+		{
+			name:      "defaulted subGroupPolicyType",
+			oldPolicy: &v1.SubGroupPolicy{SubGroupSize: ptr.To[int32](2)},
+			newPolicy: &v1.SubGroupPolicy{SubGroupSize: ptr.To[int32](2), Type: &leaderWorker},
+			want:      field.ErrorList{},
+		},
+		{
+			name:      "added subGroupPolicy",
+			newPolicy: &v1.SubGroupPolicy{SubGroupSize: ptr.To[int32](2)},
+			want: field.ErrorList{
+				field.Forbidden(path, "cannot be added after the lws is created, the pods would be reindexed into subgroups"),
+			},
+		},
+		{
+			name:      "removed subGroupPolicy",
+			oldPolicy: &v1.SubGroupPolicy{SubGroupSize: ptr.To[int32](2)},
+			want: field.ErrorList{
+				field.Forbidden(path, "cannot be removed after the lws is created, the pods would lose their subgroup indexes"),
+			},
+		},
+		{
+			name:      "changed subGroupPolicyType",
+			oldPolicy: &v1.SubGroupPolicy{SubGroupSize: ptr.To[int32](2), Type: &leaderWorker},
+			newPolicy: &v1.SubGroupPolicy{SubGroupSize: ptr.To[int32](2), Type: &leaderExcluded},
+			want: field.ErrorList{
+				field.Invalid(path.Child("subGroupPolicyType"), leaderExcluded, "field is immutable"),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := validateSubGroupPolicyUpdate(path, tc.oldPolicy, tc.newPolicy)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected errors: (-want, +got) %s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateProcessNamespaceSharing(t *testing.T) {
+	podSpecPath := field.NewPath("spec", "leaderWorkerTemplate", "workerTemplate", "spec")
+	tests := []struct {
+		name    string
+		podSpec corev1.PodSpec
+		want    field.ErrorList
+	}{
+		{
+			name:    "shared process namespace",
+			podSpec: corev1.PodSpec{ShareProcessNamespace: ptr.To(true), HostIPC: true},
+			want:    field.ErrorList{},
+		},
+		{
+			name:    "host PID namespace",
+			podSpec: corev1.PodSpec{ShareProcessNamespace: ptr.To(false), HostPID: true},
+			want:    field.ErrorList{},
+		},
+		{
+			name:    "shared process namespace with the host PID namespace",
+			podSpec: corev1.PodSpec{ShareProcessNamespace: ptr.To(true), HostPID: true},
+			want: field.ErrorList{
+				field.Invalid(podSpecPath.Child("shareProcessNamespace"), true, "ShareProcessNamespace and HostPID cannot both be enabled"),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := validateProcessNamespaceSharing(podSpecPath, &tc.podSpec)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected errors: (-want, +got) %s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateImageRegistries(t *testing.T) {
+	podSpecPath := field.NewPath("spec", "leaderWorkerTemplate", "workerTemplate", "spec")
+	podSpec := corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "init", Image: "docker.io/library/busybox:1.36"}},
+		Containers:     []corev1.Container{{Name: "worker", Image: "registry.example.com/vllm:v0.8"}},
+	}
+	tests := []struct {
+		name              string
+		allowedRegistries []string
+		existingImages    sets.Set[string]
+		want              field.ErrorList
+	}{
+		{
+			name: "no allowed registries",
+			want: field.ErrorList{},
+		},
+		{
+			name:              "existing image from a registry not allowed",
+			allowedRegistries: []string{"registry.example.com/"},
+			existingImages:    sets.New("docker.io/library/busybox:1.36"),
+			want:              field.ErrorList{},
+		},
+		{
+			name:              "images from allowed registries",
+			allowedRegistries: []string{"registry.example.com/", "docker.io/library/"},
+			want:              field.ErrorList{},
+		},
+		{
+			name:              "image from a registry not allowed",
+			allowedRegistries: []string{"registry.example.com/"},
+			want: field.ErrorList{
+				field.Forbidden(podSpecPath.Child("initContainers").Index(0).Child("image"), `image "docker.io/library/busybox:1.36" is not from an allowed registry: registry.example.com/`),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := validateImageRegistries(podSpecPath, &podSpec, tc.allowedRegistries, tc.existingImages)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected errors: (-want, +got) %s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateCreateImageRegistries(t *testing.T) {
+	tests := []struct {
+		name              string
+		allowedRegistries []string
+		wantErr           bool
+	}{
+		{
+			name: "all images allowed",
+		},
+		{
+			name:              "images from an allowed registry",
+			allowedRegistries: []string{"nginxinc/"},
+		},
+		{
+			name:              "images from a registry not allowed",
+			allowedRegistries: []string{"registry.example.com/"},
+			wantErr:           true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			wh := newLeaderWorkerSetWebhook(&configapi.Configuration{AllowedImageRegistries: tc.allowedRegistries})
+			lws := wrappers.BuildLeaderWorkerSet("default").Obj()
+			if err := wh.Default(context.TODO(), lws); err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			_, err := wh.ValidateCreate(context.TODO(), lws)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("Expected error: %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateUpdateImageRegistries(t *testing.T) {
+	// The registries were restricted once the lws already used images from another registry.
+	wh := newLeaderWorkerSetWebhook(&configapi.Configuration{AllowedImageRegistries: []string{"registry.example.com/"}})
+	oldLws := wrappers.BuildLeaderWorkerSet("default").Obj()
+	if err := wh.Default(context.TODO(), oldLws); err != nil {
+		t.Fatalf("failed with error: %s", err.Error())
+	}
+
+	scaled := oldLws.DeepCopy()
+	scaled.Spec.Replicas = ptr.To[int32](4)
+	if _, err := wh.ValidateUpdate(context.TODO(), oldLws, scaled); err != nil {
+		t.Errorf("Expected the existing images to be allowed, got %v", err)
+	}
+
+	updated := oldLws.DeepCopy()
+	updated.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec.Containers[0].Image = "docker.io/library/nginx:1.27"
+	if _, err := wh.ValidateUpdate(context.TODO(), oldLws, updated); err == nil {
+		t.Errorf("Expected the new image from a registry not allowed to be rejected")
+	}
+}
+
+func TestValidatePodSpec(t *testing.T) {
+	podSpecPath := field.NewPath("spec", "leaderWorkerTemplate", "workerTemplate", "spec")
+	tests := []struct {
+		name    string
+		podSpec corev1.PodSpec
+		want    field.ErrorList
+	}{
+		{
+			name: "valid spec",
+			podSpec: corev1.PodSpec{
+				Volumes:        []corev1.Volume{{Name: "cache"}},
+				InitContainers: []corev1.Container{{Name: "init", Image: "busybox"}},
+				Containers: []corev1.Container{{
+					Name:         "worker",
+					Image:        "nginx",
+					Ports:        []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+					Env:          []corev1.EnvVar{{Name: "MODEL_PATH"}},
+					VolumeMounts: []corev1.VolumeMount{{Name: "cache", MountPath: "/cache"}},
+				}},
+			},
+			want: field.ErrorList{},
+		},
+		{
+			name: "invalid names",
+			podSpec: corev1.PodSpec{
+				Volumes: []corev1.Volume{{Name: "Cache"}},
+				Containers: []corev1.Container{{
+					Name:  "Worker_1",
+					Image: "nginx",
+					Ports: []corev1.ContainerPort{{Name: "http_port", ContainerPort: 8080}},
+					Env:   []corev1.EnvVar{{Name: "1MODEL"}},
+				}},
+			},
+			want: field.ErrorList{
+				field.Invalid(podSpecPath.Child("volumes").Index(0).Child("name"), "Cache", "a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')"),
+				field.Invalid(podSpecPath.Child("containers").Index(0).Child("name"), "Worker_1", "a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')"),
+				field.Invalid(podSpecPath.Child("containers").Index(0).Child("ports").Index(0).Child("name"), "http_port", "must contain only alpha-numeric characters (a-z, 0-9), and hyphens (-)"),
+				field.Invalid(podSpecPath.Child("containers").Index(0).Child("env").Index(0).Child("name"), "1MODEL", "a valid environment variable name must consist of alphabetic characters, digits, '_', '-', or '.', and must not start with a digit (e.g. 'my.env-name',  or 'MY_ENV.NAME',  or 'MyEnvName1', regex used for validation is '[-._a-zA-Z][-._a-zA-Z0-9]*')"),
+			},
+		},
+		{
+			name: "duplicate and missing fields",
+			podSpec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "worker", Image: "busybox"}},
+				Containers: []corev1.Container{
+					{Name: "worker"},
+					{
+						Image:        "nginx",
+						Ports:        []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}, {Name: "http", ContainerPort: 0}},
+						VolumeMounts: []corev1.VolumeMount{{Name: "cache", MountPath: "/cache"}},
+					},
+				},
+			},
+			want: field.ErrorList{
+				field.Duplicate(podSpecPath.Child("containers").Index(0).Child("name"), "worker"),
+				field.Required(podSpecPath.Child("containers").Index(0).Child("image"), ""),
+				field.Required(podSpecPath.Child("containers").Index(1).Child("name"), ""),
+				field.Duplicate(podSpecPath.Child("containers").Index(1).Child("ports").Index(1).Child("name"), "http"),
+				field.Invalid(podSpecPath.Child("containers").Index(1).Child("ports").Index(1).Child("containerPort"), int32(0), "must be between 1 and 65535, inclusive"),
+				field.NotFound(podSpecPath.Child("containers").Index(1).Child("volumeMounts").Index(0).Child("name"), "cache"),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := validatePodSpec(podSpecPath, &tc.podSpec)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected errors: (-want, +got) %s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateCreatePodTemplates(t *testing.T) {
+	tests := []struct {
+		name                 string
+		validatePodTemplates bool
+		containerName        string
+		wantErr              bool
+	}{
+		{
+			name:          "invalid template without validation",
+			containerName: "Worker_1",
+		},
+		{
+			name:                 "valid template",
+			validatePodTemplates: true,
+			containerName:        "worker",
+		},
+		{
+			name:                 "bad container name",
+			validatePodTemplates: true,
+			containerName:        "Worker_1",
+			wantErr:              true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			wh := newLeaderWorkerSetWebhook(&configapi.Configuration{ValidatePodTemplates: tc.validatePodTemplates})
+			lws := wrappers.BuildLeaderWorkerSet("default").Obj()
+			lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec.Containers[0].Name = tc.containerName
+			if err := wh.Default(context.TODO(), lws); err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			_, err := wh.ValidateCreate(context.TODO(), lws)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("Expected error: %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateDNS(t *testing.T) {
+	podSpecPath := field.NewPath("spec", "leaderWorkerTemplate", "workerTemplate", "spec")
+	tests := []struct {
+		name    string
+		podSpec corev1.PodSpec
+		want    field.ErrorList
+	}{
+		{
+			name:    "omitted dns policy",
+			podSpec: corev1.PodSpec{},
+			want:    field.ErrorList{},
+		},
+		{
+			name:    "host network with the ClusterFirstWithHostNet dns policy",
+			podSpec: corev1.PodSpec{HostNetwork: true, DNSPolicy: corev1.DNSClusterFirstWithHostNet},
+			want:    field.ErrorList{},
+		},
+		{
+			name: "None dns policy with a dns config",
+			podSpec: corev1.PodSpec{
+				DNSPolicy: corev1.DNSNone,
+				DNSConfig: &corev1.PodDNSConfig{Nameservers: []string{"10.0.0.10"}},
+			},
+			want: field.ErrorList{},
+		},
+		{
+			name:    "None dns policy without a dns config",
+			podSpec: corev1.PodSpec{DNSPolicy: corev1.DNSNone},
+			want: field.ErrorList{
+				field.Required(podSpecPath.Child("dnsConfig"), "must be specified when dnsPolicy is None"),
+			},
+		},
+		{
+			name:    "unsupported dns policy",
+			podSpec: corev1.PodSpec{DNSPolicy: "ClusterLast"},
+			want: field.ErrorList{
+				field.NotSupported(podSpecPath.Child("dnsPolicy"), corev1.DNSPolicy("ClusterLast"), supportedDNSPolicies),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := validateDNS(podSpecPath, &tc.podSpec)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected errors: (-want, +got) %s", diff)
+			}
+		})
+	}
+}
+
+func TestTemplateWarnings(t *testing.T) {
+	tests := []struct {
+		name          string
+		leaderPodSpec *corev1.PodSpec
+		workerPodSpec corev1.PodSpec
+		wantWarnings  int
+	}{
+		{
+			name:          "pod network",
+			workerPodSpec: corev1.PodSpec{},
+		},
+		{
+			name:          "host network with the ClusterFirstWithHostNet dns policy",
+			workerPodSpec: corev1.PodSpec{HostNetwork: true, DNSPolicy: corev1.DNSClusterFirstWithHostNet},
+		},
+		{
+			name:          "host network with the omitted dns policy",
+			workerPodSpec: corev1.PodSpec{HostNetwork: true},
+			wantWarnings:  1,
+		},
+		{
+			name:          "host network with the Default dns policy in both templates",
+			leaderPodSpec: &corev1.PodSpec{HostNetwork: true, DNSPolicy: corev1.DNSDefault},
+			workerPodSpec: corev1.PodSpec{HostNetwork: true, DNSPolicy: corev1.DNSDefault},
+			wantWarnings:  2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := &v1.LeaderWorkerSet{}
+			if tc.leaderPodSpec != nil {
+				lws.Spec.LeaderWorkerTemplate.LeaderTemplate = &corev1.PodTemplateSpec{Spec: *tc.leaderPodSpec}
+			}
+			lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec = tc.workerPodSpec
+			if got := templateWarnings(lws); len(got) != tc.wantWarnings {
+				t.Errorf("Expected %d warnings, got %v", tc.wantWarnings, got)
+			}
+		})
+	}
+}
+
+func TestValidateStartupPolicy(t *testing.T) {
+	path := field.NewPath("spec", "startupPolicy")
+	tests := []struct {
+		name        string
+		gateEnabled bool
+		policy      v1.StartupPolicyType
+		want        field.ErrorList
+	}{
+		{
+			name:   "leader ready with the gate disabled",
+			policy: v1.LeaderReadyStartupPolicy,
+			want:   field.ErrorList{},
+		},
+		{
+			name:   "all leaders ready with the gate disabled",
+			policy: v1.AllLeadersReadyStartupPolicy,
+			want: field.ErrorList{
+				field.Forbidden(path, "requires the AllLeadersReadyStartupPolicy feature gate"),
+			},
+		},
+		{
+			name:        "all leaders ready with the gate enabled",
+			gateEnabled: true,
+			policy:      v1.AllLeadersReadyStartupPolicy,
+			want:        field.ErrorList{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			featuregatetesting.SetFeatureGateDuringTest(t, features.DefaultFeatureGate, features.AllLeadersReadyStartupPolicy, tc.gateEnabled)
+			got := validateStartupPolicy(path, tc.policy)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected errors: (-want, +got) %s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateRolloutStrategy(t *testing.T) {
+	path := field.NewPath("spec", "rolloutStrategy")
+	rollingUpdateConfiguration := func(maxSurge intstr.IntOrString) *v1.RollingUpdateConfiguration {
+		return &v1.RollingUpdateConfiguration{MaxUnavailable: intstr.FromInt32(1), MaxSurge: maxSurge}
+	}
+	tests := []struct {
+		name     string
+		strategy v1.RolloutStrategy
+		want     field.ErrorList
+	}{
+		{
+			name:     "recreate",
+			strategy: v1.RolloutStrategy{Type: v1.RecreateStrategyType},
+		},
+		{
+			name:     "recreate with maxSurge",
+			strategy: v1.RolloutStrategy{Type: v1.RecreateStrategyType, RollingUpdateConfiguration: rollingUpdateConfiguration(intstr.FromInt32(1))},
+			want: field.ErrorList{
+				field.Forbidden(path.Child("rollingUpdateConfiguration", "maxSurge"), "may not be specified when type is Recreate, which deletes all the groups before creating the updated ones"),
+			},
+		},
+		{
+			name:     "recreate with a maxSurge percentage",
+			strategy: v1.RolloutStrategy{Type: v1.RecreateStrategyType, RollingUpdateConfiguration: rollingUpdateConfiguration(intstr.FromString("10%"))},
+			want: field.ErrorList{
+				field.Forbidden(path.Child("rollingUpdateConfiguration", "maxSurge"), "may not be specified when type is Recreate, which deletes all the groups before creating the updated ones"),
+			},
+		},
+		{
+			name:     "recreate with a zero maxSurge",
+			strategy: v1.RolloutStrategy{Type: v1.RecreateStrategyType, RollingUpdateConfiguration: rollingUpdateConfiguration(intstr.FromString("0%"))},
+			want: field.ErrorList{
+				field.Forbidden(path.Child("rollingUpdateConfiguration"), "may not be specified when type is Recreate"),
+			},
+		},
+		{
+			name:     "rolling update with maxSurge",
+			strategy: v1.RolloutStrategy{Type: v1.RollingUpdateStrategyType, RollingUpdateConfiguration: rollingUpdateConfiguration(intstr.FromInt32(2))},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := wrappers.BuildLeaderWorkerSet("default").RolloutStrategy(tc.strategy).Obj()
+			got := validateRolloutStrategy(path, lws)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected errors (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDefaultReplicas(t *testing.T) {
+	tests := []struct {
+		name         string
+		replicas     *int32
+		wantReplicas int32
+	}{
+		{
+			name:         "omitted replicas",
+			wantReplicas: 1,
+		},
+		{
+			name:         "scaled to zero is preserved",
+			replicas:     ptr.To[int32](0),
+			wantReplicas: 0,
+		},
+		{
+			name:         "explicit replicas are preserved",
+			replicas:     ptr.To[int32](3),
+			wantReplicas: 3,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := &v1.LeaderWorkerSet{Spec: v1.LeaderWorkerSetSpec{Replicas: tc.replicas}}
+			if err := newLeaderWorkerSetWebhook(&configapi.Configuration{}).Default(context.TODO(), lws); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if lws.Spec.Replicas == nil || *lws.Spec.Replicas != tc.wantReplicas {
+				t.Errorf("unexpected replicas, want %d, got %v", tc.wantReplicas, lws.Spec.Replicas)
+			}
+		})
+	}
+}
+
+func TestValidateScaleToZero(t *testing.T) {
+	wh := newLeaderWorkerSetWebhook(&configapi.Configuration{})
+	oldLws := wrappers.BuildLeaderWorkerSet("default").Replica(2).Size(2).Obj()
+	if err := wh.Default(context.TODO(), oldLws); err != nil {
+		t.Fatalf("failed with error: %s", err.Error())
+	}
+	lws := oldLws.DeepCopy()
+	lws.Spec.Replicas = ptr.To[int32](0)
+
+	if _, err := wh.ValidateCreate(context.TODO(), lws); err != nil {
+		t.Errorf("Expected the creation with zero replicas to be allowed, got %v", err)
+	}
+	if _, err := wh.ValidateUpdate(context.TODO(), oldLws, lws); err != nil {
+		t.Errorf("Expected the scale to zero to be allowed, got %v", err)
+	}
+	if _, err := wh.ValidateUpdate(context.TODO(), lws, oldLws); err != nil {
+		t.Errorf("Expected the scale back up to be allowed, got %v", err)
+	}
+
+	lws.Spec.Replicas = ptr.To[int32](-1)
+	wantErr := "spec.replicas: Invalid value: -1: replicas must be equal or greater than 0"
+	if _, err := wh.ValidateCreate(context.TODO(), lws); err == nil || !strings.Contains(err.Error(), wantErr) {
+		t.Errorf("Expected error %q, got %v", wantErr, err)
+	}
+}
+
+func TestValidateCreateMaxPodsPerLeaderWorkerSet(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxPods  int32
+		replicas int
+		size     int
+		wantErr  string
+	}{
+		{
+			name:     "unlimited",
+			replicas: 100,
+			size:     8,
+		},
+		{
+			name:     "at the cap",
+			maxPods:  64,
+			replicas: 8,
+			size:     8,
+		},
+		{
+			name:     "above the cap",
+			maxPods:  64,
+			replicas: 13,
+			size:     5,
+			wantErr:  "spec.replicas: Invalid value: 13: the 65 pods of the replicas and size must not exceed the 64 pods per LeaderWorkerSet allowed by the controller configuration",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			wh := newLeaderWorkerSetWebhook(&configapi.Configuration{MaxPodsPerLeaderWorkerSet: tc.maxPods})
+			lws := wrappers.BuildLeaderWorkerSet("default").Replica(tc.replicas).Size(tc.size).Obj()
+			if err := wh.Default(context.TODO(), lws); err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			_, err := wh.ValidateCreate(context.TODO(), lws)
+			gotErr := ""
+			if err != nil {
+				gotErr = err.Error()
+			}
+			if gotErr != tc.wantErr {
+				t.Errorf("Expected error %q, got %q", tc.wantErr, gotErr)
+			}
+		})
+	}
+}
+
+func TestValidateUpdateMaxPodsPerLeaderWorkerSet(t *testing.T) {
+	// The cap was lowered once the lws already had 100 pods.
+	wh := newLeaderWorkerSetWebhook(&configapi.Configuration{MaxPodsPerLeaderWorkerSet: 64})
+	oldLws := wrappers.BuildLeaderWorkerSet("default").Replica(20).Size(5).Obj()
+	if err := wh.Default(context.TODO(), oldLws); err != nil {
+		t.Fatalf("failed with error: %s", err.Error())
+	}
+	tests := []struct {
+		name     string
+		replicas int32
+		wantErr  bool
+	}{
+		{
+			name:     "unchanged replicas",
+			replicas: 20,
+		},
+		{
+			name:     "scaled down above the cap",
+			replicas: 15,
+		},
+		{
+			name:     "scaled up",
+			replicas: 21,
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			newLws := oldLws.DeepCopy()
+			newLws.Spec.Replicas = ptr.To(tc.replicas)
+			_, err := wh.ValidateUpdate(context.TODO(), oldLws, newLws)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("Expected error: %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestApplyConfiguration(t *testing.T) {
+	wh := newLeaderWorkerSetWebhook(&configapi.Configuration{MaxPodsPerLeaderWorkerSet: 64})
+	lws := wrappers.BuildLeaderWorkerSet("default").Replica(13).Size(5).Obj()
+	if err := wh.Default(context.TODO(), lws); err != nil {
+		t.Fatalf("failed with error: %s", err.Error())
+	}
+	if _, err := wh.ValidateCreate(context.TODO(), lws); err == nil {
+		t.Errorf("Expected the 65 pods to exceed the cap")
+	}
+
+	// The reloaded configuration raises the cap and changes the defaults of the new lws.
+	wh.ApplyConfiguration(&configapi.Configuration{
+		MaxPodsPerLeaderWorkerSet: 128,
+		DefaultStartupPolicy:      string(v1.LeaderReadyStartupPolicy),
+	})
+	if _, err := wh.ValidateCreate(context.TODO(), lws); err != nil {
+		t.Errorf("Expected the 65 pods to be allowed by the reloaded cap, got %v", err)
+	}
+	newLws := wrappers.BuildLeaderWorkerSet("default").Replica(1).Size(1).Obj()
+	newLws.Spec.StartupPolicy = ""
+	if err := wh.Default(context.TODO(), newLws); err != nil {
+		t.Fatalf("failed with error: %s", err.Error())
+	}
+	if newLws.Spec.StartupPolicy != v1.LeaderReadyStartupPolicy {
+		t.Errorf("Expected the reloaded default startup policy %q, got %q", v1.LeaderReadyStartupPolicy, newLws.Spec.StartupPolicy)
+	}
+}
+
+func TestValidatePodInjectionAnnotation(t *testing.T) {
+	tests := []struct {
+		name           string
+		oldAnnotations map[string]string
+		newAnnotations map[string]string
+		wantErr        bool
+	}{
+		{
+			name:           "disabled on creation",
+			newAnnotations: map[string]string{v1.DisablePodInjectionAnnotationKey: "true"},
+		},
+		{
+			name:           "malformed value",
+			newAnnotations: map[string]string{v1.DisablePodInjectionAnnotationKey: "yes"},
+			wantErr:        true,
+		},
+		{
+			name:           "unchanged on update",
+			oldAnnotations: map[string]string{v1.DisablePodInjectionAnnotationKey: "true"},
+			newAnnotations: map[string]string{v1.DisablePodInjectionAnnotationKey: "true"},
+		},
+		{
+			name:           "disabled on update",
+			oldAnnotations: map[string]string{},
+			newAnnotations: map[string]string{v1.DisablePodInjectionAnnotationKey: "true"},
+			wantErr:        true,
+		},
+		{
+			name:           "removed on update",
+			oldAnnotations: map[string]string{v1.DisablePodInjectionAnnotationKey: "true"},
+			newAnnotations: map[string]string{},
+			wantErr:        true,
+		},
+		{
+			name:           "value rewritten on update",
+			oldAnnotations: map[string]string{v1.DisablePodInjectionAnnotationKey: "true"},
+			newAnnotations: map[string]string{v1.DisablePodInjectionAnnotationKey: "True"},
+			wantErr:        true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			wh := newLeaderWorkerSetWebhook(&configapi.Configuration{})
+			newLws := wrappers.BuildLeaderWorkerSet("default").Annotation(tc.newAnnotations).Obj()
+			if err := wh.Default(context.TODO(), newLws); err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			var err error
+			if tc.oldAnnotations == nil {
+				_, err = wh.ValidateCreate(context.TODO(), newLws)
+			} else {
+				oldLws := newLws.DeepCopy()
+				oldLws.Annotations = tc.oldAnnotations
+				_, err = wh.ValidateUpdate(context.TODO(), oldLws, newLws)
+			}
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("Expected error: %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidatePodInjectionOptOutInTemplates(t *testing.T) {
+	optOut := map[string]string{v1.DisablePodInjectionAnnotationKey: "true"}
+	tests := []struct {
+		name    string
+		mutate  func(lws *v1.LeaderWorkerSet)
+		wantErr bool
+	}{
+		{
+			name:   "no opt-out",
+			mutate: func(lws *v1.LeaderWorkerSet) {},
+		},
+		{
+			name: "opt-out in the leader template",
+			mutate: func(lws *v1.LeaderWorkerSet) {
+				lws.Spec.LeaderWorkerTemplate.LeaderTemplate.Annotations = optOut
+			},
+			wantErr: true,
+		},
+		{
+			name: "opt-out in the worker template",
+			mutate: func(lws *v1.LeaderWorkerSet) {
+				lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Annotations = optOut
+			},
+			wantErr: true,
+		},
+		{
+			name: "opt-out in the coordinator template",
+			mutate: func(lws *v1.LeaderWorkerSet) {
+				lws.Spec.LeaderWorkerTemplate.CoordinatorTemplate = lws.Spec.LeaderWorkerTemplate.WorkerTemplate.DeepCopy()
+				lws.Spec.LeaderWorkerTemplate.CoordinatorTemplate.Annotations = optOut
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			wh := newLeaderWorkerSetWebhook(&configapi.Configuration{})
+			lws := wrappers.BuildLeaderWorkerSet("default").Obj()
+			tc.mutate(lws)
+			if err := wh.Default(context.TODO(), lws); err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			_, err := wh.ValidateCreate(context.TODO(), lws)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("Expected error: %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateWorkloadIdentityAnnotation(t *testing.T) {
+	tests := []struct {
+		name           string
+		oldAnnotations map[string]string
+		newAnnotations map[string]string
+		wantErr        bool
+	}{
+		{
+			name:           "enabled on creation",
+			newAnnotations: map[string]string{v1.InjectWorkloadIdentityAnnotationKey: "true"},
+		},
+		{
+			name:           "malformed value",
+			newAnnotations: map[string]string{v1.InjectWorkloadIdentityAnnotationKey: "yes"},
+			wantErr:        true,
+		},
+		{
+			name:           "unchanged on update",
+			oldAnnotations: map[string]string{v1.InjectWorkloadIdentityAnnotationKey: "true"},
+			newAnnotations: map[string]string{v1.InjectWorkloadIdentityAnnotationKey: "true"},
+		},
+		{
+			name:           "enabled on update",
+			oldAnnotations: map[string]string{},
+			newAnnotations: map[string]string{v1.InjectWorkloadIdentityAnnotationKey: "true"},
+			wantErr:        true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			wh := newLeaderWorkerSetWebhook(&configapi.Configuration{})
+			newLws := wrappers.BuildLeaderWorkerSet("default").Annotation(tc.newAnnotations).Obj()
+			if err := wh.Default(context.TODO(), newLws); err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			var err error
+			if tc.oldAnnotations == nil {
+				_, err = wh.ValidateCreate(context.TODO(), newLws)
+			} else {
+				oldLws := newLws.DeepCopy()
+				oldLws.Annotations = tc.oldAnnotations
+				_, err = wh.ValidateUpdate(context.TODO(), oldLws, newLws)
+			}
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("Expected error: %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateStickyGroupsAnnotation(t *testing.T) {
+	tests := []struct {
+		name           string
+		oldAnnotations map[string]string
+		newAnnotations map[string]string
+		wantErr        bool
+	}{
+		{
+			name:           "group indices",
+			newAnnotations: map[string]string{v1.StickyGroupsAnnotationKey: "0, 3"},
+		},
+		{
+			name:           "negative group index",
+			newAnnotations: map[string]string{v1.StickyGroupsAnnotationKey: "-1"},
+			wantErr:        true,
+		},
+		{
+			name:           "malformed value",
+			newAnnotations: map[string]string{v1.StickyGroupsAnnotationKey: "0,,1"},
+			wantErr:        true,
+		},
+		{
+			name:           "marked on update",
+			oldAnnotations: map[string]string{},
+			newAnnotations: map[string]string{v1.StickyGroupsAnnotationKey: "1"},
+		},
+		{
+			name:           "unmarked on update",
+			oldAnnotations: map[string]string{v1.StickyGroupsAnnotationKey: "1"},
+			newAnnotations: map[string]string{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			wh := newLeaderWorkerSetWebhook(&configapi.Configuration{})
+			newLws := wrappers.BuildLeaderWorkerSet("default").Annotation(tc.newAnnotations).Obj()
+			if err := wh.Default(context.TODO(), newLws); err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			var err error
+			if tc.oldAnnotations == nil {
+				_, err = wh.ValidateCreate(context.TODO(), newLws)
+			} else {
+				oldLws := newLws.DeepCopy()
+				oldLws.Annotations = tc.oldAnnotations
+				_, err = wh.ValidateUpdate(context.TODO(), oldLws, newLws)
+			}
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("Expected error: %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDefaultLeaderless(t *testing.T) {
+	lws := &v1.LeaderWorkerSet{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{v1.LeaderlessAnnotationKey: "true"}}}
+	cfg := &configapi.Configuration{DefaultStartupPolicy: string(v1.LeaderReadyStartupPolicy)}
+	if err := newLeaderWorkerSetWebhook(cfg).Default(context.TODO(), lws); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lws.Spec.StartupPolicy != v1.LeaderCreatedStartupPolicy {
+		t.Errorf("unexpected startup policy, want %q, got %q", v1.LeaderCreatedStartupPolicy, lws.Spec.StartupPolicy)
+	}
+	if got := *lws.Spec.NetworkConfig.SubdomainPolicy; got != v1.SubdomainUniquePerReplica {
+		t.Errorf("unexpected subdomain policy, want %q, got %q", v1.SubdomainUniquePerReplica, got)
+	}
+}
+
+func TestValidateLeaderlessAnnotation(t *testing.T) {
+	tests := []struct {
+		name           string
+		oldAnnotations map[string]string
+		newAnnotations map[string]string
+		mutate         func(*v1.LeaderWorkerSet)
+		wantErr        bool
+	}{
+		{
+			name:           "enabled on creation",
+			newAnnotations: map[string]string{v1.LeaderlessAnnotationKey: "true"},
+		},
+		{
+			name:           "malformed value",
+			newAnnotations: map[string]string{v1.LeaderlessAnnotationKey: "yes"},
+			wantErr:        true,
+		},
+		{
+			name:           "leader template",
+			newAnnotations: map[string]string{v1.LeaderlessAnnotationKey: "true"},
+			mutate: func(lws *v1.LeaderWorkerSet) {
+				lws.Spec.LeaderWorkerTemplate.LeaderTemplate = &corev1.PodTemplateSpec{Spec: wrappers.MakeLeaderPodSpec()}
+			},
+			wantErr: true,
+		},
+		{
+			name:           "group readiness policy",
+			newAnnotations: map[string]string{v1.LeaderlessAnnotationKey: "true"},
+			mutate: func(lws *v1.LeaderWorkerSet) {
+				lws.Spec.GroupReadinessPolicy = &v1.GroupReadinessPolicy{MinReadyWorkers: ptr.To[int32](1)}
+			},
+			wantErr: true,
+		},
+		{
+			name:           "group readiness gate",
+			newAnnotations: map[string]string{v1.LeaderlessAnnotationKey: "true", v1.GroupReadinessGateAnnotationKey: "true"},
+			wantErr:        true,
+		},
+		{
+			name:           "leader ready startup policy",
+			newAnnotations: map[string]string{v1.LeaderlessAnnotationKey: "true"},
+			mutate: func(lws *v1.LeaderWorkerSet) {
+				lws.Spec.StartupPolicy = v1.LeaderReadyStartupPolicy
+			},
+			wantErr: true,
+		},
+		{
+			name:           "shared subdomain policy",
+			newAnnotations: map[string]string{v1.LeaderlessAnnotationKey: "true"},
+			mutate: func(lws *v1.LeaderWorkerSet) {
+				subdomainPolicy := v1.SubdomainShared
+				lws.Spec.NetworkConfig.SubdomainPolicy = &subdomainPolicy
+			},
+			wantErr: true,
+		},
+		{
+			name:           "unchanged on update",
+			oldAnnotations: map[string]string{v1.LeaderlessAnnotationKey: "true"},
+			newAnnotations: map[string]string{v1.LeaderlessAnnotationKey: "true"},
+		},
+		{
+			name:           "enabled on update",
+			oldAnnotations: map[string]string{},
+			newAnnotations: map[string]string{v1.LeaderlessAnnotationKey: "true"},
+			wantErr:        true,
+		},
+		{
+			name:           "value rewritten on update",
+			oldAnnotations: map[string]string{v1.LeaderlessAnnotationKey: "true"},
+			newAnnotations: map[string]string{v1.LeaderlessAnnotationKey: "True"},
+			wantErr:        true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			wh := newLeaderWorkerSetWebhook(&configapi.Configuration{})
+			newLws := wrappers.BuildLeaderWorkerSet("default").Annotation(tc.newAnnotations).Obj()
+			newLws.Spec.LeaderWorkerTemplate.LeaderTemplate = nil
+			newLws.Spec.StartupPolicy = ""
+			newLws.Spec.NetworkConfig = nil
+			if err := wh.Default(context.TODO(), newLws); err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			if tc.mutate != nil {
+				tc.mutate(newLws)
+			}
+			var err error
+			if tc.oldAnnotations == nil {
+				_, err = wh.ValidateCreate(context.TODO(), newLws)
+			} else {
+				oldLws := newLws.DeepCopy()
+				oldLws.Annotations = tc.oldAnnotations
+				_, err = wh.ValidateUpdate(context.TODO(), oldLws, newLws)
+			}
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("Expected error: %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateInjectedContainerResourcesAnnotation(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources string
+		wantErr   bool
+	}{
+		{
+			name:      "requests and limits",
+			resources: `{"requests":{"cpu":"10m","memory":"16Mi"},"limits":{"memory":"32Mi"}}`,
+		},
+		{
+			name:      "malformed value",
+			resources: `{"requests":`,
+			wantErr:   true,
+		},
+		{
+			name:      "invalid quantity",
+			resources: `{"requests":{"cpu":"ten"}}`,
+			wantErr:   true,
+		},
+		{
+			name:      "negative quantity",
+			resources: `{"requests":{"cpu":"-10m"}}`,
+			wantErr:   true,
+		},
+		{
+			name:      "requests exceeding the limits",
+			resources: `{"requests":{"memory":"64Mi"},"limits":{"memory":"32Mi"}}`,
+			wantErr:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			wh := newLeaderWorkerSetWebhook(&configapi.Configuration{})
+			lws := wrappers.BuildLeaderWorkerSet("default").Annotation(map[string]string{v1.InjectedContainerResourcesAnnotationKey: tc.resources}).Obj()
+			if err := wh.Default(context.TODO(), lws); err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			_, err := wh.ValidateCreate(context.TODO(), lws)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("Expected error: %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+
+	// Changing the resources would change the leader pod template and recreate all the groups.
+	wh := newLeaderWorkerSetWebhook(&configapi.Configuration{})
+	oldLws := wrappers.BuildLeaderWorkerSet("default").Annotation(map[string]string{v1.InjectedContainerResourcesAnnotationKey: `{"requests":{"cpu":"10m"}}`}).Obj()
+	if err := wh.Default(context.TODO(), oldLws); err != nil {
+		t.Fatalf("failed with error: %s", err.Error())
+	}
+	newLws := oldLws.DeepCopy()
+	newLws.Annotations[v1.InjectedContainerResourcesAnnotationKey] = `{"requests":{"cpu":"50m"}}`
+	if _, err := wh.ValidateUpdate(context.TODO(), oldLws, newLws); err == nil {
+		t.Errorf("Expected changing the annotation to be rejected")
+	}
+}
+
+func TestValidateQueueNameAnnotation(t *testing.T) {
+	tests := []struct {
+		name         string
+		oldQueueName string
+		newQueueName string
+		update       bool
+		wantErr      bool
+	}{
+		{
+			name:         "valid queue",
+			newQueueName: "team-a",
+		},
+		{
+			name:         "invalid queue",
+			newQueueName: "Team_A",
+			wantErr:      true,
+		},
+		{
+			name:         "unchanged on update",
+			oldQueueName: "team-a",
+			newQueueName: "team-a",
+			update:       true,
+		},
+		{
+			name:         "changed on update",
+			oldQueueName: "team-a",
+			newQueueName: "team-b",
+			update:       true,
+			wantErr:      true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			wh := newLeaderWorkerSetWebhook(&configapi.Configuration{})
+			newLws := wrappers.BuildLeaderWorkerSet("default").Annotation(map[string]string{v1.QueueNameAnnotationKey: tc.newQueueName}).Obj()
+			if err := wh.Default(context.TODO(), newLws); err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			var err error
+			if !tc.update {
+				_, err = wh.ValidateCreate(context.TODO(), newLws)
+			} else {
+				oldLws := newLws.DeepCopy()
+				oldLws.Annotations = map[string]string{v1.QueueNameAnnotationKey: tc.oldQueueName}
+				_, err = wh.ValidateUpdate(context.TODO(), oldLws, newLws)
+			}
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("Expected error: %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateAnnotationPaths(t *testing.T) {
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		startupPolicy v1.StartupPolicyType
+		wantField     string
+	}{
+		{
+			name:          "group readiness gate",
+			annotations:   map[string]string{v1.GroupReadinessGateAnnotationKey: "true"},
+			startupPolicy: v1.LeaderReadyStartupPolicy,
+			wantField:     "metadata.annotations[leaderworkerset.sigs.k8s.io/group-readiness-gate]",
+		},
+		{
+			name:          "subgroup exclusive topology",
+			annotations:   map[string]string{v1.SubGroupExclusiveKeyAnnotationKey: "topologyKey"},
+			startupPolicy: v1.LeaderCreatedStartupPolicy,
+			wantField:     "metadata.annotations[leaderworkerset.sigs.k8s.io/subgroup-exclusive-topology]",
+		},
+		{
+			name:          "groups per minute",
+			annotations:   map[string]string{v1.GroupsPerMinuteAnnotationKey: "0"},
+			startupPolicy: v1.LeaderCreatedStartupPolicy,
+			wantField:     "metadata.annotations[leaderworkerset.sigs.k8s.io/groups-per-minute]",
+		},
+		{
+			name:          "drain timeout",
+			annotations:   map[string]string{v1.DrainTimeoutAnnotationKey: "0"},
+			startupPolicy: v1.LeaderCreatedStartupPolicy,
+			wantField:     "metadata.annotations[leaderworkerset.sigs.k8s.io/drain-timeout-seconds]",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			wh := newLeaderWorkerSetWebhook(&configapi.Configuration{})
+			lws := wrappers.BuildLeaderWorkerSet("default").Annotation(tc.annotations).StartupPolicy(tc.startupPolicy).Obj()
+			if err := wh.Default(context.TODO(), lws); err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			errs := wh.generalValidate(lws, nil)
+			if len(errs) != 1 || errs[0].Field != tc.wantField {
+				t.Errorf("Expected an error for %s, got %v", tc.wantField, errs)
+			}
+		})
+	}
+}
+
+func TestValidateGroupReadinessGateAnnotation(t *testing.T) {
+	tests := []struct {
+		name           string
+		oldAnnotations map[string]string
+		newAnnotations map[string]string
+		startupPolicy  v1.StartupPolicyType
+		wantErr        bool
+	}{
+		{
+			name:           "enabled on creation",
+			newAnnotations: map[string]string{v1.GroupReadinessGateAnnotationKey: "true"},
+		},
+		{
+			name:           "malformed value",
+			newAnnotations: map[string]string{v1.GroupReadinessGateAnnotationKey: "yes"},
+			wantErr:        true,
+		},
+		{
+			name:           "enabled with the LeaderReady startup policy",
+			newAnnotations: map[string]string{v1.GroupReadinessGateAnnotationKey: "true"},
+			startupPolicy:  v1.LeaderReadyStartupPolicy,
+			wantErr:        true,
+		},
+		{
+			name:           "disabled with the LeaderReady startup policy",
+			newAnnotations: map[string]string{v1.GroupReadinessGateAnnotationKey: "false"},
+			startupPolicy:  v1.LeaderReadyStartupPolicy,
+		},
+		{
+			name:           "unchanged on update",
+			oldAnnotations: map[string]string{v1.GroupReadinessGateAnnotationKey: "true"},
+			newAnnotations: map[string]string{v1.GroupReadinessGateAnnotationKey: "true"},
+		},
+		{
+			name:           "enabled on update",
+			oldAnnotations: map[string]string{},
+			newAnnotations: map[string]string{v1.GroupReadinessGateAnnotationKey: "true"},
+			wantErr:        true,
+		},
+		{
+			name:           "removed on update",
+			oldAnnotations: map[string]string{v1.GroupReadinessGateAnnotationKey: "true"},
+			newAnnotations: map[string]string{},
+			wantErr:        true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			wh := newLeaderWorkerSetWebhook(&configapi.Configuration{})
+			newLws := wrappers.BuildLeaderWorkerSet("default").Annotation(tc.newAnnotations).StartupPolicy(tc.startupPolicy).Obj()
+			if err := wh.Default(context.TODO(), newLws); err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			var err error
+			if tc.oldAnnotations == nil {
+				_, err = wh.ValidateCreate(context.TODO(), newLws)
+			} else {
+				oldLws := newLws.DeepCopy()
+				oldLws.Annotations = tc.oldAnnotations
+				_, err = wh.ValidateUpdate(context.TODO(), oldLws, newLws)
+			}
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("Expected error: %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateGroupResourceOverrides(t *testing.T) {
+	path := field.NewPath("spec", "leaderWorkerTemplate", "groupResourceOverrides")
+	containerOverride := func(name, request, limit string) v1.ContainerResourceOverride {
+		override := v1.ContainerResourceOverride{Name: name}
+		if request != "" {
+			override.Resources.Requests = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(request)}
+		}
+		if limit != "" {
+			override.Resources.Limits = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(limit)}
+		}
+		return override
+	}
+	tests := []struct {
+		name      string
+		overrides []v1.GroupResourceOverride
+		want      field.ErrorList
+	}{
+		{
+			name: "valid overrides",
+			overrides: []v1.GroupResourceOverride{
+				{GroupIndices: []int32{0}, Containers: []v1.ContainerResourceOverride{containerOverride("leader", "4", "8")}},
+				{GroupIndices: []int32{1, 2}, Containers: []v1.ContainerResourceOverride{containerOverride("leader", "2", "")}},
+			},
+			want: field.ErrorList{},
+		},
+		{
+			name: "negative group index",
+			overrides: []v1.GroupResourceOverride{
+				{GroupIndices: []int32{-1}, Containers: []v1.ContainerResourceOverride{containerOverride("leader", "4", "")}},
+			},
+			want: field.ErrorList{field.Invalid(path.Index(0).Child("groupIndices").Index(0), int64(-1), "must be grater than or equal to 0")},
+		},
+		{
+			name: "group index targeted twice",
+			overrides: []v1.GroupResourceOverride{
+				{GroupIndices: []int32{0, 1}, Containers: []v1.ContainerResourceOverride{containerOverride("leader", "4", "")}},
+				{GroupIndices: []int32{1}, Containers: []v1.ContainerResourceOverride{containerOverride("leader", "2", "")}},
+			},
+			want: field.ErrorList{field.Duplicate(path.Index(1).Child("groupIndices").Index(0), int32(1))},
+		},
+		{
+			name: "unknown container",
+			overrides: []v1.GroupResourceOverride{
+				{GroupIndices: []int32{0}, Containers: []v1.ContainerResourceOverride{containerOverride("sidecar", "4", "")}},
+			},
+			want: field.ErrorList{field.NotFound(path.Index(0).Child("containers").Index(0).Child("name"), "sidecar")},
+		},
+		{
+			name: "request above the limit",
+			overrides: []v1.GroupResourceOverride{
+				{GroupIndices: []int32{0}, Containers: []v1.ContainerResourceOverride{containerOverride("leader", "8", "4")}},
+			},
+			want: field.ErrorList{field.Invalid(path.Index(0).Child("containers").Index(0).Child("resources", "requests").Key("cpu"), "8", "must be less than or equal to cpu limit of 4")},
+		},
+		{
+			name: "request above the limit of the template",
+			overrides: []v1.GroupResourceOverride{
+				{GroupIndices: []int32{0}, Containers: []v1.ContainerResourceOverride{containerOverride("worker", "8", "")}},
+			},
+			want: field.ErrorList{field.Invalid(path.Index(0).Child("containers").Index(0).Child("resources", "requests").Key("cpu"), "8", "must be less than or equal to cpu limit of 4")},
+		},
+		{
+			name: "limit below the request of the template",
+			overrides: []v1.GroupResourceOverride{
+				{GroupIndices: []int32{0}, Containers: []v1.ContainerResourceOverride{containerOverride("worker", "", "1")}},
+			},
+			want: field.ErrorList{field.Invalid(path.Index(0).Child("containers").Index(0).Child("resources", "requests").Key("cpu"), "2", "must be less than or equal to cpu limit of 1")},
+		},
+		{
+			name: "no containers",
+			overrides: []v1.GroupResourceOverride{
+				{GroupIndices: []int32{0}},
+			},
+			want: field.ErrorList{field.Required(path.Index(0).Child("containers"), "must override at least one container")},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := wrappers.BuildLeaderWorkerSet("default").Obj()
+			lws.Spec.LeaderWorkerTemplate.LeaderTemplate.Spec.Containers[0].Resources = corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+			}
+			lws.Spec.LeaderWorkerTemplate.GroupResourceOverrides = tc.overrides
+			got := validateGroupResourceOverrides(path, lws)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected errors (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateCoordinatorTemplate(t *testing.T) {
+	path := field.NewPath("spec", "leaderWorkerTemplate", "coordinatorTemplate")
+	tests := []struct {
+		name string
+		spec corev1.PodSpec
+		want field.ErrorList
+	}{
+		{
+			name: "valid template",
+			spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "coordinator", Image: "registry.example.com/coordinator"}}},
+			want: field.ErrorList{},
+		},
+		{
+			name: "no containers",
+			want: field.ErrorList{field.Required(path.Child("spec", "containers"), "must have at least one container")},
+		},
+		{
+			name: "unsupported image pull policy",
+			spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "coordinator", Image: "registry.example.com/coordinator", ImagePullPolicy: "Sometimes"}}},
+			want: field.ErrorList{field.NotSupported(path.Child("spec", "containers").Index(0).Child("imagePullPolicy"), corev1.PullPolicy("Sometimes"), supportedPullPolicies)},
+		},
+		{
+			name: "image of a registry not allowed",
+			spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "coordinator", Image: "docker.io/coordinator"}}},
+			want: field.ErrorList{field.Forbidden(path.Child("spec", "containers").Index(0).Child("image"), `image "docker.io/coordinator" is not from an allowed registry: registry.example.com`)},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := validateCoordinatorTemplate(path, &corev1.PodTemplateSpec{Spec: tc.spec}, []string{"registry.example.com"}, sets.New[string]())
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected errors (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateRequiredLabels(t *testing.T) {
+	requiredLabels := []string{"example.com/team", "example.com/cost-center"}
+	tests := []struct {
+		name           string
+		requiredLabels []string
+		labels         map[string]string
+		wantErr        string
+	}{
+		{
+			name: "no required labels",
+		},
+		{
+			name:           "required labels present",
+			requiredLabels: requiredLabels,
+			labels:         map[string]string{"example.com/team": "serving", "example.com/cost-center": "1234"},
+		},
+		{
+			name:           "required label missing",
+			requiredLabels: requiredLabels,
+			labels:         map[string]string{"example.com/team": "serving"},
+			wantErr:        `metadata.labels[example.com/cost-center]: Required value: is required by the controller configuration`,
+		},
+		{
+			name:           "required labels missing",
+			requiredLabels: requiredLabels,
+			wantErr:        `[metadata.labels[example.com/team]: Required value: is required by the controller configuration, metadata.labels[example.com/cost-center]: Required value: is required by the controller configuration]`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			wh := newLeaderWorkerSetWebhook(&configapi.Configuration{RequiredLabels: tc.requiredLabels})
+			lws := wrappers.BuildLeaderWorkerSet("default").Obj()
+			lws.Labels = tc.labels
+			if err := wh.Default(context.TODO(), lws); err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			_, err := wh.ValidateCreate(context.TODO(), lws)
+			gotErr := ""
+			if err != nil {
+				gotErr = err.Error()
+			}
+			if gotErr != tc.wantErr {
+				t.Errorf("Expected error %q, got %q", tc.wantErr, gotErr)
+			}
+		})
+	}
+}
+
+func TestValidateUpdateRequiredLabels(t *testing.T) {
+	tests := []struct {
+		name      string
+		oldLabels map[string]string
+		newLabels map[string]string
+		wantErr   bool
+	}{
+		{
+			name:      "label kept",
+			oldLabels: map[string]string{"example.com/team": "serving"},
+			newLabels: map[string]string{"example.com/team": "training"},
+		},
+		{
+			name:      "label removed",
+			oldLabels: map[string]string{"example.com/team": "serving"},
+			wantErr:   true,
+		},
+		{
+			// The lws created before the label was required can still be updated, e.g. scaled.
+			name: "label missing before",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			wh := newLeaderWorkerSetWebhook(&configapi.Configuration{RequiredLabels: []string{"example.com/team"}})
+			oldLws := wrappers.BuildLeaderWorkerSet("default").Obj()
+			oldLws.Labels = tc.oldLabels
+			if err := wh.Default(context.TODO(), oldLws); err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			newLws := oldLws.DeepCopy()
+			newLws.Labels = tc.newLabels
+			newLws.Spec.Replicas = ptr.To[int32](3)
+			_, err := wh.ValidateUpdate(context.TODO(), oldLws, newLws)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("Expected error: %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
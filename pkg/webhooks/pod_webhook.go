@@ -17,33 +17,81 @@ package webhooks
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
+	"sync/atomic"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
 	"sigs.k8s.io/lws/pkg/utils"
 	acceleratorutils "sigs.k8s.io/lws/pkg/utils/accelerators"
+	"sigs.k8s.io/lws/pkg/utils/naming"
 	podutils "sigs.k8s.io/lws/pkg/utils/pod"
 	statefulsetutils "sigs.k8s.io/lws/pkg/utils/statefulset"
 )
 
-type PodWebhook struct{}
+type PodWebhook struct {
+	// config holds the fields applied again when the configuration is reloaded, loaded once
+	// per admission.
+	config atomic.Pointer[podWebhookConfig]
+	// recommendedLabels are stamped on the pods, unless set by their template.
+	recommendedLabels map[string]string
+	// namer names the headless services, which are the subdomains of the pods.
+	namer naming.Namer
+}
+
+// podWebhookConfig are the fields of the PodWebhook which can be changed without a restart.
+type podWebhookConfig struct {
+	// topologyFile configures the injection of the topology file init container, nil means disabled.
+	topologyFile *configapi.TopologyFile
+	// defaultAnnotations are stamped on the pods, unless set by their template.
+	defaultAnnotations map[string]string
+	// securityContextDefaults are merged into the security contexts of the pods, nil means none.
+	securityContextDefaults *configapi.SecurityContextDefaults
+}
 
-func SetupPodWebhook(mgr ctrl.Manager) error {
-	return ctrl.NewWebhookManagedBy(mgr).
+// SetupPodWebhook will setup the manager to manage the pod webhooks, the returned webhook
+// applies the reloaded configurations.
+func SetupPodWebhook(mgr ctrl.Manager, cfg *configapi.Configuration) (*PodWebhook, error) {
+	wh := &PodWebhook{
+		recommendedLabels: utils.RecommendedLabels(cfg.RecommendedLabels),
+		namer:             naming.Default(),
+	}
+	wh.ApplyConfiguration(cfg)
+	return wh, ctrl.NewWebhookManagedBy(mgr).
 		For(&corev1.Pod{}).
-		WithDefaulter(&PodWebhook{}).
-		WithValidator(&PodWebhook{}).
+		WithDefaulter(wh).
+		WithValidator(wh).
 		Complete()
 }
 
+// ApplyConfiguration sets the fields of the webhook which can be changed without a restart,
+// the pods being admitted are still defaulted with the previous ones.
+func (p *PodWebhook) ApplyConfiguration(cfg *configapi.Configuration) {
+	p.config.Store(&podWebhookConfig{
+		topologyFile:            cfg.TopologyFile,
+		defaultAnnotations:      cfg.DefaultAnnotations,
+		securityContextDefaults: cfg.SecurityContextDefaults,
+	})
+}
+
+// configuration returns the configuration applied to the webhook, the zero one if none was applied.
+func (p *PodWebhook) configuration() *podWebhookConfig {
+	if cfg := p.config.Load(); cfg != nil {
+		return cfg
+	}
+	return &podWebhookConfig{}
+}
+
 //+kubebuilder:webhook:path=/validate--v1-pod,mutating=false,failurePolicy=fail,sideEffects=None,groups="",resources=pods,verbs=create;update,versions=v1,name=vpod.kb.io,sideEffects=None,admissionReviewVersions=v1
 
 // validate admits a pod if a specific annotation exists.
@@ -80,6 +128,7 @@ func (p *PodWebhook) ValidateDelete(ctx context.Context, obj runtime.Object) (ad
 //+kubebuilder:webhook:path=/mutate--v1-pod,mutating=true,failurePolicy=fail,groups="",resources=pods,verbs=create,versions=v1,name=mpod.kb.io,sideEffects=None,admissionReviewVersions=v1
 
 func (p *PodWebhook) Default(ctx context.Context, obj runtime.Object) error {
+	cfg := p.configuration()
 	log := logf.FromContext(ctx)
 	pod, ok := obj.(*corev1.Pod)
 	if !ok {
@@ -100,6 +149,25 @@ func (p *PodWebhook) Default(ctx context.Context, obj runtime.Object) error {
 	if err != nil {
 		return err
 	}
+	// The lws opted out of the injection still gets the labels the controllers rely on.
+	injectionDisabled := pod.Annotations[leaderworkerset.DisablePodInjectionAnnotationKey] == "true"
+	for key, value := range p.recommendedLabels {
+		if _, found := pod.Labels[key]; !found {
+			pod.Labels[key] = value
+		}
+	}
+	for key, value := range cfg.defaultAnnotations {
+		if _, found := pod.Annotations[key]; !found {
+			pod.Annotations[key] = value
+		}
+	}
+	// the gang of the group is recorded for the external schedulers and dashboards, from the
+	// annotations of the current template so that it follows the lws
+	gang, err := json.Marshal(utils.Gang{Size: int32(podCount), Queue: pod.Annotations[leaderworkerset.QueueNameAnnotationKey]})
+	if err != nil {
+		return err
+	}
+	pod.Annotations[leaderworkerset.GangAnnotationKey] = string(gang)
 	// adding labels for pods
 	if podutils.LeaderPod(*pod) {
 		// add group index label to group pods
@@ -110,9 +178,29 @@ func (p *PodWebhook) Default(ctx context.Context, obj runtime.Object) error {
 			}
 			pod.Labels[leaderworkerset.GroupIndexLabelKey] = fmt.Sprint(groupIndex)
 		}
+		// The coordinator template replaces the spec, so it is applied before anything is injected,
+		// and only once.
+		coordinatorTemplate, err := utils.ParseCoordinatorTemplate(pod.Annotations)
+		if err != nil {
+			return err
+		}
+		if coordinatorTemplate != nil && pod.Labels[leaderworkerset.GroupIndexLabelKey] == "0" && pod.Labels[leaderworkerset.CoordinatorGroupLabelKey] != "true" {
+			podutils.ApplyCoordinatorTemplate(pod, coordinatorTemplate)
+		}
+		overrides, err := utils.ParseGroupResourceOverrides(pod.Annotations)
+		if err != nil {
+			return err
+		}
+		if len(overrides) != 0 {
+			groupIndex, err := strconv.Atoi(pod.Labels[leaderworkerset.GroupIndexLabelKey])
+			if err != nil {
+				return fmt.Errorf("parsing the group index of pod %s: %w", pod.Name, err)
+			}
+			podutils.ApplyGroupResourceOverrides(&pod.Spec, overrides, int32(groupIndex))
+		}
 		subdomainPolicy, foundSubdomainPolicy := pod.Annotations[leaderworkerset.SubdomainPolicyAnnotationKey]
 		if foundSubdomainPolicy && subdomainPolicy == string(leaderworkerset.SubdomainUniquePerReplica) {
-			pod.Spec.Subdomain = pod.Name
+			pod.Spec.Subdomain = p.namer.GroupServiceName(pod.Name)
 		}
 		// add group unique key label for exclusive placement, and use it to check whether the node affinity has been applied
 		var groupUniqueKey string
@@ -122,7 +210,11 @@ func (p *PodWebhook) Default(ctx context.Context, obj runtime.Object) error {
 		} else {
 			groupUniqueKey = pod.Labels[leaderworkerset.GroupUniqueHashLabelKey]
 		}
-		if epKey, foundEpKey := pod.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey]; foundEpKey {
+		epKey, foundEpKey, err := utils.ParseExclusiveTopology(pod.Annotations)
+		if err != nil {
+			return err
+		}
+		if foundEpKey && !injectionDisabled {
 			SetExclusiveAffinities(pod, groupUniqueKey, epKey, leaderworkerset.GroupUniqueHashLabelKey)
 		}
 		_, foundSubGroupSize := pod.Annotations[leaderworkerset.SubGroupSizeAnnotationKey]
@@ -132,7 +224,11 @@ func (p *PodWebhook) Default(ctx context.Context, obj runtime.Object) error {
 			pod.Labels[leaderworkerset.SubGroupIndexLabelKey] = "0"
 			subGroupUniqueKey := genGroupUniqueKey(pod.Name, "0")
 			pod.Labels[leaderworkerset.SubGroupUniqueHashLabelKey] = subGroupUniqueKey
-			if subEpKey, foundSubEpKey := pod.Annotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey]; foundSubEpKey {
+			subEpKey, foundSubEpKey, err := utils.ParseSubGroupExclusiveTopology(pod.Annotations)
+			if err != nil {
+				return err
+			}
+			if foundSubEpKey && !injectionDisabled {
 				SetExclusiveAffinities(pod, subGroupUniqueKey, subEpKey, leaderworkerset.SubGroupUniqueHashLabelKey)
 			}
 		}
@@ -153,12 +249,50 @@ func (p *PodWebhook) Default(ctx context.Context, obj runtime.Object) error {
 			pod.Labels[leaderworkerset.SubGroupIndexLabelKey] = subGroupIndexKey
 			subGroupUniqueKey := genGroupUniqueKey(leaderName, subGroupIndexKey)
 			pod.Labels[leaderworkerset.SubGroupUniqueHashLabelKey] = subGroupUniqueKey
-			if subEpKey, foundSubEpKey := pod.Annotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey]; foundSubEpKey {
+			subEpKey, foundSubEpKey, err := utils.ParseSubGroupExclusiveTopology(pod.Annotations)
+			if err != nil {
+				return err
+			}
+			if foundSubEpKey && !injectionDisabled {
 				SetExclusiveAffinities(pod, subGroupUniqueKey, subEpKey, leaderworkerset.SubGroupUniqueHashLabelKey)
 			}
 		}
 	}
 
+	// the statefulsets reject the active deadline in their templates, so it is set on each pod,
+	// after the coordinator template replaced the spec of the leader pod of the group 0
+	activeDeadlineSeconds, err := utils.ParseActiveDeadlineSeconds(pod.Annotations)
+	if err != nil {
+		return err
+	}
+	if activeDeadlineSeconds != nil {
+		pod.Spec.ActiveDeadlineSeconds = activeDeadlineSeconds
+	}
+
+	if !injectionDisabled {
+		// the topology init container is injected ahead of the env vars so that it gets them as well
+		if cfg.topologyFile != nil && ptr.Deref(cfg.topologyFile.Enable, false) {
+			resources, err := utils.ParseInjectedContainerResources(pod.Annotations)
+			if err != nil {
+				return err
+			}
+			if resources == nil {
+				resources = cfg.topologyFile.Resources
+			}
+			podutils.AddTopologyFileInitContainer(pod, *cfg.topologyFile.Image, *cfg.topologyFile.MountPath, resources)
+		}
+	}
+
+	// the security baseline of the cluster applies to the pods opted out of the injection as
+	// well, and to the injected init container
+	if cfg.securityContextDefaults != nil {
+		podutils.ApplySecurityContextDefaults(&pod.Spec, cfg.securityContextDefaults.Pod, cfg.securityContextDefaults.Container)
+	}
+
+	if injectionDisabled {
+		return nil
+	}
+
 	// injecting env vars if needed
 	if acceleratorutils.PodRequestsTPUs(pod.Spec) {
 		if err := acceleratorutils.AddTPUVariables(pod, podCount); err != nil {
@@ -169,6 +303,7 @@ func (p *PodWebhook) Default(ctx context.Context, obj runtime.Object) error {
 	if err := podutils.AddLWSVariables(pod); err != nil {
 		return err
 	}
+	podutils.AddWorkloadIdentityVariables(pod)
 
 	return nil
 }
@@ -243,9 +378,5 @@ func exclusiveAffinityApplied(pod corev1.Pod, topologyKey string) bool {
 }
 
 func getSubGroupIndex(podCount int, subGroupSize int, workerIndex int) string {
-	if (podCount-1)%subGroupSize == 0 {
-		// Leader is considered as extra pod, it is part of the first group
-		return fmt.Sprint((workerIndex - 1) / subGroupSize)
-	}
-	return fmt.Sprint(workerIndex / subGroupSize)
+	return fmt.Sprint(utils.SubGroupIndex(podCount, subGroupSize, workerIndex))
 }
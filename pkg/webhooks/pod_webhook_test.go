@@ -17,13 +17,23 @@ limitations under the License.
 package webhooks
 
 import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/ptr"
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/lws/pkg/utils"
+	"sigs.k8s.io/lws/pkg/utils/naming"
 )
 
 func TestGenGroupUniqueKey(t *testing.T) {
@@ -303,3 +313,740 @@ func TestGetSubGroupIndex(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultRecommendedLabels(t *testing.T) {
+	recommendedLabels := map[string]string{
+		"app.kubernetes.io/managed-by": "platform-operator",
+		"app.kubernetes.io/part-of":    "inference",
+	}
+	tests := []struct {
+		name       string
+		labels     map[string]string
+		wantLabels map[string]string
+	}{
+		{
+			name: "recommended labels are stamped",
+			labels: map[string]string{
+				leaderworkerset.SetNameLabelKey:     "test-sample",
+				leaderworkerset.WorkerIndexLabelKey: "1",
+				leaderworkerset.GroupIndexLabelKey:  "0",
+			},
+			wantLabels: map[string]string{
+				"app.kubernetes.io/managed-by": "platform-operator",
+				"app.kubernetes.io/part-of":    "inference",
+			},
+		},
+		{
+			name: "labels set by the template are preserved",
+			labels: map[string]string{
+				leaderworkerset.SetNameLabelKey:     "test-sample",
+				leaderworkerset.WorkerIndexLabelKey: "1",
+				leaderworkerset.GroupIndexLabelKey:  "0",
+				"app.kubernetes.io/part-of":         "chat",
+			},
+			wantLabels: map[string]string{
+				"app.kubernetes.io/managed-by": "platform-operator",
+				"app.kubernetes.io/part-of":    "chat",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-sample-0-1",
+					Namespace:   "default",
+					Labels:      tc.labels,
+					Annotations: map[string]string{leaderworkerset.SizeAnnotationKey: "2"},
+				},
+			}
+			wh := &PodWebhook{recommendedLabels: recommendedLabels}
+			if err := wh.Default(context.TODO(), pod); err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			for key, value := range tc.wantLabels {
+				if got := pod.Labels[key]; got != value {
+					t.Errorf("Expected label %s=%s, got %q", key, value, got)
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultAnnotations(t *testing.T) {
+	defaultAnnotations := map[string]string{
+		"sidecar.istio.io/inject":     "false",
+		"example.com/cost-allocation": "inference",
+	}
+	tests := []struct {
+		name            string
+		annotations     map[string]string
+		wantAnnotations map[string]string
+	}{
+		{
+			name:        "default annotations are stamped",
+			annotations: map[string]string{leaderworkerset.SizeAnnotationKey: "2"},
+			wantAnnotations: map[string]string{
+				leaderworkerset.SizeAnnotationKey: "2",
+				leaderworkerset.GangAnnotationKey: `{"size":2}`,
+				"sidecar.istio.io/inject":         "false",
+				"example.com/cost-allocation":     "inference",
+			},
+		},
+		{
+			name: "annotations set by the template are preserved",
+			annotations: map[string]string{
+				leaderworkerset.SizeAnnotationKey: "2",
+				"sidecar.istio.io/inject":         "true",
+			},
+			wantAnnotations: map[string]string{
+				leaderworkerset.SizeAnnotationKey: "2",
+				leaderworkerset.GangAnnotationKey: `{"size":2}`,
+				"sidecar.istio.io/inject":         "true",
+				"example.com/cost-allocation":     "inference",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-sample-0-1",
+					Namespace: "default",
+					Labels: map[string]string{
+						leaderworkerset.SetNameLabelKey:     "test-sample",
+						leaderworkerset.WorkerIndexLabelKey: "1",
+						leaderworkerset.GroupIndexLabelKey:  "0",
+					},
+					Annotations: tc.annotations,
+				},
+			}
+			wh := &PodWebhook{}
+			wh.config.Store(&podWebhookConfig{defaultAnnotations: defaultAnnotations})
+			if err := wh.Default(context.TODO(), pod); err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			if diff := cmp.Diff(tc.wantAnnotations, pod.Annotations); diff != "" {
+				t.Errorf("unexpected annotations (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDefaultGangAnnotation(t *testing.T) {
+	tests := []struct {
+		name        string
+		podName     string
+		workerIndex string
+		annotations map[string]string
+		wantGang    utils.Gang
+	}{
+		{
+			name:        "leader pod",
+			podName:     "test-sample-1",
+			workerIndex: "0",
+			annotations: map[string]string{leaderworkerset.SizeAnnotationKey: "4"},
+			wantGang:    utils.Gang{Size: 4},
+		},
+		{
+			name:        "worker pod with a queue",
+			podName:     "test-sample-1-3",
+			workerIndex: "3",
+			annotations: map[string]string{
+				leaderworkerset.SizeAnnotationKey:      "4",
+				leaderworkerset.QueueNameAnnotationKey: "team-a",
+			},
+			wantGang: utils.Gang{Size: 4, Queue: "team-a"},
+		},
+		{
+			name:        "injection disabled",
+			podName:     "test-sample-1",
+			workerIndex: "0",
+			annotations: map[string]string{
+				leaderworkerset.SizeAnnotationKey:                "4",
+				leaderworkerset.DisablePodInjectionAnnotationKey: "true",
+			},
+			wantGang: utils.Gang{Size: 4},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      tc.podName,
+					Namespace: "default",
+					Labels: map[string]string{
+						leaderworkerset.SetNameLabelKey:     "test-sample",
+						leaderworkerset.WorkerIndexLabelKey: tc.workerIndex,
+						leaderworkerset.GroupIndexLabelKey:  "1",
+					},
+					Annotations: tc.annotations,
+				},
+			}
+			wh := &PodWebhook{namer: naming.Default()}
+			if err := wh.Default(context.TODO(), pod); err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			gang, err := utils.ParseGang(pod.Annotations)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(&tc.wantGang, gang); diff != "" {
+				t.Errorf("unexpected gang (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDefaultOrdinalLabels(t *testing.T) {
+	const groups, size = 3, 3
+	for groupIndex := range groups {
+		for workerIndex := range size {
+			// The leader statefulset stamps the worker index of the leaders, the worker
+			// statefulsets the group index of their workers.
+			name := fmt.Sprintf("test-sample-%d", groupIndex)
+			labels := map[string]string{
+				leaderworkerset.SetNameLabelKey:     "test-sample",
+				leaderworkerset.WorkerIndexLabelKey: "0",
+			}
+			annotations := map[string]string{leaderworkerset.SizeAnnotationKey: strconv.Itoa(size)}
+			if workerIndex != 0 {
+				name = fmt.Sprintf("test-sample-%d-%d", groupIndex, workerIndex)
+				labels = map[string]string{
+					leaderworkerset.SetNameLabelKey:    "test-sample",
+					leaderworkerset.GroupIndexLabelKey: strconv.Itoa(groupIndex),
+				}
+				annotations[leaderworkerset.LeaderPodNameAnnotationKey] = fmt.Sprintf("test-sample-%d", groupIndex)
+			}
+			t.Run(name, func(t *testing.T) {
+				pod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        name,
+						Namespace:   "default",
+						Labels:      labels,
+						Annotations: annotations,
+					},
+				}
+				wh := &PodWebhook{namer: naming.Default()}
+				if err := wh.Default(context.TODO(), pod); err != nil {
+					t.Fatalf("failed with error: %s", err.Error())
+				}
+				if got := pod.Labels[leaderworkerset.GroupIndexLabelKey]; got != strconv.Itoa(groupIndex) {
+					t.Errorf("unexpected group index, want %d, got %q", groupIndex, got)
+				}
+				if got := pod.Labels[leaderworkerset.WorkerIndexLabelKey]; got != strconv.Itoa(workerIndex) {
+					t.Errorf("unexpected worker index, want %d, got %q", workerIndex, got)
+				}
+			})
+		}
+	}
+}
+
+// prefixNamer names the services after the default names with a prefix.
+type prefixNamer struct{}
+
+func (prefixNamer) ServiceName(lwsName string) string {
+	return "svc-" + lwsName
+}
+
+func (prefixNamer) GroupServiceName(leaderPodName string) string {
+	return "svc-" + leaderPodName
+}
+
+func TestLeaderPodSubdomain(t *testing.T) {
+	tests := []struct {
+		name          string
+		namer         naming.Namer
+		annotations   map[string]string
+		wantSubdomain string
+	}{
+		{
+			name:  "shared subdomain",
+			namer: naming.Default(),
+			annotations: map[string]string{
+				leaderworkerset.SizeAnnotationKey: "2",
+			},
+		},
+		{
+			name:  "unique subdomain per replica",
+			namer: naming.Default(),
+			annotations: map[string]string{
+				leaderworkerset.SizeAnnotationKey:            "2",
+				leaderworkerset.SubdomainPolicyAnnotationKey: string(leaderworkerset.SubdomainUniquePerReplica),
+			},
+			wantSubdomain: "test-sample-1",
+		},
+		{
+			name:  "unique subdomain per replica with a custom namer",
+			namer: prefixNamer{},
+			annotations: map[string]string{
+				leaderworkerset.SizeAnnotationKey:            "2",
+				leaderworkerset.SubdomainPolicyAnnotationKey: string(leaderworkerset.SubdomainUniquePerReplica),
+			},
+			wantSubdomain: "svc-test-sample-1",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-sample-1",
+					Namespace: "default",
+					Labels: map[string]string{
+						leaderworkerset.SetNameLabelKey:     "test-sample",
+						leaderworkerset.WorkerIndexLabelKey: "0",
+					},
+					Annotations: tc.annotations,
+				},
+			}
+			wh := &PodWebhook{namer: tc.namer}
+			if err := wh.Default(context.TODO(), pod); err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			if pod.Spec.Subdomain != tc.wantSubdomain {
+				t.Errorf("Expected subdomain %q, got %q", tc.wantSubdomain, pod.Spec.Subdomain)
+			}
+		})
+	}
+}
+
+func TestDefaultPodInjectionDisabled(t *testing.T) {
+	tests := []struct {
+		name              string
+		injectionDisabled bool
+	}{
+		{
+			name: "injection enabled",
+		},
+		{
+			name:              "injection disabled",
+			injectionDisabled: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			annotations := map[string]string{
+				leaderworkerset.SizeAnnotationKey:         "2",
+				leaderworkerset.ExclusiveKeyAnnotationKey: "topology.kubernetes.io/zone",
+			}
+			if tc.injectionDisabled {
+				annotations[leaderworkerset.DisablePodInjectionAnnotationKey] = "true"
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-sample-1",
+					Namespace: "default",
+					Labels: map[string]string{
+						leaderworkerset.SetNameLabelKey:     "test-sample",
+						leaderworkerset.WorkerIndexLabelKey: "0",
+					},
+					Annotations: annotations,
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "leader", Image: "nginx"}},
+				},
+			}
+			wh := &PodWebhook{namer: naming.Default()}
+			wh.config.Store(&podWebhookConfig{
+				topologyFile: &configapi.TopologyFile{
+					Enable:    ptr.To(true),
+					Image:     ptr.To("busybox:1.36"),
+					MountPath: ptr.To("/etc/lws"),
+				},
+			})
+			if err := wh.Default(context.TODO(), pod); err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+
+			// The labels the controllers rely on are always stamped.
+			if pod.Labels[leaderworkerset.GroupIndexLabelKey] != "1" || pod.Labels[leaderworkerset.GroupUniqueHashLabelKey] == "" {
+				t.Errorf("Expected the group labels to be stamped, got %v", pod.Labels)
+			}
+			injected := map[string]bool{
+				"affinity":       pod.Spec.Affinity != nil,
+				"initContainers": len(pod.Spec.InitContainers) != 0,
+				"env":            len(pod.Spec.Containers[0].Env) != 0,
+			}
+			for name, got := range injected {
+				if got == tc.injectionDisabled {
+					t.Errorf("Expected %s injected: %v, got %v", name, !tc.injectionDisabled, got)
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultInjectedContainerResources(t *testing.T) {
+	configResources := &corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("10m"),
+			corev1.ResourceMemory: resource.MustParse("16Mi"),
+		},
+	}
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		wantResources corev1.ResourceRequirements
+	}{
+		{
+			name:          "configured resources",
+			wantResources: *configResources,
+		},
+		{
+			name: "resources overridden by the lws",
+			annotations: map[string]string{
+				leaderworkerset.InjectedContainerResourcesAnnotationKey: `{"requests":{"cpu":"50m"},"limits":{"memory":"64Mi"}}`,
+			},
+			wantResources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50m")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			annotations := map[string]string{leaderworkerset.SizeAnnotationKey: "2"}
+			maps.Copy(annotations, tc.annotations)
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-sample-1",
+					Namespace: "default",
+					Labels: map[string]string{
+						leaderworkerset.SetNameLabelKey:     "test-sample",
+						leaderworkerset.WorkerIndexLabelKey: "0",
+					},
+					Annotations: annotations,
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "leader", Image: "nginx"}},
+				},
+			}
+			wh := &PodWebhook{namer: naming.Default()}
+			wh.config.Store(&podWebhookConfig{
+				topologyFile: &configapi.TopologyFile{
+					Enable:    ptr.To(true),
+					Image:     ptr.To("busybox:1.36"),
+					MountPath: ptr.To("/etc/lws"),
+					Resources: configResources,
+				},
+			})
+			if err := wh.Default(context.TODO(), pod); err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+
+			if diff := cmp.Diff(tc.wantResources, pod.Spec.InitContainers[0].Resources); diff != "" {
+				t.Errorf("unexpected injected container resources (-want +got):\n%s", diff)
+			}
+			// The user containers are left alone.
+			if diff := cmp.Diff(corev1.ResourceRequirements{}, pod.Spec.Containers[0].Resources); diff != "" {
+				t.Errorf("unexpected container resources (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDefaultLeaderGroupResourceOverrides(t *testing.T) {
+	overrides := `[{"groupIndices":[0],"containers":[{"name":"leader","resources":{"limits":{"nvidia.com/gpu":"8"}}}]}]`
+	tests := []struct {
+		name          string
+		podName       string
+		wantResources corev1.ResourceRequirements
+	}{
+		{
+			name:    "targeted group",
+			podName: "test-sample-0",
+			wantResources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+				Limits:   corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("8")},
+			},
+		},
+		{
+			name:    "other group",
+			podName: "test-sample-1",
+			wantResources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      tc.podName,
+					Namespace: "default",
+					Labels: map[string]string{
+						leaderworkerset.SetNameLabelKey:     "test-sample",
+						leaderworkerset.WorkerIndexLabelKey: "0",
+					},
+					Annotations: map[string]string{
+						leaderworkerset.SizeAnnotationKey:                   "2",
+						leaderworkerset.GroupResourceOverridesAnnotationKey: overrides,
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:      "leader",
+						Image:     "nginx",
+						Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}},
+					}},
+				},
+			}
+			wh := &PodWebhook{namer: naming.Default()}
+			if err := wh.Default(context.TODO(), pod); err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			if diff := cmp.Diff(tc.wantResources, pod.Spec.Containers[0].Resources); diff != "" {
+				t.Errorf("Unexpected leader resources (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDefaultLeaderCoordinatorTemplate(t *testing.T) {
+	coordinatorTemplate := `{"metadata":{"labels":{"role":"coordinator"}},"spec":{"containers":[{"name":"coordinator","image":"coordinator"}]}}`
+	tokenMount := corev1.VolumeMount{Name: "kube-api-access", MountPath: "/var/run/secrets/kubernetes.io/serviceaccount", ReadOnly: true}
+	tests := []struct {
+		name            string
+		podName         string
+		wantImage       string
+		wantCoordinator bool
+	}{
+		{
+			name:            "coordinator group",
+			podName:         "test-sample-0",
+			wantImage:       "coordinator",
+			wantCoordinator: true,
+		},
+		{
+			name:      "other group",
+			podName:   "test-sample-1",
+			wantImage: "nginx",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      tc.podName,
+					Namespace: "default",
+					Labels: map[string]string{
+						leaderworkerset.SetNameLabelKey:     "test-sample",
+						leaderworkerset.WorkerIndexLabelKey: "0",
+					},
+					Annotations: map[string]string{
+						leaderworkerset.SizeAnnotationKey:                "2",
+						leaderworkerset.CoordinatorTemplateAnnotationKey: coordinatorTemplate,
+					},
+				},
+				Spec: corev1.PodSpec{
+					Hostname:  tc.podName,
+					Subdomain: "test-sample",
+					Containers: []corev1.Container{{
+						Name:         "leader",
+						Image:        "nginx",
+						VolumeMounts: []corev1.VolumeMount{tokenMount},
+					}},
+					// The volume of the service account token is added by the admission plugins.
+					Volumes: []corev1.Volume{{Name: "kube-api-access"}},
+				},
+			}
+			wh := &PodWebhook{namer: naming.Default()}
+			if err := wh.Default(context.TODO(), pod); err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			if len(pod.Spec.Containers) != 1 || pod.Spec.Containers[0].Image != tc.wantImage {
+				t.Fatalf("Expected a single container with the %s image, got %v", tc.wantImage, pod.Spec.Containers)
+			}
+			if pod.Spec.Hostname != tc.podName || pod.Spec.Subdomain != "test-sample" {
+				t.Errorf("Expected the hostname and subdomain of the statefulset to be kept, got %s.%s", pod.Spec.Hostname, pod.Spec.Subdomain)
+			}
+			if diff := cmp.Diff([]corev1.VolumeMount{tokenMount}, pod.Spec.Containers[0].VolumeMounts); diff != "" {
+				t.Errorf("Unexpected volume mounts (-want +got):\n%s", diff)
+			}
+			_, coordinatorLabel := pod.Labels[leaderworkerset.CoordinatorGroupLabelKey]
+			_, templateLabel := pod.Labels["role"]
+			coordinatorEnv := slices.ContainsFunc(pod.Spec.Containers[0].Env, func(env corev1.EnvVar) bool {
+				return env.Name == leaderworkerset.LwsCoordinatorGroup && env.Value == "true"
+			})
+			if coordinatorLabel != tc.wantCoordinator || templateLabel != tc.wantCoordinator || coordinatorEnv != tc.wantCoordinator {
+				t.Errorf("Expected the coordinator label, template label and env to be %t, got %t, %t and %t",
+					tc.wantCoordinator, coordinatorLabel, templateLabel, coordinatorEnv)
+			}
+		})
+	}
+}
+
+func TestDefaultActiveDeadlineSeconds(t *testing.T) {
+	coordinatorTemplate := `{"spec":{"containers":[{"name":"coordinator","image":"coordinator"}]}}`
+	tests := []struct {
+		name        string
+		podName     string
+		labels      map[string]string
+		annotations map[string]string
+		want        *int64
+	}{
+		{
+			name:    "leader pod",
+			podName: "test-sample-1",
+			labels:  map[string]string{leaderworkerset.WorkerIndexLabelKey: "0"},
+			annotations: map[string]string{
+				leaderworkerset.ActiveDeadlineSecondsAnnotationKey: "3600",
+			},
+			want: ptr.To[int64](3600),
+		},
+		{
+			name:    "leader pod of the coordinator group",
+			podName: "test-sample-0",
+			labels:  map[string]string{leaderworkerset.WorkerIndexLabelKey: "0"},
+			annotations: map[string]string{
+				leaderworkerset.ActiveDeadlineSecondsAnnotationKey: "3600",
+				leaderworkerset.CoordinatorTemplateAnnotationKey:   coordinatorTemplate,
+			},
+			want: ptr.To[int64](3600),
+		},
+		{
+			name:    "worker pod",
+			podName: "test-sample-1-1",
+			labels:  map[string]string{leaderworkerset.GroupIndexLabelKey: "1"},
+			annotations: map[string]string{
+				leaderworkerset.ActiveDeadlineSecondsAnnotationKey: "3600",
+			},
+			want: ptr.To[int64](3600),
+		},
+		{
+			name:    "no active deadline",
+			podName: "test-sample-1-1",
+			labels:  map[string]string{leaderworkerset.GroupIndexLabelKey: "1"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        tc.podName,
+					Namespace:   "default",
+					Labels:      map[string]string{leaderworkerset.SetNameLabelKey: "test-sample"},
+					Annotations: map[string]string{leaderworkerset.SizeAnnotationKey: "2"},
+				},
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "worker", Image: "nginx"}}},
+			}
+			maps.Copy(pod.Labels, tc.labels)
+			maps.Copy(pod.Annotations, tc.annotations)
+			wh := &PodWebhook{namer: naming.Default()}
+			if err := wh.Default(context.TODO(), pod); err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+			if diff := cmp.Diff(tc.want, pod.Spec.ActiveDeadlineSeconds); diff != "" {
+				t.Errorf("Unexpected active deadline seconds (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDefaultSecurityContextDefaults(t *testing.T) {
+	defaults := &configapi.SecurityContextDefaults{
+		Pod: &corev1.PodSecurityContext{
+			RunAsNonRoot:   ptr.To(true),
+			SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+		},
+		Container: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: ptr.To(false),
+			Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+		},
+	}
+	tests := []struct {
+		name                         string
+		podSecurityContext           *corev1.PodSecurityContext
+		containerSecurityContext     *corev1.SecurityContext
+		wantPodSecurityContext       *corev1.PodSecurityContext
+		wantContainerSecurityContext *corev1.SecurityContext
+	}{
+		{
+			name: "unset security contexts",
+			wantPodSecurityContext: &corev1.PodSecurityContext{
+				RunAsNonRoot:   ptr.To(true),
+				SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+			},
+			wantContainerSecurityContext: &corev1.SecurityContext{
+				AllowPrivilegeEscalation: ptr.To(false),
+				Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+			},
+		},
+		{
+			name: "explicit settings are preserved",
+			podSecurityContext: &corev1.PodSecurityContext{
+				RunAsNonRoot: ptr.To(false),
+				RunAsUser:    ptr.To[int64](1000),
+			},
+			containerSecurityContext: &corev1.SecurityContext{
+				Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"NET_ADMIN"}},
+			},
+			wantPodSecurityContext: &corev1.PodSecurityContext{
+				RunAsNonRoot:   ptr.To(false),
+				RunAsUser:      ptr.To[int64](1000),
+				SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+			},
+			wantContainerSecurityContext: &corev1.SecurityContext{
+				AllowPrivilegeEscalation: ptr.To(false),
+				Capabilities:             &corev1.Capabilities{Add: []corev1.Capability{"NET_ADMIN"}},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-sample-1",
+					Namespace: "default",
+					Labels: map[string]string{
+						leaderworkerset.SetNameLabelKey:     "test-sample",
+						leaderworkerset.WorkerIndexLabelKey: "0",
+					},
+					Annotations: map[string]string{leaderworkerset.SizeAnnotationKey: "2"},
+				},
+				Spec: corev1.PodSpec{
+					SecurityContext: tc.podSecurityContext,
+					Containers:      []corev1.Container{{Name: "leader", Image: "nginx", SecurityContext: tc.containerSecurityContext}},
+				},
+			}
+			wh := &PodWebhook{namer: naming.Default()}
+			wh.config.Store(&podWebhookConfig{
+				topologyFile: &configapi.TopologyFile{
+					Enable:    ptr.To(true),
+					Image:     ptr.To("busybox:1.36"),
+					MountPath: ptr.To("/etc/lws"),
+				},
+				securityContextDefaults: defaults,
+			})
+			if err := wh.Default(context.TODO(), pod); err != nil {
+				t.Fatalf("failed with error: %s", err.Error())
+			}
+
+			if diff := cmp.Diff(tc.wantPodSecurityContext, pod.Spec.SecurityContext); diff != "" {
+				t.Errorf("unexpected pod security context (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantContainerSecurityContext, pod.Spec.Containers[0].SecurityContext); diff != "" {
+				t.Errorf("unexpected container security context (-want +got):\n%s", diff)
+			}
+			// The injected init container gets the defaults as well.
+			if diff := cmp.Diff(defaults.Container, pod.Spec.InitContainers[0].SecurityContext); diff != "" {
+				t.Errorf("unexpected init container security context (-want +got):\n%s", diff)
+			}
+			// The defaults are copied, not shared with the pods.
+			pod.Spec.SecurityContext.SeccompProfile.Type = corev1.SeccompProfileTypeUnconfined
+			if defaults.Pod.SeccompProfile.Type != corev1.SeccompProfileTypeRuntimeDefault {
+				t.Errorf("Expected the defaults not to be modified, got %v", defaults.Pod.SeccompProfile)
+			}
+		})
+	}
+}
@@ -340,6 +340,37 @@ var _ = ginkgo.Describe("leaderWorkerSet e2e tests", func() {
 		}, timeout, interval).Should(gomega.Equal(0))
 	})
 
+	ginkgo.It("Recreates a deleted group at its own index", func() {
+		lws = wrappers.BuildLeaderWorkerSet(ns.Name).Replica(5).Size(2).RestartPolicy(v1.NoneRestartPolicy).Obj()
+		testing.MustCreateLws(ctx, k8sClient, lws)
+		testing.ExpectLeaderWorkerSetAvailable(ctx, k8sClient, lws, "All replicas are ready")
+
+		deletedLeader := &corev1.Pod{}
+		gomega.Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: lws.Namespace, Name: lws.Name + "-2"}, deletedLeader)).To(gomega.Succeed())
+		gomega.Expect(k8sClient.Delete(ctx, deletedLeader)).To(gomega.Succeed())
+
+		// The group fills the gap at index 2 rather than being appended at index 5.
+		gomega.Eventually(func() ([]string, error) {
+			leaders := &corev1.PodList{}
+			if err := k8sClient.List(ctx, leaders, client.InNamespace(lws.Namespace), client.MatchingLabels{
+				v1.SetNameLabelKey:     lws.Name,
+				v1.WorkerIndexLabelKey: "0",
+			}); err != nil {
+				return nil, err
+			}
+			var groupIndexes []string
+			for _, leader := range leaders.Items {
+				if leader.Name == deletedLeader.Name && leader.UID == deletedLeader.UID {
+					return nil, nil
+				}
+				groupIndexes = append(groupIndexes, leader.Labels[v1.GroupIndexLabelKey])
+			}
+			return groupIndexes, nil
+		}, timeout, interval).Should(gomega.ConsistOf("0", "1", "2", "3", "4"))
+		testing.ExpectLeaderWorkerSetAvailable(ctx, k8sClient, lws, "All replicas are ready")
+		testing.ExpectValidWorkerStatefulSets(ctx, lws, k8sClient, true)
+	})
+
 	// metricsRoleBindingName := "lws-metrics-reader-rolebinding"
 	serviceAccountName := "lws-controller-manager"
 	metricsServiceName := "lws-controller-manager-metrics-service"
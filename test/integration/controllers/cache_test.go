@@ -0,0 +1,98 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrlcache "sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/config"
+)
+
+var _ = ginkgo.Describe("Pod cache label selector", func() {
+	ginkgo.It("should only cache the pods matching the configured selector", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		configFile := filepath.Join(ginkgo.GinkgoT().TempDir(), "config.yaml")
+		gomega.Expect(os.WriteFile(configFile, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+cache:
+  podLabelSelector: leaderworkerset.sigs.k8s.io/name
+`), os.FileMode(0600))).To(gomega.Succeed())
+		options, _, err := config.Load(scheme.Scheme, configFile)
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+		cacheOptions := options.Cache
+		cacheOptions.Scheme = scheme.Scheme
+		podCache, err := ctrlcache.New(cfg, cacheOptions)
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		go func() {
+			defer ginkgo.GinkgoRecover()
+			gomega.Expect(podCache.Start(ctx)).To(gomega.Succeed())
+		}()
+		gomega.Expect(podCache.WaitForCacheSync(ctx)).To(gomega.BeTrue())
+
+		ns := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: "lws-ns-",
+			},
+		}
+		gomega.Expect(k8sClient.Create(ctx, ns)).To(gomega.Succeed())
+		makePod := func(name string, labels map[string]string) *corev1.Pod {
+			return &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: ns.Name,
+					Labels:    labels,
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "c", Image: "pause"}},
+				},
+			}
+		}
+		gomega.Expect(k8sClient.Create(ctx, makePod("managed", map[string]string{leaderworkerset.SetNameLabelKey: "test-sample"}))).To(gomega.Succeed())
+		gomega.Expect(k8sClient.Create(ctx, makePod("unrelated", map[string]string{"app": "unrelated"}))).To(gomega.Succeed())
+
+		gomega.Eventually(func() ([]string, error) {
+			var pods corev1.PodList
+			if err := podCache.List(ctx, &pods, client.InNamespace(ns.Name)); err != nil {
+				return nil, err
+			}
+			var names []string
+			for _, pod := range pods.Items {
+				names = append(names, pod.Name)
+			}
+			return names, nil
+		}).Should(gomega.Equal([]string{"managed"}))
+		gomega.Consistently(func() error {
+			var pod corev1.Pod
+			return podCache.Get(ctx, client.ObjectKey{Namespace: ns.Name, Name: "unrelated"}, &pod)
+		}).ShouldNot(gomega.Succeed())
+	})
+})
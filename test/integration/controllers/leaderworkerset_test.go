@@ -612,6 +612,118 @@ var _ = ginkgo.Describe("LeaderWorkerSet controller", func() {
 				},
 			},
 		}),
+		ginkgo.Entry("leaderTemplate changed with Recreate strategy", &testCase{
+			makeLeaderWorkerSet: func(nsName string) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(nsName).Replica(2).
+					RolloutStrategy(leaderworkerset.RolloutStrategy{Type: leaderworkerset.RecreateStrategyType})
+			},
+			updates: []*update{
+				{
+					// Set lws to available condition.
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.SetPodGroupsToReady(ctx, k8sClient, lws, 2)
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectLeaderWorkerSetAvailable(ctx, k8sClient, lws, "All replicas are ready")
+						testing.ExpectValidLeaderStatefulSet(ctx, k8sClient, lws, 2)
+						testing.ExpectLeaderWorkerSetStatusReplicas(ctx, k8sClient, lws, 2, 2)
+					},
+				},
+				{
+					// All the groups are torn down once the template changes.
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.UpdateLeaderTemplate(ctx, k8sClient, lws)
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectValidLeaderStatefulSet(ctx, k8sClient, lws, 0)
+						testing.ExpectStatefulsetPartitionEqualTo(ctx, k8sClient, lws, 0)
+						testing.ExpectLeaderWorkerSetUnavailable(ctx, k8sClient, lws, "All replicas are ready")
+						testing.ExpectLeaderWorkerSetUpgradeInProgress(ctx, k8sClient, lws, "Recreate is in progress, groups are unavailable until all of them are recreated")
+						testing.ValidateEvent(ctx, k8sClient, controllers.GroupsRecreating, corev1.EventTypeNormal, "Deleting all groups to recreate them with the updated revision", lws.Namespace)
+					},
+				},
+				{
+					// The groups are recreated with the new revision once the outdated ones are gone.
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.DeleteLeaderPod(ctx, k8sClient, lws, 0, 2)
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectValidLeaderStatefulSet(ctx, k8sClient, lws, 2)
+					},
+				},
+				{
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.SetPodGroupsToReady(ctx, k8sClient, lws, 2)
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectValidLeaderStatefulSet(ctx, k8sClient, lws, 2)
+						testing.ExpectValidWorkerStatefulSets(ctx, lws, k8sClient, true)
+						testing.ExpectLeaderWorkerSetNoUpgradeInProgress(ctx, k8sClient, lws, "Recreate is in progress, groups are unavailable until all of them are recreated")
+						testing.ExpectLeaderWorkerSetAvailable(ctx, k8sClient, lws, "All replicas are ready")
+						testing.ExpectLeaderWorkerSetStatusReplicas(ctx, k8sClient, lws, 2, 2)
+					},
+				},
+			},
+		}),
+		ginkgo.Entry("fields managed by other systems are preserved across reconciles", &testCase{
+			makeLeaderWorkerSet: func(nsName string) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(nsName).Replica(1)
+			},
+			updates: []*update{
+				{
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						var workerSts appsv1.StatefulSet
+						gomega.Eventually(func() error {
+							return k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-0", Namespace: lws.Namespace}, &workerSts)
+						}, testing.Timeout, testing.Interval).Should(gomega.Succeed())
+						// Another system annotates the worker statefulset and the leader pod, and drops a label of
+						// the worker statefulset owned by the controller, which the controller then applies again.
+						foreignAnnotation := []byte(`{"metadata":{"annotations":{"example.com/managed-by-other":"true"}}}`)
+						workerStsPatch := []byte(fmt.Sprintf(`{"metadata":{"annotations":{"example.com/managed-by-other":"true"},"labels":{%q:null}}}`, leaderworkerset.GroupUniqueHashLabelKey))
+						gomega.Expect(k8sClient.Patch(ctx, &workerSts, client.RawPatch(types.MergePatchType, workerStsPatch), client.FieldOwner("other-system"))).To(gomega.Succeed())
+						var leaderPod corev1.Pod
+						gomega.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-0", Namespace: lws.Namespace}, &leaderPod)).To(gomega.Succeed())
+						gomega.Expect(k8sClient.Patch(ctx, &leaderPod, client.RawPatch(types.MergePatchType, foreignAnnotation), client.FieldOwner("other-system"))).To(gomega.Succeed())
+						// Trigger reconciles of the leader pod and the lws.
+						testing.SetPodGroupsToReady(ctx, k8sClient, lws, 1)
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectLeaderWorkerSetAvailable(ctx, k8sClient, lws, "All replicas are ready")
+						gomega.Eventually(func() (map[string]string, error) {
+							var workerSts appsv1.StatefulSet
+							if err := k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-0", Namespace: lws.Namespace}, &workerSts); err != nil {
+								return nil, err
+							}
+							return workerSts.Labels, nil
+						}, testing.Timeout, testing.Interval).Should(gomega.HaveKey(leaderworkerset.GroupUniqueHashLabelKey))
+						gomega.Consistently(func() (map[string]string, error) {
+							var workerSts appsv1.StatefulSet
+							if err := k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-0", Namespace: lws.Namespace}, &workerSts); err != nil {
+								return nil, err
+							}
+							return workerSts.Annotations, nil
+						}, 3*time.Second, testing.Interval).Should(gomega.HaveKeyWithValue("example.com/managed-by-other", "true"))
+						gomega.Consistently(func() (map[string]string, error) {
+							var leaderPod corev1.Pod
+							if err := k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-0", Namespace: lws.Namespace}, &leaderPod); err != nil {
+								return nil, err
+							}
+							return leaderPod.Annotations, nil
+						}, 3*time.Second, testing.Interval).Should(gomega.HaveKeyWithValue("example.com/managed-by-other", "true"))
+						// The worker statefulset fields set by the controller are owned by its dedicated field manager.
+						var workerSts appsv1.StatefulSet
+						gomega.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-0", Namespace: lws.Namespace}, &workerSts)).To(gomega.Succeed())
+						var managers []string
+						for _, entry := range workerSts.ManagedFields {
+							if entry.Operation == metav1.ManagedFieldsOperationApply {
+								managers = append(managers, entry.Manager)
+							}
+						}
+						gomega.Expect(managers).To(gomega.ConsistOf("lws-pod-controller"))
+					},
+				},
+			},
+		}),
 		ginkgo.Entry("workerTemplate changed with maxUnavailable=2", &testCase{
 			makeLeaderWorkerSet: func(nsName string) *wrappers.LeaderWorkerSetWrapper {
 				return wrappers.BuildLeaderWorkerSet(nsName).Replica(4).MaxUnavailable(2)
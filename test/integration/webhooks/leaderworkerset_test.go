@@ -243,6 +243,71 @@ var _ = ginkgo.Describe("leaderworkerset defaulting, creation and update", func(
 			},
 			lwsCreationShouldFail: true,
 		}),
+		ginkgo.Entry("creation with minReadyWorkers not greater than the number of workers should succeed", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(ns.Name).Size(4).MinReadyWorkers(3)
+			},
+			lwsCreationShouldFail: false,
+		}),
+		ginkgo.Entry("creation with minReadyWorkers greater than the number of workers should fail", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(ns.Name).Size(4).MinReadyWorkers(4)
+			},
+			lwsCreationShouldFail: true,
+		}),
+		ginkgo.Entry("creation with a coordination-only leader should succeed", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(ns.Name).Size(4).MinReadyWorkers(2).CoordinationOnlyLeader()
+			},
+			lwsCreationShouldFail: false,
+		}),
+		ginkgo.Entry("creation with a coordination-only leader and no workers should fail", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(ns.Name).Size(1).CoordinationOnlyLeader()
+			},
+			lwsCreationShouldFail: true,
+		}),
+		ginkgo.Entry("creation with a coordination-only leader and zero minReadyWorkers should fail", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(ns.Name).Size(4).MinReadyWorkers(0).CoordinationOnlyLeader()
+			},
+			lwsCreationShouldFail: true,
+		}),
+		ginkgo.Entry("creation with different leader and worker image pull policies should succeed", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				leaderPodSpec := wrappers.MakeLeaderPodSpec()
+				leaderPodSpec.Containers[0].ImagePullPolicy = corev1.PullAlways
+				workerPodSpec := wrappers.MakeWorkerPodSpec()
+				workerPodSpec.Containers[0].ImagePullPolicy = corev1.PullIfNotPresent
+				return wrappers.BuildLeaderWorkerSet(ns.Name).LeaderTemplateSpec(leaderPodSpec).WorkerTemplateSpec(workerPodSpec)
+			},
+			lwsCreationShouldFail: false,
+		}),
+		ginkgo.Entry("creation with unsupported worker image pull policy should fail", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				workerPodSpec := wrappers.MakeWorkerPodSpec()
+				workerPodSpec.Containers[0].ImagePullPolicy = "Sometimes"
+				return wrappers.BuildLeaderWorkerSet(ns.Name).WorkerTemplateSpec(workerPodSpec)
+			},
+			lwsCreationShouldFail: true,
+		}),
+		ginkgo.Entry("creation with a worker template sharing the process namespace should succeed", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				workerPodSpec := wrappers.MakeWorkerPodSpec()
+				workerPodSpec.ShareProcessNamespace = ptr.To(true)
+				return wrappers.BuildLeaderWorkerSet(ns.Name).WorkerTemplateSpec(workerPodSpec)
+			},
+			lwsCreationShouldFail: false,
+		}),
+		ginkgo.Entry("creation with a leader template sharing the process namespace with the host PID namespace should fail", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				leaderPodSpec := wrappers.MakeLeaderPodSpec()
+				leaderPodSpec.ShareProcessNamespace = ptr.To(true)
+				leaderPodSpec.HostPID = true
+				return wrappers.BuildLeaderWorkerSet(ns.Name).LeaderTemplateSpec(leaderPodSpec)
+			},
+			lwsCreationShouldFail: true,
+		}),
 		ginkgo.Entry("creation with invalid subGroupSize should fail", &testValidationCase{
 			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
 				return wrappers.BuildLeaderWorkerSet(ns.Name).Size(2).SubGroupSize(-1)
@@ -325,6 +390,24 @@ var _ = ginkgo.Describe("leaderworkerset defaulting, creation and update", func(
 			},
 			updateShouldFail: true,
 		}),
+		ginkgo.Entry("subGroupPolicyType can not be updated", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(ns.Name).Replica(1).Size(3).SubGroupSize(2).SubGroupType(leaderworkerset.SubGroupPolicyTypeLeaderExcluded)
+			},
+			updateLeaderWorkerSet: func(lws *leaderworkerset.LeaderWorkerSet) {
+				lws.Spec.LeaderWorkerTemplate.SubGroupPolicy.Type = ptr.To(leaderworkerset.SubGroupPolicyTypeLeaderWorker)
+			},
+			updateShouldFail: true,
+		}),
+		ginkgo.Entry("subGroupPolicy can be left unchanged on update", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(ns.Name).Replica(1).Size(2).SubGroupSize(1)
+			},
+			updateLeaderWorkerSet: func(lws *leaderworkerset.LeaderWorkerSet) {
+				lws.Spec.Replicas = ptr.To[int32](2)
+			},
+			updateShouldFail: false,
+		}),
 		ginkgo.Entry("number of replicas can be updated", &testValidationCase{
 			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
 				return wrappers.BuildLeaderWorkerSet(ns.Name).Replica(1).Size(1)
@@ -404,6 +487,22 @@ var _ = ginkgo.Describe("leaderworkerset defaulting, creation and update", func(
 			},
 			lwsCreationShouldFail: true,
 		}),
+		ginkgo.Entry("set Recreate rolloutStrategyType should succeed", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(ns.Name).RolloutStrategy(leaderworkerset.RolloutStrategy{
+					Type: leaderworkerset.RecreateStrategyType,
+				})
+			},
+			lwsCreationShouldFail: false,
+		}),
+		ginkgo.Entry("set Recreate rolloutStrategyType with rollingUpdateConfiguration should be failed", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				lws := wrappers.BuildLeaderWorkerSet(ns.Name)
+				lws.Spec.RolloutStrategy.Type = leaderworkerset.RecreateStrategyType
+				return lws
+			},
+			lwsCreationShouldFail: true,
+		}),
 		ginkgo.Entry("set maxUnavailable greater than replicas is allowed", &testValidationCase{
 			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
 				lws := wrappers.BuildLeaderWorkerSet(ns.Name)
@@ -479,5 +578,35 @@ var _ = ginkgo.Describe("leaderworkerset defaulting, creation and update", func(
 			},
 			lwsCreationShouldFail: true,
 		}),
+		ginkgo.Entry("creation with malformed exclusive-topology annotation should fail", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(ns.Name).Annotation(map[string]string{leaderworkerset.ExclusiveKeyAnnotationKey: "invalid topology"})
+			},
+			lwsCreationShouldFail: true,
+		}),
+		ginkgo.Entry("creation with valid groups-per-minute annotation should succeed", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(ns.Name).Annotation(map[string]string{leaderworkerset.GroupsPerMinuteAnnotationKey: "5"})
+			},
+			lwsCreationShouldFail: false,
+		}),
+		ginkgo.Entry("creation with non-positive groups-per-minute annotation should fail", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(ns.Name).Annotation(map[string]string{leaderworkerset.GroupsPerMinuteAnnotationKey: "0"})
+			},
+			lwsCreationShouldFail: true,
+		}),
+		ginkgo.Entry("creation with valid drain-timeout-seconds annotation should succeed", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(ns.Name).Annotation(map[string]string{leaderworkerset.DrainTimeoutAnnotationKey: "30"})
+			},
+			lwsCreationShouldFail: false,
+		}),
+		ginkgo.Entry("creation with non-positive drain-timeout-seconds annotation should fail", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(ns.Name).Annotation(map[string]string{leaderworkerset.DrainTimeoutAnnotationKey: "0"})
+			},
+			lwsCreationShouldFail: true,
+		}),
 	)
 })
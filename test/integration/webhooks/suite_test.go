@@ -44,6 +44,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
 	"sigs.k8s.io/lws/pkg/webhooks"
 )
@@ -133,10 +134,10 @@ var _ = BeforeSuite(func() {
 
 	/*err = controller.SetupIndexes(mgr.GetFieldIndexer())
 	Expect(err).NotTo(HaveOccurred())*/
-	err = webhooks.SetupLeaderWorkerSetWebhook(mgr)
+	_, err = webhooks.SetupLeaderWorkerSetWebhook(mgr, &configapi.Configuration{})
 	Expect(err).NotTo(HaveOccurred())
 
-	err = webhooks.SetupPodWebhook(mgr)
+	_, err = webhooks.SetupPodWebhook(mgr, &configapi.Configuration{})
 	Expect(err).NotTo(HaveOccurred())
 	//+kubebuilder:scaffold:webhook
 
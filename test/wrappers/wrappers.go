@@ -88,6 +88,11 @@ func (lwsWrapper *LeaderWorkerSetWrapper) RestartPolicy(policy leaderworkerset.R
 	return lwsWrapper
 }
 
+func (lwsWrapper *LeaderWorkerSetWrapper) ActiveDeadlineSeconds(seconds int64) *LeaderWorkerSetWrapper {
+	lwsWrapper.Spec.LeaderWorkerTemplate.ActiveDeadlineSeconds = &seconds
+	return lwsWrapper
+}
+
 func (lwsWrapper *LeaderWorkerSetWrapper) RolloutStrategy(strategy leaderworkerset.RolloutStrategy) *LeaderWorkerSetWrapper {
 	lwsWrapper.Spec.RolloutStrategy = strategy
 	return lwsWrapper
@@ -136,6 +141,21 @@ func (lwsWrapper *LeaderWorkerSetWrapper) SubdomainNil() *LeaderWorkerSetWrapper
 	return lwsWrapper
 }
 
+func (lwsWrapper *LeaderWorkerSetWrapper) MinReadyWorkers(minReadyWorkers int32) *LeaderWorkerSetWrapper {
+	lwsWrapper.Spec.GroupReadinessPolicy = &leaderworkerset.GroupReadinessPolicy{
+		MinReadyWorkers: &minReadyWorkers,
+	}
+	return lwsWrapper
+}
+
+func (lwsWrapper *LeaderWorkerSetWrapper) CoordinationOnlyLeader() *LeaderWorkerSetWrapper {
+	if lwsWrapper.Spec.GroupReadinessPolicy == nil {
+		lwsWrapper.Spec.GroupReadinessPolicy = &leaderworkerset.GroupReadinessPolicy{}
+	}
+	lwsWrapper.Spec.GroupReadinessPolicy.CoordinationOnlyLeader = true
+	return lwsWrapper
+}
+
 func BuildBasicLeaderWorkerSet(name, ns string) *LeaderWorkerSetWrapper {
 	return &LeaderWorkerSetWrapper{
 		leaderworkerset.LeaderWorkerSet{